@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"mime"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,10 +29,68 @@ import (
 
 // A Header represents a set of media ranges as sent in the Accept header
 // of an HTTP request.
+// An empty Header represents an absent or empty Accept header,
+// which means that any media type is acceptable.
 //
-// http://tools.ietf.org/html/rfc2616#section-14.1
+// https://www.rfc-editor.org/rfc/rfc9110#section-12.5.1
 type Header []MediaRange
 
+// NewHeader returns an empty Header.
+// It is intended for building Accept headers for outgoing requests
+// with [Header.Add]:
+//
+//	req.Header.Set("Accept", accept.NewHeader().
+//		Add("application/json", 1.0).
+//		Add("text/html", 0.8).
+//		String())
+func NewHeader() Header {
+	return Header{}
+}
+
+// Add returns h with a media range appended.
+// The contentType may include parameters, as in "text/html; charset=utf-8",
+// and may be a wildcard range like "text/*" or "*/*".
+// The range is stored in canonical form (see [*MediaRange.Canonical])
+// and quality is rounded to the three decimal places permitted by RFC 9110.
+// Add panics if contentType cannot be parsed
+// or quality is not between 0 and 1 inclusive,
+// since these indicate a programming error.
+func (h Header) Add(contentType string, quality float32) Header {
+	if !(0 <= quality && quality <= 1) {
+		panic(fmt.Sprintf("accept.Header.Add: quality %g out of range [0, 1]", quality))
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		panic(fmt.Sprintf("accept.Header.Add(%q): %v", contentType, err))
+	}
+	typ, subtype := splitContentType(mediaType)
+	if !isToken(typ) || !isToken(subtype) || typ == "*" && subtype != "*" {
+		panic(fmt.Sprintf("accept.Header.Add(%q): not a media range", contentType))
+	}
+	if _, hasQ := params["q"]; hasQ {
+		panic(fmt.Sprintf("accept.Header.Add(%q): weight must be passed as quality", contentType))
+	}
+	mr := MediaRange{
+		Range:   mediaType,
+		Quality: float32(math.Round(float64(quality)*1000) / 1000),
+		Params:  params,
+	}
+	return append(h, mr.Canonical())
+}
+
+// IsWildcard reports whether h accepts every media type equally:
+// either h is empty or it only contains "*/*" ranges
+// with the same non-zero quality and no parameters.
+func (h Header) IsWildcard() bool {
+	for i := range h {
+		mr := &h[i]
+		if mr.Range != "*/*" || len(mr.Params) > 0 || mr.Quality <= 0 || mr.Quality != h[0].Quality {
+			return false
+		}
+	}
+	return true
+}
+
 // String formats the media ranges in the format for an Accept header.
 func (h Header) String() string {
 	parts := make([]string, len(h))
@@ -41,114 +101,297 @@ func (h Header) String() string {
 	return strings.Join(parts, ",")
 }
 
+// MarshalText formats the media ranges in the format for an Accept header.
+// It returns an error if any of the media ranges cannot be formatted
+// in a way that [ParseHeader] would parse back to the same value.
+func (h Header) MarshalText() ([]byte, error) {
+	for i := range h {
+		if err := h[i].validate(); err != nil {
+			return nil, fmt.Errorf("marshal accept header: %w", err)
+		}
+	}
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText parses an Accept header as if by [ParseHeader].
+func (h *Header) UnmarshalText(text []byte) error {
+	h2, err := ParseHeader(string(text))
+	if err != nil {
+		return err
+	}
+	*h = h2
+	return nil
+}
+
 // Quality returns the quality of a content type based on the media ranges in h.
+// If h is empty, then Quality returns 1.
 func (h Header) Quality(contentType string, params map[string]string) float32 {
-	results := make(mediaRangeMatches, 0, len(h))
+	if len(h) == 0 {
+		return 1.0
+	}
+	mr := h.MatchingRange(contentType, params)
+	if mr == nil {
+		return 0.0
+	}
+	return mr.Quality
+}
+
+// MatchingRange returns the most specific media range in h
+// that applies to a content type,
+// or nil if none of the media ranges apply.
+// The returned media range determines the content type's [Header.Quality].
+// If more than one media range is equally specific, the first one is returned.
+func (h Header) MatchingRange(contentType string, params map[string]string) *MediaRange {
+	var best mediaRangeMatch
 	for i := range h {
-		mr := &h[i]
-		if m := mr.match(contentType, params); m.Valid {
-			results = append(results, m)
+		if m := h[i].match(contentType, params); m.moreSpecific(&best) {
+			best = m
 		}
 	}
-	if len(results) == 0 {
-		return 0.0
+	return best.MediaRange
+}
+
+// An Offer is a content type that a server is able to produce.
+type Offer struct {
+	// ContentType is the media type without parameters, like "text/html".
+	ContentType string
+	// Params is the set of media type parameters, like "charset".
+	Params map[string]string
+	// Language is the language tag of the content, like "en-US".
+	// It is only used by [NegotiateRequest].
+	// An empty Language matches any Accept-Language header.
+	Language string
+	// Quality is ignored by [Header.Sort] and [NegotiateRequest].
+	// They fill it in on the offers they return.
+	Quality float32
+}
+
+// Sort returns the offers that are acceptable according to h
+// (that is, have a non-zero [Header.Quality]),
+// ordered from highest to lowest quality.
+// Offers with equal quality are ordered by the specificity
+// of the media range that determines their quality
+// (see [Header.MatchingRange] and [*MediaRange.Specificity]),
+// so that a type the client named explicitly ranks above one matched by a wildcard.
+// Offers with equal quality and specificity keep their relative order,
+// so offers should be listed in order of server preference.
+// The offers slice is not modified.
+func (h Header) Sort(offers []Offer) []Offer {
+	ranked := make([]Offer, 0, len(offers))
+	ranks := make([]offerRank, 0, len(offers))
+	for _, o := range offers {
+		r := h.rank(o.ContentType, o.Params)
+		if r.quality > 0 {
+			o.Quality = r.quality
+			ranked = append(ranked, o)
+			ranks = append(ranks, r)
+		}
 	}
+	sort.Stable(rankedOffers{ranked, ranks})
+	return ranked
+}
 
-	// find most specific
-	i := 0
-	for j := 1; j < results.Len(); j++ {
-		if results.Less(j, i) {
-			i = j
+// Best returns the index of the offer that [Header.Sort] would rank first,
+// or -1 if none of the offers are acceptable.
+// It is equivalent to Sort, but does not allocate.
+func (h Header) Best(offers []Offer) int {
+	best := -1
+	var bestRank offerRank
+	for i := range offers {
+		if r := h.rank(offers[i].ContentType, offers[i].Params); r.better(bestRank) {
+			best, bestRank = i, r
 		}
 	}
-	return results[i].MediaRange.Quality
+	return best
+}
+
+// rankedOffers sorts offers by their ranks.
+type rankedOffers struct {
+	offers []Offer
+	ranks  []offerRank
+}
+
+func (ro rankedOffers) Len() int { return len(ro.offers) }
+
+func (ro rankedOffers) Less(i, j int) bool {
+	return ro.ranks[i].better(ro.ranks[j])
+}
+
+func (ro rankedOffers) Swap(i, j int) {
+	ro.offers[i], ro.offers[j] = ro.offers[j], ro.offers[i]
+	ro.ranks[i], ro.ranks[j] = ro.ranks[j], ro.ranks[i]
+}
+
+// offerRank is the sort key of an offer during negotiation.
+type offerRank struct {
+	quality float32
+	// specificity is the specificity of the media range
+	// that determines quality, or -1 if no media range applies.
+	specificity int
+}
+
+// rank returns the rank of a content type according to h.
+func (h Header) rank(contentType string, params map[string]string) offerRank {
+	if len(h) == 0 {
+		return offerRank{quality: 1, specificity: -1}
+	}
+	mr := h.MatchingRange(contentType, params)
+	if mr == nil {
+		return offerRank{specificity: -1}
+	}
+	return offerRank{quality: mr.Quality, specificity: mr.Specificity()}
+}
+
+// better reports whether r1 should be chosen over r2.
+// Unacceptable offers are never better than one another.
+func (r1 offerRank) better(r2 offerRank) bool {
+	if r1.quality != r2.quality {
+		return r1.quality > r2.quality
+	}
+	if r1.quality == 0 {
+		return false
+	}
+	return r1.specificity > r2.specificity
+}
+
+// Sorted returns a copy of h ordered by client preference:
+// from highest to lowest quality,
+// then from most to least specific (see [*MediaRange.Specificity]),
+// then in the order the ranges appear in h.
+// Ranges with a quality of zero are included at the end.
+func (h Header) Sorted() Header {
+	if len(h) == 0 {
+		return nil
+	}
+	sorted := make(Header, len(h))
+	copy(sorted, h)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		mi, mj := &sorted[i], &sorted[j]
+		if mi.Quality != mj.Quality {
+			return mi.Quality > mj.Quality
+		}
+		return mi.Specificity() > mj.Specificity()
+	})
+	return sorted
+}
+
+// Preferred returns up to n of the client's most preferred media ranges
+// in the order given by [Header.Sorted],
+// omitting ranges with a quality of zero.
+// If n is negative, all acceptable ranges are returned.
+// Preferred returns nil for an empty Header,
+// which callers should treat as accepting any media type.
+func (h Header) Preferred(n int) Header {
+	sorted := h.Sorted()
+	for i := range sorted {
+		if sorted[i].Quality <= 0 {
+			sorted = sorted[:i]
+			break
+		}
+	}
+	if n >= 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	if len(sorted) == 0 {
+		return nil
+	}
+	return sorted
 }
 
 // ParseHeader parses an Accept header of an HTTP request.  The media
 // ranges are unsorted.
+// RFC 9110 requires the "q" weight to be the last parameter
+// and no longer defines parameters after it
+// (RFC 7231 called them accept-ext).
+// ParseHeader accepts them for compatibility
+// and stores them in [MediaRange.Ext] rather than Params,
+// so they do not affect matching.
+// Use [ParseHeaderStrict] to reject them.
+// ParseHeader uses the default limits of a zero [ParserOptions].
 func ParseHeader(accept string) (Header, error) {
-	var h Header
-	p := &parser{s: accept}
+	return (*ParserOptions)(nil).AppendHeader(nil, accept)
+}
+
+// AppendHeader parses an Accept header like [ParseHeader]
+// and appends the media ranges to dst, returning the extended slice.
+// Passing a slice with spare capacity, like buf[:0],
+// avoids allocating a new Header for every request.
+// If the header cannot be parsed,
+// AppendHeader returns dst with its original length and an error.
+// AppendHeader uses the default limits of a zero [ParserOptions].
+func AppendHeader(dst Header, accept string) (Header, error) {
+	return (*ParserOptions)(nil).AppendHeader(dst, accept)
+}
+
+// ParseHeader parses an Accept header of an HTTP request like [ParseHeader],
+// but returns an error wrapping [ErrTooLarge]
+// if the header exceeds the limits in opts.
+func (opts *ParserOptions) ParseHeader(accept string) (Header, error) {
+	return opts.AppendHeader(nil, accept)
+}
+
+// AppendHeader parses an Accept header of an HTTP request like [AppendHeader],
+// but returns an error wrapping [ErrTooLarge]
+// if the header exceeds the limits in opts.
+func (opts *ParserOptions) AppendHeader(dst Header, accept string) (Header, error) {
+	maxRanges, maxParams := opts.maxRanges(), opts.maxParams()
+	h := dst
+	p := &parser{s: accept, input: accept}
+	var paramArray [DefaultMaxParams]rawParam
 	p.space()
-	for !p.eof() {
-		if len(h) > 0 {
+	for n := 0; !p.eof(); n++ {
+		if n > 0 {
 			if !p.consume(",") {
-				return nil, fmt.Errorf("parse accept header: expected ',', found %s", p.first())
+				return dst, fmt.Errorf("parse accept header: expected ',', found %s", p.first())
 			}
 			p.space()
 		}
-
-		r, err := parseMediaRange(p)
-		if err != nil {
-			return nil, fmt.Errorf("parse accept header: %w", err)
+		if exceeds(n+1, maxRanges) {
+			return dst, fmt.Errorf("parse accept header: more than %d media ranges: %w", maxRanges, ErrTooLarge)
 		}
-		quality, params, err := parseParams(p)
+		raw, err := p.mediaRange(paramArray[:0], maxParams)
 		if err != nil {
-			return nil, fmt.Errorf("parse accept header: %w", err)
+			return dst, fmt.Errorf("parse accept header: %w", err)
 		}
-		h = append(h, MediaRange{Range: r, Quality: quality, Params: params})
+		h = append(h, raw.toMediaRange(accept))
 	}
 	return h, nil
 }
 
-func parseMediaRange(p *parser) (string, error) {
-	const sep = "/"
-	input := p.s
-	typ := p.token()
-	if len(typ) == 0 {
-		return "", fmt.Errorf("parse media range: expected token, found %s", p.first())
-	}
-	if !p.consume(sep) {
-		return "", fmt.Errorf("parse media range: expected %q, found %s", sep, p.first())
+// toMediaRange converts a scanned media range to a [MediaRange].
+// Parameters after the weight are stored in Ext.
+// The Params and Ext maps are only allocated if they have entries,
+// since most media ranges do not have parameters.
+func (raw *rawMediaRange) toMediaRange(input string) MediaRange {
+	mr := MediaRange{
+		// strings.ToLower returns its argument without allocating
+		// if it is already lowercase, as media ranges usually are.
+		Range:   strings.ToLower(raw.mediaType(input)),
+		Quality: raw.quality,
 	}
-	subtype := p.token()
-	if len(subtype) == 0 {
-		return "", fmt.Errorf("parse media range: expected subtype, found %s", p.first())
-	}
-	return string(strings.ToLower(input[:len(typ)+len(sep)+len(subtype)])), nil
-}
-
-func parseParams(p *parser) (float32, map[string]string, error) {
-	quality, params := float32(1.0), make(map[string]string)
-	qset := false
-	p.space()
-	for p.consume(";") {
-		p.space()
-		key := strings.ToLower(p.token())
-		p.space()
-		if !p.consume("=") {
-			return 0, nil, fmt.Errorf("parse parameters: expected '=', found %s", p.first())
+	for _, param := range raw.params {
+		m := &mr.Params
+		if param.ext {
+			m = &mr.Ext
 		}
-		p.space()
-		var value string
-		if s, err := p.quotedString(); errors.Is(err, errNotQuotedString) {
-			value = p.token()
-		} else if err != nil {
-			return 0, nil, fmt.Errorf("parse parameters: %w", err)
-		} else {
-			value = string(s)
+		if *m == nil {
+			*m = make(map[string]string)
 		}
-		p.space()
+		(*m)[param.key] = param.value
+	}
+	return mr
+}
 
-		if key == "q" {
-			// check for qvalue
-			if qset {
-				return 0, nil, fmt.Errorf("parse parameters: duplicate q value")
-			}
-			q, err := strconv.ParseFloat(value, 64)
-			if err != nil || q < 0 || 1 < q {
-				return 0, nil, fmt.Errorf("parse parameters: invalid q value %q", value)
-			}
-			quality = float32(q)
-			qset = true
-		} else {
-			if _, dupe := params[key]; dupe {
-				return 0, nil, fmt.Errorf("parse parameters: duplicate name %q", key)
-			}
-			params[key] = value
-		}
+// parseParams parses the parameters of an element
+// of a header that uses weights,
+// returning an error if there are more than maxParams.
+// It only returns the weight: other parameters are ignored.
+func parseParams(p *parser, maxParams int) (quality float32, err error) {
+	list, err := p.params(nil, maxParams)
+	if err != nil {
+		return 0, fmt.Errorf("parse parameters: %w", err)
 	}
-	return quality, params, nil
+	return list.quality, nil
 }
 
 // A MediaRange represents a set of MIME types as sent in the Accept header of
@@ -156,7 +399,16 @@ func parseParams(p *parser) (float32, map[string]string, error) {
 type MediaRange struct {
 	Range   string
 	Quality float32
-	Params  map[string]string
+	// Params holds the media type parameters, like "charset".
+	// Parsed media ranges without parameters have a nil Params.
+	Params map[string]string
+	// Ext holds the parameters that follow the "q" weight,
+	// like "profile" in "text/html;q=0.9;profile=foo".
+	// RFC 7231 defined these as accept-ext parameters;
+	// RFC 9110 removed them from the grammar,
+	// but [ParseHeader] keeps them here for compatibility.
+	// They do not affect matching.
+	Ext map[string]string
 }
 
 // Match reports whether the range applies to a content type.
@@ -164,6 +416,29 @@ func (mr *MediaRange) Match(contentType string, params map[string]string) bool {
 	return mr.match(contentType, params).Valid
 }
 
+// Specificity returns a score that is higher
+// for media ranges that apply to fewer content types.
+// Media ranges with more parameters are the most specific,
+// followed by ranges with a concrete subtype like "text/html",
+// then ranges with a structured syntax suffix like "application/*+json",
+// then ranges with only a concrete type like "text/*".
+// "*/*" has the lowest specificity.
+func (mr *MediaRange) Specificity() int {
+	typ, subtype := splitContentType(mr.Range)
+	score := len(mr.Params) * 6
+	switch {
+	case subtype == "*":
+	case strings.HasPrefix(subtype, "*+"):
+		score += 2
+	default:
+		score += 4
+	}
+	if typ != "*" {
+		score++
+	}
+	return score
+}
+
 type mediaRangeMatch struct {
 	MediaRange *MediaRange
 	Valid      bool
@@ -172,12 +447,22 @@ type mediaRangeMatch struct {
 	Params     int
 }
 
+// specificity returns the [*MediaRange.Specificity] of a valid match's range.
+func (m *mediaRangeMatch) specificity() int {
+	return m.Params*6 + m.Subtype*2 + m.Type
+}
+
 type mediaRangeMatches []mediaRangeMatch
 
 func (m mediaRangeMatches) Len() int      { return len(m) }
 func (m mediaRangeMatches) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
 func (m mediaRangeMatches) Less(i, j int) bool {
-	mi, mj := &m[i], &m[j]
+	return m[i].moreSpecific(&m[j])
+}
+
+// moreSpecific reports whether mi is a valid match
+// that is more specific than mj.
+func (mi *mediaRangeMatch) moreSpecific(mj *mediaRangeMatch) bool {
 	switch {
 	case !mi.Valid && !mj.Valid:
 		return false
@@ -200,22 +485,21 @@ func (mr *MediaRange) match(contentType string, params map[string]string) mediaR
 	ctType, ctSubtype := splitContentType(contentType)
 	match := mediaRangeMatch{MediaRange: mr}
 
-	if !(mrSubtype == "*" || mrSubtype == ctSubtype) || !(mrType == "*" || mrType == ctType) {
+	if !(mrType == "*" || strings.EqualFold(mrType, ctType)) {
+		return match
+	}
+	subtypeScore, ok := matchSubtype(mrSubtype, ctSubtype)
+	if !ok {
 		return match
 	}
 	if mrType != "*" {
 		match.Type++
 	}
-	if mrSubtype != "*" {
-		match.Subtype++
-	}
+	match.Subtype = subtypeScore
 
 	for k, v1 := range mr.Params {
-		v2, ok := params[k]
-		if !ok {
-			return match
-		}
-		if v1 != v2 {
+		v2, ok := lookupParam(params, k)
+		if !ok || !paramValueEqual(k, v1, v2) {
 			return match
 		}
 		match.Params++
@@ -224,6 +508,54 @@ func (mr *MediaRange) match(contentType string, params map[string]string) mediaR
 	return match
 }
 
+// matchSubtype reports whether a media range subtype applies to subtype.
+// In addition to "*" and exact matches, a range subtype like "*+json"
+// matches any subtype with that structured syntax suffix (RFC 6839),
+// such as "vnd.api+json".
+// The returned score is higher for more specific matches.
+func matchSubtype(rangeSubtype, subtype string) (score int, ok bool) {
+	switch {
+	case rangeSubtype == "*":
+		return 0, true
+	case strings.EqualFold(rangeSubtype, subtype):
+		return 2, true
+	case strings.HasPrefix(rangeSubtype, "*+"):
+		suffix := rangeSubtype[1:]
+		if len(subtype) > len(suffix) && strings.EqualFold(subtype[len(subtype)-len(suffix):], suffix) {
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+// lookupParam returns the value of the parameter with the given name,
+// which must be lowercase.
+// Parameter names are case-insensitive,
+// so names in params do not need to be lowercase.
+func lookupParam(params map[string]string, name string) (string, bool) {
+	if v, ok := params[name]; ok {
+		return v, true
+	}
+	for k, v := range params {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// paramValueEqual reports whether two values
+// of the parameter with the given lowercase name are equivalent.
+// Values are compared case-sensitively
+// except for charset, whose values are case-insensitive
+// (RFC 9110 Section 8.3.2).
+func paramValueEqual(name, v1, v2 string) bool {
+	if name == "charset" {
+		return strings.EqualFold(v1, v2)
+	}
+	return v1 == v2
+}
+
 func splitContentType(s string) (string, string) {
 	i := strings.IndexRune(s, '/')
 	if i == -1 {
@@ -232,22 +564,149 @@ func splitContentType(s string) (string, string) {
 	return s[:i], s[i+1:]
 }
 
+// Canonical returns a copy of mr
+// with its range, parameter names, and extension parameter names in lowercase.
+// Values of the charset parameter are also lowercased,
+// since they are case-insensitive.
+// Other parameter values are case-sensitive and are left unchanged.
+func (mr *MediaRange) Canonical() MediaRange {
+	c := MediaRange{
+		Range:   strings.ToLower(mr.Range),
+		Quality: mr.Quality,
+	}
+	if mr.Params != nil {
+		c.Params = make(map[string]string, len(mr.Params))
+		for k, v := range mr.Params {
+			k = strings.ToLower(k)
+			if k == "charset" {
+				v = strings.ToLower(v)
+			}
+			c.Params[k] = v
+		}
+	}
+	if mr.Ext != nil {
+		c.Ext = make(map[string]string, len(mr.Ext))
+		for k, v := range mr.Ext {
+			c.Ext[strings.ToLower(k)] = v
+		}
+	}
+	return c
+}
+
+// MarshalText formats the media range as it would appear in an Accept header.
+// It returns an error if the media range cannot be formatted
+// in a way that [*MediaRange.UnmarshalText] would parse back to the same value.
+// MarshalText has a value receiver so that MediaRange values
+// are formatted as text even when they are not addressable.
+func (mr MediaRange) MarshalText() ([]byte, error) {
+	if err := mr.validate(); err != nil {
+		return nil, fmt.Errorf("marshal media range: %w", err)
+	}
+	return []byte(mr.String()), nil
+}
+
+// UnmarshalText parses a single media range
+// in the format of an element of an Accept header.
+func (mr *MediaRange) UnmarshalText(text []byte) error {
+	p := &parser{s: string(text), input: string(text)}
+	p.space()
+	raw, err := p.mediaRange(nil, DefaultMaxParams)
+	if err != nil {
+		return fmt.Errorf("unmarshal media range: %w", err)
+	}
+	if !p.eof() {
+		return fmt.Errorf("unmarshal media range: unexpected %s after parameters", p.first())
+	}
+	*mr = raw.toMediaRange(p.input)
+	return nil
+}
+
+// validate reports an error if mr would not survive
+// a round trip through its String method and parsing.
+func (mr *MediaRange) validate() error {
+	typ, subtype := splitContentType(mr.Range)
+	if !isToken(typ) || !isToken(subtype) {
+		return fmt.Errorf("invalid range %q", mr.Range)
+	}
+	if hasUpper(mr.Range) {
+		return fmt.Errorf("range %q is not lowercase (use Canonical)", mr.Range)
+	}
+	if !(0 <= mr.Quality && mr.Quality <= 1) {
+		return fmt.Errorf("%s: quality %g out of range [0, 1]", mr.Range, mr.Quality)
+	}
+	for k := range mr.Params {
+		if k == "q" || !isToken(k) {
+			return fmt.Errorf("%s: invalid parameter name %q", mr.Range, k)
+		}
+		if hasUpper(k) {
+			return fmt.Errorf("%s: parameter name %q is not lowercase (use Canonical)", mr.Range, k)
+		}
+	}
+	for k := range mr.Ext {
+		if k == "q" || !isToken(k) {
+			return fmt.Errorf("%s: invalid extension parameter name %q", mr.Range, k)
+		}
+		if hasUpper(k) {
+			return fmt.Errorf("%s: extension parameter name %q is not lowercase (use Canonical)", mr.Range, k)
+		}
+	}
+	return nil
+}
+
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isTokenChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 func (mr *MediaRange) String() string {
-	parts := make([]string, 0, len(mr.Params)+1)
+	parts := make([]string, 0, len(mr.Params)+len(mr.Ext)+2)
 	parts = append(parts, mr.Range)
-	if mr.Quality != 1.0 {
-		parts = append(parts, "q="+strconv.FormatFloat(float64(mr.Quality), 'f', 3, 32))
+	parts = appendParams(parts, mr.Params)
+	// RFC 9110 requires the weight to be the last media type parameter.
+	// The weight is always written if there are extension parameters
+	// so that they are not mistaken for media type parameters.
+	if q := formatQuality(mr.Quality); q != "1" || len(mr.Ext) > 0 {
+		parts = append(parts, "q="+q)
 	}
-	keys := make([]string, 0, len(mr.Params))
-	for k := range mr.Params {
+	parts = appendParams(parts, mr.Ext)
+	return strings.Join(parts, ";")
+}
+
+// formatQuality formats a weight as RFC 9110 section 12.4.2 requires:
+// clamped to the range [0, 1] with at most three decimal places.
+// The shortest decimal that parses to the same float32 is used
+// so that weights like 0.05 survive a round trip unchanged.
+func formatQuality(q float32) string {
+	switch {
+	case q >= 1:
+		return "1"
+	case !(q > 0):
+		// Also catches NaN.
+		return "0"
+	}
+	rounded := float32(math.Round(float64(q)*1000) / 1000)
+	return strconv.FormatFloat(float64(rounded), 'f', -1, 32)
+}
+
+// appendParams appends "key=value" strings for params to parts,
+// sorted by key.
+func appendParams(parts []string, params map[string]string) []string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 	for _, k := range keys {
-		v := mr.Params[k]
-		parts = append(parts, k+"="+quoteHTTP(v))
+		parts = append(parts, k+"="+quoteHTTP(params[k]))
 	}
-	return strings.Join(parts, ";")
+	return parts
 }
 
 func quoteHTTP(s string) string {
@@ -280,6 +739,9 @@ func quoteHTTP(s string) string {
 
 type parser struct {
 	s string
+	// input is the entire string being parsed.
+	// It is only needed for [*parser.offset].
+	input string
 }
 
 func (p *parser) eof() bool {
@@ -308,6 +770,15 @@ func (p *parser) consume(literal string) bool {
 	return true
 }
 
+func hasUpper(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if 'A' <= s[i] && s[i] <= 'Z' {
+			return true
+		}
+	}
+	return false
+}
+
 func isTokenChar(c byte) bool {
 	const chars = "!#$%&'*+-.0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ^_`abcdefghijklmnopqrstuvwxyz|~"
 	return strings.IndexByte(chars, c) != -1
@@ -331,26 +802,61 @@ func (p *parser) quotedString() (string, error) {
 	if len(p.s) == 0 || p.s[0] != '"' {
 		return "", errNotQuotedString
 	}
+	s, _, err := p.checkedQuotedString()
+	return s, err
+}
+
+// checkedQuotedString parses a quoted-string at the beginning of p.s.
+// bad is the byte offset (relative to the opening quote)
+// of the first character that RFC 9110 Section 5.6.4 does not permit
+// in a quoted-string, or -1 if all characters are permitted.
+func (p *parser) checkedQuotedString() (s string, bad int, err error) {
+	bad = -1
+	// Fast path: no escapes.
+	for i := 1; i < len(p.s) && p.s[i] != '\\'; i++ {
+		if c := p.s[i]; c == '"' {
+			s := p.s[1:i]
+			p.s = p.s[i+1:]
+			return s, bad, nil
+		} else if bad == -1 && !isQuotedPairChar(c) {
+			bad = i
+		}
+	}
+
+	bad = -1
 	sb := new(strings.Builder)
 	i := 1
 	for ; i < len(p.s); i++ {
 		switch c := p.s[i]; c {
 		case '"':
 			p.s = p.s[i+1:]
-			return sb.String(), nil
+			return sb.String(), bad, nil
 		case '\\':
 			i++
 			if i >= len(p.s) {
 				p.s = p.s[i:]
-				return "", io.ErrUnexpectedEOF
+				return "", bad, io.ErrUnexpectedEOF
+			}
+			if bad == -1 && !isQuotedPairChar(p.s[i]) {
+				bad = i
 			}
 			sb.WriteByte(p.s[i])
 		default:
+			if bad == -1 && !isQuotedPairChar(c) {
+				bad = i
+			}
 			sb.WriteByte(c)
 		}
 	}
 	p.s = p.s[i:]
-	return "", io.ErrUnexpectedEOF
+	return "", bad, io.ErrUnexpectedEOF
+}
+
+// isQuotedPairChar reports whether c may follow a backslash in a quoted-string.
+// This is HTAB, SP, VCHAR, or obs-text,
+// which is also every character permitted unescaped except '"' and '\\'.
+func isQuotedPairChar(c byte) bool {
+	return c == '\t' || c == ' ' || '!' <= c && c <= '~' || c >= 0x80
 }
 
 func (p *parser) space() string {
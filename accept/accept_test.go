@@ -15,6 +15,8 @@
 package accept
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -44,6 +46,33 @@ func TestHeader(t *testing.T) {
 				{"text/html", map[string]string{"level": "3"}, 0.7},
 			},
 		},
+		{
+			"application/*+json;q=0.8, application/vnd.api+json, */*;q=0.1",
+			[]QualityCheck{
+				{"application/vnd.api+json", map[string]string{}, 1.0},
+				{"application/problem+json", map[string]string{}, 0.8},
+				{"application/json", map[string]string{}, 0.1},
+				{"application/vnd.api+xml", map[string]string{}, 0.1},
+			},
+		},
+		{
+			// Mixed case as sent by some real-world clients.
+			"Text/HTML;Charset=UTF-8, Application/XHTML+XML;q=0.9, IMAGE/*;q=0.5",
+			[]QualityCheck{
+				{"text/html", map[string]string{"charset": "utf-8"}, 1.0},
+				{"TEXT/html", map[string]string{"CHARSET": "utf-8"}, 1.0},
+				{"text/html", map[string]string{"charset": "ISO-8859-1"}, 0.0},
+				{"application/xhtml+xml", map[string]string{}, 0.9},
+				{"Image/PNG", map[string]string{}, 0.5},
+			},
+		},
+		{
+			"",
+			[]QualityCheck{
+				{"text/html", map[string]string{}, 1.0},
+				{"image/jpeg", map[string]string{}, 1.0},
+			},
+		},
 	}
 	for _, test := range tests {
 		h, err := ParseHeader(test.Accept)
@@ -60,6 +89,281 @@ func TestHeader(t *testing.T) {
 	}
 }
 
+func TestHeaderSort(t *testing.T) {
+	offers := []Offer{
+		{ContentType: "text/html", Params: map[string]string{"charset": "utf-8"}},
+		{ContentType: "application/json"},
+		{ContentType: "text/plain", Params: map[string]string{"charset": "utf-8"}},
+	}
+	tests := []struct {
+		accept string
+		want   []Offer
+	}{
+		{
+			accept: "",
+			want: []Offer{
+				{ContentType: "text/html", Params: map[string]string{"charset": "utf-8"}, Quality: 1},
+				{ContentType: "application/json", Quality: 1},
+				{ContentType: "text/plain", Params: map[string]string{"charset": "utf-8"}, Quality: 1},
+			},
+		},
+		{
+			accept: "text/*;q=0.5, application/json",
+			want: []Offer{
+				{ContentType: "application/json", Quality: 1},
+				{ContentType: "text/html", Params: map[string]string{"charset": "utf-8"}, Quality: 0.5},
+				{ContentType: "text/plain", Params: map[string]string{"charset": "utf-8"}, Quality: 0.5},
+			},
+		},
+		{
+			accept: "text/*;q=0.5, text/plain;q=0.8, text/html;q=0",
+			want: []Offer{
+				{ContentType: "text/plain", Params: map[string]string{"charset": "utf-8"}, Quality: 0.8},
+			},
+		},
+		{
+			accept: "image/png",
+			want:   []Offer{},
+		},
+		{
+			// Equal q-values: the offer named explicitly ranks first.
+			accept: "*/*, text/*, text/plain",
+			want: []Offer{
+				{ContentType: "text/plain", Params: map[string]string{"charset": "utf-8"}, Quality: 1},
+				{ContentType: "text/html", Params: map[string]string{"charset": "utf-8"}, Quality: 1},
+				{ContentType: "application/json", Quality: 1},
+			},
+		},
+		{
+			// Equal q-values: ranges with parameters are more specific.
+			accept: "text/plain;q=0.5, text/html;charset=utf-8;q=0.5, application/json;q=0.5",
+			want: []Offer{
+				{ContentType: "text/html", Params: map[string]string{"charset": "utf-8"}, Quality: 0.5},
+				{ContentType: "application/json", Quality: 0.5},
+				{ContentType: "text/plain", Params: map[string]string{"charset": "utf-8"}, Quality: 0.5},
+			},
+		},
+	}
+	for _, test := range tests {
+		h, err := ParseHeader(test.accept)
+		if err != nil {
+			t.Errorf("ParseHeader(%q): %v", test.accept, err)
+			continue
+		}
+		got := h.Sort(offers)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseHeader(%q).Sort(...) (-want +got):\n%s", test.accept, diff)
+		}
+		best := h.Best(offers)
+		if len(got) == 0 && best != -1 || len(got) > 0 && (best < 0 || offers[best].ContentType != got[0].ContentType) {
+			t.Errorf("ParseHeader(%q).Best(...) = %d; want index of first sorted offer", test.accept, best)
+		}
+	}
+	for i, o := range offers {
+		if o.Quality != 0 {
+			t.Errorf("offers[%d].Quality = %v after Sort; want 0 (unmodified)", i, o.Quality)
+		}
+	}
+}
+
+func TestHeaderSorted(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   []string
+	}{
+		{accept: "", want: nil},
+		{
+			accept: "text/*;q=0.5, application/json, text/html;q=0.5",
+			want:   []string{"application/json", "text/html;q=0.5", "text/*;q=0.5"},
+		},
+		{
+			accept: "*/*, text/html;level=1, text/html, image/png",
+			want:   []string{"text/html;level=1", "text/html", "image/png", "*/*"},
+		},
+		{
+			accept: "image/png;q=0, image/webp;q=0.8, image/avif;q=0.8",
+			want:   []string{"image/webp;q=0.8", "image/avif;q=0.8", "image/png;q=0"},
+		},
+	}
+	for _, test := range tests {
+		h, err := ParseHeader(test.accept)
+		if err != nil {
+			t.Errorf("ParseHeader(%q): %v", test.accept, err)
+			continue
+		}
+		orig := h.String()
+		var got []string
+		for _, mr := range h.Sorted() {
+			got = append(got, mr.String())
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseHeader(%q).Sorted() (-want +got):\n%s", test.accept, diff)
+		}
+		if h.String() != orig {
+			t.Errorf("ParseHeader(%q).Sorted() modified header to %q", test.accept, h.String())
+		}
+	}
+}
+
+func TestHeaderPreferred(t *testing.T) {
+	h, err := ParseHeader("image/png;q=0, */*;q=0.1, image/webp;q=0.8, image/avif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{n: 0, want: nil},
+		{n: 1, want: []string{"image/avif"}},
+		{n: 2, want: []string{"image/avif", "image/webp;q=0.8"}},
+		{n: 10, want: []string{"image/avif", "image/webp;q=0.8", "*/*;q=0.1"}},
+		{n: -1, want: []string{"image/avif", "image/webp;q=0.8", "*/*;q=0.1"}},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, mr := range h.Preferred(test.n) {
+			got = append(got, mr.String())
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("Preferred(%d) (-want +got):\n%s", test.n, diff)
+		}
+	}
+	if got := Header(nil).Preferred(3); got != nil {
+		t.Errorf("Header(nil).Preferred(3) = %v; want nil", got)
+	}
+}
+
+func TestHeaderAdd(t *testing.T) {
+	tests := []struct {
+		h    Header
+		want string
+	}{
+		{NewHeader(), ""},
+		{NewHeader().Add("application/json", 1.0), "application/json"},
+		{
+			NewHeader().Add("application/json", 1.0).Add("text/html", 0.8),
+			"application/json,text/html;q=0.8",
+		},
+		{
+			NewHeader().Add("Text/HTML; Charset=UTF-8; Level=1", 0.5).Add("*/*", 0.1),
+			"text/html;charset=utf-8;level=1;q=0.5,*/*;q=0.1",
+		},
+		{
+			NewHeader().Add(`text/plain; format="a b"`, 0.12345),
+			`text/plain;format="a b";q=0.123`,
+		},
+		{NewHeader().Add("image/*", 0), "image/*;q=0"},
+	}
+	for _, test := range tests {
+		if got := test.h.String(); got != test.want {
+			t.Errorf("%#v.String() = %q; want %q", test.h, got, test.want)
+		}
+		if _, err := ParseHeaderStrict(test.h.String()); err != nil {
+			t.Errorf("ParseHeaderStrict(%q): %v", test.h.String(), err)
+		}
+	}
+}
+
+func TestHeaderAddPanics(t *testing.T) {
+	tests := []struct {
+		contentType string
+		quality     float32
+	}{
+		{"text/html", -0.5},
+		{"text/html", 1.5},
+		{"text", 1},
+		{"*/html", 1},
+		{"text/html; q=0.5", 1},
+		{"text/html;;", 1},
+	}
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewHeader().Add(%q, %g) did not panic", test.contentType, test.quality)
+				}
+			}()
+			NewHeader().Add(test.contentType, test.quality)
+		}()
+	}
+}
+
+func TestHeaderIsWildcard(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", true},
+		{"*/*", true},
+		{"*/*;q=0.5", true},
+		{"*/*;q=0", false},
+		{"*/*;foo=bar", false},
+		{"text/html", false},
+		{"text/html, */*;q=0.1", false},
+		{"*/*, */*;q=0.5", false},
+	}
+	for _, test := range tests {
+		h, err := ParseHeader(test.accept)
+		if err != nil {
+			t.Errorf("ParseHeader(%q): %v", test.accept, err)
+			continue
+		}
+		if got := h.IsWildcard(); got != test.want {
+			t.Errorf("ParseHeader(%q).IsWildcard() = %t; want %t", test.accept, got, test.want)
+		}
+	}
+}
+
+func TestHeaderMatchingRange(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+		params      map[string]string
+		want        string
+	}{
+		{"text/html", "application/json", nil, ""},
+		{"text/html, */*;q=0.1", "text/html", nil, "text/html"},
+		{"*/*;q=0.1, text/html", "text/html", nil, "text/html"},
+		{"text/html, */*;q=0.1", "application/json", nil, "*/*"},
+		{"application/*, application/*+json", "application/vnd.api+json", nil, "application/*+json"},
+		{"text/*;charset=utf-8, text/plain", "text/plain", map[string]string{"charset": "utf-8"}, "text/*"},
+	}
+	for _, test := range tests {
+		h, err := ParseHeader(test.accept)
+		if err != nil {
+			t.Errorf("ParseHeader(%q): %v", test.accept, err)
+			continue
+		}
+		got := ""
+		if mr := h.MatchingRange(test.contentType, test.params); mr != nil {
+			got = mr.Range
+		}
+		if got != test.want {
+			t.Errorf("ParseHeader(%q).MatchingRange(%q, %v) = %q; want %q", test.accept, test.contentType, test.params, got, test.want)
+		}
+	}
+}
+
+func TestMediaRangeSpecificity(t *testing.T) {
+	// Listed from least to most specific.
+	ranges := []MediaRange{
+		{Range: "*/*"},
+		{Range: "text/*"},
+		{Range: "application/*+json"},
+		{Range: "text/html"},
+		{Range: "text/*", Params: map[string]string{"charset": "utf-8"}},
+		{Range: "text/html", Params: map[string]string{"charset": "utf-8"}},
+		{Range: "text/html", Params: map[string]string{"charset": "utf-8", "level": "1"}},
+	}
+	for i := 1; i < len(ranges); i++ {
+		prev, curr := &ranges[i-1], &ranges[i]
+		if prev.Specificity() >= curr.Specificity() {
+			t.Errorf("(%v).Specificity() = %d >= (%v).Specificity() = %d", prev, prev.Specificity(), curr, curr.Specificity())
+		}
+	}
+}
+
 func TestParseHeader(t *testing.T) {
 	tests := []struct {
 		accept  string
@@ -71,19 +375,19 @@ func TestParseHeader(t *testing.T) {
 		{
 			accept: `text/html; q=1`,
 			want: Header{
-				{"text/html", 1.0, map[string]string{}},
+				{"text/html", 1.0, map[string]string{}, nil},
 			},
 		},
 		{
 			accept: `text/html; q=0.001`,
 			want: Header{
-				{"text/html", 0.001, map[string]string{}},
+				{"text/html", 0.001, map[string]string{}, nil},
 			},
 		},
 		{
 			accept: `text/html; q=0`,
 			want: Header{
-				{"text/html", 0.0, map[string]string{}},
+				{"text/html", 0.0, map[string]string{}, nil},
 			},
 		},
 		{
@@ -93,64 +397,80 @@ func TestParseHeader(t *testing.T) {
 		{
 			accept: "audio/*; q=0.2, audio/basic",
 			want: Header{
-				{"audio/*", 0.2, map[string]string{}},
-				{"audio/basic", 1.0, map[string]string{}},
+				{"audio/*", 0.2, map[string]string{}, nil},
+				{"audio/basic", 1.0, map[string]string{}, nil},
 			},
 		},
 		{
 			accept: `text/html; charset="utf-8"`,
 			want: Header{
-				{"text/html", 1.0, map[string]string{"charset": "utf-8"}},
+				{"text/html", 1.0, map[string]string{"charset": "utf-8"}, nil},
 			},
 		},
 		{
-			accept: `TEXT/HTML; Q=0.5; CHARSET="UTF-8"`,
+			accept: `TEXT/HTML; CHARSET="UTF-8"; Q=0.5`,
 			want: Header{
-				{"text/html", 0.5, map[string]string{"charset": "UTF-8"}},
+				{"text/html", 0.5, map[string]string{"charset": "UTF-8"}, nil},
+			},
+		},
+		{
+			accept: `text/html; q=0.5; level=1`,
+			want: Header{
+				{"text/html", 0.5, map[string]string{}, map[string]string{"level": "1"}},
 			},
 		},
 		{
 			accept: `text/html; charset="utf 8"`,
 			want: Header{
-				{"text/html", 1.0, map[string]string{"charset": "utf 8"}},
+				{"text/html", 1.0, map[string]string{"charset": "utf 8"}, nil},
 			},
 		},
 		{
 			accept: `text/html; charset="utf\"8"`,
 			want: Header{
-				{"text/html", 1.0, map[string]string{"charset": "utf\"8"}},
+				{"text/html", 1.0, map[string]string{"charset": "utf\"8"}, nil},
 			},
 		},
 		{
 			accept:  `text/html; charset="utf-8"; charset="utf 8"; charset="utf\"8"`,
 			wantErr: true,
 		},
+		{
+			accept: `text/html; level=1; q=0.5; level=2`,
+			want: Header{
+				{"text/html", 0.5, map[string]string{"level": "1"}, map[string]string{"level": "2"}},
+			},
+		},
+		{
+			accept:  `text/html; q=0.5; q=0.6`,
+			wantErr: true,
+		},
 		{
 			accept: "text/plain; q=0.5, text/html, text/x-dvi; q=0.8, text/x-c",
 			want: Header{
-				{"text/plain", 0.5, map[string]string{}},
-				{"text/html", 1.0, map[string]string{}},
-				{"text/x-dvi", 0.8, map[string]string{}},
-				{"text/x-c", 1.0, map[string]string{}},
+				{"text/plain", 0.5, map[string]string{}, nil},
+				{"text/html", 1.0, map[string]string{}, nil},
+				{"text/x-dvi", 0.8, map[string]string{}, nil},
+				{"text/x-c", 1.0, map[string]string{}, nil},
 			},
 		},
 		{
 			accept: "text/*, text/html, text/html;level=1, */*",
 			want: Header{
-				{"text/*", 1.0, map[string]string{}},
-				{"text/html", 1.0, map[string]string{}},
-				{"text/html", 1.0, map[string]string{"level": "1"}},
-				{"*/*", 1.0, map[string]string{}},
+				{"text/*", 1.0, map[string]string{}, nil},
+				{"text/html", 1.0, map[string]string{}, nil},
+				{"text/html", 1.0, map[string]string{"level": "1"}, nil},
+				{"*/*", 1.0, map[string]string{}, nil},
 			},
 		},
 		{
 			accept: "text/*;q=0.3, text/html;q=0.7, text/html;level=1, text/html;level=2;q=0.4, */*;q=0.5",
 			want: Header{
-				{"text/*", 0.3, map[string]string{}},
-				{"text/html", 0.7, map[string]string{}},
-				{"text/html", 1.0, map[string]string{"level": "1"}},
-				{"text/html", 0.4, map[string]string{"level": "2"}},
-				{"*/*", 0.5, map[string]string{}},
+				{"text/*", 0.3, map[string]string{}, nil},
+				{"text/html", 0.7, map[string]string{}, nil},
+				{"text/html", 1.0, map[string]string{"level": "1"}, nil},
+				{"text/html", 0.4, map[string]string{"level": "2"}, nil},
+				{"*/*", 0.5, map[string]string{}, nil},
 			},
 		},
 	}
@@ -173,6 +493,66 @@ func TestParseHeader(t *testing.T) {
 	}
 }
 
+func TestAppendHeader(t *testing.T) {
+	buf := make(Header, 1, 8)
+	buf[0] = MediaRange{Range: "image/png", Quality: 1}
+	got, err := AppendHeader(buf, "text/html;level=1, */*;q=0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Header{
+		{Range: "image/png", Quality: 1},
+		{Range: "text/html", Quality: 1, Params: map[string]string{"level": "1"}},
+		{Range: "*/*", Quality: 0.5},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AppendHeader(...) (-want +got):\n%s", diff)
+	}
+	if &got[0] != &buf[0] {
+		t.Error("AppendHeader did not reuse the slice's capacity")
+	}
+
+	got, err = AppendHeader(buf, "text/html,bad")
+	if err == nil {
+		t.Error("AppendHeader(..., \"text/html,bad\") did not return an error")
+	}
+	if len(got) != len(buf) {
+		t.Errorf("AppendHeader(...) on error returned %d media ranges; want %d", len(got), len(buf))
+	}
+}
+
+func TestAppendHeaderAllocs(t *testing.T) {
+	const accept = `text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8`
+	buf := make(Header, 0, 8)
+	allocs := testing.AllocsPerRun(100, func() {
+		AppendHeader(buf[:0], accept)
+	})
+	if allocs > 0 {
+		t.Errorf("AppendHeader allocated %.1f times per run; want 0", allocs)
+	}
+}
+
+func BenchmarkParseHeader(b *testing.B) {
+	const accept = `text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8`
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := ParseHeader(accept); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Append", func(b *testing.B) {
+		buf := make(Header, 0, 8)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := AppendHeader(buf[:0], accept); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 func TestMediaRange_match(t *testing.T) {
 	tests := []struct {
 		Range  string
@@ -186,7 +566,7 @@ func TestMediaRange_match(t *testing.T) {
 		{
 			"text/html", map[string]string{},
 			"text/html", map[string]string{},
-			mediaRangeMatch{nil, true, 1, 1, 0},
+			mediaRangeMatch{nil, true, 1, 2, 0},
 		},
 		{
 			"text/html", map[string]string{},
@@ -211,32 +591,67 @@ func TestMediaRange_match(t *testing.T) {
 		{
 			"text/html", map[string]string{"level": "1"},
 			"text/html", map[string]string{"level": "1"},
-			mediaRangeMatch{nil, true, 1, 1, 1},
+			mediaRangeMatch{nil, true, 1, 2, 1},
 		},
 		{
 			"text/html", map[string]string{"level": "1"},
 			"text/html", map[string]string{"level": "2"},
-			mediaRangeMatch{nil, false, 1, 1, 0},
+			mediaRangeMatch{nil, false, 1, 2, 0},
 		},
 		{
 			"text/html", map[string]string{"level": "1"},
 			"text/html", map[string]string{},
-			mediaRangeMatch{nil, false, 1, 1, 0},
+			mediaRangeMatch{nil, false, 1, 2, 0},
 		},
 		{
 			"text/html", map[string]string{},
 			"text/html", map[string]string{"level": "1"},
-			mediaRangeMatch{nil, true, 1, 1, 0},
+			mediaRangeMatch{nil, true, 1, 2, 0},
 		},
 		{
 			"text/html", map[string]string{"level": "1"},
 			"text/html", map[string]string{"level": "1", "foo": "bar"},
-			mediaRangeMatch{nil, true, 1, 1, 1},
+			mediaRangeMatch{nil, true, 1, 2, 1},
 		},
 		{
 			"text/html", map[string]string{"level": "1", "charset": "utf-8"},
 			"text/html", map[string]string{"level": "1", "charset": "utf-8", "foo": "bar"},
-			mediaRangeMatch{nil, true, 1, 1, 2},
+			mediaRangeMatch{nil, true, 1, 2, 2},
+		},
+		{
+			"text/html", map[string]string{"charset": "utf-8"},
+			"Text/HTML", map[string]string{"Charset": "UTF-8"},
+			mediaRangeMatch{nil, true, 1, 2, 1},
+		},
+		{
+			"text/html", map[string]string{"level": "a"},
+			"text/html", map[string]string{"level": "A"},
+			mediaRangeMatch{nil, false, 1, 2, 0},
+		},
+		{
+			"application/*+json", map[string]string{},
+			"application/vnd.api+json", map[string]string{},
+			mediaRangeMatch{nil, true, 1, 1, 0},
+		},
+		{
+			"*/*+json", map[string]string{},
+			"application/vnd.api+json", map[string]string{},
+			mediaRangeMatch{nil, true, 0, 1, 0},
+		},
+		{
+			"application/*+json", map[string]string{},
+			"application/json", map[string]string{},
+			mediaRangeMatch{nil, false, 0, 0, 0},
+		},
+		{
+			"application/*+json", map[string]string{},
+			"application/vnd.api+xml", map[string]string{},
+			mediaRangeMatch{nil, false, 0, 0, 0},
+		},
+		{
+			"text/*+json", map[string]string{},
+			"application/vnd.api+json", map[string]string{},
+			mediaRangeMatch{nil, false, 0, 0, 0},
 		},
 	}
 	for _, test := range tests {
@@ -249,6 +664,32 @@ func TestMediaRange_match(t *testing.T) {
 	}
 }
 
+func TestMediaRangeCanonical(t *testing.T) {
+	tests := []struct {
+		mr   MediaRange
+		want MediaRange
+	}{
+		{
+			mr:   MediaRange{Range: "text/html", Quality: 1},
+			want: MediaRange{Range: "text/html", Quality: 1},
+		},
+		{
+			mr:   MediaRange{Range: "Text/HTML", Quality: 0.5, Params: map[string]string{"Charset": "UTF-8", "Level": "A"}},
+			want: MediaRange{Range: "text/html", Quality: 0.5, Params: map[string]string{"charset": "utf-8", "level": "A"}},
+		},
+		{
+			mr:   MediaRange{Range: "*/*", Quality: 0.1, Params: map[string]string{}},
+			want: MediaRange{Range: "*/*", Quality: 0.1, Params: map[string]string{}},
+		},
+	}
+	for _, test := range tests {
+		got := test.mr.Canonical()
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("(%v).Canonical() (-want +got):\n%s", &test.mr, diff)
+		}
+	}
+}
+
 func TestMediaRangeMatchLess(t *testing.T) {
 	tests := []struct {
 		A, B mediaRangeMatch
@@ -278,3 +719,116 @@ func TestMediaRangeMatchLess(t *testing.T) {
 		}
 	}
 }
+
+func TestMediaRangeString(t *testing.T) {
+	tests := []struct {
+		mr   MediaRange
+		want string
+	}{
+		{MediaRange{Range: "text/html", Quality: 1}, "text/html"},
+		{MediaRange{Range: "text/html", Quality: 0}, "text/html;q=0"},
+		{MediaRange{Range: "text/html", Quality: 0.5}, "text/html;q=0.5"},
+		{MediaRange{Range: "text/html", Quality: 0.05}, "text/html;q=0.05"},
+		{MediaRange{Range: "text/html", Quality: 0.001}, "text/html;q=0.001"},
+		{MediaRange{Range: "text/html", Quality: 0.12345}, "text/html;q=0.123"},
+		{MediaRange{Range: "text/html", Quality: 0.0004}, "text/html;q=0"},
+		{MediaRange{Range: "text/html", Quality: 0.9996}, "text/html"},
+		{MediaRange{Range: "text/html", Quality: 1.5}, "text/html"},
+		{MediaRange{Range: "text/html", Quality: -0.5}, "text/html;q=0"},
+		{MediaRange{Range: "text/html", Quality: float32(math.NaN())}, "text/html;q=0"},
+		{MediaRange{Range: "text/html", Quality: 0.3, Params: map[string]string{"level": "1"}}, "text/html;level=1;q=0.3"},
+		{MediaRange{Range: "text/html", Quality: 0.9, Ext: map[string]string{"profile": "foo"}}, "text/html;q=0.9;profile=foo"},
+		{MediaRange{Range: "text/html", Quality: 1, Ext: map[string]string{"profile": "foo"}}, "text/html;q=1;profile=foo"},
+	}
+	for _, test := range tests {
+		if got := test.mr.String(); got != test.want {
+			t.Errorf("(%+v).String() = %q; want %q", test.mr, got, test.want)
+		}
+	}
+
+	// Parsed weights should format the same as they were sent.
+	for _, accept := range []string{"text/html;q=0.05", "text/html;q=0.123", "*/*;q=0.8"} {
+		h, err := ParseHeader(accept)
+		if err != nil {
+			t.Errorf("ParseHeader(%q): %v", accept, err)
+			continue
+		}
+		if got := h.String(); got != accept {
+			t.Errorf("ParseHeader(%q).String() = %q", accept, got)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	type config struct {
+		Accept Header
+		Range  MediaRange
+	}
+	tests := []config{
+		{
+			Range: MediaRange{Range: "*/*", Quality: 1, Params: map[string]string{}},
+		},
+		{
+			Accept: Header{
+				{Range: "text/html", Quality: 1, Params: map[string]string{"level": "1"}},
+				{Range: "text/*", Quality: 0.3, Params: map[string]string{}},
+				{Range: "*/*", Quality: 0.125, Params: map[string]string{"foo": `a "b", c`}},
+			},
+			Range: MediaRange{Range: "application/json", Quality: 0, Params: map[string]string{"charset": "utf-8"}},
+		},
+		{
+			Range: MediaRange{
+				Range:   "text/html",
+				Quality: 1,
+				Params:  map[string]string{"level": "1"},
+				Ext:     map[string]string{"profile": "foo"},
+			},
+		},
+	}
+	for _, want := range tests {
+		data, err := json.Marshal(want)
+		if err != nil {
+			t.Errorf("json.Marshal(%+v): %v", want, err)
+			continue
+		}
+		var got config
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("json.Unmarshal(%s): %v", data, err)
+			continue
+		}
+		if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("round trip through %s (-want +got):\n%s", data, diff)
+		}
+	}
+}
+
+func TestMarshalTextErrors(t *testing.T) {
+	tests := []MediaRange{
+		{Range: "text", Quality: 1},
+		{Range: "Text/HTML", Quality: 1},
+		{Range: "text/html", Quality: 1.5},
+		{Range: "text/html", Quality: -1},
+		{Range: "text/html", Quality: 1, Params: map[string]string{"q": "1"}},
+		{Range: "text/html", Quality: 1, Params: map[string]string{"Level": "1"}},
+		{Range: "text/html", Quality: 1, Params: map[string]string{"a b": "1"}},
+		{Range: "text/html", Quality: 1, Ext: map[string]string{"Profile": "foo"}},
+		{Range: "text/html", Quality: 1, Ext: map[string]string{"q": "1"}},
+	}
+	for _, mr := range tests {
+		if got, err := mr.MarshalText(); err == nil {
+			t.Errorf("(%+v).MarshalText() = %q, <nil>; want error", mr, got)
+		}
+		if got, err := (Header{mr}).MarshalText(); err == nil {
+			t.Errorf("Header{%+v}.MarshalText() = %q, <nil>; want error", mr, got)
+		}
+	}
+}
+
+func TestMediaRangeUnmarshalTextErrors(t *testing.T) {
+	for _, text := range []string{"", "text", "text/html, text/plain", "text/html;q=2"} {
+		var mr MediaRange
+		if err := mr.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) = <nil>; want error (got %+v)", text, mr)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MaxCachedHeaderLength is the length of the longest Accept header
+// that a [Cache] will store.
+// Longer headers are parsed on every call,
+// so that unusual clients cannot fill the cache with large keys.
+const MaxCachedHeaderLength = 1024
+
+// A Cache memoizes the results of [ParseHeader],
+// keeping the most recently used headers.
+// Browsers send the same few Accept headers on every request,
+// so a small Cache avoids most parsing on busy servers.
+//
+// A Cache is safe to use from multiple goroutines.
+type Cache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     list.List // of *cacheEntry, most recently used first
+}
+
+type cacheEntry struct {
+	accept string
+	header Header
+	err    error
+}
+
+// NewCache returns a new empty [Cache]
+// that stores up to size parsed headers.
+// NewCache panics if size is not positive.
+func NewCache(size int) *Cache {
+	if size <= 0 {
+		panic("accept.NewCache: size must be positive")
+	}
+	return &Cache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Parse returns the result of calling [ParseHeader] with accept,
+// using a previous result if one is available.
+// The returned Header is shared between callers
+// and must not be modified.
+func (c *Cache) Parse(accept string) (Header, error) {
+	if len(accept) > MaxCachedHeaderLength {
+		return ParseHeader(accept)
+	}
+	c.mu.Lock()
+	if elem := c.entries[accept]; elem != nil {
+		c.lru.MoveToFront(elem)
+		ent := elem.Value.(*cacheEntry)
+		c.mu.Unlock()
+		return ent.header, ent.err
+	}
+	c.mu.Unlock()
+
+	// Parse without holding the lock.
+	// Concurrent misses for the same header may parse more than once,
+	// but produce equivalent results.
+	h, err := ParseHeader(accept)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem := c.entries[accept]; elem != nil {
+		c.lru.MoveToFront(elem)
+		ent := elem.Value.(*cacheEntry)
+		return ent.header, ent.err
+	}
+	c.entries[accept] = c.lru.PushFront(&cacheEntry{
+		accept: accept,
+		header: h,
+		err:    err,
+	})
+	if c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).accept)
+	}
+	return h, err
+}
+
+// Len returns the number of headers in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
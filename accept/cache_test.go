@@ -0,0 +1,96 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCache(t *testing.T) {
+	c := NewCache(2)
+	const (
+		html = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+		json = "application/json"
+		png  = "image/png"
+	)
+	for _, accept := range []string{html, json, html, png} {
+		got, err := c.Parse(accept)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", accept, err)
+		}
+		want, _ := ParseHeader(accept)
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Parse(%q) (-want +got):\n%s", accept, diff)
+		}
+	}
+	if got, want := c.Len(), 2; got != want {
+		t.Errorf("Len() = %d; want %d", got, want)
+	}
+	// json was least recently used, so it should have been evicted.
+	c.mu.Lock()
+	_, hasHTML := c.entries[html]
+	_, hasJSON := c.entries[json]
+	c.mu.Unlock()
+	if !hasHTML || hasJSON {
+		t.Errorf("after eviction, has html = %t, has json = %t; want true, false", hasHTML, hasJSON)
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if _, err := c.Parse("text/html;q=2"); err == nil {
+				t.Errorf("Parse #%d did not return an error", i+1)
+			}
+		}
+	})
+
+	t.Run("Long", func(t *testing.T) {
+		c := NewCache(2)
+		long := "text/html;foo=" + strings.Repeat("x", MaxCachedHeaderLength) + ",*/*"
+		if _, err := c.Parse(long); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Len(); got != 0 {
+			t.Errorf("Len() = %d after parsing long header; want 0", got)
+		}
+	})
+}
+
+func TestCacheAllocs(t *testing.T) {
+	c := NewCache(8)
+	const accept = `text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8`
+	if _, err := c.Parse(accept); err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		c.Parse(accept)
+	})
+	if allocs > 0 {
+		t.Errorf("Parse allocated %.1f times per run on a hit; want 0", allocs)
+	}
+}
+
+func BenchmarkCache(b *testing.B) {
+	c := NewCache(8)
+	const accept = `text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8`
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Parse(accept); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
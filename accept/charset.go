@@ -0,0 +1,86 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A CharsetHeader represents a set of charsets
+// as sent in the Accept-Charset header of an HTTP request.
+// An empty CharsetHeader represents an absent or empty Accept-Charset header,
+// which means that any charset is acceptable.
+//
+// https://www.rfc-editor.org/rfc/rfc9110#section-12.5.2
+type CharsetHeader []Charset
+
+// A Charset is a charset and its weight
+// as sent in the Accept-Charset header of an HTTP request.
+type Charset struct {
+	// Charset is the lowercased name of the charset or "*".
+	Charset string
+	Quality float32
+}
+
+// ParseCharsetHeader parses an Accept-Charset header of an HTTP request.
+// The charsets are unsorted.
+func ParseCharsetHeader(acceptCharset string) (CharsetHeader, error) {
+	var h CharsetHeader
+	p := &parser{s: acceptCharset}
+	p.space()
+	for !p.eof() {
+		if len(h) > 0 {
+			if !p.consume(",") {
+				return nil, fmt.Errorf("parse accept-charset header: expected ',', found %s", p.first())
+			}
+			p.space()
+		}
+		if exceeds(len(h)+1, DefaultMaxRanges) {
+			return nil, fmt.Errorf("parse accept-charset header: more than %d charsets: %w", DefaultMaxRanges, ErrTooLarge)
+		}
+		charset := p.token()
+		if charset == "" {
+			return nil, fmt.Errorf("parse accept-charset header: expected token, found %s", p.first())
+		}
+		quality, err := parseParams(p, DefaultMaxParams)
+		if err != nil {
+			return nil, fmt.Errorf("parse accept-charset header: %w", err)
+		}
+		h = append(h, Charset{Charset: strings.ToLower(charset), Quality: quality})
+	}
+	return h, nil
+}
+
+// Quality returns the quality of a charset based on h.
+// A charset named explicitly takes precedence over "*".
+// If h is empty, then Quality returns 1.
+// Otherwise, charsets not matched by h have a quality of 0.
+func (h CharsetHeader) Quality(charset string) float32 {
+	if len(h) == 0 {
+		return 1
+	}
+	charset = strings.ToLower(charset)
+	wildcard := float32(0)
+	for _, c := range h {
+		switch c.Charset {
+		case charset:
+			return c.Quality
+		case "*":
+			wildcard = c.Quality
+		}
+	}
+	return wildcard
+}
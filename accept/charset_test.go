@@ -0,0 +1,45 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import "testing"
+
+func TestCharsetHeaderQuality(t *testing.T) {
+	tests := []struct {
+		accept  string
+		charset string
+		want    float32
+	}{
+		{accept: "", charset: "utf-8", want: 1},
+		{accept: "UTF-8", charset: "utf-8", want: 1},
+		{accept: "iso-8859-5, unicode-1-1;q=0.8", charset: "unicode-1-1", want: 0.8},
+		{accept: "iso-8859-5", charset: "utf-8", want: 0},
+		{accept: "iso-8859-5, *;q=0.5", charset: "utf-8", want: 0.5},
+		{accept: "*, utf-8;q=0", charset: "UTF-8", want: 0},
+	}
+	for _, test := range tests {
+		h, err := ParseCharsetHeader(test.accept)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got := h.Quality(test.charset); got != test.want {
+			t.Errorf("ParseCharsetHeader(%q).Quality(%q) = %g; want %g", test.accept, test.charset, got, test.want)
+		}
+	}
+	if _, err := ParseCharsetHeader("utf-8;q=bork"); err == nil {
+		t.Errorf("ParseCharsetHeader(%q) did not return an error", "utf-8;q=bork")
+	}
+}
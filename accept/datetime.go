@@ -0,0 +1,33 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ParseAcceptDatetime parses the Accept-Datetime header of an HTTP request,
+// which asks for the state of a resource as it was at the returned time.
+//
+// https://www.rfc-editor.org/rfc/rfc7089#section-2.1.1
+func ParseAcceptDatetime(acceptDatetime string) (time.Time, error) {
+	t, err := http.ParseTime(acceptDatetime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse accept-datetime header: invalid date %q", acceptDatetime)
+	}
+	return t, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAcceptDatetime(t *testing.T) {
+	tests := []struct {
+		acceptDatetime string
+		want           time.Time
+		wantErr        bool
+	}{
+		{
+			acceptDatetime: "Thu, 31 May 2007 20:35:00 GMT",
+			want:           time.Date(2007, time.May, 31, 20, 35, 0, 0, time.UTC),
+		},
+		{acceptDatetime: "", wantErr: true},
+		{acceptDatetime: "2007-05-31T20:35:00Z", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseAcceptDatetime(test.acceptDatetime)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("ParseAcceptDatetime(%q) = _, %v; want %v, <nil>", test.acceptDatetime, err, test.want)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("ParseAcceptDatetime(%q) = %v, <nil>; want error", test.acceptDatetime, got)
+			continue
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("ParseAcceptDatetime(%q) = %v; want %v", test.acceptDatetime, got, test.want)
+		}
+	}
+}
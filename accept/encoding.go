@@ -0,0 +1,143 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Identity is the content coding that represents no encoding.
+const Identity = "identity"
+
+// An EncodingHeader represents a set of content codings
+// as sent in the Accept-Encoding header of an HTTP request.
+// An empty EncodingHeader represents an absent or empty Accept-Encoding header,
+// which is treated as only accepting the identity coding.
+//
+// https://www.rfc-editor.org/rfc/rfc9110#section-12.5.3
+type EncodingHeader []Coding
+
+// A Coding is a content coding and its weight
+// as sent in the Accept-Encoding header of an HTTP request.
+type Coding struct {
+	// Coding is the lowercased name of the coding or "*".
+	Coding  string
+	Quality float32
+}
+
+// ParseEncodingHeader parses an Accept-Encoding header of an HTTP request.
+// The codings are unsorted.
+// The "x-gzip" and "x-compress" aliases are normalized
+// to "gzip" and "compress", respectively.
+func ParseEncodingHeader(acceptEncoding string) (EncodingHeader, error) {
+	var h EncodingHeader
+	p := &parser{s: acceptEncoding}
+	p.space()
+	for !p.eof() {
+		if len(h) > 0 {
+			if !p.consume(",") {
+				return nil, fmt.Errorf("parse accept-encoding header: expected ',', found %s", p.first())
+			}
+			p.space()
+		}
+		if exceeds(len(h)+1, DefaultMaxRanges) {
+			return nil, fmt.Errorf("parse accept-encoding header: more than %d codings: %w", DefaultMaxRanges, ErrTooLarge)
+		}
+		coding := p.token()
+		if coding == "" {
+			return nil, fmt.Errorf("parse accept-encoding header: expected token, found %s", p.first())
+		}
+		quality, err := parseParams(p, DefaultMaxParams)
+		if err != nil {
+			return nil, fmt.Errorf("parse accept-encoding header: %w", err)
+		}
+		h = append(h, Coding{Coding: normalizeCoding(coding), Quality: quality})
+	}
+	return h, nil
+}
+
+func normalizeCoding(coding string) string {
+	coding = strings.ToLower(coding)
+	switch coding {
+	case "x-gzip":
+		return "gzip"
+	case "x-compress":
+		return "compress"
+	default:
+		return coding
+	}
+}
+
+// Quality returns the quality of a content coding based on h.
+// A coding named explicitly takes precedence over "*".
+// The identity coding is acceptable with a quality of 1
+// unless it is excluded by "identity;q=0" or "*;q=0".
+// Other codings not matched by h have a quality of 0.
+func (h EncodingHeader) Quality(coding string) float32 {
+	coding = normalizeCoding(coding)
+	wildcard := float32(-1)
+	for _, c := range h {
+		switch c.Coding {
+		case coding:
+			return c.Quality
+		case "*":
+			wildcard = c.Quality
+		}
+	}
+	switch {
+	case wildcard >= 0:
+		return wildcard
+	case coding == Identity:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Negotiate returns the offered coding with the highest quality in h,
+// using the earliest offer in case of a tie.
+// Offers should be listed in order of server preference
+// and should include [Identity] if an unencoded response is possible.
+// If none of the offers are acceptable, then Negotiate returns the empty string,
+// in which case the server may either respond with 415 (Unsupported Media Type)
+// or send an unencoded response.
+func (h EncodingHeader) Negotiate(offered ...string) string {
+	best, bestQuality := "", float32(0)
+	for _, offer := range offered {
+		if q := h.Quality(offer); q > bestQuality {
+			best, bestQuality = offer, q
+		}
+	}
+	return best
+}
+
+// String formats the codings in the format for an Accept-Encoding header.
+func (h EncodingHeader) String() string {
+	parts := make([]string, len(h))
+	for i, c := range h {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// String formats the coding in the format for an Accept-Encoding header.
+func (c Coding) String() string {
+	if c.Quality == 1.0 {
+		return c.Coding
+	}
+	return c.Coding + ";q=" + strconv.FormatFloat(float64(c.Quality), 'f', 3, 32)
+}
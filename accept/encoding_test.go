@@ -0,0 +1,105 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseEncodingHeader(t *testing.T) {
+	tests := []struct {
+		accept  string
+		want    EncodingHeader
+		wantErr bool
+	}{
+		{accept: "", want: nil},
+		{accept: "gzip", want: EncodingHeader{{Coding: "gzip", Quality: 1}}},
+		{
+			accept: "GZIP, br;q=0.8, *;q=0",
+			want: EncodingHeader{
+				{Coding: "gzip", Quality: 1},
+				{Coding: "br", Quality: 0.8},
+				{Coding: "*", Quality: 0},
+			},
+		},
+		{accept: "x-gzip", want: EncodingHeader{{Coding: "gzip", Quality: 1}}},
+		{accept: "identity ; q=0.5", want: EncodingHeader{{Coding: "identity", Quality: 0.5}}},
+		{accept: "gzip;q=2", wantErr: true},
+		{accept: "gzip br", wantErr: true},
+		{accept: ",", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseEncodingHeader(test.accept)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("ParseEncodingHeader(%q) = _, %v; want %v, <nil>", test.accept, err, test.want)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("ParseEncodingHeader(%q) = %v, <nil>; want error", test.accept, got)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseEncodingHeader(%q) (-want +got):\n%s", test.accept, diff)
+		}
+	}
+}
+
+func TestEncodingHeaderNegotiate(t *testing.T) {
+	offers := []string{"br", "gzip", Identity}
+	tests := []struct {
+		accept string
+		offers []string
+		want   string
+	}{
+		{accept: "", offers: offers, want: Identity},
+		{accept: "", offers: []string{"gzip"}, want: ""},
+		{accept: "gzip", offers: offers, want: "gzip"},
+		{accept: "gzip, br", offers: offers, want: "br"},
+		{accept: "gzip, br;q=0.5", offers: offers, want: "gzip"},
+		{accept: "x-gzip", offers: offers, want: "gzip"},
+		{accept: "*", offers: offers, want: "br"},
+		{accept: "*;q=0.5, gzip", offers: offers, want: "gzip"},
+		{accept: "deflate", offers: offers, want: Identity},
+		{accept: "deflate, identity;q=0", offers: offers, want: ""},
+		{accept: "deflate, *;q=0", offers: offers, want: ""},
+		{accept: "*;q=0, identity", offers: offers, want: Identity},
+		{accept: "br;q=0, *", offers: offers, want: "gzip"},
+	}
+	for _, test := range tests {
+		h, err := ParseEncodingHeader(test.accept)
+		if err != nil {
+			t.Errorf("ParseEncodingHeader(%q): %v", test.accept, err)
+			continue
+		}
+		if got := h.Negotiate(test.offers...); got != test.want {
+			t.Errorf("ParseEncodingHeader(%q).Negotiate(%q...) = %q; want %q", test.accept, test.offers, got, test.want)
+		}
+	}
+}
+
+func TestEncodingHeaderString(t *testing.T) {
+	h := EncodingHeader{
+		{Coding: "gzip", Quality: 1},
+		{Coding: "*", Quality: 0.5},
+	}
+	const want = "gzip,*;q=0.500"
+	if got := h.String(); got != want {
+		t.Errorf("%#v.String() = %q; want %q", h, got, want)
+	}
+}
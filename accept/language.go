@@ -0,0 +1,112 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A LanguageHeader represents a set of language ranges
+// as sent in the Accept-Language header of an HTTP request.
+// An empty LanguageHeader represents an absent or empty Accept-Language header,
+// which means that any language is acceptable.
+//
+// https://www.rfc-editor.org/rfc/rfc9110#section-12.5.4
+type LanguageHeader []LanguageRange
+
+// A LanguageRange is a language range and its weight
+// as sent in the Accept-Language header of an HTTP request.
+type LanguageRange struct {
+	// Range is the lowercased language range, like "en-us", or "*".
+	Range   string
+	Quality float32
+}
+
+// ParseLanguageHeader parses an Accept-Language header of an HTTP request.
+// The language ranges are unsorted.
+func ParseLanguageHeader(acceptLanguage string) (LanguageHeader, error) {
+	var h LanguageHeader
+	p := &parser{s: acceptLanguage}
+	p.space()
+	for !p.eof() {
+		if len(h) > 0 {
+			if !p.consume(",") {
+				return nil, fmt.Errorf("parse accept-language header: expected ',', found %s", p.first())
+			}
+			p.space()
+		}
+		if exceeds(len(h)+1, DefaultMaxRanges) {
+			return nil, fmt.Errorf("parse accept-language header: more than %d language ranges: %w", DefaultMaxRanges, ErrTooLarge)
+		}
+		lang := p.token()
+		if lang == "" {
+			return nil, fmt.Errorf("parse accept-language header: expected language range, found %s", p.first())
+		}
+		quality, err := parseParams(p, DefaultMaxParams)
+		if err != nil {
+			return nil, fmt.Errorf("parse accept-language header: %w", err)
+		}
+		h = append(h, LanguageRange{Range: strings.ToLower(lang), Quality: quality})
+	}
+	return h, nil
+}
+
+// Quality returns the quality of a language tag (like "en-US") based on h,
+// using the basic filtering scheme in RFC 4647 Section 3.3.1:
+// a range matches a tag if it is equal to the tag
+// or is a prefix of the tag followed by "-".
+// The longest matching range takes precedence, and "*" matches any tag.
+// If h is empty, then Quality returns 1.
+func (h LanguageHeader) Quality(tag string) float32 {
+	if len(h) == 0 {
+		return 1
+	}
+	tag = strings.ToLower(tag)
+	best, bestLen := float32(0), -1
+	for _, lr := range h {
+		n := len(lr.Range)
+		switch {
+		case lr.Range == "*":
+			n = 0
+		case lr.Range == tag:
+		case strings.HasPrefix(tag, lr.Range) && tag[n] == '-':
+		default:
+			continue
+		}
+		if n > bestLen {
+			best, bestLen = lr.Quality, n
+		}
+	}
+	return best
+}
+
+// String formats the language ranges in the format for an Accept-Language header.
+func (h LanguageHeader) String() string {
+	parts := make([]string, len(h))
+	for i, lr := range h {
+		parts[i] = lr.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// String formats the language range in the format for an Accept-Language header.
+func (lr LanguageRange) String() string {
+	if lr.Quality == 1.0 {
+		return lr.Range
+	}
+	return lr.Range + ";q=" + strconv.FormatFloat(float64(lr.Quality), 'f', 3, 32)
+}
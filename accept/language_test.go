@@ -0,0 +1,86 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseLanguageHeader(t *testing.T) {
+	tests := []struct {
+		accept  string
+		want    LanguageHeader
+		wantErr bool
+	}{
+		{accept: "", want: nil},
+		{
+			accept: "en-US, en;q=0.8, *;q=0.1",
+			want: LanguageHeader{
+				{Range: "en-us", Quality: 1},
+				{Range: "en", Quality: 0.8},
+				{Range: "*", Quality: 0.1},
+			},
+		},
+		{accept: "fr;q=2", wantErr: true},
+		{accept: "fr de", wantErr: true},
+		{accept: ",", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseLanguageHeader(test.accept)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("ParseLanguageHeader(%q) = _, %v; want %v, <nil>", test.accept, err, test.want)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("ParseLanguageHeader(%q) = %v, <nil>; want error", test.accept, got)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseLanguageHeader(%q) (-want +got):\n%s", test.accept, diff)
+		}
+	}
+}
+
+func TestLanguageHeaderQuality(t *testing.T) {
+	tests := []struct {
+		accept string
+		tag    string
+		want   float32
+	}{
+		{accept: "", tag: "en", want: 1},
+		{accept: "en", tag: "en", want: 1},
+		{accept: "en", tag: "EN-us", want: 1},
+		{accept: "en", tag: "eng", want: 0},
+		{accept: "en-US", tag: "en", want: 0},
+		{accept: "en;q=0.5, en-GB", tag: "en-GB", want: 1},
+		{accept: "en;q=0.5, en-GB", tag: "en-US", want: 0.5},
+		{accept: "fr, *;q=0.1", tag: "de", want: 0.1},
+		{accept: "*;q=0.1, de;q=0", tag: "de-CH", want: 0},
+	}
+	for _, test := range tests {
+		h, err := ParseLanguageHeader(test.accept)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got := h.Quality(test.tag); got != test.want {
+			t.Errorf("ParseLanguageHeader(%q).Quality(%q) = %g; want %g", test.accept, test.tag, got, test.want)
+		}
+	}
+}
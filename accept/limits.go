@@ -0,0 +1,67 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"errors"
+)
+
+// Default limits used by [ParseHeader] and a zero [ParserOptions].
+// Real browsers send fewer than ten media ranges
+// with at most a couple of parameters each.
+const (
+	DefaultMaxRanges = 64
+	DefaultMaxParams = 16
+)
+
+// ErrTooLarge is returned (possibly wrapped)
+// when a header exceeds the limits in [ParserOptions].
+var ErrTooLarge = errors.New("header exceeds parser limits")
+
+// ParserOptions limits the resources used to parse a header,
+// so that a hostile client cannot force the server
+// to allocate memory in proportion to a very large header.
+// A nil *ParserOptions is equivalent to a zero ParserOptions.
+type ParserOptions struct {
+	// MaxRanges is the maximum number of media ranges in a header.
+	// If zero, DefaultMaxRanges is used.
+	// If negative, the number of media ranges is not limited.
+	MaxRanges int
+	// MaxParams is the maximum number of parameters in a media range,
+	// including the "q" weight and any extension parameters after it.
+	// If zero, DefaultMaxParams is used.
+	// If negative, the number of parameters is not limited.
+	MaxParams int
+}
+
+func (opts *ParserOptions) maxRanges() int {
+	if opts == nil || opts.MaxRanges == 0 {
+		return DefaultMaxRanges
+	}
+	return opts.MaxRanges
+}
+
+func (opts *ParserOptions) maxParams() int {
+	if opts == nil || opts.MaxParams == 0 {
+		return DefaultMaxParams
+	}
+	return opts.MaxParams
+}
+
+// exceeds reports whether n is over the given limit.
+// Negative limits are never exceeded.
+func exceeds(n, limit int) bool {
+	return limit >= 0 && n > limit
+}
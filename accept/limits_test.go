@@ -0,0 +1,90 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParserOptions(t *testing.T) {
+	manyRanges := strings.Repeat("text/html,", DefaultMaxRanges) + "*/*"
+	manyParams := "text/html"
+	for i := 0; i < DefaultMaxParams; i++ {
+		manyParams += ";p" + strconv.Itoa(i) + "=x"
+	}
+	manyParams += ";q=0.5"
+	tests := []struct {
+		name     string
+		opts     *ParserOptions
+		accept   string
+		tooLarge bool
+	}{
+		{
+			name:     "DefaultRanges",
+			accept:   manyRanges,
+			tooLarge: true,
+		},
+		{
+			name:     "DefaultParams",
+			accept:   manyParams,
+			tooLarge: true,
+		},
+		{
+			name:   "AtLimit",
+			opts:   &ParserOptions{MaxRanges: 2, MaxParams: 2},
+			accept: "text/html;level=1;q=0.5, */*",
+		},
+		{
+			name:     "OverRangeLimit",
+			opts:     &ParserOptions{MaxRanges: 2},
+			accept:   "text/html, text/plain, */*",
+			tooLarge: true,
+		},
+		{
+			name:     "OverParamLimit",
+			opts:     &ParserOptions{MaxParams: 1},
+			accept:   "text/html;level=1;q=0.5",
+			tooLarge: true,
+		},
+		{
+			name:   "Unlimited",
+			opts:   &ParserOptions{MaxRanges: -1, MaxParams: -1},
+			accept: manyRanges + "," + manyParams,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, parse := range []struct {
+				name string
+				f    func(string) (Header, error)
+			}{
+				{"ParseHeader", test.opts.ParseHeader},
+				{"ParseHeaderStrict", test.opts.ParseHeaderStrict},
+			} {
+				_, err := parse.f(test.accept)
+				if test.tooLarge {
+					if !errors.Is(err, ErrTooLarge) {
+						t.Errorf("%s(%q) error = %v; want %v", parse.name, test.accept, err, ErrTooLarge)
+					}
+				} else if err != nil {
+					t.Errorf("%s(%q): %v", parse.name, test.accept, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// A Negotiator selects among a fixed set of offered media types.
+// Creating a Negotiator does the work of parsing the offered media types once,
+// so that [*Negotiator.Best] can be called on every request
+// without building a [Header].
+// A Negotiator is safe to use from multiple goroutines.
+type Negotiator struct {
+	offers []negotiatorOffer
+}
+
+type negotiatorOffer struct {
+	contentType string
+	typ         string
+	subtype     string
+	params      map[string]string
+}
+
+// NewNegotiator returns a new [Negotiator] for the given content types,
+// listed in order of server preference.
+func NewNegotiator(offers ...string) (*Negotiator, error) {
+	n := &Negotiator{offers: make([]negotiatorOffer, 0, len(offers))}
+	for _, contentType := range offers {
+		mediaType, params, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, fmt.Errorf("new negotiator: %w", err)
+		}
+		typ, subtype := splitContentType(mediaType)
+		if typ == "" || subtype == "" || typ == "*" || strings.HasPrefix(subtype, "*") {
+			return nil, fmt.Errorf("new negotiator: %q is not a concrete media type", contentType)
+		}
+		n.offers = append(n.offers, negotiatorOffer{
+			contentType: contentType,
+			typ:         typ,
+			subtype:     subtype,
+			params:      params,
+		})
+	}
+	return n, nil
+}
+
+// Negotiate returns the offered content type
+// that [Header.Sort] would rank first for the given Accept header value.
+// If the header is empty, then Negotiate returns the first offer.
+// If none of the offers are acceptable, then Negotiate returns the empty string.
+// Negotiate returns an error if the header cannot be parsed
+// or an offer is not a concrete media type.
+//
+// Handlers that negotiate among the same offers on every request
+// should create a [Negotiator] once instead.
+func Negotiate(acceptHeader string, offered ...string) (string, error) {
+	n, err := NewNegotiator(offered...)
+	if err != nil {
+		return "", err
+	}
+	return n.Best(acceptHeader)
+}
+
+// negotiatorMaxOffers is the number of offers
+// that [*Negotiator.Best] can track without allocating.
+const negotiatorMaxOffers = 8
+
+// negotiatorMaxParams is the number of parameters per media range
+// that [*Negotiator.Best] can track without allocating.
+const negotiatorMaxParams = 4
+
+// Best returns the offered content type (as passed to [NewNegotiator])
+// that [Header.Sort] would rank first for the given Accept header value:
+// the offer with the highest quality,
+// then the most specific matching media range,
+// then the earliest offer.
+// If the header is empty, then Best returns the first offer.
+// If none of the offers are acceptable, then Best returns the empty string.
+func (n *Negotiator) Best(acceptHeader string) (string, error) {
+	if len(n.offers) == 0 {
+		return "", nil
+	}
+	if strings.TrimLeft(acceptHeader, " \t") == "" {
+		return n.offers[0].contentType, nil
+	}
+
+	type offerState struct {
+		match   mediaRangeMatch
+		quality float32
+	}
+	var stateArray [negotiatorMaxOffers]offerState
+	var states []offerState
+	if len(n.offers) <= len(stateArray) {
+		states = stateArray[:len(n.offers)]
+	} else {
+		states = make([]offerState, len(n.offers))
+	}
+	var paramArray [negotiatorMaxParams]rawParam
+	p := &parser{s: acceptHeader, input: acceptHeader}
+	p.space()
+	for nranges := 1; !p.eof(); nranges++ {
+		if nranges > 1 {
+			if !p.consume(",") {
+				return "", fmt.Errorf("parse accept header: expected ',', found %s", p.first())
+			}
+			p.space()
+		}
+		if exceeds(nranges, DefaultMaxRanges) {
+			return "", fmt.Errorf("parse accept header: more than %d media ranges: %w", DefaultMaxRanges, ErrTooLarge)
+		}
+		mr, err := p.mediaRange(paramArray[:0], DefaultMaxParams)
+		if err != nil {
+			return "", fmt.Errorf("parse accept header: %w", err)
+		}
+		for i := range n.offers {
+			m, ok := n.offers[i].match(mr.typ, mr.subtype, mr.params)
+			if !ok {
+				continue
+			}
+			if s := &states[i]; !s.match.Valid || m.moreSpecific(&s.match) {
+				s.match = m
+				s.quality = mr.quality
+			}
+		}
+	}
+
+	best := -1
+	var bestRank offerRank
+	for i := range states {
+		s := &states[i]
+		if !s.match.Valid {
+			continue
+		}
+		r := offerRank{quality: s.quality, specificity: s.match.specificity()}
+		if r.better(bestRank) {
+			best, bestRank = i, r
+		}
+	}
+	if best == -1 {
+		return "", nil
+	}
+	return n.offers[best].contentType, nil
+}
+
+func (offer *negotiatorOffer) match(typ, subtype string, params []rawParam) (mediaRangeMatch, bool) {
+	var m mediaRangeMatch
+	if typ != "*" {
+		if !strings.EqualFold(typ, offer.typ) {
+			return m, false
+		}
+		m.Type++
+	}
+	subtypeScore, ok := matchSubtype(subtype, offer.subtype)
+	if !ok {
+		return m, false
+	}
+	m.Subtype = subtypeScore
+	for _, param := range params {
+		if param.ext {
+			// Parameters after the weight are extensions, not media type parameters.
+			continue
+		}
+		v, ok := offer.params[param.key]
+		if !ok || !paramValueEqual(param.key, v, param.value) {
+			return m, false
+		}
+		m.Params++
+	}
+	m.Valid = true
+	return m, true
+}
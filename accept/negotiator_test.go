@@ -0,0 +1,141 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import "testing"
+
+func TestNegotiator(t *testing.T) {
+	offers := []string{
+		"text/html; charset=utf-8",
+		"application/json",
+		"text/plain; charset=utf-8",
+	}
+	tests := []struct {
+		accept  string
+		want    string
+		wantErr bool
+	}{
+		{accept: "", want: "text/html; charset=utf-8"},
+		{accept: "*/*", want: "text/html; charset=utf-8"},
+		{accept: "application/json", want: "application/json"},
+		{accept: "APPLICATION/JSON", want: "application/json"},
+		{accept: "text/html;q=0.5, text/plain", want: "text/plain; charset=utf-8"},
+		{accept: "text/*;q=0.3, application/json;q=0.2", want: "text/html; charset=utf-8"},
+		{accept: "text/*, text/html;q=0", want: "text/plain; charset=utf-8"},
+		{accept: `text/plain; charset="utf-8", */*;q=0.1`, want: "text/plain; charset=utf-8"},
+		{accept: "text/plain; charset=latin1", want: ""},
+		{accept: "Text/Plain; Charset=UTF-8", want: "text/plain; charset=utf-8"},
+		{accept: "TEXT/*;Q=0.3, Application/JSON;Q=0.2", want: "text/html; charset=utf-8"},
+		{accept: "text/plain; q=0.5; charset=latin1", want: "text/plain; charset=utf-8"},
+		{accept: "image/png", want: ""},
+		{accept: "*/*, text/*, text/plain", want: "text/plain; charset=utf-8"},
+		{accept: "text/*, application/json", want: "application/json"},
+		{accept: "application/*+json", want: ""},
+		{accept: "text/html;q=2", wantErr: true},
+		{accept: "foo/)bar", wantErr: true},
+	}
+	n, err := NewNegotiator(offers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		got, err := n.Best(test.accept)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("Best(%q) = _, %v; want %q, <nil>", test.accept, err, test.want)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("Best(%q) = %q, <nil>; want error", test.accept, got)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Best(%q) = %q; want %q", test.accept, got, test.want)
+		}
+	}
+}
+
+func TestNewNegotiatorErrors(t *testing.T) {
+	for _, offer := range []string{"", "text", "text/*", "*/*", "application/*+json"} {
+		if _, err := NewNegotiator(offer); err == nil {
+			t.Errorf("NewNegotiator(%q) did not return an error", offer)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept  string
+		offers  []string
+		want    string
+		wantErr bool
+	}{
+		{accept: "", offers: []string{"text/html", "application/json"}, want: "text/html"},
+		{accept: "application/json", offers: []string{"text/html", "application/json"}, want: "application/json"},
+		{accept: "image/png", offers: []string{"text/html", "application/json"}, want: ""},
+		{accept: "text/html", offers: nil, want: ""},
+		{accept: "application/*+json", offers: []string{"text/html", "application/vnd.api+json"}, want: "application/vnd.api+json"},
+		{accept: "application/*+json;q=0.5, application/problem+json", offers: []string{"application/vnd.api+json", "application/problem+json"}, want: "application/problem+json"},
+		{accept: "text/html;q=2", offers: []string{"text/html"}, wantErr: true},
+		{accept: "text/html", offers: []string{"text/*"}, wantErr: true},
+		{accept: "text/html;level=1;level=2", offers: []string{"text/html"}, wantErr: true},
+		{accept: "text/html;q=0.5;level=1", offers: []string{"text/html;level=2"}, want: "text/html;level=2"},
+	}
+	for _, test := range tests {
+		got, err := Negotiate(test.accept, test.offers...)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("Negotiate(%q, %q...) = _, %v; want %q, <nil>", test.accept, test.offers, err, test.want)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("Negotiate(%q, %q...) = %q, <nil>; want error", test.accept, test.offers, got)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Negotiate(%q, %q...) = %q; want %q", test.accept, test.offers, got, test.want)
+		}
+	}
+}
+
+func TestNegotiatorAllocs(t *testing.T) {
+	n, err := NewNegotiator("text/vnd.turbo-stream.html; charset=utf-8", "text/html; charset=utf-8", "application/json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const accept = `text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8`
+	allocs := testing.AllocsPerRun(100, func() {
+		n.Best(accept)
+	})
+	if allocs > 0 {
+		t.Errorf("Best allocated %.1f times per run; want 0", allocs)
+	}
+}
+
+func BenchmarkNegotiator(b *testing.B) {
+	n, err := NewNegotiator("text/vnd.turbo-stream.html; charset=utf-8", "text/html; charset=utf-8", "application/json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	const accept = `text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8`
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := n.Best(accept); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
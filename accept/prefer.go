@@ -0,0 +1,198 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values of the "return" preference.
+const (
+	ReturnMinimal        = "minimal"
+	ReturnRepresentation = "representation"
+)
+
+// A PreferHeader represents the preferences
+// sent in the Prefer header of an HTTP request.
+// Servers should ignore preferences they do not understand.
+//
+// https://www.rfc-editor.org/rfc/rfc7240
+type PreferHeader []Preference
+
+// A Preference is a single preference
+// as sent in the Prefer header of an HTTP request.
+type Preference struct {
+	// Name is the lowercased name of the preference.
+	Name string
+	// Value is the preference's value or the empty string if it has none.
+	Value string
+	// Params maps lowercased parameter names to their values.
+	Params map[string]string
+}
+
+// ParsePreferHeader parses a Prefer header of an HTTP request.
+// Multiple Prefer header fields should be joined with commas
+// before being passed to ParsePreferHeader.
+func ParsePreferHeader(prefer string) (PreferHeader, error) {
+	var h PreferHeader
+	p := &parser{s: prefer}
+	p.space()
+	for !p.eof() {
+		if len(h) > 0 {
+			if !p.consume(",") {
+				return nil, fmt.Errorf("parse prefer header: expected ',', found %s", p.first())
+			}
+			p.space()
+		}
+		if exceeds(len(h)+1, DefaultMaxRanges) {
+			return nil, fmt.Errorf("parse prefer header: more than %d preferences: %w", DefaultMaxRanges, ErrTooLarge)
+		}
+		name, value, err := parsePreferenceParam(p)
+		if err != nil {
+			return nil, fmt.Errorf("parse prefer header: %w", err)
+		}
+		pref := Preference{Name: name, Value: value}
+		for n := 1; p.consume(";"); n++ {
+			if exceeds(n, DefaultMaxParams) {
+				return nil, fmt.Errorf("parse prefer header: more than %d parameters: %w", DefaultMaxParams, ErrTooLarge)
+			}
+			p.space()
+			if p.eof() || p.peek() == ',' || p.peek() == ';' {
+				// Empty parameter.
+				continue
+			}
+			key, value, err := parsePreferenceParam(p)
+			if err != nil {
+				return nil, fmt.Errorf("parse prefer header: %w", err)
+			}
+			if pref.Params == nil {
+				pref.Params = make(map[string]string)
+			}
+			if _, dupe := pref.Params[key]; !dupe {
+				pref.Params[key] = value
+			}
+		}
+		h = append(h, pref)
+	}
+	return h, nil
+}
+
+// parsePreferenceParam parses a token with an optional value,
+// along with any trailing whitespace.
+func parsePreferenceParam(p *parser) (name, value string, err error) {
+	name = strings.ToLower(p.token())
+	if name == "" {
+		return "", "", fmt.Errorf("expected token, found %s", p.first())
+	}
+	p.space()
+	if !p.consume("=") {
+		return name, "", nil
+	}
+	p.space()
+	if s, err := p.quotedString(); errors.Is(err, errNotQuotedString) {
+		value = p.token()
+		if value == "" {
+			return "", "", fmt.Errorf("expected value for %s, found %s", name, p.first())
+		}
+	} else if err != nil {
+		return "", "", err
+	} else {
+		value = s
+	}
+	p.space()
+	return name, value, nil
+}
+
+// Get returns the first preference in h with the given name.
+// Per RFC 7240, later instances of the same preference are ignored.
+func (h PreferHeader) Get(name string) (Preference, bool) {
+	for _, pref := range h {
+		if strings.EqualFold(pref.Name, name) {
+			return pref, true
+		}
+	}
+	return Preference{}, false
+}
+
+// Return returns the value of the "return" preference,
+// either [ReturnMinimal] or [ReturnRepresentation].
+// If the preference is absent or has any other value,
+// Return returns the empty string.
+func (h PreferHeader) Return() string {
+	pref, ok := h.Get("return")
+	if !ok {
+		return ""
+	}
+	switch v := strings.ToLower(pref.Value); v {
+	case ReturnMinimal, ReturnRepresentation:
+		return v
+	default:
+		return ""
+	}
+}
+
+// Wait returns the duration given by the "wait" preference,
+// which is how long the client is willing to wait for a response.
+// ok is false if the preference is absent or is not
+// a non-negative number of seconds.
+func (h PreferHeader) Wait() (_ time.Duration, ok bool) {
+	pref, ok := h.Get("wait")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(pref.Value, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(n) * time.Second, true
+}
+
+// String formats the preferences in the format for a Prefer header.
+func (h PreferHeader) String() string {
+	parts := make([]string, len(h))
+	for i, pref := range h {
+		parts[i] = pref.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// String formats the preference in the format for a Prefer
+// or Preference-Applied header.
+// Parameters are sorted by name.
+func (pref Preference) String() string {
+	parts := make([]string, 0, len(pref.Params)+1)
+	parts = append(parts, formatPreferenceParam(pref.Name, pref.Value))
+	keys := make([]string, 0, len(pref.Params))
+	for k := range pref.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, formatPreferenceParam(k, pref.Params[k]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func formatPreferenceParam(name, value string) string {
+	if value == "" {
+		return name
+	}
+	return name + "=" + quoteHTTP(value)
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePreferHeader(t *testing.T) {
+	tests := []struct {
+		prefer  string
+		want    PreferHeader
+		wantErr bool
+	}{
+		{prefer: "", want: nil},
+		{
+			prefer: "return=minimal",
+			want:   PreferHeader{{Name: "return", Value: "minimal"}},
+		},
+		{
+			prefer: `respond-async, Wait=100, handling="lenient"`,
+			want: PreferHeader{
+				{Name: "respond-async"},
+				{Name: "wait", Value: "100"},
+				{Name: "handling", Value: "lenient"},
+			},
+		},
+		{
+			prefer: "foo; bar=baz;; Qux",
+			want: PreferHeader{
+				{Name: "foo", Params: map[string]string{"bar": "baz", "qux": ""}},
+			},
+		},
+		{prefer: "return=", wantErr: true},
+		{prefer: "return=minimal wait=5", wantErr: true},
+		{prefer: ",", wantErr: true},
+		{prefer: `foo="bar`, wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParsePreferHeader(test.prefer)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("ParsePreferHeader(%q) = _, %v; want %v, <nil>", test.prefer, err, test.want)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("ParsePreferHeader(%q) = %v, <nil>; want error", test.prefer, got)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParsePreferHeader(%q) (-want +got):\n%s", test.prefer, diff)
+		}
+	}
+}
+
+func TestPreferHeaderReturn(t *testing.T) {
+	tests := []struct {
+		prefer string
+		want   string
+	}{
+		{prefer: "", want: ""},
+		{prefer: "return=minimal", want: ReturnMinimal},
+		{prefer: "return=Representation", want: ReturnRepresentation},
+		{prefer: "return=representation, return=minimal", want: ReturnRepresentation},
+		{prefer: "return=everything", want: ""},
+		{prefer: "wait=10", want: ""},
+	}
+	for _, test := range tests {
+		h, err := ParsePreferHeader(test.prefer)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got := h.Return(); got != test.want {
+			t.Errorf("ParsePreferHeader(%q).Return() = %q; want %q", test.prefer, got, test.want)
+		}
+	}
+}
+
+func TestPreferHeaderWait(t *testing.T) {
+	tests := []struct {
+		prefer string
+		want   time.Duration
+		wantOK bool
+	}{
+		{prefer: "", wantOK: false},
+		{prefer: "wait=10", want: 10 * time.Second, wantOK: true},
+		{prefer: "wait=0", want: 0, wantOK: true},
+		{prefer: "wait=-1", wantOK: false},
+		{prefer: "wait=1.5", wantOK: false},
+		{prefer: "wait", wantOK: false},
+	}
+	for _, test := range tests {
+		h, err := ParsePreferHeader(test.prefer)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		got, ok := h.Wait()
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("ParsePreferHeader(%q).Wait() = %v, %t; want %v, %t", test.prefer, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestPreferHeaderString(t *testing.T) {
+	h := PreferHeader{
+		{Name: "return", Value: "minimal"},
+		{Name: "foo", Value: "a b", Params: map[string]string{"z": "", "a": "1"}},
+	}
+	const want = `return=minimal, foo="a b"; a=1; z`
+	if got := h.String(); got != want {
+		t.Errorf("h.String() = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"net/http"
+	"strings"
+)
+
+// A Decision is the result of [NegotiateRequest].
+type Decision struct {
+	// Offer is the chosen offer, with its Quality set to the combined quality
+	// of its media type, language, and charset.
+	// If no offer is acceptable, Offer is the zero value.
+	Offer Offer
+	// Index is the position of Offer in the offers passed to NegotiateRequest
+	// or -1 if no offer is acceptable.
+	Index int
+	// Vary holds the request headers that were consulted.
+	// Use [Vary.Set] to add them to the response's Vary header.
+	Vary Vary
+}
+
+// Acceptable reports whether an offer was chosen.
+func (d *Decision) Acceptable() bool {
+	return d.Index >= 0
+}
+
+// NegotiateRequest chooses the offer that best satisfies
+// r's Accept, Accept-Language, and Accept-Charset headers together.
+// Each offer's quality is the product of its qualities
+// according to [Header.Quality], [LanguageHeader.Quality],
+// and [CharsetHeader.Quality] (using the offer's "charset" parameter).
+// Offers are ranked as by [Header.Sort]:
+// the offer with the highest quality is chosen,
+// then the one whose media type matched the most specific media range,
+// then the earliest offer,
+// so offers should be listed in order of server preference.
+//
+// Accept-Language is only consulted if an offer has a Language,
+// and Accept-Charset is only consulted if an offer has a charset.
+// NegotiateRequest returns an error if any consulted header is malformed.
+func NegotiateRequest(r *http.Request, offers []Offer) (*Decision, error) {
+	d := &Decision{Index: -1}
+	if len(offers) == 0 {
+		return d, nil
+	}
+	hasLanguage, hasCharset := false, false
+	for _, o := range offers {
+		hasLanguage = hasLanguage || o.Language != ""
+		_, ok := lookupParam(o.Params, "charset")
+		hasCharset = hasCharset || ok
+	}
+
+	accept, err := ParseHeader(headerValue(&d.Vary, r.Header, "Accept"))
+	if err != nil {
+		return nil, err
+	}
+	var languages LanguageHeader
+	if hasLanguage {
+		languages, err = ParseLanguageHeader(headerValue(&d.Vary, r.Header, "Accept-Language"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	var charsets CharsetHeader
+	if hasCharset {
+		charsets, err = ParseCharsetHeader(headerValue(&d.Vary, r.Header, "Accept-Charset"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var bestRank offerRank
+	for i, o := range offers {
+		r := accept.rank(o.ContentType, o.Params)
+		if o.Language != "" {
+			r.quality *= languages.Quality(o.Language)
+		}
+		if charset, ok := lookupParam(o.Params, "charset"); ok {
+			r.quality *= charsets.Quality(charset)
+		}
+		if r.better(bestRank) {
+			d.Offer, d.Index, bestRank = o, i, r
+		}
+	}
+	d.Offer.Quality = bestRank.quality
+	return d, nil
+}
+
+// headerValue records that the named header was consulted
+// and returns all of its values joined into a single list.
+func headerValue(v *Vary, h http.Header, name string) string {
+	v.Add(name)
+	return strings.Join(h.Values(name), ",")
+}
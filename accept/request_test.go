@@ -0,0 +1,123 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateRequest(t *testing.T) {
+	offers := []Offer{
+		{ContentType: "text/html", Language: "en"},
+		{ContentType: "text/html", Language: "fr"},
+		{ContentType: "application/json"},
+	}
+	tests := []struct {
+		name      string
+		header    http.Header
+		offers    []Offer
+		wantIndex int
+		wantQ     float32
+		wantVary  string
+	}{
+		{
+			name:      "NoHeaders",
+			offers:    offers,
+			wantIndex: 0,
+			wantQ:     1,
+			wantVary:  "Accept, Accept-Language",
+		},
+		{
+			name: "Language",
+			header: http.Header{
+				"Accept":          {"text/html"},
+				"Accept-Language": {"fr-CA, fr;q=0.9, en;q=0.5"},
+			},
+			offers:    offers,
+			wantIndex: 1,
+			wantQ:     0.9,
+			wantVary:  "Accept, Accept-Language",
+		},
+		{
+			name: "Joint",
+			header: http.Header{
+				"Accept":          {"text/html;q=0.5, application/json"},
+				"Accept-Language": {"de, fr;q=0.8"},
+			},
+			offers:    offers,
+			wantIndex: 2,
+			wantQ:     1,
+			wantVary:  "Accept, Accept-Language",
+		},
+		{
+			name: "Charset",
+			header: http.Header{
+				"Accept-Charset": {"iso-8859-1, utf-8;q=0.5"},
+			},
+			offers: []Offer{
+				{ContentType: "text/plain", Params: map[string]string{"charset": "utf-8"}},
+				{ContentType: "text/plain", Params: map[string]string{"charset": "ISO-8859-1"}},
+			},
+			wantIndex: 1,
+			wantQ:     1,
+			wantVary:  "Accept, Accept-Charset",
+		},
+		{
+			name: "NoneAcceptable",
+			header: http.Header{
+				"Accept":          {"text/html"},
+				"Accept-Language": {"de"},
+			},
+			offers:    offers,
+			wantIndex: -1,
+			wantVary:  "Accept, Accept-Language",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header = test.header
+			if r.Header == nil {
+				r.Header = make(http.Header)
+			}
+			d, err := NegotiateRequest(r, test.offers)
+			if err != nil {
+				t.Fatal("NegotiateRequest:", err)
+			}
+			if d.Index != test.wantIndex {
+				t.Errorf("Index = %d; want %d", d.Index, test.wantIndex)
+			}
+			if got, want := d.Acceptable(), test.wantIndex >= 0; got != want {
+				t.Errorf("Acceptable() = %t; want %t", got, want)
+			}
+			if d.Offer.Quality != test.wantQ {
+				t.Errorf("Offer.Quality = %g; want %g", d.Offer.Quality, test.wantQ)
+			}
+			if got := d.Vary.String(); got != test.wantVary {
+				t.Errorf("Vary = %q; want %q", got, test.wantVary)
+			}
+		})
+	}
+
+	t.Run("Malformed", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Language", "en;q=bork")
+		if _, err := NegotiateRequest(r, offers); err == nil {
+			t.Error("NegotiateRequest did not return an error")
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Require returns a handler that calls handler
+// only if the request's Accept header permits
+// at least one of the offered content types.
+// Otherwise, it responds with 406 (Not Acceptable)
+// and a plain text body listing the offered content types.
+// Requests with a malformed Accept header
+// receive a 400 (Bad Request) response.
+//
+// Require panics if any of the offers is not a concrete media type
+// (see [NewNegotiator]).
+func Require(handler http.Handler, offered ...string) http.Handler {
+	n, err := NewNegotiator(offered...)
+	if err != nil {
+		panic("accept.Require: " + err.Error())
+	}
+	sb := new(strings.Builder)
+	sb.WriteString("None of the available content types are acceptable:\n")
+	for _, offer := range offered {
+		sb.WriteString(offer)
+		sb.WriteString("\n")
+	}
+	body := sb.String()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		best, err := n.Best(r.Header.Get("Accept"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if best == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.WriteHeader(http.StatusNotAcceptable)
+			if r.Method != http.MethodHead {
+				w.Write([]byte(body))
+			}
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
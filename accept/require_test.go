@@ -0,0 +1,67 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	h := Require(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}), "text/html; charset=utf-8", "application/json")
+
+	tests := []struct {
+		accept     string
+		wantStatus int
+		wantBody   string
+	}{
+		{accept: "", wantStatus: http.StatusOK, wantBody: "ok"},
+		{accept: "application/json", wantStatus: http.StatusOK, wantBody: "ok"},
+		{accept: "text/*;q=0.5", wantStatus: http.StatusOK, wantBody: "ok"},
+		{
+			accept:     "image/png, text/html;q=0",
+			wantStatus: http.StatusNotAcceptable,
+			wantBody:   "None of the available content types are acceptable:\ntext/html; charset=utf-8\napplication/json\n",
+		},
+		{accept: "text/html;q=2", wantStatus: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != test.wantStatus {
+			t.Errorf("Accept: %s: status = %d; want %d", test.accept, rec.Code, test.wantStatus)
+		}
+		if test.wantBody != "" && rec.Body.String() != test.wantBody {
+			t.Errorf("Accept: %s: body = %q; want %q", test.accept, rec.Body, test.wantBody)
+		}
+	}
+}
+
+func TestRequirePanicsOnInvalidOffer(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Require did not panic")
+		}
+	}()
+	Require(http.NotFoundHandler(), "text/*")
+}
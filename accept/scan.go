@@ -0,0 +1,231 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A rawMediaRange is an element of an Accept header
+// as scanned by [*parser.mediaRange],
+// before its weight and parameters have been interpreted.
+// Strings are slices of the input
+// except for lowercased names and quoted values with escapes,
+// so scanning a header usually does not allocate.
+type rawMediaRange struct {
+	// offset is the byte offset of the media range in the input.
+	offset int
+	// end is the byte offset in the input just past the subtype.
+	// It is an offset rather than a string
+	// so that converting a rawMediaRange does not cause
+	// the params buffer to escape.
+	end     int
+	typ     string
+	subtype string
+	rawParams
+}
+
+// rawParams is the parameter list of a header element
+// as scanned by [*parser.params].
+type rawParams struct {
+	// params holds the parameters in the order they appear,
+	// including any that follow the weight.
+	params []rawParam
+	// weight is the "q" parameter, if hasWeight is true.
+	weight    rawParam
+	hasWeight bool
+	// quality is the weight's value, or 1 if there is no weight.
+	// It is parsed during scanning rather than by the caller
+	// so that reading it does not cause the params buffer to escape.
+	quality float32
+}
+
+// A rawParam is a media range parameter.
+type rawParam struct {
+	// key is the lowercased parameter name.
+	key   string
+	value string
+	// keyOffset and valueOffset are the byte offsets
+	// of the name and value in the input.
+	keyOffset   int
+	valueOffset int
+	// spaced is true if whitespace surrounds the '=',
+	// which RFC 9110 does not permit.
+	spaced bool
+	// quoted is true if the value is a quoted-string.
+	quoted bool
+	// badChar is the byte offset in the input of the first character
+	// in a quoted value that RFC 9110 does not permit, or -1.
+	badChar int
+	// ext is true if the parameter follows the weight.
+	// RFC 7231 called these accept-ext parameters.
+	// RFC 9110 removed them from the grammar.
+	ext bool
+}
+
+// A scanError is a syntax error found by [*parser.mediaRange].
+type scanError struct {
+	offset   int
+	msg      string
+	tooLarge bool
+}
+
+func (e *scanError) Error() string {
+	if e.tooLarge {
+		return e.msg + ": " + ErrTooLarge.Error()
+	}
+	return e.msg
+}
+
+func (e *scanError) Unwrap() error {
+	if e.tooLarge {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+// offset returns the byte offset of the parser's position in p.input.
+func (p *parser) offset() int {
+	return len(p.input) - len(p.s)
+}
+
+// mediaType returns the type and subtype of raw as written in input,
+// like "text/html".
+func (raw *rawMediaRange) mediaType(input string) string {
+	return input[raw.offset:raw.end]
+}
+
+func (p *parser) scanErrorf(offset int, format string, args ...any) *scanError {
+	return &scanError{offset: offset, msg: fmt.Sprintf(format, args...)}
+}
+
+// mediaRange scans a media range and its parameters
+// as defined in RFC 9110 Section 12.5.1,
+// appending the parameters to params.
+// It is shared by every parser of the Accept header
+// so that they agree on tokens, quoting, whitespace,
+// and the handling of parameters after the weight.
+// Whitespace is permitted around '=' in parameters
+// and empty parameters are skipped;
+// the returned rawMediaRange records enough detail
+// for [ParseHeaderStrict] to reject what RFC 9110 does not allow.
+// The media range must be followed by the end of input or a ','.
+func (p *parser) mediaRange(params []rawParam, maxParams int) (rawMediaRange, error) {
+	mr := rawMediaRange{offset: p.offset()}
+	mr.typ = p.token()
+	if mr.typ == "" {
+		return mr, p.scanErrorf(p.offset(), "expected media type, found %s", p.first())
+	}
+	if !p.consume("/") {
+		return mr, p.scanErrorf(p.offset(), "expected '/', found %s", p.first())
+	}
+	mr.subtype = p.token()
+	if mr.subtype == "" {
+		return mr, p.scanErrorf(p.offset(), "expected subtype, found %s", p.first())
+	}
+	mr.end = p.offset()
+	var err error
+	mr.rawParams, err = p.params(params, maxParams)
+	if err != nil {
+		return mr, err
+	}
+	if !p.eof() && p.peek() != ',' {
+		return mr, p.scanErrorf(p.offset(), "expected ',' or ';', found %s", p.first())
+	}
+	return mr, nil
+}
+
+// params scans a list of parameters, each preceded by ';',
+// appending them to params.
+// The first "q" parameter is the weight
+// and the parameters after it are marked as extensions.
+// A name may not repeat among the parameters
+// or among the extensions.
+func (p *parser) params(params []rawParam, maxParams int) (rawParams, error) {
+	list := rawParams{params: params, quality: 1.0}
+	nparams := 0
+	p.space()
+	for p.consume(";") {
+		p.space()
+		if p.eof() || p.peek() == ',' || p.peek() == ';' {
+			// RFC 9110 permits empty parameters.
+			continue
+		}
+		if nparams++; exceeds(nparams, maxParams) {
+			err := p.scanErrorf(p.offset(), "more than %d parameters", maxParams)
+			err.tooLarge = true
+			return list, err
+		}
+		param, err := p.param()
+		if err != nil {
+			return list, err
+		}
+		switch {
+		case param.key == "q" && !list.hasWeight:
+			// Any decimal number from 0 to 1 is accepted here.
+			// ParseHeaderStrict checks the qvalue syntax afterward.
+			q, err := strconv.ParseFloat(param.value, 32)
+			if err != nil || q < 0 || 1 < q {
+				return list, p.scanErrorf(param.valueOffset, "invalid q value %q", param.value)
+			}
+			list.weight = param
+			list.hasWeight = true
+			list.quality = float32(q)
+		case param.key == "q":
+			return list, p.scanErrorf(param.keyOffset, "duplicate weight")
+		default:
+			param.ext = list.hasWeight
+			for _, prev := range list.params[len(params):] {
+				if prev.key == param.key && prev.ext == param.ext {
+					return list, p.scanErrorf(param.keyOffset, "duplicate parameter %q", param.key)
+				}
+			}
+			list.params = append(list.params, param)
+		}
+		p.space()
+	}
+	return list, nil
+}
+
+// param scans a single media range parameter.
+func (p *parser) param() (rawParam, error) {
+	param := rawParam{keyOffset: p.offset(), badChar: -1}
+	param.key = strings.ToLower(p.token())
+	if param.key == "" {
+		return param, p.scanErrorf(param.keyOffset, "expected parameter name, found %s", p.first())
+	}
+	param.spaced = p.space() != ""
+	if !p.consume("=") {
+		return param, p.scanErrorf(p.offset(), "expected '=', found %s", p.first())
+	}
+	param.spaced = p.space() != "" || param.spaced
+	param.valueOffset = p.offset()
+	if p.peek() != '"' {
+		param.value = p.token()
+		return param, nil
+	}
+	param.quoted = true
+	value, bad, err := p.checkedQuotedString()
+	if err != nil {
+		return param, p.scanErrorf(param.valueOffset, "unterminated quoted string")
+	}
+	param.value = value
+	if bad >= 0 {
+		param.badChar = param.valueOffset + bad
+	}
+	return param, nil
+}
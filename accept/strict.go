@@ -0,0 +1,158 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A SyntaxError is returned by [ParseHeaderStrict]
+// to describe a malformed Accept header.
+type SyntaxError struct {
+	// Offset is the byte offset in the header where the problem was found.
+	Offset int
+	msg    string
+	err    error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("parse accept header: offset %d: %s", e.Offset, e.msg)
+}
+
+// Unwrap returns [ErrTooLarge] if the header exceeded the parser limits
+// or nil otherwise.
+func (e *SyntaxError) Unwrap() error {
+	return e.err
+}
+
+// ParseHeaderStrict parses an Accept header of an HTTP request
+// like [ParseHeader], but only accepts headers
+// that follow the grammar in RFC 9110 Section 12.5.1.
+// In particular, it rejects:
+//
+//   - empty list elements
+//   - media ranges like "*/html"
+//   - whitespace around '=' in parameters
+//   - parameters after the "q" weight
+//   - weights not written as a qvalue, like "0.5000" or ".5"
+//   - control characters in quoted strings
+//
+// Errors returned by ParseHeaderStrict are of type [*SyntaxError].
+// ParseHeaderStrict uses the default limits of a zero [ParserOptions].
+func ParseHeaderStrict(accept string) (Header, error) {
+	return (*ParserOptions)(nil).ParseHeaderStrict(accept)
+}
+
+// ParseHeaderStrict parses an Accept header of an HTTP request
+// like [ParseHeaderStrict], using the limits in opts.
+// If the header exceeds the limits,
+// the returned [*SyntaxError] wraps [ErrTooLarge].
+func (opts *ParserOptions) ParseHeaderStrict(accept string) (Header, error) {
+	p := &parser{s: accept, input: accept}
+	maxRanges, maxParams := opts.maxRanges(), opts.maxParams()
+	var h Header
+	p.space()
+	for !p.eof() {
+		if len(h) > 0 {
+			if !p.consume(",") {
+				return nil, syntaxErrorf(p.offset(), "expected ',', found %s", p.first())
+			}
+			p.space()
+			if p.eof() || p.peek() == ',' {
+				return nil, syntaxErrorf(p.offset(), "empty list element")
+			}
+		}
+		if exceeds(len(h)+1, maxRanges) {
+			return nil, &SyntaxError{
+				Offset: p.offset(),
+				msg:    fmt.Sprintf("more than %d media ranges", maxRanges),
+				err:    ErrTooLarge,
+			}
+		}
+		raw, err := p.mediaRange(nil, maxParams)
+		if err != nil {
+			scanErr := err.(*scanError)
+			return nil, &SyntaxError{Offset: scanErr.offset, msg: scanErr.msg, err: scanErr.Unwrap()}
+		}
+		mr, err := strictMediaRange(&raw, accept)
+		if err != nil {
+			return nil, err
+		}
+		h = append(h, mr)
+	}
+	return h, nil
+}
+
+func syntaxErrorf(offset int, format string, args ...any) error {
+	return &SyntaxError{Offset: offset, msg: fmt.Sprintf(format, args...)}
+}
+
+// strictMediaRange converts a scanned media range to a [MediaRange],
+// returning an error for anything the RFC 9110 grammar does not allow.
+func strictMediaRange(raw *rawMediaRange, input string) (MediaRange, error) {
+	if raw.typ == "*" && raw.subtype != "*" {
+		return MediaRange{}, syntaxErrorf(raw.offset, "media range %q has wildcard type but not wildcard subtype", raw.mediaType(input))
+	}
+	mr := MediaRange{
+		Range:   strings.ToLower(raw.mediaType(input)),
+		Quality: raw.quality,
+		Params:  make(map[string]string),
+	}
+	params := raw.params
+	if raw.hasWeight {
+		params = append(params[:len(params):len(params)], raw.weight)
+	}
+	for _, param := range params {
+		switch {
+		case param.ext:
+			return MediaRange{}, syntaxErrorf(param.keyOffset, "parameter after weight")
+		case param.spaced:
+			return MediaRange{}, syntaxErrorf(param.keyOffset+len(param.key), "whitespace around '=' in parameter")
+		case param.badChar >= 0:
+			return MediaRange{}, syntaxErrorf(param.badChar, "invalid character in quoted string")
+		case !param.quoted && param.value == "":
+			return MediaRange{}, syntaxErrorf(param.valueOffset, "expected parameter value")
+		}
+		if param.key == "q" {
+			continue
+		}
+		mr.Params[param.key] = param.value
+	}
+	if raw.hasWeight && !isQValue(raw.weight.value) {
+		return MediaRange{}, syntaxErrorf(raw.weight.valueOffset, "invalid weight %q", raw.weight.value)
+	}
+	return mr, nil
+}
+
+// isQValue reports whether s matches the qvalue production
+// in RFC 9110 Section 12.4.2.
+func isQValue(s string) bool {
+	if len(s) == 0 || len(s) > len("0.000") || s[0] != '0' && s[0] != '1' {
+		return false
+	}
+	if len(s) == 1 {
+		return true
+	}
+	if s[1] != '.' {
+		return false
+	}
+	for i := 2; i < len(s); i++ {
+		if s[0] == '1' && s[i] != '0' || !('0' <= s[i] && s[i] <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseHeaderStrict(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   Header
+	}{
+		{accept: "", want: nil},
+		{
+			accept: "text/html",
+			want:   Header{{Range: "text/html", Quality: 1, Params: map[string]string{}}},
+		},
+		{
+			accept: `Text/HTML;Level=1 ; charset="utf-8";q=0.5 , */*;q=0`,
+			want: Header{
+				{Range: "text/html", Quality: 0.5, Params: map[string]string{"level": "1", "charset": "utf-8"}},
+				{Range: "*/*", Quality: 0, Params: map[string]string{}},
+			},
+		},
+		{
+			accept: `text/plain;foo="a \"b\"";q=1.000;`,
+			want:   Header{{Range: "text/plain", Quality: 1, Params: map[string]string{"foo": `a "b"`}}},
+		},
+		{
+			accept: "text/*;q=0.",
+			want:   Header{{Range: "text/*", Quality: 0, Params: map[string]string{}}},
+		},
+	}
+	for _, test := range tests {
+		got, err := ParseHeaderStrict(test.accept)
+		if err != nil {
+			t.Errorf("ParseHeaderStrict(%q): %v", test.accept, err)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseHeaderStrict(%q) (-want +got):\n%s", test.accept, diff)
+		}
+	}
+}
+
+func TestParseHeaderStrictErrors(t *testing.T) {
+	tests := []struct {
+		accept     string
+		wantOffset int
+	}{
+		{accept: "text", wantOffset: 4},
+		{accept: "text/", wantOffset: 5},
+		{accept: "text/html,,text/plain", wantOffset: 10},
+		{accept: "text/html, ", wantOffset: 11},
+		{accept: "*/html", wantOffset: 0},
+		{accept: "text/html, */plain", wantOffset: 11},
+		{accept: "text/html;q = 0.5", wantOffset: 11},
+		{accept: "text/html;q=.5", wantOffset: 12},
+		{accept: "text/html;q=0.5000", wantOffset: 12},
+		{accept: "text/html;q=1.5", wantOffset: 12},
+		{accept: "text/html;q=1e-1", wantOffset: 12},
+		{accept: "text/html;q=0.5;level=1", wantOffset: 16},
+		{accept: "text/html;level=1;LEVEL=2", wantOffset: 18},
+		{accept: "text/html;level=", wantOffset: 16},
+		{accept: "text/html;=1", wantOffset: 10},
+		{accept: "text/html;foo=\"a\x01\"", wantOffset: 16},
+		{accept: "text/html;foo=\"abc", wantOffset: 14},
+		{accept: "text/html text/plain", wantOffset: 10},
+	}
+	for _, test := range tests {
+		_, err := ParseHeaderStrict(test.accept)
+		if err == nil {
+			t.Errorf("ParseHeaderStrict(%q) did not return an error", test.accept)
+			continue
+		}
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseHeaderStrict(%q) = _, %v; want *SyntaxError", test.accept, err)
+			continue
+		}
+		if syntaxErr.Offset != test.wantOffset {
+			t.Errorf("ParseHeaderStrict(%q) error offset = %d (%v); want %d", test.accept, syntaxErr.Offset, err, test.wantOffset)
+		}
+	}
+}
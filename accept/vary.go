@@ -0,0 +1,85 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// Vary records the request headers that were consulted
+// while choosing a response,
+// so that the response can list them in its Vary header
+// (RFC 9110 Section 12.5.5).
+// Caches use the Vary header to avoid serving a response
+// negotiated for one client to another.
+// The zero value is an empty set of headers.
+type Vary struct {
+	names []string
+}
+
+// Get returns the value of the named header in reqHeader
+// and records that it was consulted.
+func (v *Vary) Get(reqHeader http.Header, name string) string {
+	v.Add(name)
+	return reqHeader.Get(name)
+}
+
+// Add records that the named request header was consulted.
+func (v *Vary) Add(name string) {
+	name = textproto.CanonicalMIMEHeaderKey(name)
+	for _, existing := range v.names {
+		if existing == name {
+			return
+		}
+	}
+	v.names = append(v.names, name)
+}
+
+// String returns the recorded header names
+// in the format of a Vary header, like "Accept, Accept-Encoding".
+func (v *Vary) String() string {
+	return strings.Join(v.names, ", ")
+}
+
+// Set adds the recorded header names to the Vary header in respHeader,
+// skipping any names that it already lists.
+// Set does nothing if no headers were recorded
+// or if the Vary header is already "*".
+func (v *Vary) Set(respHeader http.Header) {
+	if len(v.names) == 0 {
+		return
+	}
+	present := make(map[string]bool)
+	for _, line := range respHeader.Values("Vary") {
+		for _, name := range strings.Split(line, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return
+			}
+			present[textproto.CanonicalMIMEHeaderKey(name)] = true
+		}
+	}
+	var missing []string
+	for _, name := range v.names {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		respHeader.Add("Vary", strings.Join(missing, ", "))
+	}
+}
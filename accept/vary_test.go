@@ -0,0 +1,81 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accept
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVary(t *testing.T) {
+	reqHeader := http.Header{
+		"Accept":          {"text/html"},
+		"Accept-Encoding": {"gzip"},
+	}
+	v := new(Vary)
+	if got := v.Get(reqHeader, "accept"); got != "text/html" {
+		t.Errorf("Get(_, %q) = %q; want %q", "accept", got, "text/html")
+	}
+	v.Add("ACCEPT-ENCODING")
+	v.Add("Accept")
+	if got, want := v.String(), "Accept, Accept-Encoding"; got != want {
+		t.Errorf("String() = %q; want %q", got, want)
+	}
+
+	tests := []struct {
+		name string
+		resp http.Header
+		want []string
+	}{
+		{
+			name: "Empty",
+			resp: http.Header{},
+			want: []string{"Accept, Accept-Encoding"},
+		},
+		{
+			name: "Merge",
+			resp: http.Header{"Vary": {"Cookie, accept"}},
+			want: []string{"Cookie, accept", "Accept-Encoding"},
+		},
+		{
+			name: "AllPresent",
+			resp: http.Header{"Vary": {"Accept", "Accept-Encoding"}},
+			want: []string{"Accept", "Accept-Encoding"},
+		},
+		{
+			name: "Star",
+			resp: http.Header{"Vary": {"*"}},
+			want: []string{"*"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v.Set(test.resp)
+			if diff := cmp.Diff(test.want, test.resp.Values("Vary")); diff != "" {
+				t.Errorf("Vary (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Unused", func(t *testing.T) {
+		h := http.Header{}
+		new(Vary).Set(h)
+		if _, ok := h["Vary"]; ok {
+			t.Errorf("Vary = %q; want unset", h.Values("Vary"))
+		}
+	})
+}
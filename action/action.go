@@ -22,8 +22,11 @@ import (
 	"html/template"
 	"io/fs"
 	"net/http"
+	"time"
 
 	"zombiezen.com/go/bass/accept"
+	"zombiezen.com/go/bass/flashkv"
+	"zombiezen.com/go/bass/templateloader"
 )
 
 const acceptHeaderName = "Accept"
@@ -34,6 +37,9 @@ type Func[R any] func(context.Context, R) (*Response, error)
 type Handler[R any] struct {
 	f   Func[R]
 	cfg Config[R]
+	sem chan struct{}
+	// templates is nil if cfg.ReloadTemplates is true.
+	templates *templateloader.Cache
 }
 
 // NewHandler returns a new [Handler] with a default [Config] that calls f.
@@ -47,11 +53,82 @@ func NewHandler(templateFiles fs.FS, f Func[*http.Request]) *Handler[*http.Reque
 
 // ServeHTTP handles an HTTP request.
 func (h *Handler[R]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.cfg.OnRequestStart != nil || h.cfg.OnRequestEnd != nil {
+		var end func()
+		w, r, end = h.observe(w, r)
+		defer end()
+	}
+	info := requestInfoFromContext(r.Context())
 	ctx := r.Context()
 	if h.cfg.MaxRequestSize > 0 {
 		r = r.Clone(ctx)
 		r.Body = http.MaxBytesReader(w, r.Body, h.cfg.MaxRequestSize)
 	}
+	if h.cfg.BodySnapshotSize > 0 && r.Body != nil && r.Body != http.NoBody {
+		rec := &bodyRecorder{
+			r:           r.Body,
+			contentType: r.Header.Get("Content-Type"),
+			redact:      h.cfg.BodySnapshotRedact,
+			limit:       h.cfg.BodySnapshotSize,
+		}
+		r = r.Clone(ctx)
+		r.Body = rec
+		// Only errors reported after this point observe the snapshot.
+		// The request passed to the Func keeps its original context.
+		ctx = context.WithValue(ctx, bodySnapshotContextKey{}, rec)
+	}
+	if h.cfg.CookieCodec != nil {
+		r = r.WithContext(withCookieCodec(r.Context(), h.cfg.CookieCodec))
+	}
+	debugTiming := h.cfg.DebugTiming != nil && h.cfg.DebugTiming(r)
+	var cacheTarget *cacheTarget
+	if h.cfg.Cache != nil && !debugTiming && !h.cfg.ReloadTemplates {
+		var hit bool
+		cacheTarget, hit = h.cfg.Cache.serve(w, r, &cacheServeOptions{
+			isTLS:              isTLSRequest(r, h.cfg.TrustProxyHeaders),
+			securityHeaders:    h.cfg.SecurityHeaders,
+			negotiation:        h.cfg.negotiation(),
+			rejectUnacceptable: h.cfg.RejectUnacceptable,
+			compressMinSize:    h.cfg.CompressMinSize,
+		})
+		if hit {
+			if info != nil {
+				info.CacheHit = true
+			}
+			return
+		}
+	}
+	if h.cfg.CSRF != nil {
+		var err error
+		r, err = h.cfg.CSRF.protect(w, r, h.cfg.CookieCodec, isTLSRequest(r, h.cfg.TrustProxyHeaders))
+		if err != nil {
+			info.setErr(err)
+			h.cfg.reportError(ctx, err)
+			h.serveError(w, r, err)
+			return
+		}
+	}
+	if h.sem != nil {
+		select {
+		case h.sem <- struct{}{}:
+			defer func() { <-h.sem }()
+		default:
+			info.setErr(errOverloaded)
+			w.Header().Set("Retry-After", overloadRetryAfter)
+			h.serveError(w, r, errOverloaded)
+			return
+		}
+	}
+	if h.cfg.Sessions != nil {
+		sessionReq, err := loadSession(h.cfg.Sessions, r)
+		if err != nil {
+			info.setErr(err)
+			h.cfg.reportError(ctx, err)
+			h.serveError(w, r, err)
+			return
+		}
+		r = sessionReq
+	}
 	resp, renderOpts, err := h.serve(r)
 	defer func() {
 		if err := resp.Close(); err != nil {
@@ -59,50 +136,111 @@ func (h *Handler[R]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 	if err != nil {
+		info.setErr(err)
 		h.cfg.reportError(ctx, err)
 		if resp == nil {
 			resp = h.cfg.transformError(err)
 		}
+	} else {
+		renderOpts.rejectUnacceptable = h.cfg.RejectUnacceptable
+		if cacheTarget != nil && resp.isCacheable() && !renderOpts.session.wasUsed() {
+			renderOpts.cache = cacheTarget
+		}
+	}
+	if debugTiming {
+		renderOpts.timing = new(renderTiming)
 	}
 	resp.render(ctx, w, renderOpts)
 }
 
-func (h *Handler[R]) serve(r *http.Request) (*Response, *renderOptions, error) {
+// errOverloaded is the error served when a [Handler]
+// has reached its MaxConcurrent limit.
+var errOverloaded = WithStatusCode(http.StatusServiceUnavailable, errors.New("server is busy; try again later"))
+
+// overloadRetryAfter is the value of the Retry-After header
+// sent with errOverloaded.
+const overloadRetryAfter = "1"
+
+// serveError serves the response from TransformError for err
+// in the representation preferred by the request
+// without calling the handler's [Func].
+func (h *Handler[R]) serveError(w http.ResponseWriter, r *http.Request, err error) {
 	ctx := r.Context()
-	renderOpts := &renderOptions{
-		reqMethod:     r.Method,
-		reqPath:       r.URL.Path,
-		templateFiles: h.cfg.TemplateFiles,
-		reportError:   h.cfg.ReportError,
+	renderOpts := h.newRenderOptions(r)
+	renderOpts.templateFuncs = renderOpts.baseTemplateFuncs(h.cfg.TemplateFuncs)
+	// Errors parsing the header are ignored:
+	// a nil header will pick the first representation.
+	renderOpts.acceptHeader, _ = accept.AppendHeader(renderOpts.acceptBuf[:0], r.Header.Get(acceptHeaderName))
+	resp := h.cfg.transformError(err)
+	defer func() {
+		if err := resp.Close(); err != nil {
+			h.cfg.reportError(ctx, err)
+		}
+	}()
+	resp.render(ctx, w, renderOpts)
+}
+
+func (h *Handler[R]) newRenderOptions(r *http.Request) *renderOptions {
+	return &renderOptions{
+		reqMethod:       r.Method,
+		reqPath:         r.URL.Path,
+		isTLS:           isTLSRequest(r, h.cfg.TrustProxyHeaders),
+		templateFiles:   h.cfg.TemplateFiles,
+		templates:       h.templates,
+		reportError:     h.cfg.ReportError,
+		securityHeaders: h.cfg.SecurityHeaders,
+		turboStreamJSON: h.cfg.TurboStreamJSON,
+		messagePack:     h.cfg.MessagePack,
+		negotiation:     h.cfg.negotiation(),
+		cookieCodec:     h.cfg.CookieCodec,
+		conditions:      requestConditions(r),
+		languages:       requestLanguages(r),
+		returnMinimal:   prefersMinimal(r),
+		autoETag:        h.cfg.AutoETag,
+		acceptEncoding:  requestAcceptEncoding(r),
+		compressMinSize: h.cfg.CompressMinSize,
+		templateTimeout: h.cfg.TemplateTimeout,
+		maxTemplateSize: h.cfg.MaxTemplateSize,
+		fileHeader:      fileRequestHeader(r),
+		csrf:            csrfStateFromContext(r.Context()),
+		session:         SessionFromContext(r.Context()),
+		info:            requestInfoFromContext(r.Context()),
 	}
+}
+
+func (h *Handler[R]) serve(r *http.Request) (*Response, *renderOptions, error) {
+	ctx := r.Context()
+	renderOpts := h.newRenderOptions(r)
 	var err error
-	renderOpts.acceptHeader, err = accept.ParseHeader(r.Header.Get(acceptHeaderName))
+	renderOpts.acceptHeader, err = accept.AppendHeader(renderOpts.acceptBuf[:0], r.Header.Get(acceptHeaderName))
+	baseFuncs := renderOpts.baseTemplateFuncs(h.cfg.TemplateFuncs)
 	if err != nil {
-		renderOpts.templateFuncs = h.cfg.TemplateFuncs
+		renderOpts.templateFuncs = baseFuncs
 		return nil, renderOpts, WithStatusCode(http.StatusBadRequest, err)
 	}
 	req, cleanup, err := h.cfg.transformRequest(r)
 	if err != nil {
-		renderOpts.templateFuncs = h.cfg.TemplateFuncs
+		renderOpts.templateFuncs = baseFuncs
 		return nil, renderOpts, err
 	}
 	if cleanup != nil {
 		defer cleanup()
 	}
+	renderOpts.request = req
 	// TODO(maybe): Randomize order of f and MakeTemplateFuncs.
 	resp, err := h.f(ctx, req)
 	if h.cfg.MakeRequestTemplateFuncs != nil && (err == nil || resp != nil) {
 		// Only set template functions if we are not using transformError.
 		// This keeps transformError robust because it cannot ever observe request-specific functions.
 		renderOpts.templateFuncs = make(template.FuncMap)
-		for name, f := range h.cfg.TemplateFuncs {
+		for name, f := range baseFuncs {
 			renderOpts.templateFuncs[name] = f
 		}
 		for name, f := range h.cfg.MakeRequestTemplateFuncs(ctx, req) {
 			renderOpts.templateFuncs[name] = f
 		}
 	} else {
-		renderOpts.templateFuncs = h.cfg.TemplateFuncs
+		renderOpts.templateFuncs = baseFuncs
 	}
 	return resp, renderOpts, err
 }
@@ -130,7 +268,9 @@ type Config[R any] struct {
 
 	// TransformError is an optional callback to convert errors into responses.
 	// If nil, a basic plain text conversion will be performed
-	// that uses the status code from [ErrorStatusCode].
+	// that uses the status code from [ErrorStatusCode],
+	// along with an HTML representation if ErrorTemplates applies.
+	// A [*ValidationError] is instead presented as described in its documentation.
 	//
 	// Templated error responses can only use funcs from TemplateFuncs,
 	// not MakeRequestTemplateFuncs,
@@ -138,11 +278,31 @@ type Config[R any] struct {
 	// in case of a bad request.
 	TransformError func(error) *Response
 
+	// ErrorTemplates is an optional map of HTTP status codes
+	// to html/template files in TemplateFiles
+	// used to present errors as HTML when TransformError is nil.
+	// The templates are executed with an [*ErrorTemplateData]
+	// and are offered alongside the plain text error message,
+	// so clients that do not accept HTML still receive plain text.
+	ErrorTemplates map[int]string
+	// FallbackErrorTemplate is an optional html/template file
+	// used for errors whose status code is not in ErrorTemplates.
+	FallbackErrorTemplate string
+
 	// TemplateFiles is used for reading templates for responses.
 	// It is only needed if the handler uses the template fields in [Response].
 	TemplateFiles fs.FS
 
 	// TemplateFuncs is a set of functions available in every response.
+	//
+	// In addition, templates can call formatNumber, formatCurrency, and formatDate
+	// to format values for the language the request prefers most
+	// according to its Accept-Language header:
+	// {{formatNumber 1234.5}} produces "1.234,5" for German,
+	// {{formatCurrency 9.99 "EUR"}} formats an amount in an ISO 4217 currency,
+	// and {{formatDate .Time}} formats a time.Time as a numeric date.
+	// Functions in TemplateFuncs or MakeRequestTemplateFuncs
+	// with the same names take precedence.
 	TemplateFuncs template.FuncMap
 
 	// MakeRequestTemplateFuncs is a callback that produces a set of functions
@@ -152,6 +312,190 @@ type Config[R any] struct {
 	// ReportError is an optional callback
 	// for application errors that occur during request processing.
 	ReportError func(context.Context, error)
+
+	// OnRequestStart is an optional callback
+	// that is called when the Handler starts serving a request.
+	// It returns the context to use for the rest of the request,
+	// which must be ctx or derived from it,
+	// so that it can start a tracing span, for example.
+	OnRequestStart func(ctx context.Context, r *http.Request) context.Context
+	// OnRequestEnd is an optional callback
+	// that is called after the Handler has written a response,
+	// with the context returned by OnRequestStart (if set).
+	// It can be used to record metrics or end a tracing span
+	// using the outcome of content negotiation and rendering,
+	// which middleware wrapping the Handler cannot observe.
+	OnRequestEnd func(ctx context.Context, info *RequestInfo)
+
+	// If BodySnapshotSize is greater than zero,
+	// then Handler records up to that many bytes of the request body
+	// as the request is read.
+	// The recorded bytes are available to ReportError
+	// through [BodySnapshotFromContext].
+	BodySnapshotSize int
+
+	// BodySnapshotRedact is a list of field names
+	// whose values are replaced in body snapshots.
+	// Names are matched case-insensitively against
+	// URL-encoded form fields and JSON object members at any depth.
+	// If BodySnapshotRedact is not empty,
+	// snapshots of bodies with other media types
+	// (or truncated JSON bodies) omit the data,
+	// since they cannot be redacted reliably.
+	BodySnapshotRedact []string
+
+	// SecurityHeaders is an optional set of headers to send with every response.
+	// [NewConfig] sets it to [DefaultSecurityHeaders].
+	SecurityHeaders *SecurityHeaders
+
+	// If TrustProxyHeaders is true, then a request with
+	// an X-Forwarded-Proto header of "https" is treated as if it used TLS
+	// for SecurityHeaders, CSRF cookies, and CSRF origin checks.
+	// Only set it if the Handler is behind a reverse proxy
+	// that sets or strips the header,
+	// since otherwise any client can send it.
+	TrustProxyHeaders bool
+
+	// If MaxConcurrent is greater than zero,
+	// then it is the maximum number of requests that the Handler will process at once.
+	// Requests beyond the limit are immediately served
+	// a 503 (Service Unavailable) error with a Retry-After header
+	// rather than waiting.
+	// Responses served from Cache do not count toward the limit.
+	MaxConcurrent int
+
+	// If RejectUnacceptable is true and none of a response's representations
+	// are acceptable according to the request's Accept header,
+	// then the Handler responds with 406 (Not Acceptable)
+	// and a plain text list of the available content types.
+	// Otherwise, the Handler serves the first representation.
+	// Responses for errors are always served.
+	RejectUnacceptable bool
+
+	// CookieCodec signs and verifies cookies
+	// set with [Response] SetSignedCookies and read with [SignedCookie],
+	// as well as the [CSRF] cookie.
+	// Keys are rotated with the codec's [flashkv.Options] Keys:
+	// add a new key to the front of the list
+	// and remove the old key once its cookies have expired.
+	CookieCodec *flashkv.Codec
+
+	// CSRF enables protection against cross-site request forgery.
+	// See [CSRF] for details.
+	CSRF *CSRF
+
+	// Sessions is an optional store for per-client sessions.
+	// If it is not nil, then the Handler loads the request's session
+	// before calling the [Func], which can read and modify it
+	// with [SessionFromContext].
+	// If the session was modified, the Handler saves it
+	// and sends the returned cookie along with the response
+	// (including error responses and redirects).
+	// Errors loading the session are served through TransformError
+	// without calling the Func.
+	// Use [CookieSessions] to keep sessions in a [flashkv.Store] cookie.
+	Sessions SessionStore
+
+	// PreferContentTypes is an optional list of media types
+	// (like "text/html") in the server's order of preference.
+	// It breaks ties between representations that the request's Accept header
+	// ranks equally, both in quality and in the specificity of the media range
+	// that matched them.
+	// For example, with a PreferContentTypes of {"application/json"},
+	// a request that accepts "*/*" receives JSON
+	// even if the response also offers HTML.
+	// Types that are not listed rank after listed ones
+	// in the order the [Response] offers them.
+	PreferContentTypes []string
+
+	// TraceNegotiation is an optional callback that is called
+	// with the details of each content negotiation decision.
+	// It is intended for debugging surprising representation choices.
+	TraceNegotiation func(context.Context, *NegotiationTrace)
+
+	// If TurboStreamJSON is true, then responses that have TurboStreams
+	// but neither a JSONValue nor a TurboStreamTemplate
+	// also offer the actions as a JSON array of objects
+	// (as formatted by the turbostream package's Action.MarshalJSON method)
+	// to clients that prefer application/json, such as Turbo Native apps.
+	TurboStreamJSON bool
+
+	// If MessagePack is true, then responses with a JSONValue
+	// are also offered as [MessagePack] (application/x-msgpack)
+	// using the same encoding rules (including struct tags) as [encoding/json].
+	//
+	// [MessagePack]: https://msgpack.org/
+	MessagePack bool
+
+	// DebugTiming is an optional predicate that reports whether a request
+	// should be served with a breakdown of how long rendering took,
+	// typically by checking for a header or cookie set by developers.
+	// The breakdown is sent in a Server-Timing header,
+	// an HTML comment at the end of HTML responses,
+	// and a "_timing" member of JSON object responses.
+	// Such requests bypass Cache.
+	DebugTiming func(*http.Request) bool
+
+	// If CompressMinSize is greater than zero,
+	// then representations of at least that many bytes
+	// with a compressible content type (like text or JSON)
+	// are compressed with gzip or deflate
+	// if the request's Accept-Encoding header allows it.
+	// Representations that already have a Content-Encoding are sent as-is.
+	CompressMinSize int
+
+	// If TemplateTimeout is greater than zero,
+	// then executing a response's template is aborted
+	// if it takes longer than the given duration.
+	// Like MaxTemplateSize, the deadline is only checked
+	// when the template writes output,
+	// so it cannot interrupt a loop that produces no output.
+	TemplateTimeout time.Duration
+
+	// If MaxTemplateSize is greater than zero,
+	// then executing a response's template is aborted
+	// once its output exceeds the given number of bytes.
+	// Aborted templates are served like any other rendering error:
+	// the error is passed to ReportError
+	// and the client receives a 500 (Internal Server Error) response.
+	MaxTemplateSize int64
+
+	// Cache is an optional cache of rendered responses.
+	// If it is not nil, then GET and HEAD requests
+	// are served from the cache when possible.
+	Cache *Cache
+
+	// If AutoETag is true, then responses without an ETag
+	// are sent with a weak ETag computed from a hash of the chosen representation,
+	// and GET requests whose If-None-Match header matches it
+	// receive a 304 (Not Modified) response.
+	// The representation must still be rendered to compute its hash,
+	// so AutoETag saves bandwidth, not server time.
+	// Representations created by FileRepresentation or StreamRepresentation
+	// do not get an automatic ETag.
+	//
+	// Additionally, HEAD requests are answered without rendering
+	// templates or other representations produced on demand,
+	// so they only include the Content-Type and no Content-Length or ETag.
+	AutoETag bool
+
+	// Templates are normally parsed once per Handler.
+	// If ReloadTemplates is true, then templates are read from TemplateFiles
+	// and parsed on every request and rendered responses are never served from Cache,
+	// so that edits to templates are visible without restarting the server.
+	// This is intended for development
+	// with a TemplateFiles that reads from disk (like [os.DirFS]):
+	// an [embed.FS] cannot change without rebuilding the program.
+	ReloadTemplates bool
+}
+
+// NewConfig returns a new [Config] that reads templates from templateFiles
+// and sends [DefaultSecurityHeaders].
+func NewConfig[R any](templateFiles fs.FS) *Config[R] {
+	return &Config[R]{
+		TemplateFiles:   templateFiles,
+		SecurityHeaders: DefaultSecurityHeaders(),
+	}
 }
 
 // NewHandler creates a [Handler] with the given function.
@@ -159,7 +503,14 @@ func (cfg *Config[R]) NewHandler(f Func[R]) *Handler[R] {
 	if cfg == nil {
 		cfg = new(Config[R])
 	}
-	return &Handler[R]{f, *cfg}
+	h := &Handler[R]{f: f, cfg: *cfg}
+	if cfg.MaxConcurrent > 0 {
+		h.sem = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	if !cfg.ReloadTemplates {
+		h.templates = new(templateloader.Cache)
+	}
+	return h
 }
 
 var errNoFunc = errors.New("TransformRequest function not provided")
@@ -186,17 +537,39 @@ func (cfg *Config[R]) transformRequest(r *http.Request) (req R, cleanup func(),
 
 func (cfg *Config[R]) transformError(err error) *Response {
 	if cfg == nil || cfg.TransformError == nil {
-		return defaultTransformError(err)
+		resp := defaultTransformError(err)
+		if name := cfg.errorTemplate(resp.StatusCode); name != "" && resp.HTMLTemplate == "" {
+			resp.HTMLTemplate = name
+			resp.TemplateData = newErrorTemplateData(resp.StatusCode, err)
+		}
+		return resp
 	}
 	return cfg.TransformError(err)
 }
 
+func (cfg *Config[R]) errorTemplate(code int) string {
+	if cfg == nil {
+		return ""
+	}
+	if name := cfg.ErrorTemplates[code]; name != "" {
+		return name
+	}
+	return cfg.FallbackErrorTemplate
+}
+
 func (cfg *Config[R]) reportError(ctx context.Context, err error) {
 	if cfg != nil && cfg.ReportError != nil {
 		cfg.ReportError(ctx, err)
 	}
 }
 
+func (cfg *Config[R]) negotiation() negotiation {
+	return negotiation{
+		prefer: cfg.PreferContentTypes,
+		trace:  cfg.TraceNegotiation,
+	}
+}
+
 func identity(r *http.Request) (*http.Request, func(), error) {
 	return r, func() {}, nil
 }
@@ -24,6 +24,9 @@ import (
 	"strings"
 	"testing"
 	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"zombiezen.com/go/bass/turbostream"
 )
 
 func TestHandler(t *testing.T) {
@@ -85,4 +88,195 @@ func TestHandler(t *testing.T) {
 			t.Errorf("Body = %q; want to contain %q", got, errorMessage)
 		}
 	})
+
+	t.Run("SecurityHeaders", func(t *testing.T) {
+		cfg := NewConfig[*http.Request](nil)
+		cfg.TrustProxyHeaders = true
+		h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			repr := TextRepresentation("Hello, World!\n")
+			repr.Header.Set("X-Frame-Options", "SAMEORIGIN")
+			return &Response{Other: []*Representation{repr}}, nil
+		})
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Forwarded-Proto", "https")
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		want := http.Header{
+			"X-Frame-Options":           {"SAMEORIGIN"},
+			"Content-Security-Policy":   {"frame-ancestors 'none'"},
+			"Referrer-Policy":           {"strict-origin-when-cross-origin"},
+			"Strict-Transport-Security": {"max-age=63072000; includeSubDomains"},
+		}
+		for k, v := range want {
+			if got := resp.Header.Values(k); !cmp.Equal(got, v) {
+				t.Errorf("%s = %q; want %q", k, got, v)
+			}
+		}
+	})
+
+	t.Run("NoHSTSWithoutTLS", func(t *testing.T) {
+		cfg := NewConfig[*http.Request](nil)
+		h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			return nil, nil
+		})
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+		resp, err := srv.Client().Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get("Strict-Transport-Security"); got != "" {
+			t.Errorf("Strict-Transport-Security = %q; want \"\"", got)
+		}
+		if got, want := resp.Header.Get("X-Frame-Options"), "DENY"; got != want {
+			t.Errorf("X-Frame-Options = %q; want %q", got, want)
+		}
+	})
+	t.Run("UntrustedForwardedProto", func(t *testing.T) {
+		cfg := NewConfig[*http.Request](nil)
+		h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			return nil, nil
+		})
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Forwarded-Proto", "https")
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get("Strict-Transport-Security"); got != "" {
+			t.Errorf("Strict-Transport-Security = %q; want \"\" without TrustProxyHeaders", got)
+		}
+	})
+	t.Run("MaxConcurrent", func(t *testing.T) {
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		cfg := &Config[*http.Request]{MaxConcurrent: 1}
+		h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			entered <- struct{}{}
+			<-release
+			return nil, nil
+		})
+		firstDone := make(chan int)
+		go func() {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			firstDone <- rec.Code
+		}()
+		<-entered
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got, want := rec.Code, http.StatusServiceUnavailable; got != want {
+			t.Errorf("StatusCode = %d; want %d", got, want)
+		}
+		if got := rec.Header().Get("Retry-After"); got == "" {
+			t.Error("Retry-After not set")
+		}
+
+		close(release)
+		if got, want := <-firstDone, http.StatusNoContent; got != want {
+			t.Errorf("first request StatusCode = %d; want %d", got, want)
+		}
+		go func() { <-entered }()
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got, want := rec.Code, http.StatusNoContent; got != want {
+			t.Errorf("after release, StatusCode = %d; want %d", got, want)
+		}
+	})
+
+	t.Run("RejectUnacceptable", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			accept     string
+			err        error
+			wantStatus int
+		}{
+			{name: "Acceptable", accept: "application/json", wantStatus: http.StatusOK},
+			{name: "Wildcard", accept: "*/*", wantStatus: http.StatusOK},
+			{name: "Unacceptable", accept: "image/png", wantStatus: http.StatusNotAcceptable},
+			{name: "Error", accept: "image/png", err: WithStatusCode(http.StatusNotFound, errors.New("bork")), wantStatus: http.StatusNotFound},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				cfg := &Config[*http.Request]{RejectUnacceptable: true}
+				h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+					if test.err != nil {
+						return nil, test.err
+					}
+					return &Response{JSONValue: "hi"}, nil
+				})
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Accept", test.accept)
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				if rec.Code != test.wantStatus {
+					t.Errorf("StatusCode = %d; want %d", rec.Code, test.wantStatus)
+				}
+				if test.wantStatus == http.StatusNotAcceptable && !strings.Contains(rec.Body.String(), "application/json") {
+					t.Errorf("406 body does not list available types:\n%s", rec.Body)
+				}
+			})
+		}
+	})
+
+	t.Run("TurboStreamJSON", func(t *testing.T) {
+		tests := []struct {
+			name            string
+			turboStreamJSON bool
+			accept          string
+			want            string
+		}{
+			{
+				name:            "JSON",
+				turboStreamJSON: true,
+				accept:          "application/json",
+				want:            `[{"action":"remove","target":"message_1"}]`,
+			},
+			{
+				name:            "TurboStream",
+				turboStreamJSON: true,
+				accept:          turbostream.ContentType,
+				want:            `<turbo-stream action="remove" target="message_1"></turbo-stream>` + "\n",
+			},
+			{
+				name:            "Disabled",
+				turboStreamJSON: false,
+				accept:          "application/json",
+				want:            `<turbo-stream action="remove" target="message_1"></turbo-stream>` + "\n",
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				cfg := &Config[*http.Request]{TurboStreamJSON: test.turboStreamJSON}
+				h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+					return &Response{
+						TurboStreams: []*turbostream.Action{nil, turbostream.NewRemove("message_1")},
+					}, nil
+				})
+				req := httptest.NewRequest(http.MethodPost, "/", nil)
+				req.Header.Set("Accept", test.accept)
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				if got := rec.Body.String(); got != test.want {
+					t.Errorf("body = %q; want %q", got, test.want)
+				}
+			})
+		}
+	})
 }
@@ -0,0 +1,257 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"zombiezen.com/go/bass/accept"
+	"zombiezen.com/go/bass/clock"
+)
+
+// maxCacheEntries is the number of URLs a [Cache] stores
+// before it discards all of its entries.
+const maxCacheEntries = 10000
+
+// A Cache stores rendered representations of a [Handler]'s responses
+// so that the handler's [Func] does not need to be called on every request.
+// Responses are keyed by URL path, query string, and negotiated media type.
+// Only responses to GET and HEAD requests
+// that have a 200 (OK) status code and do not set cookies are cached,
+// so a Cache should only be used for responses that are the same for every user.
+//
+// A Cache is safe to use from multiple goroutines.
+type Cache struct {
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu      sync.Mutex
+	gen     uint64
+	entries map[cacheKey]*cacheEntry
+}
+
+type cacheKey struct {
+	path  string
+	query string
+}
+
+type cacheEntry struct {
+	expires time.Time
+	offers  []parsedRepresentation
+	reprs   []*cachedRepresentation
+}
+
+type cachedRepresentation struct {
+	header http.Header
+	body   []byte
+}
+
+// NewCache returns a new empty [Cache]
+// whose entries expire after the given duration.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		clock:   clock.Real,
+		entries: make(map[cacheKey]*cacheEntry),
+	}
+}
+
+// SetClock sets the source of the current time for entry expiration.
+// By default, a Cache uses [clock.Real].
+// SetClock must be called before the Cache is used.
+func (c *Cache) SetClock(clk clock.Clock) {
+	c.clock = clock.Or(clk)
+}
+
+// Invalidate removes the cached responses for the given URL path,
+// regardless of query string.
+func (c *Cache) Invalidate(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for k := range c.entries {
+		if k.path == path {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// InvalidateAll removes all cached responses.
+func (c *Cache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.entries = make(map[cacheKey]*cacheEntry)
+}
+
+// cacheTarget is where a rendered representation should be stored.
+type cacheTarget struct {
+	cache *Cache
+	key   cacheKey
+	gen   uint64
+}
+
+// cacheServeOptions holds the [Handler] settings
+// that affect how a cached representation is served.
+type cacheServeOptions struct {
+	isTLS           bool
+	securityHeaders *SecurityHeaders
+	negotiation     negotiation
+	// rejectUnacceptable is true if an unacceptable representation
+	// should be treated as a miss so that the Handler can respond with an error.
+	rejectUnacceptable bool
+	compressMinSize    int
+}
+
+// serve writes a cached representation for r to w if one is available.
+// Otherwise, it returns a target for storing the rendered response.
+func (c *Cache) serve(w http.ResponseWriter, r *http.Request, opts *cacheServeOptions) (target *cacheTarget, hit bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return nil, false
+	}
+	key := cacheKey{path: r.URL.Path, query: r.URL.RawQuery}
+	var acceptBuf [8]accept.MediaRange
+	acceptHeader, err := accept.AppendHeader(acceptBuf[:0], r.Header.Get(acceptHeaderName))
+	if err != nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	target = &cacheTarget{cache: c, key: key, gen: c.gen}
+	var repr *cachedRepresentation
+	var offers []parsedRepresentation
+	if ent := c.entries[key]; ent != nil {
+		if c.clock.Now().Before(ent.expires) {
+			offers = ent.offers
+			p := opts.negotiation.choose(r.Context(), ent.offers, acceptHeader)
+			if !opts.rejectUnacceptable || p.isAcceptable(acceptHeader) {
+				for i := range ent.offers {
+					if &ent.offers[i] == p {
+						repr = ent.reprs[i]
+						break
+					}
+				}
+			}
+		} else {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+	if repr == nil {
+		return target, false
+	}
+
+	opts.securityHeaders.set(w.Header(), opts.isTLS)
+	setVary(w.Header(), offers, opts.rejectUnacceptable)
+	header := repr.header.Clone()
+	if contentCoding(header.Get(contentTypeHeaderName), requestAcceptEncoding(r), opts.compressMinSize) != "" {
+		weakenETag(header)
+	}
+	if requestConditions(r).notModified(header) {
+		writeNotModified(w, header)
+		return nil, true
+	}
+	cached := &Representation{
+		Header: header,
+		Body:   io.NopCloser(bytes.NewReader(repr.body)),
+	}
+	cached, err = compressRepresentation(w.Header(), cached, requestAcceptEncoding(r), opts.compressMinSize)
+	if err != nil {
+		http.Error(w, "Error while serving page. Check server logs.", http.StatusInternalServerError)
+		return nil, true
+	}
+	cached.write(r.Context(), w, http.StatusOK, r.Method != http.MethodHead)
+	return nil, true
+}
+
+// store saves a representation of the response
+// that was chosen from the given possibilities.
+func (t *cacheTarget) store(possibilities []parsedRepresentation, chosen *parsedRepresentation, repr *cachedRepresentation) {
+	c := t.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != t.gen {
+		// Invalidated while rendering.
+		return
+	}
+	now := c.clock.Now()
+	ent := c.entries[t.key]
+	if ent == nil || !now.Before(ent.expires) || !sameOffers(ent.offers, possibilities) {
+		if len(c.entries) >= maxCacheEntries {
+			c.removeExpired(now)
+		}
+		if len(c.entries) >= maxCacheEntries {
+			c.entries = make(map[cacheKey]*cacheEntry)
+		}
+		ent = &cacheEntry{
+			expires: now.Add(c.ttl),
+			offers:  make([]parsedRepresentation, len(possibilities)),
+			reprs:   make([]*cachedRepresentation, len(possibilities)),
+		}
+		for i, p := range possibilities {
+			ent.offers[i] = parsedRepresentation{
+				contentType: p.contentType,
+				mediaType:   p.mediaType,
+				typeParams:  p.typeParams,
+			}
+		}
+		c.entries[t.key] = ent
+	}
+	for i := range possibilities {
+		if &possibilities[i] == chosen {
+			ent.reprs[i] = repr
+			return
+		}
+	}
+}
+
+// removeExpired removes expired entries from c.
+// The caller must hold c.mu.
+func (c *Cache) removeExpired(now time.Time) {
+	for k, ent := range c.entries {
+		if !now.Before(ent.expires) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+func sameOffers(offers, possibilities []parsedRepresentation) bool {
+	if len(offers) != len(possibilities) {
+		return false
+	}
+	for i := range offers {
+		if offers[i].contentType != possibilities[i].contentType {
+			return false
+		}
+	}
+	return true
+}
+
+// isCacheable reports whether resp can be stored in a [Cache].
+func (resp *Response) isCacheable() bool {
+	return resp != nil &&
+		(resp.StatusCode == 0 || resp.StatusCode == http.StatusOK) &&
+		resp.SeeOther == "" &&
+		len(resp.SetCookies) == 0 &&
+		len(resp.SetSignedCookies) == 0 &&
+		resp.EventStream == nil &&
+		!hasNoStore(resp.CacheControl)
+}
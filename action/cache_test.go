@@ -0,0 +1,154 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"zombiezen.com/go/bass/clock"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache(time.Minute)
+	clk := clock.NewFake(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))
+	cache.SetClock(clk)
+	calls := 0
+	cfg := &Config[*http.Request]{Cache: cache}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		calls++
+		msg := fmt.Sprintf("call %d", calls)
+		return &Response{
+			JSONValue: msg,
+			Other:     []*Representation{TextRepresentation(msg)},
+		}, nil
+	})
+
+	get := func(target, accept string) string {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d; want %d", target, rec.Code, http.StatusOK)
+		}
+		return rec.Body.String()
+	}
+
+	tests := []struct {
+		name   string
+		do     func()
+		target string
+		accept string
+		want   string
+	}{
+		{name: "FirstJSON", target: "/", accept: "application/json", want: `"call 1"`},
+		{name: "CachedJSON", target: "/", accept: "application/json", want: `"call 1"`},
+		{name: "NewMediaType", target: "/", accept: "text/plain", want: "call 2"},
+		{name: "CachedText", target: "/", accept: "text/plain", want: "call 2"},
+		{name: "NewQuery", target: "/?page=2", accept: "text/plain", want: "call 3"},
+		{
+			name:   "Expired",
+			do:     func() { clk.Advance(2 * time.Minute) },
+			target: "/",
+			accept: "text/plain",
+			want:   "call 4",
+		},
+		{name: "NewPath", target: "/other", accept: "text/plain", want: "call 5"},
+		{
+			name:   "Invalidated",
+			do:     func() { cache.Invalidate("/") },
+			target: "/",
+			accept: "text/plain",
+			want:   "call 6",
+		},
+		{name: "OtherPathStillCached", target: "/other", accept: "text/plain", want: "call 5"},
+		{
+			name:   "InvalidateAll",
+			do:     cache.InvalidateAll,
+			target: "/other",
+			accept: "text/plain",
+			want:   "call 7",
+		},
+	}
+	for _, test := range tests {
+		if test.do != nil {
+			test.do()
+		}
+		if got := get(test.target, test.accept); got != test.want {
+			t.Errorf("%s: GET %s (Accept: %s) = %q; want %q", test.name, test.target, test.accept, got, test.want)
+		}
+	}
+}
+
+func TestCacheSkipsUncacheable(t *testing.T) {
+	calls := 0
+	cfg := &Config[*http.Request]{Cache: NewCache(time.Minute)}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		calls++
+		return &Response{
+			SetCookies: []*http.Cookie{{Name: "session", Value: "abc"}},
+			Other:      []*Representation{TextRepresentation("Hello")},
+		}, nil
+	})
+	for i := 0; i < 2; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times; want 2", calls)
+	}
+}
+
+func TestCacheBypassedByReloadTemplates(t *testing.T) {
+	templateFiles := fstest.MapFS{
+		"base.html": {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+		"page.html": {Data: []byte(`{{ define "content" }}Hello{{ end }}`)},
+	}
+	cfg := &Config[*http.Request]{
+		TemplateFiles:   templateFiles,
+		Cache:           NewCache(time.Minute),
+		ReloadTemplates: true,
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{HTMLTemplate: "page.html"}, nil
+	})
+	get := func() string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET / status = %d; want %d", rec.Code, http.StatusOK)
+		}
+		return rec.Body.String()
+	}
+
+	if got, want := get(), "Hello"; got != want {
+		t.Errorf("first GET / = %q; want %q", got, want)
+	}
+	templateFiles["page.html"] = &fstest.MapFile{Data: []byte(`{{ define "content" }}Goodbye{{ end }}`)}
+	if got, want := get(), "Goodbye"; got != want {
+		t.Errorf("GET / after editing template = %q; want %q", got, want)
+	}
+}
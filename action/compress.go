@@ -0,0 +1,177 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"zombiezen.com/go/bass/accept"
+)
+
+const (
+	acceptEncodingHeaderName  = "Accept-Encoding"
+	contentEncodingHeaderName = "Content-Encoding"
+)
+
+// requestAcceptEncoding returns all of r's Accept-Encoding headers
+// joined into a single list.
+func requestAcceptEncoding(r *http.Request) string {
+	return strings.Join(r.Header.Values(acceptEncodingHeaderName), ",")
+}
+
+// compressRepresentation returns repr encoded with the content coding
+// that the request's Accept-Encoding header prefers
+// if repr is at least minSize bytes long
+// and its content type benefits from compression.
+// respHeader is the response's header,
+// to which compressRepresentation adds "Vary: Accept-Encoding"
+// if the representation is eligible for compression.
+// A strong entity tag in respHeader or repr's header
+// is made weak when the body is encoded (see [weakenETag]).
+// The returned representation does not need to be closed,
+// but repr must still be closed by the caller.
+func compressRepresentation(respHeader http.Header, repr *Representation, acceptEncoding string, minSize int) (*Representation, error) {
+	if minSize <= 0 ||
+		repr.Header.Get(contentEncodingHeaderName) != "" ||
+		!isCompressible(repr.Header.Get(contentTypeHeaderName)) {
+		return repr, nil
+	}
+	if n, err := strconv.ParseInt(repr.Header.Get(contentLengthHeaderName), 10, 64); err == nil && n < int64(minSize) {
+		return repr, nil
+	}
+	body, err := io.ReadAll(repr.Body)
+	if err != nil {
+		return nil, err
+	}
+	header := repr.Header.Clone()
+	header.Set(contentLengthHeaderName, strconv.Itoa(len(body)))
+	repr = &Representation{
+		Header: header,
+		Body:   io.NopCloser(bytes.NewReader(body)),
+	}
+	if len(body) < minSize {
+		return repr, nil
+	}
+
+	var vary accept.Vary
+	vary.Add(acceptEncodingHeaderName)
+	vary.Set(respHeader)
+	coding := contentCoding(repr.Header.Get(contentTypeHeaderName), acceptEncoding, minSize)
+	buf := new(bytes.Buffer)
+	var cw io.WriteCloser
+	switch coding {
+	case "gzip":
+		cw = gzip.NewWriter(buf)
+	case "deflate":
+		// The "deflate" content coding is the zlib format.
+		// See https://www.rfc-editor.org/rfc/rfc9110#section-8.4.1.2
+		cw = zlib.NewWriter(buf)
+	default:
+		return repr, nil
+	}
+	if _, err := cw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := cw.Close(); err != nil {
+		return nil, err
+	}
+	header = repr.Header.Clone()
+	header.Set(contentEncodingHeaderName, coding)
+	header.Set(contentLengthHeaderName, strconv.Itoa(buf.Len()))
+	weakenETag(header)
+	weakenETag(respHeader)
+	return &Representation{
+		Header: header,
+		Body:   io.NopCloser(buf),
+	}, nil
+}
+
+// contentCoding returns the content coding that compressRepresentation
+// uses for a representation with the given content type
+// that is at least minSize bytes long,
+// or the empty string if such a representation is sent unencoded.
+func contentCoding(contentType, acceptEncoding string, minSize int) string {
+	if minSize <= 0 || !isCompressible(contentType) {
+		return ""
+	}
+	codings, err := accept.ParseEncodingHeader(acceptEncoding)
+	if err != nil {
+		return ""
+	}
+	switch coding := codings.Negotiate("gzip", "deflate", accept.Identity); coding {
+	case "gzip", "deflate":
+		return coding
+	default:
+		return ""
+	}
+}
+
+// weakenETag marks the entity tag in h as weak if it is strong.
+// A strong tag must not be shared by the encoded and identity bodies,
+// since they are not byte-for-byte identical
+// (see RFC 9110 section 8.8.3.3),
+// but weak comparison, which If-None-Match uses,
+// still matches a weak tag to its strong form.
+// Responses that may be compressed are sent with a weak tag
+// even if they turn out to be too small to compress,
+// so that a 304 (Not Modified) response,
+// which is decided before the body is rendered,
+// carries the same tag as the full response.
+func weakenETag(h http.Header) {
+	if etag := h.Get(etagHeaderName); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set(etagHeaderName, "W/"+etag)
+	}
+}
+
+// isCompressible reports whether a representation with the given content type
+// is likely to shrink when compressed.
+// Most image, audio, and video formats are already compressed.
+func isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "image/svg+xml",
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	case strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "font/"):
+		return false
+	}
+	switch mediaType {
+	case jsonType,
+		"application/javascript",
+		"application/xml",
+		protobufType,
+		msgpackType:
+		return true
+	default:
+		return false
+	}
+}
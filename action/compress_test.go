@@ -0,0 +1,199 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompression(t *testing.T) {
+	long := strings.Repeat("hello, world\n", 100)
+	tests := []struct {
+		name           string
+		body           string
+		contentType    string
+		acceptEncoding string
+		wantEncoding   string
+		wantVary       bool
+	}{
+		{
+			name:           "Gzip",
+			body:           long,
+			acceptEncoding: "gzip, deflate",
+			wantEncoding:   "gzip",
+			wantVary:       true,
+		},
+		{
+			name:           "Deflate",
+			body:           long,
+			acceptEncoding: "deflate",
+			wantEncoding:   "deflate",
+			wantVary:       true,
+		},
+		{
+			name:     "NoAcceptEncoding",
+			body:     long,
+			wantVary: true,
+		},
+		{
+			name:           "IdentityPreferred",
+			body:           long,
+			acceptEncoding: "gzip;q=0.5, identity",
+			wantVary:       true,
+		},
+		{
+			name:           "Small",
+			body:           "hi",
+			acceptEncoding: "gzip",
+		},
+		{
+			name:           "Incompressible",
+			body:           long,
+			contentType:    "image/png",
+			acceptEncoding: "gzip",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contentType := test.contentType
+			if contentType == "" {
+				contentType = "text/plain; charset=utf-8"
+			}
+			cfg := &Config[*http.Request]{TransformRequest: identity, CompressMinSize: 256}
+			h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+				return &Response{Other: []*Representation{{
+					Header: http.Header{"Content-Type": {contentType}},
+					Body:   io.NopCloser(strings.NewReader(test.body)),
+				}}}, nil
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", test.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if got := rec.Header().Get("Content-Encoding"); got != test.wantEncoding {
+				t.Errorf("Content-Encoding = %q; want %q", got, test.wantEncoding)
+			}
+			if got := rec.Header().Get("Vary") == "Accept-Encoding"; got != test.wantVary {
+				t.Errorf("Vary = %q; want Accept-Encoding = %t", rec.Header().Values("Vary"), test.wantVary)
+			}
+			if got, want := rec.Header().Get("Content-Length"), strconv.Itoa(rec.Body.Len()); test.wantEncoding != "" && got != want {
+				t.Errorf("Content-Length = %s; want %s", got, want)
+			}
+			if got := decodeBody(t, test.wantEncoding, rec.Body); got != test.body {
+				t.Errorf("decoded body = %q; want %q", got, test.body)
+			}
+		})
+	}
+}
+
+func TestCompressionCached(t *testing.T) {
+	long := strings.Repeat("hello, world\n", 100)
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		CompressMinSize:  256,
+		Cache:            NewCache(time.Minute),
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{Other: []*Representation{TextRepresentation(long)}}, nil
+	})
+	for i, acceptEncoding := range []string{"gzip", "", "gzip"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Content-Encoding"); got != acceptEncoding {
+			t.Errorf("request #%d Content-Encoding = %q; want %q", i+1, got, acceptEncoding)
+		}
+		if got := decodeBody(t, acceptEncoding, rec.Body); got != long {
+			t.Errorf("request #%d decoded body = %q; want %q", i+1, got, long)
+		}
+	}
+}
+
+func TestCompressionETag(t *testing.T) {
+	long := strings.Repeat("hello, world\n", 100)
+	cfg := &Config[*http.Request]{TransformRequest: identity, CompressMinSize: 256}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{
+			ETag:  "v1",
+			Other: []*Representation{TextRepresentation(long)},
+		}, nil
+	})
+	tests := []struct {
+		acceptEncoding string
+		ifNoneMatch    string
+		wantCode       int
+		wantETag       string
+	}{
+		{acceptEncoding: "", wantCode: http.StatusOK, wantETag: `"v1"`},
+		{acceptEncoding: "gzip", wantCode: http.StatusOK, wantETag: `W/"v1"`},
+		{acceptEncoding: "gzip", ifNoneMatch: `W/"v1"`, wantCode: http.StatusNotModified, wantETag: `W/"v1"`},
+		{acceptEncoding: "", ifNoneMatch: `"v1"`, wantCode: http.StatusNotModified, wantETag: `"v1"`},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", test.acceptEncoding)
+		}
+		if test.ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", test.ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != test.wantCode {
+			t.Errorf("Accept-Encoding: %s, If-None-Match: %s: status = %d; want %d", test.acceptEncoding, test.ifNoneMatch, rec.Code, test.wantCode)
+		}
+		if got := rec.Header().Get("ETag"); got != test.wantETag {
+			t.Errorf("Accept-Encoding: %s, If-None-Match: %s: ETag = %s; want %s", test.acceptEncoding, test.ifNoneMatch, got, test.wantETag)
+		}
+	}
+}
+
+func decodeBody(tb testing.TB, encoding string, body io.Reader) string {
+	tb.Helper()
+	var r io.Reader
+	var err error
+	switch encoding {
+	case "gzip":
+		r, err = gzip.NewReader(body)
+	case "deflate":
+		r, err = zlib.NewReader(body)
+	default:
+		r = body
+	}
+	if err != nil {
+		tb.Fatal(err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,252 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"zombiezen.com/go/bass/turbostream"
+)
+
+const (
+	etagHeaderName            = "ETag"
+	cacheControlHeaderName    = "Cache-Control"
+	lastModifiedHeaderName    = "Last-Modified"
+	ifNoneMatchHeaderName     = "If-None-Match"
+	ifModifiedSinceHeaderName = "If-Modified-Since"
+)
+
+// setCacheHeaders sets the ETag, Cache-Control, and Last-Modified headers
+// in h from the corresponding fields of resp.
+// mediaType is the media type of the representation being sent,
+// lang is the language tag of its localized template (if any),
+// and negotiated is true if resp has other representations.
+func (resp *Response) setCacheHeaders(h http.Header, mediaType, lang string, negotiated bool) {
+	if etag := resp.etag(mediaType, lang, negotiated); etag != "" {
+		h.Set(etagHeaderName, etag)
+	}
+	if resp.CacheControl != "" {
+		h.Set(cacheControlHeaderName, resp.CacheControl)
+	}
+	if !resp.LastModified.IsZero() {
+		h.Set(lastModifiedHeaderName, resp.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// etag returns the formatted entity tag for the representation of resp
+// with the given media type and localized template language
+// or the empty string if it has none.
+// If negotiated is true, then an explicit ETag is suffixed with the media type
+// and if lang is not empty, then it is suffixed with the language
+// so that each representation has a distinct tag,
+// as RFC 9110 section 8.8.3 requires.
+func (resp *Response) etag(mediaType, lang string, negotiated bool) string {
+	if resp.ETag != "" {
+		tag := formatETag(resp.ETag)
+		var suffix string
+		if negotiated && mediaType != "" {
+			suffix += ";" + mediaType
+		}
+		if lang != "" {
+			suffix += ";" + lang
+		}
+		if suffix == "" {
+			return tag
+		}
+		// Media types and language tags are tokens,
+		// so they are valid etagc characters.
+		return tag[:len(tag)-1] + suffix + `"`
+	}
+	if resp.ETagKey == "" {
+		return ""
+	}
+	h := sha256.New()
+	io.WriteString(h, mediaType)
+	h.Write([]byte{0})
+	io.WriteString(h, resp.templateFor(mediaType))
+	h.Write([]byte{0})
+	io.WriteString(h, lang)
+	h.Write([]byte{0})
+	io.WriteString(h, resp.ETagKey)
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// templateFor returns the name of the template
+// that renders the representation of resp with the given media type
+// or the empty string if the representation does not use a template.
+func (resp *Response) templateFor(mediaType string) string {
+	switch mediaType {
+	case htmlType:
+		return resp.HTMLTemplate
+	case turbostream.ContentType:
+		return resp.TurboStreamTemplate
+	case plainType:
+		return resp.TextTemplate
+	default:
+		return ""
+	}
+}
+
+// statusCode returns resp.StatusCode or 200 (OK) if it is zero.
+func (resp *Response) statusCode() int {
+	if resp.StatusCode == 0 {
+		return http.StatusOK
+	}
+	return resp.StatusCode
+}
+
+// withContentETag returns a copy of repr with an ETag header
+// derived from a hash of its body.
+// The tag is weak because the body may be compressed before it is sent.
+func withContentETag(repr *Representation) (*Representation, error) {
+	body, err := io.ReadAll(repr.Body)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	header := repr.Header.Clone()
+	header.Set(etagHeaderName, `W/"`+hex.EncodeToString(sum[:16])+`"`)
+	return &Representation{
+		Header: header,
+		Body:   io.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+// formatETag quotes tag if it is not already
+// a quoted strong or weak entity tag.
+func formatETag(tag string) string {
+	if strings.HasPrefix(tag, `"`) || strings.HasPrefix(tag, `W/"`) {
+		return tag
+	}
+	return `"` + tag + `"`
+}
+
+// conditions holds the conditional request headers of a GET or HEAD request.
+// The zero value has no conditions.
+type conditions struct {
+	ifNoneMatch     string
+	ifModifiedSince string
+}
+
+// requestConditions returns the conditions for r.
+// Conditions are only evaluated for GET and HEAD requests.
+func requestConditions(r *http.Request) conditions {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return conditions{}
+	}
+	return conditions{
+		ifNoneMatch:     r.Header.Get(ifNoneMatchHeaderName),
+		ifModifiedSince: r.Header.Get(ifModifiedSinceHeaderName),
+	}
+}
+
+// notModified reports whether a response with the validators in h
+// should be replaced by a 304 (Not Modified) response,
+// following the precedence rules in RFC 9110 section 13.2.2:
+// If-Modified-Since is ignored if If-None-Match is present.
+func (c conditions) notModified(h http.Header) bool {
+	if c.ifNoneMatch != "" {
+		etag := h.Get(etagHeaderName)
+		return etag != "" && etagListMatches(c.ifNoneMatch, etag)
+	}
+	if c.ifModifiedSince != "" {
+		lastModified, err := http.ParseTime(h.Get(lastModifiedHeaderName))
+		if err != nil {
+			return false
+		}
+		since, err := http.ParseTime(c.ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// etagListMatches reports whether any entity tag in an If-None-Match list
+// is a weak match for etag.
+func etagListMatches(list, etag string) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for {
+		list = strings.TrimLeft(list, " \t,")
+		if list == "" {
+			return false
+		}
+		tag, rest, ok := scanETag(list)
+		if !ok {
+			return false
+		}
+		if strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+		list = rest
+	}
+}
+
+// scanETag returns the entity tag at the beginning of s.
+func scanETag(s string) (tag, rest string, ok bool) {
+	start := 0
+	if strings.HasPrefix(s, "W/") {
+		start = 2
+	}
+	if len(s) <= start || s[start] != '"' {
+		return "", "", false
+	}
+	end := strings.IndexByte(s[start+1:], '"')
+	if end == -1 {
+		return "", "", false
+	}
+	end += start + 2
+	return s[:end], s[end:], true
+}
+
+// writeNotModified sends a 304 (Not Modified) response.
+// Only the headers that a 304 response is expected to carry
+// are copied from h.
+func writeNotModified(w http.ResponseWriter, h http.Header) {
+	dst := w.Header()
+	for _, k := range []string{etagHeaderName, cacheControlHeaderName, lastModifiedHeaderName, "Expires"} {
+		if v := h.Values(k); len(v) > 0 {
+			dst[http.CanonicalHeaderKey(k)] = v
+		}
+	}
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// hasNoStore reports whether a Cache-Control value
+// forbids storing the response in a shared cache.
+func hasNoStore(cacheControl string) bool {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name := strings.TrimSpace(directive)
+		if i := strings.IndexByte(name, '='); i >= 0 {
+			name = strings.TrimSpace(name[:i])
+		}
+		if strings.EqualFold(name, "no-store") || strings.EqualFold(name, "private") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,370 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditionalGet(t *testing.T) {
+	lastModified := time.Date(2026, time.March, 1, 12, 30, 15, 500, time.UTC)
+	renders := 0
+	cfg := &Config[*http.Request]{TransformRequest: identity}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		resp := &Response{
+			ETag:         "v1",
+			CacheControl: "max-age=60",
+			LastModified: lastModified,
+		}
+		resp.RepresentationFunc("text/plain", func(ctx context.Context, rc *RenderContext) (*Representation, error) {
+			renders++
+			return TextRepresentation("hello"), nil
+		})
+		return resp, nil
+	})
+
+	tests := []struct {
+		name            string
+		method          string
+		ifNoneMatch     string
+		ifModifiedSince string
+		wantCode        int
+	}{
+		{
+			name:     "Unconditional",
+			method:   http.MethodGet,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:        "IfNoneMatch",
+			method:      http.MethodGet,
+			ifNoneMatch: `"v1"`,
+			wantCode:    http.StatusNotModified,
+		},
+		{
+			name:        "IfNoneMatchList",
+			method:      http.MethodGet,
+			ifNoneMatch: `"v0", W/"v1"`,
+			wantCode:    http.StatusNotModified,
+		},
+		{
+			name:        "IfNoneMatchStar",
+			method:      http.MethodHead,
+			ifNoneMatch: `*`,
+			wantCode:    http.StatusNotModified,
+		},
+		{
+			name:        "IfNoneMatchStale",
+			method:      http.MethodGet,
+			ifNoneMatch: `"v0"`,
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:            "IfNoneMatchTakesPrecedence",
+			method:          http.MethodGet,
+			ifNoneMatch:     `"v0"`,
+			ifModifiedSince: lastModified.Format(http.TimeFormat),
+			wantCode:        http.StatusOK,
+		},
+		{
+			name:            "IfModifiedSinceSame",
+			method:          http.MethodGet,
+			ifModifiedSince: lastModified.Format(http.TimeFormat),
+			wantCode:        http.StatusNotModified,
+		},
+		{
+			name:            "IfModifiedSinceBefore",
+			method:          http.MethodGet,
+			ifModifiedSince: lastModified.Add(-time.Hour).Format(http.TimeFormat),
+			wantCode:        http.StatusOK,
+		},
+		{
+			name:        "Post",
+			method:      http.MethodPost,
+			ifNoneMatch: `"v1"`,
+			wantCode:    http.StatusOK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			renders = 0
+			req := httptest.NewRequest(test.method, "/", nil)
+			if test.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", test.ifNoneMatch)
+			}
+			if test.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", test.ifModifiedSince)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != test.wantCode {
+				t.Errorf("status = %d; want %d", rec.Code, test.wantCode)
+			}
+			if got, want := rec.Header().Get("ETag"), `"v1"`; got != want {
+				t.Errorf("ETag = %q; want %q", got, want)
+			}
+			if got, want := rec.Header().Get("Cache-Control"), "max-age=60"; got != want {
+				t.Errorf("Cache-Control = %q; want %q", got, want)
+			}
+			if got, want := rec.Header().Get("Last-Modified"), "Sun, 01 Mar 2026 12:30:15 GMT"; got != want {
+				t.Errorf("Last-Modified = %q; want %q", got, want)
+			}
+			if test.wantCode == http.StatusNotModified {
+				if renders != 0 {
+					t.Errorf("representation rendered %d times for 304 response", renders)
+				}
+				if rec.Body.Len() > 0 {
+					t.Errorf("body = %q; want empty", rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestConditionalGetCached(t *testing.T) {
+	cache := NewCache(time.Minute)
+	calls := 0
+	cfg := &Config[*http.Request]{TransformRequest: identity, Cache: cache}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		calls++
+		return &Response{
+			ETag:  `W/"v1"`,
+			Other: []*Representation{TextRepresentation("hello")},
+		}, nil
+	})
+	for i, want := range []int{http.StatusOK, http.StatusNotModified} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if i > 0 {
+			req.Header.Set("If-None-Match", `"v1"`)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("request #%d status = %d; want %d", i+1, rec.Code, want)
+		}
+		if got := rec.Header().Get("ETag"); got != `W/"v1"` {
+			t.Errorf("request #%d ETag = %q; want %q", i+1, got, `W/"v1"`)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Func called %d times; want 1", calls)
+	}
+}
+
+func TestNegotiatedETag(t *testing.T) {
+	cfg := &Config[*http.Request]{TransformRequest: identity}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{
+			ETag:      "v1",
+			JSONValue: map[string]string{"greeting": "hello"},
+			Other:     []*Representation{TextRepresentation("hello")},
+		}, nil
+	})
+	do := func(accept, ifNoneMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	jsonTag := do("application/json", "").Header().Get("ETag")
+	if want := `"v1;application/json"`; jsonTag != want {
+		t.Errorf("ETag for JSON = %q; want %q", jsonTag, want)
+	}
+	textTag := do("text/plain", "").Header().Get("ETag")
+	if want := `"v1;text/plain"`; textTag != want {
+		t.Errorf("ETag for text = %q; want %q", textTag, want)
+	}
+	if rec := do("application/json", jsonTag); rec.Code != http.StatusNotModified {
+		t.Errorf("GET JSON with If-None-Match: %s = %d; want %d", jsonTag, rec.Code, http.StatusNotModified)
+	}
+	if rec := do("text/plain", jsonTag); rec.Code != http.StatusOK {
+		t.Errorf("GET text with If-None-Match: %s = %d; want %d", jsonTag, rec.Code, http.StatusOK)
+	}
+}
+
+func TestCacheControlNoStore(t *testing.T) {
+	cache := NewCache(time.Minute)
+	calls := 0
+	cfg := &Config[*http.Request]{TransformRequest: identity, Cache: cache}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		calls++
+		return &Response{
+			CacheControl: "private, max-age=60",
+			Other:        []*Representation{TextRepresentation("hello")},
+		}, nil
+	})
+	for i := 0; i < 2; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	if calls != 2 {
+		t.Errorf("Func called %d times; want 2", calls)
+	}
+}
+
+func TestAutoETag(t *testing.T) {
+	renders := 0
+	body := "hello"
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		AutoETag:         true,
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		resp := new(Response)
+		resp.RepresentationFunc("text/plain", func(ctx context.Context, rc *RenderContext) (*Representation, error) {
+			renders++
+			return TextRepresentation(body), nil
+		})
+		return resp, nil
+	})
+	do := func(method, ifNoneMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, "")
+	if rec.Code != http.StatusOK || rec.Body.String() != body {
+		t.Fatalf("GET = %d %q; want %d %q", rec.Code, rec.Body.String(), http.StatusOK, body)
+	}
+	etag := rec.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("ETag = %q; want weak entity tag", etag)
+	}
+
+	if rec := do(http.MethodGet, etag); rec.Code != http.StatusNotModified {
+		t.Errorf("GET with If-None-Match: %s = %d; want %d", etag, rec.Code, http.StatusNotModified)
+	} else if got := rec.Header().Get("ETag"); got != etag {
+		t.Errorf("304 ETag = %q; want %q", got, etag)
+	}
+
+	body = "goodbye"
+	if rec := do(http.MethodGet, etag); rec.Code != http.StatusOK {
+		t.Errorf("GET with stale If-None-Match = %d; want %d", rec.Code, http.StatusOK)
+	} else if got := rec.Header().Get("ETag"); got == etag {
+		t.Errorf("ETag did not change after body changed (still %q)", got)
+	}
+
+	rendersBeforeHead := renders
+	rec = do(http.MethodHead, "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("HEAD = %d; want %d", rec.Code, http.StatusOK)
+	}
+	if got, want := rec.Header().Get("Content-Type"), "text/plain"; got != want {
+		t.Errorf("HEAD Content-Type = %q; want %q", got, want)
+	}
+	if renders != rendersBeforeHead {
+		t.Error("HEAD request rendered the representation")
+	}
+}
+
+func TestAutoETagKeepsExplicitETag(t *testing.T) {
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		AutoETag:         true,
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{
+			ETag:  "v1",
+			Other: []*Representation{TextRepresentation("hello")},
+		}, nil
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Values("ETag"); len(got) != 1 || got[0] != `"v1"` {
+		t.Errorf("ETag = %q; want [%q]", got, `"v1"`)
+	}
+}
+
+func TestETagKey(t *testing.T) {
+	renders := 0
+	key := "post1@v1"
+	cfg := &Config[*http.Request]{TransformRequest: identity}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		resp := &Response{ETagKey: key}
+		resp.RepresentationFunc("text/plain", func(ctx context.Context, rc *RenderContext) (*Representation, error) {
+			renders++
+			return TextRepresentation("hello"), nil
+		})
+		return resp, nil
+	})
+	do := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET = %d; want %d", rec.Code, http.StatusOK)
+	}
+	etag := rec.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("ETag = %q; want weak entity tag", etag)
+	}
+
+	rendersBefore := renders
+	if rec := do(etag); rec.Code != http.StatusNotModified {
+		t.Errorf("GET with If-None-Match: %s = %d; want %d", etag, rec.Code, http.StatusNotModified)
+	}
+	if renders != rendersBefore {
+		t.Error("304 response rendered the representation")
+	}
+
+	key = "post1@v2"
+	if rec := do(etag); rec.Code != http.StatusOK {
+		t.Errorf("GET with stale If-None-Match = %d; want %d", rec.Code, http.StatusOK)
+	} else if got := rec.Header().Get("ETag"); got == etag {
+		t.Errorf("ETag did not change after key changed (still %q)", got)
+	}
+
+	page := (&Response{HTMLTemplate: "page.html", ETagKey: key}).etag(htmlType, "", false)
+	other := (&Response{HTMLTemplate: "other.html", ETagKey: key}).etag(htmlType, "", false)
+	if page == other {
+		t.Errorf("ETag for different templates with same key = %q", page)
+	}
+	withText := (&Response{HTMLTemplate: "page.html", TextTemplate: "page.txt", ETagKey: key}).etag(htmlType, "", true)
+	if withText != page {
+		t.Errorf("HTML ETag changed from %q to %q after adding a text template", page, withText)
+	}
+	if text := (&Response{HTMLTemplate: "page.html", TextTemplate: "page.txt", ETagKey: key}).etag(plainType, "", true); text == withText {
+		t.Errorf("ETag for HTML and text representations = %q", text)
+	}
+	if got := (&Response{ETag: "v1", ETagKey: key}).etag(htmlType, "", false); got != `"v1"` {
+		t.Errorf("ETag with explicit ETag = %q; want %q", got, `"v1"`)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"zombiezen.com/go/bass/flashkv"
+)
+
+// ErrInvalidCookieSignature is returned by [SignedCookie]
+// for a cookie that was not signed by the [Config] CookieCodec.
+var ErrInvalidCookieSignature = errors.New("invalid cookie signature")
+
+type cookieCodecContextKey struct{}
+
+// SignedCookie returns the named cookie from a request served by a [Handler]
+// after verifying that it was set with [Response] SetSignedCookies.
+// The returned cookie's Value is the value originally given to SetSignedCookies.
+// If the cookie is not present, SignedCookie returns [http.ErrNoCookie].
+// If the cookie was not signed by the [Config] CookieCodec
+// (or the Handler has no CookieCodec),
+// SignedCookie returns [ErrInvalidCookieSignature].
+func SignedCookie(r *http.Request, name string) (*http.Cookie, error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return nil, err
+	}
+	codec, _ := r.Context().Value(cookieCodecContextKey{}).(*flashkv.Codec)
+	value, ok := verifyCookieValue(codec, name, c.Value)
+	if !ok {
+		return nil, fmt.Errorf("cookie %s: %w", name, ErrInvalidCookieSignature)
+	}
+	c.Value = value
+	return c, nil
+}
+
+func withCookieCodec(ctx context.Context, codec *flashkv.Codec) context.Context {
+	return context.WithValue(ctx, cookieCodecContextKey{}, codec)
+}
+
+// signedCookieKey is the name of the single [flashkv.Values] entry
+// that holds a signed cookie's value.
+const signedCookieKey = "v"
+
+// signCookie returns a copy of c whose value is encoded with codec.
+// The codec binds the value to the cookie's name,
+// so a signed value cannot be moved to another cookie.
+func signCookie(codec *flashkv.Codec, c *http.Cookie) (*http.Cookie, error) {
+	value, err := codec.Encode(c.Name, flashkv.Values{signedCookieKey: c.Value})
+	if err != nil {
+		return nil, fmt.Errorf("sign cookie %s: %w", c.Name, err)
+	}
+	signed := new(http.Cookie)
+	*signed = *c
+	signed.Value = value
+	return signed, nil
+}
+
+// verifyCookieValue returns the original value of a cookie
+// signed by codec.
+// It returns false if codec is nil.
+func verifyCookieValue(codec *flashkv.Codec, name, value string) (string, bool) {
+	if codec == nil {
+		return "", false
+	}
+	v, err := codec.Decode(name, value)
+	if err != nil {
+		return "", false
+	}
+	decoded, ok := v[signedCookieKey]
+	return decoded, ok
+}
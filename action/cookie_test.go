@@ -0,0 +1,190 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"zombiezen.com/go/bass/flashkv"
+)
+
+func TestSignedCookies(t *testing.T) {
+	oldKey := flashkv.Key{Version: 1, Secret: bytes.Repeat([]byte{1}, 32)}
+	newKey := flashkv.Key{Version: 2, Secret: bytes.Repeat([]byte{2}, 32)}
+	const returnTo = "/account?tab=billing; x=\"y\""
+
+	newHandler := func(keys []flashkv.Key, got *string, gotErr *error) *Handler[*http.Request] {
+		cfg := &Config[*http.Request]{
+			TransformRequest: identity,
+			CookieCodec:      newTestCookieCodec(t, keys...),
+		}
+		return cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			if r.Method == http.MethodPost {
+				return &Response{
+					SetSignedCookies: []*http.Cookie{{Name: "return_to", Value: returnTo, Path: "/"}},
+				}, nil
+			}
+			c, err := SignedCookie(r, "return_to")
+			*gotErr = err
+			if err == nil {
+				*got = c.Value
+			}
+			return nil, nil
+		})
+	}
+	var got string
+	var gotErr error
+
+	// Sign with the old key.
+	rec := httptest.NewRecorder()
+	newHandler([]flashkv.Key{oldKey}, &got, &gotErr).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("got %d cookies; want 1", len(cookies))
+	}
+	signed := cookies[0]
+	if signed.Value == returnTo {
+		t.Fatal("cookie value was not signed")
+	}
+	if signed.Path != "/" {
+		t.Errorf("cookie path = %q; want %q", signed.Path, "/")
+	}
+
+	tests := []struct {
+		name    string
+		keys    []flashkv.Key
+		cookie  *http.Cookie
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "SameKey",
+			keys:   []flashkv.Key{oldKey},
+			cookie: signed,
+			want:   returnTo,
+		},
+		{
+			name:   "Rotated",
+			keys:   []flashkv.Key{newKey, oldKey},
+			cookie: signed,
+			want:   returnTo,
+		},
+		{
+			name:    "Retired",
+			keys:    []flashkv.Key{newKey},
+			cookie:  signed,
+			wantErr: ErrInvalidCookieSignature,
+		},
+		{
+			name:    "NoKeys",
+			cookie:  signed,
+			wantErr: ErrInvalidCookieSignature,
+		},
+		{
+			name:    "Tampered",
+			keys:    []flashkv.Key{oldKey},
+			cookie:  &http.Cookie{Name: "return_to", Value: "L2V2aWw" + signed.Value[len("L2V2aWw"):]},
+			wantErr: ErrInvalidCookieSignature,
+		},
+		{
+			name:    "Unsigned",
+			keys:    []flashkv.Key{oldKey},
+			cookie:  &http.Cookie{Name: "return_to", Value: "/evil"},
+			wantErr: ErrInvalidCookieSignature,
+		},
+		{
+			name:    "Missing",
+			keys:    []flashkv.Key{oldKey},
+			wantErr: http.ErrNoCookie,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr = "", nil
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.cookie != nil {
+				req.AddCookie(&http.Cookie{Name: test.cookie.Name, Value: test.cookie.Value})
+			}
+			newHandler(test.keys, &got, &gotErr).ServeHTTP(httptest.NewRecorder(), req)
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("SignedCookie(...) error = %v; want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatal("SignedCookie:", gotErr)
+			}
+			if got != test.want {
+				t.Errorf("SignedCookie(...).Value = %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSignedCookieName(t *testing.T) {
+	codec := newTestCookieCodec(t, flashkv.Key{Secret: bytes.Repeat([]byte{1}, 32)})
+	signed, err := signCookie(codec, &http.Cookie{Name: "a", Value: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := verifyCookieValue(codec, "b", signed.Value); ok {
+		t.Error("cookie signed for name \"a\" verified as \"b\"")
+	}
+}
+
+func TestSignedCookiesWithoutKeys(t *testing.T) {
+	var reported error
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		ReportError: func(ctx context.Context, err error) {
+			reported = err
+		},
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{
+			SetSignedCookies: []*http.Cookie{{Name: "pref", Value: "dark"}},
+		}, nil
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if cookies := rec.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("cookies = %v; want none", cookies)
+	}
+	if reported == nil {
+		t.Error("no error reported")
+	}
+}
+
+// newTestCookieCodec returns a codec with the given keys
+// or nil if there are no keys.
+func newTestCookieCodec(tb testing.TB, keys ...flashkv.Key) *flashkv.Codec {
+	tb.Helper()
+	if len(keys) == 0 {
+		return nil
+	}
+	codec, err := flashkv.NewCodec(&flashkv.Options{Keys: keys})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return codec
+}
@@ -0,0 +1,313 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"zombiezen.com/go/bass/flashkv"
+)
+
+// Defaults for [CSRF] fields.
+const (
+	DefaultCSRFCookieName = "_csrf"
+	DefaultCSRFFieldName  = "csrf_token"
+	DefaultCSRFHeaderName = "X-CSRF-Token"
+)
+
+const (
+	csrfSecretSize = 32
+	// maxCSRFFieldSize is the largest form field value
+	// that is read as a token from a multipart body.
+	maxCSRFFieldSize = 1 << 10
+)
+
+// CSRF configures protection against [cross-site request forgery] for a [Handler].
+//
+// Each client is given a random secret in an HttpOnly cookie
+// signed by the [Config] CookieCodec.
+// Requests with methods other than GET, HEAD, OPTIONS, and TRACE
+// must include a token derived from the secret,
+// either in the header named by HeaderName
+// or in the form field named by FieldName.
+// For multipart/form-data bodies, the token field must be the first part of the form.
+// Such requests must also not have an Origin header
+// from an origin other than the request's own or one in TrustedOrigins.
+// Requests that fail these checks are served
+// a 403 (Forbidden) error through the [Config] TransformError function
+// without calling the [Func].
+//
+// Templates rendered by the Handler can call csrfField
+// to emit a hidden form input containing the token
+// and csrfToken to obtain the token as a string
+// (for example, for a <meta name="csrf-token"> element).
+// A Func can obtain the token with [CSRFToken].
+// Responses that include a token are never stored in a [Cache].
+//
+// [cross-site request forgery]: https://developer.mozilla.org/en-US/docs/Glossary/CSRF
+type CSRF struct {
+	// CookieName is the name of the cookie that holds the client's secret.
+	// If it is empty, then [DefaultCSRFCookieName] is used.
+	CookieName string
+	// CookiePath is the path attribute of the cookie.
+	// If it is empty, then "/" is used.
+	CookiePath string
+	// FieldName is the name of the form field that holds the token.
+	// If it is empty, then [DefaultCSRFFieldName] is used.
+	FieldName string
+	// HeaderName is the name of the request header that holds the token.
+	// If it is empty, then [DefaultCSRFHeaderName] is used.
+	HeaderName string
+
+	// TrustedOrigins is a list of additional origins
+	// (like "https://example.com") that are allowed to submit requests.
+	TrustedOrigins []string
+}
+
+func (c *CSRF) cookieName() string {
+	if c.CookieName == "" {
+		return DefaultCSRFCookieName
+	}
+	return c.CookieName
+}
+
+func (c *CSRF) cookiePath() string {
+	if c.CookiePath == "" {
+		return "/"
+	}
+	return c.CookiePath
+}
+
+func (c *CSRF) fieldName() string {
+	if c.FieldName == "" {
+		return DefaultCSRFFieldName
+	}
+	return c.FieldName
+}
+
+func (c *CSRF) headerName() string {
+	if c.HeaderName == "" {
+		return DefaultCSRFHeaderName
+	}
+	return c.HeaderName
+}
+
+// csrfState is the per-request CSRF state.
+type csrfState struct {
+	secret    []byte
+	fieldName string
+	used      int32 // accessed atomically
+}
+
+type csrfContextKey struct{}
+
+// CSRFToken returns a token for the request that can be sent back
+// in the [CSRF] HeaderName request header or FieldName form field.
+// It returns the empty string if ctx is not from a [Handler]
+// with CSRF protection enabled.
+// Each call returns a different token
+// so that responses do not contain a constant secret.
+func CSRFToken(ctx context.Context) string {
+	return csrfStateFromContext(ctx).token()
+}
+
+func csrfStateFromContext(ctx context.Context) *csrfState {
+	st, _ := ctx.Value(csrfContextKey{}).(*csrfState)
+	return st
+}
+
+// token returns a new masked token for st.
+// It returns the empty string if st is nil.
+func (st *csrfState) token() string {
+	if st == nil {
+		return ""
+	}
+	atomic.StoreInt32(&st.used, 1)
+	// Masking the secret with a random pad
+	// prevents compression side-channel attacks like BREACH.
+	buf := make([]byte, 2*csrfSecretSize)
+	pad, masked := buf[:csrfSecretSize], buf[csrfSecretSize:]
+	if _, err := rand.Read(pad); err != nil {
+		panic(err)
+	}
+	xorBytes(masked, pad, st.secret)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// tokenUsed reports whether a token has been issued for st.
+func (st *csrfState) tokenUsed() bool {
+	return st != nil && atomic.LoadInt32(&st.used) != 0
+}
+
+// templateFuncs returns the CSRF template functions.
+func (st *csrfState) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"csrfToken": st.token,
+		"csrfField": func() template.HTML {
+			return template.HTML(`<input type="hidden" name="` +
+				template.HTMLEscapeString(st.fieldName) + `" value="` +
+				st.token() + `">`)
+		},
+	}
+}
+
+var errCSRF = WithStatusCode(http.StatusForbidden, errors.New("invalid CSRF token"))
+
+// protect verifies r and returns a copy of r
+// whose context holds the request's CSRF state.
+// If the client does not have a valid cookie,
+// then protect generates a new secret and adds the cookie to w.
+func (c *CSRF) protect(w http.ResponseWriter, r *http.Request, codec *flashkv.Codec, isTLS bool) (*http.Request, error) {
+	if codec == nil {
+		return r, WithStatusCode(http.StatusInternalServerError, errors.New("csrf: no cookie codec configured"))
+	}
+	st := &csrfState{fieldName: c.fieldName()}
+	if cookie, err := r.Cookie(c.cookieName()); err == nil {
+		if secret, ok := verifyCookieValue(codec, cookie.Name, cookie.Value); ok && len(secret) == csrfSecretSize {
+			st.secret = []byte(secret)
+		}
+	}
+	hadSecret := st.secret != nil
+	if !hadSecret {
+		st.secret = make([]byte, csrfSecretSize)
+		if _, err := rand.Read(st.secret); err != nil {
+			return r, fmt.Errorf("csrf: %w", err)
+		}
+		cookie, err := signCookie(codec, &http.Cookie{
+			Name:     c.cookieName(),
+			Value:    string(st.secret),
+			Path:     c.cookiePath(),
+			Secure:   isTLS,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		if err != nil {
+			return r, fmt.Errorf("csrf: %w", err)
+		}
+		http.SetCookie(w, cookie)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), csrfContextKey{}, st))
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return r, nil
+	}
+	if !c.originAllowed(r, isTLS) {
+		return r, errCSRF
+	}
+	if !hadSecret {
+		// A new secret cannot match any token.
+		return r, errCSRF
+	}
+	token := r.Header.Get(c.headerName())
+	if token == "" {
+		var err error
+		token, err = c.formToken(r)
+		if err != nil {
+			return r, err
+		}
+	}
+	if !st.verifyToken(token) {
+		return r, errCSRF
+	}
+	return r, nil
+}
+
+// originAllowed reports whether r's Origin header, if present,
+// is r's own origin or one of c.TrustedOrigins.
+func (c *CSRF) originAllowed(r *http.Request, isTLS bool) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	scheme := "http"
+	if isTLS {
+		scheme = "https"
+	}
+	if strings.EqualFold(origin, scheme+"://"+r.Host) {
+		return true
+	}
+	for _, trusted := range c.TrustedOrigins {
+		if strings.EqualFold(origin, trusted) {
+			return true
+		}
+	}
+	return false
+}
+
+// formToken reads the token from r's form body.
+// Multipart bodies are restored after reading the first part
+// so that the rest of the form can be parsed normally.
+func (c *CSRF) formToken(r *http.Request) (string, error) {
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return "", WithStatusCode(http.StatusBadRequest, fmt.Errorf("csrf: %w", err))
+		}
+		return r.PostForm.Get(c.fieldName()), nil
+	case "multipart/form-data":
+		if r.Body == nil || params["boundary"] == "" {
+			return "", nil
+		}
+		consumed := new(bytes.Buffer)
+		mr := multipart.NewReader(io.TeeReader(r.Body, consumed), params["boundary"])
+		var token string
+		if part, err := mr.NextPart(); err == nil && part.FormName() == c.fieldName() {
+			value, _ := io.ReadAll(io.LimitReader(part, maxCSRFFieldSize))
+			token = string(value)
+		}
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(consumed, r.Body), r.Body}
+		return token, nil
+	default:
+		return "", nil
+	}
+}
+
+// verifyToken reports whether token was created from st's secret.
+func (st *csrfState) verifyToken(token string) bool {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) != 2*csrfSecretSize {
+		return false
+	}
+	pad, masked := buf[:csrfSecretSize], buf[csrfSecretSize:]
+	xorBytes(masked, masked, pad)
+	return subtle.ConstantTimeCompare(masked, st.secret) == 1
+}
+
+// xorBytes sets dst[i] = x[i] ^ y[i] for each byte of dst.
+func xorBytes(dst, x, y []byte) {
+	for i := range dst {
+		dst[i] = x[i] ^ y[i]
+	}
+}
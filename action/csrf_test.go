@@ -0,0 +1,224 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"zombiezen.com/go/bass/flashkv"
+)
+
+var csrfFieldPattern = regexp.MustCompile(`<input type="hidden" name="csrf_token" value="([^"]+)">`)
+
+func TestCSRF(t *testing.T) {
+	templateFiles := fstest.MapFS{
+		"base.html": {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+		"form.html": {Data: []byte(`{{ define "content" }}<form method="POST">{{ csrfField }}</form>{{ end }}`)},
+	}
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		TemplateFiles:    templateFiles,
+		CookieCodec:      newTestCookieCodec(t, flashkv.Key{Secret: bytes.Repeat([]byte{0x42}, 32)}),
+		CSRF: &CSRF{
+			TrustedOrigins: []string{"https://trusted.example.com"},
+		},
+	}
+	var lastName string
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		if r.Method == http.MethodGet {
+			return &Response{HTMLTemplate: "form.html"}, nil
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			form, cleanup, err := ParseMultipartForm(r, nil)
+			if err != nil {
+				return nil, err
+			}
+			defer cleanup()
+			lastName = form.Value.Get("name")
+		} else {
+			lastName = r.FormValue("name")
+		}
+		return &Response{Other: []*Representation{TextRepresentation("ok")}}, nil
+	})
+
+	// Fetch the form to get a cookie and token.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d; want %d", rec.Code, http.StatusOK)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultCSRFCookieName {
+		t.Fatalf("GET cookies = %v; want one %s cookie", cookies, DefaultCSRFCookieName)
+	}
+	cookie := cookies[0]
+	if !cookie.HttpOnly {
+		t.Error("CSRF cookie is not HttpOnly")
+	}
+	m := csrfFieldPattern.FindStringSubmatch(rec.Body.String())
+	if m == nil {
+		t.Fatalf("GET body = %q; want csrf_token hidden field", rec.Body.String())
+	}
+	token := m[1]
+
+	post := func(t *testing.T, body string, setup func(r *http.Request)) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, "http://example.com/", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.AddCookie(cookie)
+		if setup != nil {
+			setup(r)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+
+	t.Run("FormField", func(t *testing.T) {
+		lastName = ""
+		rec := post(t, url.Values{"csrf_token": {token}, "name": {"Alice"}}.Encode(), nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusOK)
+		}
+		if lastName != "Alice" {
+			t.Errorf("name = %q; want %q", lastName, "Alice")
+		}
+	})
+	t.Run("Header", func(t *testing.T) {
+		rec := post(t, "name=Bob", func(r *http.Request) {
+			r.Header.Set(DefaultCSRFHeaderName, token)
+		})
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusOK)
+		}
+	})
+	t.Run("Multipart", func(t *testing.T) {
+		lastName = ""
+		body := new(bytes.Buffer)
+		mw := multipart.NewWriter(body)
+		mw.WriteField("csrf_token", token)
+		mw.WriteField("name", "Carol")
+		mw.Close()
+		rec := post(t, body.String(), func(r *http.Request) {
+			r.Header.Set("Content-Type", mw.FormDataContentType())
+		})
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusOK)
+		}
+		if lastName != "Carol" {
+			t.Errorf("name = %q; want %q", lastName, "Carol")
+		}
+	})
+	t.Run("TrustedOrigin", func(t *testing.T) {
+		rec := post(t, url.Values{"csrf_token": {token}}.Encode(), func(r *http.Request) {
+			r.Header.Set("Origin", "https://trusted.example.com")
+		})
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusOK)
+		}
+	})
+
+	forbidden := []struct {
+		name  string
+		body  string
+		setup func(r *http.Request)
+	}{
+		{
+			name: "MissingToken",
+			body: "name=Mallory",
+		},
+		{
+			name: "WrongToken",
+			body: url.Values{"csrf_token": {strings.Repeat("A", len(token))}}.Encode(),
+		},
+		{
+			name: "MissingCookie",
+			body: url.Values{"csrf_token": {token}}.Encode(),
+			setup: func(r *http.Request) {
+				r.Header.Del("Cookie")
+			},
+		},
+		{
+			name: "ForgedCookie",
+			body: url.Values{"csrf_token": {token}}.Encode(),
+			setup: func(r *http.Request) {
+				r.Header.Del("Cookie")
+				r.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: cookie.Value[:len(cookie.Value)-2] + "AA"})
+			},
+		},
+		{
+			name: "CrossOrigin",
+			body: url.Values{"csrf_token": {token}}.Encode(),
+			setup: func(r *http.Request) {
+				r.Header.Set("Origin", "https://evil.example.com")
+			},
+		},
+	}
+	for _, test := range forbidden {
+		t.Run(test.name, func(t *testing.T) {
+			lastName = ""
+			rec := post(t, test.body, test.setup)
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d; want %d", rec.Code, http.StatusForbidden)
+			}
+			if lastName != "" {
+				t.Error("Func was called")
+			}
+		})
+	}
+}
+
+func TestCSRFToken(t *testing.T) {
+	if got := CSRFToken(context.Background()); got != "" {
+		t.Errorf("CSRFToken(context.Background()) = %q; want \"\"", got)
+	}
+
+	cache := NewCache(time.Minute)
+	calls := 0
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		CookieCodec:      newTestCookieCodec(t, flashkv.Key{Secret: bytes.Repeat([]byte{0x42}, 32)}),
+		CSRF:             &CSRF{},
+		Cache:            cache,
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		calls++
+		return &Response{JSONValue: map[string]string{"token": CSRFToken(ctx)}}, nil
+	})
+	var tokens []string
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		tokens = append(tokens, rec.Body.String())
+	}
+	if calls != 2 {
+		t.Errorf("Func called %d times; want 2 (responses with tokens must not be cached)", calls)
+	}
+	if tokens[0] == tokens[1] {
+		t.Errorf("both responses have body %q; want different tokens", tokens[0])
+	}
+}
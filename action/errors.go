@@ -25,6 +25,10 @@ import (
 // that can be returned from a [Func] to render an HTTP 404 (Not Found) response.
 var ErrNotFound = WithStatusCode(http.StatusNotFound, errors.New("404 not found"))
 
+// ErrMethodNotAllowed is the error served by [Methods]
+// for requests with a method that has no [Func].
+var ErrMethodNotAllowed = WithStatusCode(http.StatusMethodNotAllowed, errors.New("405 method not allowed"))
+
 type httpError struct {
 	code int
 	err  error
@@ -47,6 +51,7 @@ func (e httpError) Unwrap() error {
 // ErrorStatusCode finds the first error in err's chain that was created by [WithStatusCode],
 // and if one is found, returns the HTTP status code.
 // If err is nil, it returns 200 (OK).
+// If err's chain contains a [*ValidationError], it returns 422 (Unprocessable Entity).
 // Otherwise, it returns 500 (Internal Server Error).
 func ErrorStatusCode(err error) int {
 	code, _ := errorStatusCode(err)
@@ -59,12 +64,22 @@ func errorStatusCode(err error) (code int, explicit bool) {
 	}
 	var e httpError
 	if !errors.As(err, &e) {
+		var ve *ValidationError
+		if errors.As(err, &ve) {
+			return http.StatusUnprocessableEntity, true
+		}
 		return http.StatusInternalServerError, false
 	}
 	return e.code, true
 }
 
 func defaultTransformError(err error) *Response {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		resp := ve.response(err)
+		resp.StatusCode = ErrorStatusCode(err)
+		return resp
+	}
 	return &Response{
 		StatusCode: ErrorStatusCode(err),
 		Other: []*Representation{
@@ -72,3 +87,25 @@ func defaultTransformError(err error) *Response {
 		},
 	}
 }
+
+// ErrorTemplateData is the TemplateData passed to
+// the [Config] ErrorTemplates.
+type ErrorTemplateData struct {
+	// StatusCode is the response's HTTP status code.
+	StatusCode int
+	// StatusText is the standard text for StatusCode,
+	// like "Not Found".
+	StatusText string
+	// Err is the error being served.
+	// Its message may include internal details,
+	// so templates should avoid displaying it for server errors.
+	Err error
+}
+
+func newErrorTemplateData(code int, err error) *ErrorTemplateData {
+	return &ErrorTemplateData{
+		StatusCode: code,
+		StatusText: http.StatusText(code),
+		Err:        err,
+	}
+}
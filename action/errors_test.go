@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"zombiezen.com/go/bass/accept"
 )
@@ -81,3 +82,69 @@ func TestDefaultTransformError(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorTemplates(t *testing.T) {
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		TemplateFiles: fstest.MapFS{
+			"base.html":  {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+			"404.html":   {Data: []byte(`{{ define "content" }}<h1>Nothing here</h1>{{ end }}`)},
+			"error.html": {Data: []byte(`{{ define "content" }}<h1>{{ .StatusCode }} {{ .StatusText }}</h1>{{ end }}`)},
+		},
+		ErrorTemplates:        map[int]string{http.StatusNotFound: "404.html"},
+		FallbackErrorTemplate: "error.html",
+	}
+	tests := []struct {
+		name            string
+		err             error
+		accept          string
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "NotFound",
+			err:             ErrNotFound,
+			accept:          "text/html",
+			wantStatusCode:  http.StatusNotFound,
+			wantContentType: "text/html; charset=utf-8",
+			wantBody:        "<h1>Nothing here</h1>",
+		},
+		{
+			name:            "Fallback",
+			err:             errors.New("bork"),
+			accept:          "text/html",
+			wantStatusCode:  http.StatusInternalServerError,
+			wantContentType: "text/html; charset=utf-8",
+			wantBody:        "<h1>500 Internal Server Error</h1>",
+		},
+		{
+			name:            "PlainText",
+			err:             ErrNotFound,
+			accept:          "text/plain",
+			wantStatusCode:  http.StatusNotFound,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "404 not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+				return nil, test.err
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", test.accept)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != test.wantStatusCode {
+				t.Errorf("status = %d; want %d", rec.Code, test.wantStatusCode)
+			}
+			if got := rec.Header().Get("Content-Type"); got != test.wantContentType {
+				t.Errorf("Content-Type = %q; want %q", got, test.wantContentType)
+			}
+			if got := rec.Body.String(); got != test.wantBody {
+				t.Errorf("body = %q; want %q", got, test.wantBody)
+			}
+		})
+	}
+}
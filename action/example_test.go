@@ -21,7 +21,6 @@ import (
 	"net/http"
 	"testing/fstest"
 
-	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"zombiezen.com/go/bass/action"
 )
@@ -62,9 +61,13 @@ func Example() {
 
 	// Add the handler to your router of choice:
 	router := mux.NewRouter()
-	router.Handle("/", handlers.MethodHandler{
-		http.MethodGet:  indexHandler,
-		http.MethodHead: indexHandler,
+	router.Handle("/", indexHandler)
+
+	// Methods routes each HTTP method to a different function
+	// and answers OPTIONS requests.
+	router.Handle("/about", &action.Methods[*http.Request]{
+		Config: action.NewConfig[*http.Request](templateFiles),
+		Get:    index,
 	})
 	http.ListenAndServe(":8080", router)
 }
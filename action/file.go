@@ -0,0 +1,129 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+)
+
+const contentDispositionHeaderName = "Content-Disposition"
+
+// fileContent is the seekable content of a representation
+// created by [FileRepresentation],
+// which allows it to be served with range requests.
+type fileContent struct {
+	name    string
+	modtime time.Time
+	content io.ReadSeeker
+}
+
+// FileRepresentation creates a representation of a file's content.
+// The Content-Type is chosen from name's extension
+// or, if the extension is not recognized, sniffed from the content,
+// and may be changed by setting the returned representation's header.
+// The Content-Disposition header suggests name as the file name
+// if the user saves the file.
+// If modtime is not the zero time, it is sent as the Last-Modified header.
+//
+// When the representation is served with a 200 (OK) status,
+// it honors range and conditional request headers like [http.ServeContent].
+// If content implements [io.Closer], it is closed along with the [Response].
+func FileRepresentation(name string, modtime time.Time, content io.ReadSeeker) *Representation {
+	header := http.Header{
+		contentTypeHeaderName:        {fileContentType(name, content)},
+		contentDispositionHeaderName: {formatContentDisposition("inline", name)},
+		"Accept-Ranges":              {"bytes"},
+	}
+	if !modtime.IsZero() {
+		header.Set(lastModifiedHeaderName, modtime.UTC().Format(http.TimeFormat))
+	}
+	body, ok := content.(io.ReadCloser)
+	if !ok {
+		body = io.NopCloser(content)
+	}
+	return &Representation{
+		Header: header,
+		Body:   body,
+		file: &fileContent{
+			name:    name,
+			modtime: modtime,
+			content: content,
+		},
+	}
+}
+
+// Attachment adds a [FileRepresentation] of content to resp.Other
+// with a Content-Disposition header that tells browsers
+// to save the file as name rather than display it.
+func (resp *Response) Attachment(name string, modtime time.Time, content io.ReadSeeker) {
+	repr := FileRepresentation(name, modtime, content)
+	repr.Header.Set(contentDispositionHeaderName, formatContentDisposition("attachment", name))
+	resp.Other = append(resp.Other, repr)
+}
+
+// fileContentType returns the media type for a file
+// based on its name's extension or its first 512 bytes.
+// content is left at its start.
+func fileContentType(name string, content io.ReadSeeker) string {
+	if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+		return ctype
+	}
+	var buf [512]byte
+	n, _ := io.ReadFull(content, buf[:])
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "application/octet-stream"
+	}
+	return http.DetectContentType(buf[:n])
+}
+
+// formatContentDisposition formats a Content-Disposition header
+// with the given disposition type and file name.
+func formatContentDisposition(disposition, name string) string {
+	name = path.Base(name)
+	if name == "." || name == "/" {
+		return disposition
+	}
+	if v := mime.FormatMediaType(disposition, map[string]string{"filename": name}); v != "" {
+		return v
+	}
+	return disposition
+}
+
+// serve writes the file to w with [http.ServeContent],
+// honoring the range and conditional headers of the original request.
+func (f *fileContent) serve(w http.ResponseWriter, repr *Representation, method string, reqHeader http.Header) {
+	repr.setHeaders(w.Header())
+	w.Header().Del(contentLengthHeaderName)
+	req := &http.Request{Method: method, Header: reqHeader}
+	http.ServeContent(w, req, f.name, f.modtime, f.content)
+}
+
+// fileRequestHeader returns the headers of r that affect
+// whether and which part of a file is served.
+func fileRequestHeader(r *http.Request) http.Header {
+	h := make(http.Header)
+	for _, k := range []string{"Range", "If-Range", "If-Match", "If-Unmodified-Since", ifNoneMatchHeaderName, ifModifiedSinceHeaderName} {
+		if v := r.Header.Values(k); len(v) > 0 {
+			h[k] = v
+		}
+	}
+	return h
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileRepresentation(t *testing.T) {
+	const content = "id,name\n1,Alice\n2,Bob\n"
+	modtime := time.Date(2026, time.April, 2, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name            string
+		attachment      bool
+		fileName        string
+		content         string
+		header          map[string]string
+		method          string
+		wantCode        int
+		wantBody        string
+		wantContentType string
+		wantDisposition string
+	}{
+		{
+			name:            "Full",
+			fileName:        "people.csv",
+			content:         content,
+			wantCode:        http.StatusOK,
+			wantBody:        content,
+			wantContentType: "text/csv; charset=utf-8",
+			wantDisposition: `inline; filename=people.csv`,
+		},
+		{
+			name:            "Attachment",
+			attachment:      true,
+			fileName:        "reports/people 2026.csv",
+			content:         content,
+			wantCode:        http.StatusOK,
+			wantBody:        content,
+			wantContentType: "text/csv; charset=utf-8",
+			wantDisposition: `attachment; filename="people 2026.csv"`,
+		},
+		{
+			name:            "Range",
+			fileName:        "people.csv",
+			content:         content,
+			header:          map[string]string{"Range": "bytes=8-15"},
+			wantCode:        http.StatusPartialContent,
+			wantBody:        "1,Alice\n",
+			wantContentType: "text/csv; charset=utf-8",
+			wantDisposition: `inline; filename=people.csv`,
+		},
+		{
+			name:            "Head",
+			fileName:        "people.csv",
+			content:         content,
+			method:          http.MethodHead,
+			wantCode:        http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantDisposition: `inline; filename=people.csv`,
+		},
+		{
+			name:            "NotModified",
+			fileName:        "people.csv",
+			content:         content,
+			header:          map[string]string{"If-Modified-Since": modtime.Format(http.TimeFormat)},
+			wantCode:        http.StatusNotModified,
+			wantDisposition: `inline; filename=people.csv`,
+		},
+		{
+			name:            "Sniffed",
+			fileName:        "download",
+			content:         "%PDF-1.7\n",
+			wantCode:        http.StatusOK,
+			wantBody:        "%PDF-1.7\n",
+			wantContentType: "application/pdf",
+			wantDisposition: `inline; filename=download`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config[*http.Request]{TransformRequest: identity, CompressMinSize: 1}
+			h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+				resp := new(Response)
+				if test.attachment {
+					resp.Attachment(test.fileName, modtime, strings.NewReader(test.content))
+				} else {
+					resp.Other = append(resp.Other, FileRepresentation(test.fileName, modtime, strings.NewReader(test.content)))
+				}
+				return resp, nil
+			})
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			for k, v := range test.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != test.wantCode {
+				t.Errorf("status = %d; want %d", rec.Code, test.wantCode)
+			}
+			if got := rec.Body.String(); got != test.wantBody {
+				t.Errorf("body = %q; want %q", got, test.wantBody)
+			}
+			if test.wantContentType != "" {
+				if got := rec.Header().Get("Content-Type"); got != test.wantContentType {
+					t.Errorf("Content-Type = %q; want %q", got, test.wantContentType)
+				}
+			}
+			if got := rec.Header().Get("Content-Disposition"); got != test.wantDisposition {
+				t.Errorf("Content-Disposition = %q; want %q", got, test.wantDisposition)
+			}
+			if got, want := rec.Header().Get("Last-Modified"), modtime.Format(http.TimeFormat); got != want {
+				t.Errorf("Last-Modified = %q; want %q", got, want)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Content-Encoding = %q; want none", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FormRequest returns a function suitable for [Config.TransformRequest]
+// that decodes the request's form into a new T with [DecodeForm].
+// T must be a struct type.
+// For GET and HEAD requests, the form is read from the URL query.
+// Otherwise, the body must be application/x-www-form-urlencoded
+// or multipart/form-data (parsed with [ParseMultipartForm] using opts),
+// and body values take precedence over URL query values with the same name.
+//
+// Errors from the returned function have status codes set with [WithStatusCode]:
+// 400 (Bad Request) for malformed forms and values,
+// and 415 (Unsupported Media Type) for other body types.
+func FormRequest[T any](opts *MultipartOptions) func(*http.Request) (*T, func(), error) {
+	return func(r *http.Request) (*T, func(), error) {
+		values, files, cleanup, err := readForm(r, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		req := new(T)
+		if err := DecodeForm(req, values, files); err != nil {
+			if cleanup != nil {
+				cleanup()
+			}
+			return nil, nil, err
+		}
+		return req, cleanup, nil
+	}
+}
+
+func readForm(r *http.Request, opts *MultipartOptions) (url.Values, map[string][]*UploadedFile, func(), error) {
+	query := r.URL.Query()
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return query, nil, nil, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil && r.Header.Get("Content-Type") != "" {
+		return nil, nil, nil, WithStatusCode(http.StatusUnsupportedMediaType, fmt.Errorf("read form: %w", err))
+	}
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, nil, nil, WithStatusCode(http.StatusBadRequest, fmt.Errorf("read form: %w", err))
+		}
+		return r.Form, nil, nil, nil
+	case "multipart/form-data":
+		form, cleanup, err := ParseMultipartForm(r, opts)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for k, v := range form.Value {
+			query[k] = v
+		}
+		return query, form.File, cleanup, nil
+	case "":
+		if r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody {
+			return query, nil, nil, nil
+		}
+		fallthrough
+	default:
+		return nil, nil, nil, WithStatusCode(http.StatusUnsupportedMediaType, fmt.Errorf("read form: unsupported content type %q", mediaType))
+	}
+}
+
+// DecodeForm stores form values and files into the struct that dst points to.
+// Each exported field is filled from the form field named by its "form" tag,
+// or the Go field name if there is no tag.
+// Fields tagged with `form:"-"` and fields with no value in the form are left unchanged.
+// Fields of embedded structs are decoded as if they were in the outer struct.
+//
+// Fields may be strings, byte slices, booleans, integers, floating-point numbers,
+// types that implement [encoding.TextUnmarshaler],
+// or slices of those types, which receive every value for the name.
+// Other fields receive the first value.
+// Booleans accept "on" (as sent by HTML checkboxes)
+// in addition to the values accepted by [strconv.ParseBool].
+// Empty values leave numeric fields unchanged,
+// since browsers send empty strings for blank inputs.
+// Fields of type *[UploadedFile] or []*UploadedFile are filled from files.
+//
+// Errors for values that cannot be parsed
+// have a 400 (Bad Request) status code set with [WithStatusCode].
+// Errors for fields of other types have a 500 (Internal Server Error) status code.
+func DecodeForm(dst any, values url.Values, files map[string][]*UploadedFile) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode form: %T is not a pointer to a struct", dst)
+	}
+	return decodeFormStruct(v.Elem(), values, files)
+}
+
+var (
+	uploadedFileType    = reflect.TypeOf((*UploadedFile)(nil))
+	uploadedFilesType   = reflect.TypeOf([]*UploadedFile(nil))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func decodeFormStruct(v reflect.Value, values url.Values, files map[string][]*UploadedFile) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := decodeFormStruct(v.Field(i), values, files); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("form"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fv := v.Field(i)
+		switch field.Type {
+		case uploadedFileType:
+			if fs := files[name]; len(fs) > 0 {
+				fv.Set(reflect.ValueOf(fs[0]))
+			}
+			continue
+		case uploadedFilesType:
+			if fs := files[name]; len(fs) > 0 {
+				fv.Set(reflect.ValueOf(fs))
+			}
+			continue
+		}
+		vals, ok := values[name]
+		if !ok || len(vals) == 0 {
+			continue
+		}
+		if field.Type.Kind() == reflect.Slice && !reflect.PointerTo(field.Type).Implements(textUnmarshalerType) && field.Type.Elem().Kind() != reflect.Uint8 {
+			slice := reflect.MakeSlice(field.Type, len(vals), len(vals))
+			for j, s := range vals {
+				if err := setFormValue(slice.Index(j), s); err != nil {
+					return formValueError(name, err)
+				}
+			}
+			fv.Set(slice)
+			continue
+		}
+		if err := setFormValue(fv, vals[0]); err != nil {
+			return formValueError(name, err)
+		}
+	}
+	return nil
+}
+
+var errUnsupportedFormField = errors.New("unsupported field type")
+
+func formValueError(name string, err error) error {
+	if errors.Is(err, errUnsupportedFormField) {
+		// The struct type is wrong, not the request.
+		return WithStatusCode(http.StatusInternalServerError, fmt.Errorf("decode form: field %q: %w", name, err))
+	}
+	return WithStatusCode(http.StatusBadRequest, fmt.Errorf("decode form: field %q: %w", name, err))
+}
+
+func setFormValue(v reflect.Value, s string) error {
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		if strings.EqualFold(s, "on") {
+			v.SetBool(true)
+			return nil
+		}
+		if s == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if s == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("%w %v", errUnsupportedFormField, v.Type())
+		}
+		v.SetBytes([]byte(s))
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setFormValue(v.Elem(), s)
+	default:
+		return fmt.Errorf("%w %v", errUnsupportedFormField, v.Type())
+	}
+	return nil
+}
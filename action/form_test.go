@@ -0,0 +1,211 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type formPaging struct {
+	Page int `form:"page"`
+}
+
+type testForm struct {
+	formPaging
+	Name     string     `form:"name"`
+	Age      int        `form:"age"`
+	Score    float64    `form:"score"`
+	Agree    bool       `form:"agree"`
+	Tags     []string   `form:"tag"`
+	Addr     netip.Addr `form:"addr"`
+	Nickname *string    `form:"nickname"`
+	Ignored  string     `form:"-"`
+	Title    string
+	private  string
+}
+
+func TestDecodeForm(t *testing.T) {
+	nickname := "Bobby"
+	tests := []struct {
+		name       string
+		values     url.Values
+		want       testForm
+		wantStatus int
+	}{
+		{
+			name: "AllFields",
+			values: url.Values{
+				"page":     {"2"},
+				"name":     {"Bob"},
+				"age":      {"42"},
+				"score":    {"9.5"},
+				"agree":    {"on"},
+				"tag":      {"a", "b"},
+				"addr":     {"192.0.2.1"},
+				"nickname": {"Bobby"},
+				"Ignored":  {"x"},
+				"-":        {"x"},
+				"Title":    {"Dr."},
+				"private":  {"x"},
+			},
+			want: testForm{
+				formPaging: formPaging{Page: 2},
+				Name:       "Bob",
+				Age:        42,
+				Score:      9.5,
+				Agree:      true,
+				Tags:       []string{"a", "b"},
+				Addr:       netip.MustParseAddr("192.0.2.1"),
+				Nickname:   &nickname,
+				Title:      "Dr.",
+			},
+		},
+		{
+			name:   "EmptyNumber",
+			values: url.Values{"age": {""}, "name": {""}},
+			want:   testForm{},
+		},
+		{
+			name:       "BadInteger",
+			values:     url.Values{"age": {"old"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadTextUnmarshaler",
+			values:     url.Values{"addr": {"nope"}},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got testForm
+			err := DecodeForm(&got, test.values, nil)
+			if test.wantStatus != 0 {
+				if err == nil {
+					t.Fatal("DecodeForm did not return an error")
+				}
+				if code := ErrorStatusCode(err); code != test.wantStatus {
+					t.Errorf("ErrorStatusCode(%v) = %d; want %d", err, code, test.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("DecodeForm:", err)
+			}
+			diff := cmp.Diff(test.want, got,
+				cmp.AllowUnexported(testForm{}),
+				cmp.Comparer(func(a, b netip.Addr) bool { return a == b }))
+			if diff != "" {
+				t.Errorf("DecodeForm(...) (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeFormUnsupported(t *testing.T) {
+	var dst struct {
+		Ch chan int `form:"ch"`
+	}
+	err := DecodeForm(&dst, url.Values{"ch": {"1"}}, nil)
+	if code := ErrorStatusCode(err); code != http.StatusInternalServerError {
+		t.Errorf("ErrorStatusCode(%v) = %d; want %d", err, code, http.StatusInternalServerError)
+	}
+}
+
+func TestFormRequest(t *testing.T) {
+	type signup struct {
+		Email  string        `form:"email"`
+		Ref    string        `form:"ref"`
+		Avatar *UploadedFile `form:"avatar"`
+	}
+	transform := FormRequest[signup](nil)
+
+	t.Run("Query", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?email=a@example.com", nil)
+		got, cleanup, err := transform(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cleanup != nil {
+			defer cleanup()
+		}
+		if got.Email != "a@example.com" {
+			t.Errorf("Email = %q; want %q", got.Email, "a@example.com")
+		}
+	})
+
+	t.Run("URLEncoded", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/?ref=query&email=query", strings.NewReader("email=a%40example.com"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		got, _, err := transform(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Email != "a@example.com" || got.Ref != "query" {
+			t.Errorf("got %+v; want Email=a@example.com Ref=query", got)
+		}
+	})
+
+	t.Run("Multipart", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		mw := multipart.NewWriter(body)
+		mw.WriteField("email", "a@example.com")
+		fw, err := mw.CreateFormFile("avatar", "me.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(fw, "PNG")
+		if err := mw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(http.MethodPost, "/", body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		got, cleanup, err := transform(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cleanup == nil {
+			t.Fatal("cleanup is nil")
+		}
+		defer cleanup()
+		if got.Email != "a@example.com" {
+			t.Errorf("Email = %q; want %q", got.Email, "a@example.com")
+		}
+		if got.Avatar == nil || got.Avatar.Filename != "me.png" || got.Avatar.Size != 3 {
+			t.Errorf("Avatar = %+v; want me.png with 3 bytes", got.Avatar)
+		}
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+		r.Header.Set("Content-Type", "application/json")
+		_, _, err := transform(r)
+		if code := ErrorStatusCode(err); code != http.StatusUnsupportedMediaType {
+			t.Errorf("ErrorStatusCode(%v) = %d; want %d", err, code, http.StatusUnsupportedMediaType)
+		}
+	})
+}
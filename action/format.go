@@ -0,0 +1,106 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"html/template"
+	"sort"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+	"zombiezen.com/go/bass/accept"
+)
+
+// requestLocale returns the language that the request prefers most,
+// or [language.Und] if the request has no usable Accept-Language header.
+func requestLocale(languages accept.LanguageHeader) language.Tag {
+	ranked := make(accept.LanguageHeader, 0, len(languages))
+	for _, lr := range languages {
+		if lr.Quality > 0 && lr.Range != "*" {
+			ranked = append(ranked, lr)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Quality > ranked[j].Quality
+	})
+	for _, lr := range ranked {
+		if tag, err := language.Parse(lr.Range); err == nil {
+			return tag
+		}
+	}
+	return language.Und
+}
+
+// dateLayouts maps languages to their conventional numeric date layouts.
+// The first entry is used for languages that don't match any other entry.
+var dateLayouts = []struct {
+	tag    language.Tag
+	layout string
+}{
+	{language.Und, "2006-01-02"},
+	{language.AmericanEnglish, "1/2/2006"},
+	{language.BritishEnglish, "02/01/2006"},
+	{language.German, "02.01.2006"},
+	{language.Spanish, "02/01/2006"},
+	{language.French, "02/01/2006"},
+	{language.Italian, "02/01/2006"},
+	{language.Dutch, "02-01-2006"},
+	{language.Portuguese, "02/01/2006"},
+	{language.Russian, "02.01.2006"},
+	{language.Japanese, "2006/01/02"},
+	{language.Korean, "2006. 1. 2."},
+	{language.Chinese, "2006/1/2"},
+}
+
+var dateLayoutMatcher = func() language.Matcher {
+	tags := make([]language.Tag, len(dateLayouts))
+	for i, dl := range dateLayouts {
+		tags[i] = dl.tag
+	}
+	return language.NewMatcher(tags)
+}()
+
+// localeTemplateFuncs returns the formatting template functions
+// bound to the given locale:
+//
+//   - formatNumber formats a number with the locale's digit grouping
+//     and decimal separator.
+//   - formatCurrency formats an amount in the currency
+//     with the given ISO 4217 code, like "EUR".
+//   - formatDate formats a time.Time as a numeric date
+//     in the order conventional for the locale.
+func localeTemplateFuncs(tag language.Tag) template.FuncMap {
+	return template.FuncMap{
+		"formatNumber": func(v any) string {
+			return message.NewPrinter(tag).Sprint(number.Decimal(v))
+		},
+		"formatCurrency": func(amount any, code string) (string, error) {
+			unit, err := currency.ParseISO(code)
+			if err != nil {
+				return "", err
+			}
+			return message.NewPrinter(tag).Sprint(currency.Symbol(unit.Amount(amount))), nil
+		},
+		"formatDate": func(t time.Time) string {
+			_, i, _ := dateLayoutMatcher.Match(tag)
+			return t.Format(dateLayouts[i].layout)
+		},
+	}
+}
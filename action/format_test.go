@@ -0,0 +1,110 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"zombiezen.com/go/bass/accept"
+)
+
+func TestLocaleTemplateFuncs(t *testing.T) {
+	date := time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		acceptLanguage string
+		wantNumber     string
+		wantCurrency   string
+		wantDate       string
+	}{
+		{
+			acceptLanguage: "",
+			wantNumber:     "1,234.5",
+			wantCurrency:   "€ 9.99",
+			wantDate:       "2026-03-04",
+		},
+		{
+			acceptLanguage: "en-US",
+			wantNumber:     "1,234.5",
+			wantCurrency:   "€ 9.99",
+			wantDate:       "3/4/2026",
+		},
+		{
+			acceptLanguage: "en-GB",
+			wantNumber:     "1,234.5",
+			wantCurrency:   "€ 9.99",
+			wantDate:       "04/03/2026",
+		},
+		{
+			acceptLanguage: "fr;q=0.5, de-DE",
+			wantNumber:     "1.234,5",
+			wantCurrency:   "€ 9,99",
+			wantDate:       "04.03.2026",
+		},
+	}
+	for _, test := range tests {
+		languages, err := accept.ParseLanguageHeader(test.acceptLanguage)
+		if err != nil {
+			t.Errorf("ParseLanguageHeader(%q): %v", test.acceptLanguage, err)
+			continue
+		}
+		funcs := localeTemplateFuncs(requestLocale(languages))
+		if got := funcs["formatNumber"].(func(any) string)(1234.5); got != test.wantNumber {
+			t.Errorf("Accept-Language: %s: formatNumber(1234.5) = %q; want %q", test.acceptLanguage, got, test.wantNumber)
+		}
+		got, err := funcs["formatCurrency"].(func(any, string) (string, error))(9.99, "EUR")
+		if err != nil || got != test.wantCurrency {
+			t.Errorf("Accept-Language: %s: formatCurrency(9.99, \"EUR\") = %q, %v; want %q, <nil>", test.acceptLanguage, got, err, test.wantCurrency)
+		}
+		if got := funcs["formatDate"].(func(time.Time) string)(date); got != test.wantDate {
+			t.Errorf("Accept-Language: %s: formatDate(...) = %q; want %q", test.acceptLanguage, got, test.wantDate)
+		}
+	}
+
+	funcs := localeTemplateFuncs(requestLocale(nil))
+	if _, err := funcs["formatCurrency"].(func(any, string) (string, error))(1, "XYZW"); err == nil {
+		t.Error("formatCurrency with invalid currency code did not return an error")
+	}
+}
+
+func TestLocaleTemplateFuncsInTemplate(t *testing.T) {
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		TemplateFiles: fstest.MapFS{
+			"base.html":  {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+			"price.html": {Data: []byte(`{{ define "content" }}{{ formatCurrency . "EUR" }}{{ end }}`)},
+		},
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{HTMLTemplate: "price.html", TemplateData: 1234.5}, nil
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; want %d", rec.Code, http.StatusOK)
+	}
+	if got, want := rec.Body.String(), "€ 1.234,50"; !strings.Contains(got, want) {
+		t.Errorf("body = %q; want to contain %q", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"zombiezen.com/go/bass/accept"
+)
+
+const (
+	acceptLanguageHeaderName  = "Accept-Language"
+	contentLanguageHeaderName = "Content-Language"
+)
+
+// requestLanguages returns the parsed Accept-Language header of r.
+// A malformed header is treated as absent.
+func requestLanguages(r *http.Request) accept.LanguageHeader {
+	languages, err := accept.ParseLanguageHeader(strings.Join(r.Header.Values(acceptLanguageHeaderName), ","))
+	if err != nil {
+		return nil
+	}
+	return languages
+}
+
+// localizedTemplate returns the variant of the named template
+// that best matches the request's languages.
+// Variants are named by inserting a lowercase language tag
+// before the file extension: "page.de.html" or "page.pt-br.html"
+// are variants of "page.html".
+// Each language range is tried in order of preference,
+// removing subtags from the end until a variant is found
+// (the lookup scheme in RFC 4647 Section 3.4),
+// so "de-AT" falls back to "page.de.html".
+// If no variant matches, localizedTemplate returns name.
+//
+// lang is the language tag of the chosen variant, if any.
+// hasVariants reports whether any variants of the template exist,
+// in which case the response varies by Accept-Language.
+func localizedTemplate(fsys fs.FS, name string, languages accept.LanguageHeader) (variant, lang string, hasVariants bool, err error) {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	matches, err := fs.Glob(fsys, escapeGlob(stem)+".*"+escapeGlob(ext))
+	if err != nil {
+		return "", "", false, err
+	}
+	tags := make(map[string]string)
+	for _, m := range matches {
+		tag := strings.TrimSuffix(strings.TrimPrefix(m, stem+"."), ext)
+		if isLanguageTag(tag) {
+			tags[strings.ToLower(tag)] = m
+		}
+	}
+	if len(tags) == 0 {
+		return name, "", false, nil
+	}
+
+	ranked := make(accept.LanguageHeader, 0, len(languages))
+	for _, lr := range languages {
+		if lr.Quality > 0 && lr.Range != "*" {
+			ranked = append(ranked, lr)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Quality > ranked[j].Quality
+	})
+	for _, lr := range ranked {
+		for tag := strings.ToLower(lr.Range); tag != ""; {
+			if m, ok := tags[tag]; ok {
+				return m, tag, true, nil
+			}
+			i := strings.LastIndexByte(tag, '-')
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	return name, "", true, nil
+}
+
+// isLanguageTag reports whether s looks like a language tag:
+// a primary language subtag of two or three letters
+// followed by zero or more alphanumeric subtags of up to eight characters.
+// This keeps names like "page.partial.html" from being treated as variants.
+func isLanguageTag(s string) bool {
+	for i, subtag := range strings.Split(s, "-") {
+		if len(subtag) == 0 || len(subtag) > 8 || i == 0 && (len(subtag) < 2 || len(subtag) > 3) {
+			return false
+		}
+		for j := 0; j < len(subtag); j++ {
+			c := subtag[j]
+			isLetter := 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+			isDigit := '0' <= c && c <= '9'
+			if !isLetter && !(isDigit && i > 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// escapeGlob escapes the metacharacters recognized by [path.Match] in s.
+func escapeGlob(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '*', '?', '[', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// localizeHTML chooses the variant of the named HTML template
+// that best matches opts.languages using localizedTemplate,
+// storing it in opts.htmlTemplate and opts.htmlLanguage.
+// If the template has localized variants, localizeHTML sets opts.localized.
+// Subsequent calls do nothing.
+func (opts *renderOptions) localizeHTML(name string) error {
+	if opts.htmlTemplate != "" {
+		return nil
+	}
+	variant, lang, hasVariants, err := localizedTemplate(opts.templateFiles, name, opts.languages)
+	if err != nil {
+		return err
+	}
+	opts.htmlTemplate = variant
+	opts.htmlLanguage = lang
+	opts.localized = opts.localized || hasVariants
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLocalizedTemplate(t *testing.T) {
+	templateFiles := fstest.MapFS{
+		"base.html":          {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+		"page.html":          {Data: []byte(`{{ define "content" }}Hello{{ end }}`)},
+		"page.de.html":       {Data: []byte(`{{ define "content" }}Hallo{{ end }}`)},
+		"page.pt-br.html":    {Data: []byte(`{{ define "content" }}Olá{{ end }}`)},
+		"other.html":         {Data: []byte(`{{ define "content" }}Other{{ end }}`)},
+		"other.partial.html": {Data: []byte(`{{ define "content" }}Partial{{ end }}`)},
+	}
+	tests := []struct {
+		name           string
+		template       string
+		acceptLanguage string
+		want           string
+		wantLanguage   string
+		wantVary       bool
+	}{
+		{
+			name:     "NoHeader",
+			template: "page.html",
+			want:     "Hello",
+			wantVary: true,
+		},
+		{
+			name:           "Exact",
+			template:       "page.html",
+			acceptLanguage: "de",
+			want:           "Hallo",
+			wantLanguage:   "de",
+			wantVary:       true,
+		},
+		{
+			name:           "Fallback",
+			template:       "page.html",
+			acceptLanguage: "de-AT",
+			want:           "Hallo",
+			wantLanguage:   "de",
+			wantVary:       true,
+		},
+		{
+			name:           "Region",
+			template:       "page.html",
+			acceptLanguage: "pt-BR, de;q=0.5",
+			want:           "Olá",
+			wantLanguage:   "pt-br",
+			wantVary:       true,
+		},
+		{
+			name:           "Quality",
+			template:       "page.html",
+			acceptLanguage: "fr, pt;q=0.2, de;q=0.8",
+			want:           "Hallo",
+			wantLanguage:   "de",
+			wantVary:       true,
+		},
+		{
+			name:           "Unavailable",
+			template:       "page.html",
+			acceptLanguage: "fr, pt",
+			want:           "Hello",
+			wantVary:       true,
+		},
+		{
+			name:           "NoVariants",
+			template:       "other.html",
+			acceptLanguage: "partial",
+			want:           "Other",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config[*http.Request]{TransformRequest: identity, TemplateFiles: templateFiles}
+			h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+				return &Response{HTMLTemplate: test.template}, nil
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", test.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d; want %d", rec.Code, http.StatusOK)
+			}
+			if got := rec.Body.String(); got != test.want {
+				t.Errorf("body = %q; want %q", got, test.want)
+			}
+			if got := rec.Header().Get("Content-Language"); got != test.wantLanguage {
+				t.Errorf("Content-Language = %q; want %q", got, test.wantLanguage)
+			}
+			gotVary := false
+			for _, v := range rec.Header().Values("Vary") {
+				gotVary = gotVary || v == "Accept-Language"
+			}
+			if gotVary != test.wantVary {
+				t.Errorf("Vary = %q; Accept-Language present = %t, want %t", rec.Header().Values("Vary"), gotVary, test.wantVary)
+			}
+		})
+	}
+}
+
+func TestLocalizedTemplateNotCached(t *testing.T) {
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		TemplateFiles: fstest.MapFS{
+			"base.html":    {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+			"page.html":    {Data: []byte(`{{ define "content" }}Hello{{ end }}`)},
+			"page.de.html": {Data: []byte(`{{ define "content" }}Hallo{{ end }}`)},
+		},
+		Cache: NewCache(time.Minute),
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{HTMLTemplate: "page.html"}, nil
+	})
+	for _, test := range []struct{ lang, want string }{{"en", "Hello"}, {"de", "Hallo"}} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", test.lang)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if got := rec.Body.String(); got != test.want {
+			t.Errorf("Accept-Language: %s body = %q; want %q", test.lang, got, test.want)
+		}
+	}
+}
+
+func TestLocalizedTemplateETag(t *testing.T) {
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		TemplateFiles: fstest.MapFS{
+			"base.html":    {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+			"page.html":    {Data: []byte(`{{ define "content" }}Hello{{ end }}`)},
+			"page.de.html": {Data: []byte(`{{ define "content" }}Hallo{{ end }}`)},
+		},
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{HTMLTemplate: "page.html", ETagKey: "post1@v1"}, nil
+	})
+	do := func(lang, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", lang)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	enTag := do("en", "").Header().Get("ETag")
+	deTag := do("de", "").Header().Get("ETag")
+	if enTag == "" || enTag == deTag {
+		t.Fatalf("ETag for en = %q, for de = %q; want distinct tags", enTag, deTag)
+	}
+	if rec := do("de", enTag); rec.Code != http.StatusOK {
+		t.Errorf("GET in de with If-None-Match: %s = %d; want %d", enTag, rec.Code, http.StatusOK)
+	} else if got := rec.Body.String(); got != "Hallo" {
+		t.Errorf("body = %q; want %q", got, "Hallo")
+	}
+	rec := do("de", deTag)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("GET in de with If-None-Match: %s = %d; want %d", deTag, rec.Code, http.StatusNotModified)
+	}
+	gotVary := false
+	for _, v := range rec.Header().Values("Vary") {
+		gotVary = gotVary || v == "Accept-Language"
+	}
+	if !gotVary {
+		t.Errorf("304 Vary = %q; want to include Accept-Language", rec.Header().Values("Vary"))
+	}
+}
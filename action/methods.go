@@ -0,0 +1,103 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Methods is an [http.Handler] that calls a different [Func]
+// depending on the request's method.
+// All of the Funcs are served with the same Config,
+// so they share limits like MaxConcurrent.
+//
+// Methods answers OPTIONS requests with an Allow header
+// listing the methods that have a Func.
+// Requests with any other method are served [ErrMethodNotAllowed]
+// through the Config's TransformError,
+// so the error is negotiated like any other response.
+//
+// A Methods must not be modified or copied after its first use.
+type Methods[R any] struct {
+	// Config is used for all of the Funcs.
+	// A nil Config is treated the same as a zero Config.
+	Config *Config[R]
+
+	Get    Func[R]
+	Post   Func[R]
+	Put    Func[R]
+	Patch  Func[R]
+	Delete Func[R]
+	// Head is called for HEAD requests.
+	// If Head is nil, then Get is used.
+	Head Func[R]
+
+	once sync.Once
+	// base is the handler used for errors
+	// and as a template for the method handlers.
+	// A [Mux] sets it before first use so that its routes share a semaphore.
+	base     *Handler[R]
+	handlers map[string]*Handler[R]
+	allow    string
+}
+
+func (m *Methods[R]) init() {
+	m.once.Do(func() {
+		if m.base == nil {
+			m.base = m.Config.NewHandler(nil)
+		}
+		m.handlers = make(map[string]*Handler[R])
+		var allow []string
+		add := func(method string, f Func[R]) {
+			if f == nil {
+				return
+			}
+			h := *m.base
+			h.f = f
+			m.handlers[method] = &h
+			allow = append(allow, method)
+		}
+		add(http.MethodGet, m.Get)
+		if m.Head != nil {
+			add(http.MethodHead, m.Head)
+		} else {
+			add(http.MethodHead, m.Get)
+		}
+		add(http.MethodPost, m.Post)
+		add(http.MethodPut, m.Put)
+		add(http.MethodPatch, m.Patch)
+		add(http.MethodDelete, m.Delete)
+		m.allow = strings.Join(append(allow, http.MethodOptions), ", ")
+	})
+}
+
+// ServeHTTP dispatches the request to the Func for its method.
+func (m *Methods[R]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.init()
+	if h := m.handlers[r.Method]; h != nil {
+		h.ServeHTTP(w, r)
+		return
+	}
+	w.Header().Set("Allow", m.allow)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	m.base.serveError(w, r, ErrMethodNotAllowed)
+}
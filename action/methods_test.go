@@ -0,0 +1,98 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMethods(t *testing.T) {
+	reply := func(s string) Func[*http.Request] {
+		return func(ctx context.Context, r *http.Request) (*Response, error) {
+			return &Response{Other: []*Representation{TextRepresentation(s)}}, nil
+		}
+	}
+	m := &Methods[*http.Request]{
+		Config: &Config[*http.Request]{
+			TransformError: func(err error) *Response {
+				return &Response{
+					StatusCode: ErrorStatusCode(err),
+					JSONValue:  map[string]string{"error": err.Error()},
+				}
+			},
+		},
+		Get:  reply("get"),
+		Post: reply("post"),
+	}
+
+	tests := []struct {
+		method     string
+		accept     string
+		wantStatus int
+		wantAllow  string
+		wantBody   string
+	}{
+		{
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   "get",
+		},
+		{
+			method:     http.MethodHead,
+			wantStatus: http.StatusOK,
+		},
+		{
+			method:     http.MethodPost,
+			wantStatus: http.StatusOK,
+			wantBody:   "post",
+		},
+		{
+			method:     http.MethodOptions,
+			wantStatus: http.StatusNoContent,
+			wantAllow:  "GET, HEAD, POST, OPTIONS",
+		},
+		{
+			method:     http.MethodDelete,
+			accept:     "application/json",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantAllow:  "GET, HEAD, POST, OPTIONS",
+			wantBody:   `{"error":"405 method not allowed"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.method, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, "/", nil)
+			if test.accept != "" {
+				r.Header.Set("Accept", test.accept)
+			}
+			rec := httptest.NewRecorder()
+			m.ServeHTTP(rec, r)
+			if rec.Code != test.wantStatus {
+				t.Errorf("status = %d; want %d", rec.Code, test.wantStatus)
+			}
+			if got := rec.Header().Get("Allow"); got != test.wantAllow {
+				t.Errorf("Allow = %q; want %q", got, test.wantAllow)
+			}
+			if got := rec.Body.String(); got != test.wantBody {
+				t.Errorf("body = %q; want %q", got, test.wantBody)
+			}
+		})
+	}
+}
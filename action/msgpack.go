@@ -0,0 +1,181 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// marshalMsgpack converts a value to [MessagePack]
+// using the same rules as [json.Marshal],
+// so that struct tags and json.Marshaler implementations are respected.
+//
+// [MessagePack]: https://github.com/msgpack/msgpack/blob/master/spec.md
+func marshalMsgpack(v any) ([]byte, error) {
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return appendMsgpack(nil, generic)
+}
+
+func appendMsgpack(dst []byte, v any) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(dst, 0xc0), nil
+	case bool:
+		if v {
+			return append(dst, 0xc3), nil
+		}
+		return append(dst, 0xc2), nil
+	case json.Number:
+		return appendMsgpackNumber(dst, v)
+	case string:
+		return appendMsgpackString(dst, v), nil
+	case []any:
+		dst = appendMsgpackLength(dst, len(v), 0x90, 0xdc)
+		for _, elem := range v {
+			var err error
+			dst, err = appendMsgpack(dst, elem)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		dst = appendMsgpackLength(dst, len(v), 0x80, 0xde)
+		for _, k := range keys {
+			dst = appendMsgpackString(dst, k)
+			var err error
+			dst, err = appendMsgpack(dst, v[k])
+			if err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	default:
+		return nil, fmt.Errorf("marshal msgpack: unexpected %T", v)
+	}
+}
+
+func appendMsgpackNumber(dst []byte, n json.Number) ([]byte, error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		switch {
+		case 0 <= i && i <= 0x7f:
+			return append(dst, byte(i)), nil
+		case -32 <= i && i < 0:
+			return append(dst, byte(int8(i))), nil
+		case i >= 0:
+			return appendMsgpackUint(dst, uint64(i)), nil
+		case i >= math.MinInt8:
+			return append(dst, 0xd0, byte(int8(i))), nil
+		case i >= math.MinInt16:
+			return appendBigEndian16(append(dst, 0xd1), uint16(int16(i))), nil
+		case i >= math.MinInt32:
+			return appendBigEndian32(append(dst, 0xd2), uint32(int32(i))), nil
+		default:
+			return appendBigEndian64(append(dst, 0xd3), uint64(i)), nil
+		}
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return appendMsgpackUint(dst, u), nil
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return nil, fmt.Errorf("marshal msgpack: %w", err)
+	}
+	return appendBigEndian64(append(dst, 0xcb), math.Float64bits(f)), nil
+}
+
+func appendMsgpackUint(dst []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(dst, byte(u))
+	case u <= math.MaxUint8:
+		return append(dst, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return appendBigEndian16(append(dst, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return appendBigEndian32(append(dst, 0xce), uint32(u))
+	default:
+		return appendBigEndian64(append(dst, 0xcf), u)
+	}
+}
+
+func appendMsgpackString(dst []byte, s string) []byte {
+	switch n := len(s); {
+	case n <= 31:
+		dst = append(dst, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		dst = appendBigEndian16(append(dst, 0xda), uint16(n))
+	default:
+		dst = appendBigEndian32(append(dst, 0xdb), uint32(n))
+	}
+	return append(dst, s...)
+}
+
+// appendMsgpackLength appends an array or map header.
+// fix is the fixarray or fixmap prefix
+// and prefix16 is the array 16 or map 16 prefix.
+// The 32-bit prefix is always prefix16+1.
+func appendMsgpackLength(dst []byte, n int, fix, prefix16 byte) []byte {
+	switch {
+	case n <= 15:
+		return append(dst, fix|byte(n))
+	case n <= math.MaxUint16:
+		return appendBigEndian16(append(dst, prefix16), uint16(n))
+	default:
+		return appendBigEndian32(append(dst, prefix16+1), uint32(n))
+	}
+}
+
+func appendBigEndian16(dst []byte, x uint16) []byte {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], x)
+	return append(dst, buf[:]...)
+}
+
+func appendBigEndian32(dst []byte, x uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], x)
+	return append(dst, buf[:]...)
+}
+
+func appendBigEndian64(dst []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], x)
+	return append(dst, buf[:]...)
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want []byte
+	}{
+		{name: "Nil", v: nil, want: []byte{0xc0}},
+		{name: "True", v: true, want: []byte{0xc3}},
+		{name: "False", v: false, want: []byte{0xc2}},
+		{name: "PositiveFixint", v: 7, want: []byte{0x07}},
+		{name: "NegativeFixint", v: -1, want: []byte{0xff}},
+		{name: "Uint8", v: 200, want: []byte{0xcc, 0xc8}},
+		{name: "Uint16", v: 1000, want: []byte{0xcd, 0x03, 0xe8}},
+		{name: "Int8", v: -100, want: []byte{0xd0, 0x9c}},
+		{name: "Int16", v: -1000, want: []byte{0xd1, 0xfc, 0x18}},
+		{name: "Uint64", v: uint64(1 << 63), want: []byte{0xcf, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "Float", v: 1.5, want: []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{name: "FixStr", v: "hi", want: []byte{0xa2, 'h', 'i'}},
+		{name: "Str8", v: strings.Repeat("x", 32), want: append([]byte{0xd9, 32}, strings.Repeat("x", 32)...)},
+		{name: "FixArray", v: []int{1, 2}, want: []byte{0x92, 0x01, 0x02}},
+		{
+			name: "Struct",
+			v: struct {
+				B string `json:"b"`
+				A int    `json:"a"`
+				C string `json:"-"`
+			}{B: "x", A: 1, C: "ignored"},
+			want: []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0xa1, 'x'},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := marshalMsgpack(test.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("marshalMsgpack(%#v) (-want +got):\n%s", test.v, diff)
+			}
+		})
+	}
+}
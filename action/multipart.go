@@ -0,0 +1,233 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+)
+
+// defaultMaxMultipartValueSize is the default limit
+// on the total size of non-file values in a multipart form.
+const defaultMaxMultipartValueSize = 10 << 20 // 10 MiB
+
+// MultipartOptions holds optional arguments to [ParseMultipartForm].
+type MultipartOptions struct {
+	// If MaxFileSize is greater than zero,
+	// then any single file larger than MaxFileSize bytes
+	// will cause an HTTP 413 (Content Too Large) error.
+	MaxFileSize int64
+	// If MaxTotalFileSize is greater than zero,
+	// then files whose sizes add up to more than MaxTotalFileSize bytes
+	// will cause an HTTP 413 (Content Too Large) error.
+	MaxTotalFileSize int64
+	// MaxValueSize is the maximum number of bytes
+	// of non-file values that will be held in memory.
+	// If it is zero, then 10 MiB is used.
+	MaxValueSize int64
+
+	// TempDir is the directory to store uploaded files in.
+	// If it is empty, then [os.TempDir] is used.
+	TempDir string
+	// If HandleFile is not nil, then it is called for each file in the form
+	// instead of writing the file to TempDir.
+	// The reader returns an error if the file exceeds the size limits.
+	// HandleFile may return before reaching the end of r.
+	HandleFile func(field string, file *UploadedFile, r io.Reader) error
+}
+
+// MultipartForm is a parsed multipart/form-data request body.
+type MultipartForm struct {
+	Value url.Values
+	File  map[string][]*UploadedFile
+}
+
+// UploadedFile describes a file part in a [MultipartForm].
+type UploadedFile struct {
+	Filename string
+	Header   textproto.MIMEHeader
+	// Size is the number of bytes in the file.
+	Size int64
+
+	tempPath string
+}
+
+// Open opens the file's temporary copy.
+// It returns an error if the file was consumed by [MultipartOptions.HandleFile].
+func (f *UploadedFile) Open() (*os.File, error) {
+	if f.tempPath == "" {
+		return nil, fmt.Errorf("open %s: file not stored", f.Filename)
+	}
+	return os.Open(f.tempPath)
+}
+
+// ParseMultipartForm reads a multipart/form-data request body
+// part-by-part, storing files in temporary files
+// (or passing them to [MultipartOptions.HandleFile])
+// instead of buffering them in memory like [*http.Request.ParseMultipartForm].
+// The returned cleanup function removes any temporary files
+// and is suitable for returning from [Config.TransformRequest].
+//
+// Errors returned by ParseMultipartForm have status codes set with [WithStatusCode].
+func ParseMultipartForm(r *http.Request, opts *MultipartOptions) (form *MultipartForm, cleanup func(), err error) {
+	if opts == nil {
+		opts = new(MultipartOptions)
+	}
+	mr, err := r.MultipartReader()
+	if errors.Is(err, http.ErrNotMultipart) {
+		return nil, nil, WithStatusCode(http.StatusUnsupportedMediaType, fmt.Errorf("parse multipart form: %w", err))
+	}
+	if err != nil {
+		return nil, nil, WithStatusCode(http.StatusBadRequest, fmt.Errorf("parse multipart form: %w", err))
+	}
+
+	parsed := &MultipartForm{
+		Value: make(url.Values),
+		File:  make(map[string][]*UploadedFile),
+	}
+	defer func() {
+		if err != nil {
+			parsed.removeAll()
+		}
+	}()
+	maxValueSize := opts.MaxValueSize
+	if maxValueSize <= 0 {
+		maxValueSize = defaultMaxMultipartValueSize
+	}
+	var valueSize, totalFileSize int64
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return parsed, parsed.removeAll, nil
+		}
+		if err != nil {
+			return nil, nil, multipartReadError(err)
+		}
+		field := part.FormName()
+		if field == "" {
+			part.Close()
+			continue
+		}
+		filename := part.FileName()
+		if filename == "" {
+			data, err := io.ReadAll(io.LimitReader(part, maxValueSize-valueSize+1))
+			part.Close()
+			if err != nil {
+				return nil, nil, multipartReadError(err)
+			}
+			valueSize += int64(len(data))
+			if valueSize > maxValueSize {
+				return nil, nil, WithStatusCode(http.StatusRequestEntityTooLarge, errors.New("parse multipart form: values too large"))
+			}
+			parsed.Value.Add(field, string(data))
+			continue
+		}
+
+		file := &UploadedFile{
+			Filename: filename,
+			Header:   part.Header,
+		}
+		lr := &fileSizeReader{r: part, n: -1, filename: filename}
+		if opts.MaxFileSize > 0 {
+			lr.n = opts.MaxFileSize
+		}
+		if opts.MaxTotalFileSize > 0 {
+			if remaining := opts.MaxTotalFileSize - totalFileSize; lr.n < 0 || remaining < lr.n {
+				lr.n = remaining
+			}
+		}
+		if opts.HandleFile != nil {
+			err = opts.HandleFile(field, file, lr)
+		} else {
+			err = storeTempFile(opts.TempDir, file, lr)
+		}
+		part.Close()
+		file.Size = lr.read
+		totalFileSize += lr.read
+		if file.tempPath != "" || opts.HandleFile != nil {
+			parsed.File[field] = append(parsed.File[field], file)
+		}
+		if lr.err != nil {
+			return nil, nil, lr.err
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse multipart form: %s: %w", filename, err)
+		}
+	}
+}
+
+func storeTempFile(dir string, file *UploadedFile, r io.Reader) error {
+	f, err := os.CreateTemp(dir, "multipart-")
+	if err != nil {
+		return err
+	}
+	file.tempPath = f.Name()
+	_, err = io.Copy(f, r)
+	closeErr := f.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+func (form *MultipartForm) removeAll() {
+	for _, files := range form.File {
+		for _, f := range files {
+			if f.tempPath != "" {
+				os.Remove(f.tempPath)
+			}
+		}
+	}
+}
+
+func multipartReadError(err error) error {
+	return WithStatusCode(http.StatusBadRequest, fmt.Errorf("parse multipart form: %w", err))
+}
+
+// fileSizeReader reads from r until more than n bytes have been read,
+// at which point it returns an HTTP 413 error.
+// If n is negative, then fileSizeReader does not limit the number of bytes.
+type fileSizeReader struct {
+	r        io.Reader
+	n        int64
+	read     int64
+	filename string
+	err      error
+}
+
+func (lr *fileSizeReader) Read(p []byte) (int, error) {
+	if lr.err != nil {
+		return 0, lr.err
+	}
+	if lr.n >= 0 && int64(len(p)) > lr.n-lr.read+1 {
+		p = p[:lr.n-lr.read+1]
+	}
+	n, err := lr.r.Read(p)
+	lr.read += int64(n)
+	if lr.n >= 0 && lr.read > lr.n {
+		n -= int(lr.read - lr.n)
+		lr.read = lr.n
+		lr.err = WithStatusCode(http.StatusRequestEntityTooLarge, fmt.Errorf("parse multipart form: %s: file too large", lr.filename))
+		return n, lr.err
+	}
+	return n, err
+}
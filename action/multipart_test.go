@@ -0,0 +1,162 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseMultipartForm(t *testing.T) {
+	newRequest := func(t *testing.T, files map[string]string) *http.Request {
+		t.Helper()
+		body := new(bytes.Buffer)
+		w := multipart.NewWriter(body)
+		if err := w.WriteField("title", "Hello"); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			fw, err := w.CreateFormFile("upload", name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.WriteString(fw, content); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(http.MethodPost, "/", body)
+		r.Header.Set("Content-Type", w.FormDataContentType())
+		return r
+	}
+
+	t.Run("TempFile", func(t *testing.T) {
+		r := newRequest(t, map[string]string{"foo.txt": "Hello, World!\n"})
+		form, cleanup, err := ParseMultipartForm(r, &MultipartOptions{
+			MaxFileSize: 1024,
+			TempDir:     t.TempDir(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := form.Value.Get("title"), "Hello"; got != want {
+			t.Errorf("form.Value.Get(%q) = %q; want %q", "title", got, want)
+		}
+		if len(form.File["upload"]) != 1 {
+			t.Fatalf("len(form.File[%q]) = %d; want 1", "upload", len(form.File["upload"]))
+		}
+		file := form.File["upload"][0]
+		if got, want := file.Filename, "foo.txt"; got != want {
+			t.Errorf("Filename = %q; want %q", got, want)
+		}
+		if got, want := file.Size, int64(len("Hello, World!\n")); got != want {
+			t.Errorf("Size = %d; want %d", got, want)
+		}
+		f, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := readAllString(f)
+		f.Close()
+		if err != nil {
+			t.Error(err)
+		}
+		if want := "Hello, World!\n"; got != want {
+			t.Errorf("content = %q; want %q", got, want)
+		}
+
+		cleanup()
+		if _, err := os.Stat(file.tempPath); !os.IsNotExist(err) {
+			t.Errorf("after cleanup, os.Stat(%q) = _, %v; want not exist", file.tempPath, err)
+		}
+	})
+
+	t.Run("HandleFile", func(t *testing.T) {
+		r := newRequest(t, map[string]string{"foo.txt": "Hello, World!\n"})
+		var got string
+		form, cleanup, err := ParseMultipartForm(r, &MultipartOptions{
+			HandleFile: func(field string, file *UploadedFile, r io.Reader) error {
+				sb := new(strings.Builder)
+				_, err := io.Copy(sb, r)
+				got = sb.String()
+				return err
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cleanup()
+		if want := "Hello, World!\n"; got != want {
+			t.Errorf("content = %q; want %q", got, want)
+		}
+		if len(form.File["upload"]) != 1 {
+			t.Fatalf("len(form.File[%q]) = %d; want 1", "upload", len(form.File["upload"]))
+		}
+		if _, err := form.File["upload"][0].Open(); err == nil {
+			t.Error("Open did not return an error for a handled file")
+		}
+	})
+
+	t.Run("FileTooLarge", func(t *testing.T) {
+		dir := t.TempDir()
+		r := newRequest(t, map[string]string{"foo.txt": "Hello, World!\n"})
+		_, _, err := ParseMultipartForm(r, &MultipartOptions{
+			MaxFileSize: 5,
+			TempDir:     dir,
+		})
+		if got, want := ErrorStatusCode(err), http.StatusRequestEntityTooLarge; got != want {
+			t.Errorf("ErrorStatusCode(%v) = %d; want %d", err, got, want)
+		}
+		if entries, err := os.ReadDir(dir); err != nil {
+			t.Error(err)
+		} else if len(entries) > 0 {
+			t.Errorf("%d temporary files left behind", len(entries))
+		}
+	})
+
+	t.Run("TotalTooLarge", func(t *testing.T) {
+		r := newRequest(t, map[string]string{
+			"foo.txt": "Hello, World!\n",
+			"bar.txt": "Goodbye, World!\n",
+		})
+		_, _, err := ParseMultipartForm(r, &MultipartOptions{
+			MaxFileSize:      20,
+			MaxTotalFileSize: 20,
+			TempDir:          t.TempDir(),
+		})
+		if got, want := ErrorStatusCode(err), http.StatusRequestEntityTooLarge; got != want {
+			t.Errorf("ErrorStatusCode(%v) = %d; want %d", err, got, want)
+		}
+	})
+
+	t.Run("NotMultipart", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("title=Hello"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		_, _, err := ParseMultipartForm(r, nil)
+		if got, want := ErrorStatusCode(err), http.StatusUnsupportedMediaType; got != want {
+			t.Errorf("ErrorStatusCode(%v) = %d; want %d", err, got, want)
+		}
+	})
+}
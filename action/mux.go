@@ -0,0 +1,233 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// A Mux routes requests to a [Func] by method and path.
+// Every route is served with the Mux's Config,
+// so the Config's TransformRequest can use [PathParams]
+// to fill in the request type from the path.
+// Paths that match a route but not its method
+// are handled as described in [Methods].
+// Paths that match no route are served [ErrNotFound].
+//
+// Mux is experimental and its API may change.
+type Mux[R any] struct {
+	cfg  *Config[R]
+	base *Handler[R]
+
+	mu     sync.RWMutex
+	routes []*muxRoute[R]
+}
+
+type muxRoute[R any] struct {
+	pattern  string
+	segments []string
+	methods  *Methods[R]
+}
+
+// NewMux returns a new [Mux] with no routes that uses the given Config.
+// A nil Config is treated the same as a zero Config.
+func NewMux[R any](cfg *Config[R]) *Mux[R] {
+	return &Mux[R]{
+		cfg:  cfg,
+		base: cfg.NewHandler(nil),
+	}
+}
+
+// Handle registers f to handle requests with the given method and path pattern.
+// Patterns are slash-separated paths, like "/users/{id}/posts".
+// A segment of the form "{name}" matches any single non-empty path segment,
+// and a final segment of the form "{name...}" matches the rest of the path.
+// Literal segments take precedence over parameters.
+//
+// Handle panics if the pattern is malformed,
+// if method is not one of the methods supported by [Methods],
+// or if the method and pattern were already registered.
+// Handle must not be called after the Mux starts serving requests.
+func (mux *Mux[R]) Handle(method, pattern string, f Func[R]) {
+	segments, err := parsePattern(pattern)
+	if err != nil {
+		panic("action.Mux.Handle: " + err.Error())
+	}
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	var route *muxRoute[R]
+	for _, r := range mux.routes {
+		if r.pattern == pattern {
+			route = r
+			break
+		}
+	}
+	if route == nil {
+		route = &muxRoute[R]{
+			pattern:  pattern,
+			segments: segments,
+			methods:  &Methods[R]{Config: mux.cfg, base: mux.base},
+		}
+		mux.routes = append(mux.routes, route)
+	}
+	var field *Func[R]
+	switch method {
+	case http.MethodGet:
+		field = &route.methods.Get
+	case http.MethodHead:
+		field = &route.methods.Head
+	case http.MethodPost:
+		field = &route.methods.Post
+	case http.MethodPut:
+		field = &route.methods.Put
+	case http.MethodPatch:
+		field = &route.methods.Patch
+	case http.MethodDelete:
+		field = &route.methods.Delete
+	default:
+		panic(fmt.Sprintf("action.Mux.Handle: unsupported method %q", method))
+	}
+	if *field != nil {
+		panic(fmt.Sprintf("action.Mux.Handle: %s %s registered more than once", method, pattern))
+	}
+	*field = f
+}
+
+// ServeHTTP dispatches the request to the route that best matches its path.
+func (mux *Mux[R]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux.mu.RLock()
+	var best *muxRoute[R]
+	var bestParams map[string]string
+	bestScore := -1
+	for _, route := range mux.routes {
+		params, score, ok := matchPattern(route.segments, r.URL.Path)
+		if ok && score > bestScore {
+			best, bestParams, bestScore = route, params, score
+		}
+	}
+	mux.mu.RUnlock()
+	if best == nil {
+		mux.base.serveError(w, r, ErrNotFound)
+		return
+	}
+	if len(bestParams) > 0 {
+		r = r.WithContext(context.WithValue(r.Context(), pathParamsContextKey{}, bestParams))
+	}
+	best.methods.ServeHTTP(w, r)
+}
+
+type pathParamsContextKey struct{}
+
+// PathParams returns the path parameters of a request routed by a [Mux].
+// The returned map must not be modified.
+func PathParams(r *http.Request) map[string]string {
+	params, _ := r.Context().Value(pathParamsContextKey{}).(map[string]string)
+	return params
+}
+
+// parsePattern splits a route pattern into segments.
+func parsePattern(pattern string) ([]string, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("pattern %q does not start with '/'", pattern)
+	}
+	segments := strings.Split(pattern[1:], "/")
+	seen := make(map[string]bool)
+	for i, seg := range segments {
+		if !strings.HasPrefix(seg, "{") && !strings.HasSuffix(seg, "}") {
+			continue
+		}
+		name, rest := paramName(seg)
+		if name == "" || !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			return nil, fmt.Errorf("pattern %q: invalid parameter %q", pattern, seg)
+		}
+		if rest && i != len(segments)-1 {
+			return nil, fmt.Errorf("pattern %q: %q must be the last segment", pattern, seg)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("pattern %q: duplicate parameter %q", pattern, name)
+		}
+		seen[name] = true
+	}
+	return segments, nil
+}
+
+// paramName returns the name of the parameter in a segment like "{id}"
+// and whether it matches the rest of the path, as in "{path...}".
+// It returns the empty string if seg is not a parameter.
+func paramName(seg string) (name string, rest bool) {
+	if len(seg) < 2 || seg[0] != '{' || seg[len(seg)-1] != '}' {
+		return "", false
+	}
+	name = seg[1 : len(seg)-1]
+	if strings.HasSuffix(name, "...") {
+		name, rest = strings.TrimSuffix(name, "..."), true
+	}
+	if strings.ContainsAny(name, "{}/") {
+		return "", false
+	}
+	return name, rest
+}
+
+// matchPattern reports whether path matches the pattern segments.
+// If so, it returns the path parameters
+// and a score that is higher for patterns with more literal segments.
+func matchPattern(segments []string, path string) (params map[string]string, score int, ok bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, 0, false
+	}
+	path = path[1:]
+	for i, seg := range segments {
+		name, rest := paramName(seg)
+		if rest {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[name] = path
+			return params, score, true
+		}
+		var elem string
+		if j := strings.IndexByte(path, '/'); j >= 0 {
+			elem, path = path[:j], path[j+1:]
+		} else if i == len(segments)-1 {
+			elem, path = path, ""
+		} else {
+			return nil, 0, false
+		}
+		if name == "" {
+			if elem != seg {
+				return nil, 0, false
+			}
+			score++
+			continue
+		}
+		if elem == "" {
+			return nil, 0, false
+		}
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[name] = elem
+	}
+	if path != "" {
+		return nil, 0, false
+	}
+	return params, score, true
+}
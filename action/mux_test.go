@@ -0,0 +1,124 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMux(t *testing.T) {
+	type userRequest struct {
+		route string
+		id    string
+		rest  string
+	}
+	mux := NewMux(&Config[*userRequest]{
+		TransformRequest: func(r *http.Request) (*userRequest, func(), error) {
+			params := PathParams(r)
+			return &userRequest{id: params["id"], rest: params["rest"]}, nil, nil
+		},
+	})
+	reply := func(route string) Func[*userRequest] {
+		return func(ctx context.Context, req *userRequest) (*Response, error) {
+			return &Response{Other: []*Representation{
+				TextRepresentation(route + " id=" + req.id + " rest=" + req.rest),
+			}}, nil
+		}
+	}
+	mux.Handle(http.MethodGet, "/", reply("index"))
+	mux.Handle(http.MethodGet, "/users/{id}", reply("user"))
+	mux.Handle(http.MethodDelete, "/users/{id}", reply("deleteUser"))
+	mux.Handle(http.MethodGet, "/users/new", reply("newUser"))
+	mux.Handle(http.MethodGet, "/files/{rest...}", reply("files"))
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantAllow  string
+		wantBody   string
+	}{
+		{method: http.MethodGet, path: "/", wantStatus: http.StatusOK, wantBody: "index id= rest="},
+		{method: http.MethodGet, path: "/users/42", wantStatus: http.StatusOK, wantBody: "user id=42 rest="},
+		{method: http.MethodDelete, path: "/users/42", wantStatus: http.StatusOK, wantBody: "deleteUser id=42 rest="},
+		{method: http.MethodGet, path: "/users/new", wantStatus: http.StatusOK, wantBody: "newUser id= rest="},
+		{method: http.MethodGet, path: "/files/a/b.txt", wantStatus: http.StatusOK, wantBody: "files id= rest=a/b.txt"},
+		{method: http.MethodGet, path: "/users/", wantStatus: http.StatusNotFound, wantBody: "404 not found"},
+		{method: http.MethodGet, path: "/users/42/posts", wantStatus: http.StatusNotFound, wantBody: "404 not found"},
+		{
+			method:     http.MethodOptions,
+			path:       "/users/42",
+			wantStatus: http.StatusNoContent,
+			wantAllow:  "GET, HEAD, DELETE, OPTIONS",
+		},
+		{
+			method:     http.MethodPost,
+			path:       "/users/new",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantAllow:  "GET, HEAD, OPTIONS",
+			wantBody:   "405 method not allowed",
+		},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, test.path, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		if rec.Code != test.wantStatus {
+			t.Errorf("%s %s status = %d; want %d", test.method, test.path, rec.Code, test.wantStatus)
+		}
+		if got := rec.Header().Get("Allow"); got != test.wantAllow {
+			t.Errorf("%s %s Allow = %q; want %q", test.method, test.path, got, test.wantAllow)
+		}
+		if got := rec.Body.String(); got != test.wantBody {
+			t.Errorf("%s %s body = %q; want %q", test.method, test.path, got, test.wantBody)
+		}
+	}
+}
+
+func TestMuxHandlePanics(t *testing.T) {
+	tests := []struct {
+		method  string
+		pattern string
+	}{
+		{http.MethodGet, "users"},
+		{http.MethodGet, "/users/{id"},
+		{http.MethodGet, "/users/{}"},
+		{http.MethodGet, "/{rest...}/more"},
+		{http.MethodGet, "/{id}/{id}"},
+		{http.MethodGet, "/dup"},
+		{"CONNECT", "/"},
+	}
+	mux := NewMux[*http.Request](nil)
+	mux.Handle(http.MethodGet, "/dup", func(ctx context.Context, r *http.Request) (*Response, error) {
+		return nil, nil
+	})
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Handle(%q, %q, ...) did not panic", test.method, test.pattern)
+				}
+			}()
+			mux.Handle(test.method, test.pattern, func(ctx context.Context, r *http.Request) (*Response, error) {
+				return nil, nil
+			})
+		}()
+	}
+}
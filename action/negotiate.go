@@ -0,0 +1,146 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"zombiezen.com/go/bass/accept"
+)
+
+// A NegotiationTrace describes how a [Handler] chose
+// the representation of a response.
+// It is passed to the [Config] TraceNegotiation hook.
+type NegotiationTrace struct {
+	// Accept is the request's Accept header,
+	// as formatted by [accept.Header.String].
+	Accept string
+	// Candidates is the list of representations that were considered,
+	// in the order they were offered.
+	Candidates []NegotiationCandidate
+	// Chosen is the index in Candidates of the chosen representation.
+	Chosen int
+}
+
+// A NegotiationCandidate is a representation considered during content negotiation.
+// Candidates are ranked by Quality,
+// then by the specificity of Range (see [accept.MediaRange.Specificity]),
+// then by Preference, and finally by the order they were offered.
+type NegotiationCandidate struct {
+	ContentType string
+	// Quality is the quality the Accept header assigns to ContentType.
+	Quality float32
+	// Range is the most specific media range in the Accept header
+	// that applies to ContentType,
+	// or the empty string if none apply.
+	Range string
+	// Preference is the index of the content type
+	// in the [Config] PreferContentTypes list,
+	// or -1 if it is not listed.
+	Preference int
+}
+
+// negotiation holds the server's settings for content negotiation.
+type negotiation struct {
+	prefer []string
+	trace  func(context.Context, *NegotiationTrace)
+}
+
+// choose returns the preferred representation from possibilities
+// and reports the decision to n.trace, if set.
+func (n negotiation) choose(ctx context.Context, possibilities []parsedRepresentation, acceptHeader accept.Header) *parsedRepresentation {
+	p := preferredRepresentation(possibilities, acceptHeader, n.prefer)
+	if n.trace == nil || p == nil {
+		return p
+	}
+	trace := &NegotiationTrace{
+		Accept:     acceptHeader.String(),
+		Candidates: make([]NegotiationCandidate, len(possibilities)),
+	}
+	for i := range possibilities {
+		pi := &possibilities[i]
+		if pi == p {
+			trace.Chosen = i
+		}
+		c := &trace.Candidates[i]
+		c.ContentType = pi.contentType
+		c.Quality = acceptHeader.Quality(pi.mediaType, pi.typeParams)
+		if mr := acceptHeader.MatchingRange(pi.mediaType, pi.typeParams); mr != nil {
+			c.Range = mr.String()
+		}
+		c.Preference = preferenceIndex(n.prefer, pi.mediaType)
+	}
+	n.trace(ctx, trace)
+	return p
+}
+
+// preferredRepresentation returns the user's most preferred representation from the list,
+// or nil if the list is empty.
+// Representations are ranked by [accept.Header.Sort],
+// after ordering them by the position of their media type in prefer,
+// so the server's preference breaks ties
+// that quality and specificity do not.
+// Any remaining ties go to the representation listed first.
+// If no representation is acceptable, the first one is returned.
+func preferredRepresentation(possibilities []parsedRepresentation, acceptHeader accept.Header, prefer []string) *parsedRepresentation {
+	if len(possibilities) == 0 {
+		return nil
+	}
+	if len(prefer) == 0 && acceptHeader.IsWildcard() {
+		return &possibilities[0]
+	}
+	order := make([]int, len(possibilities))
+	for i := range order {
+		order[i] = i
+	}
+	if len(prefer) > 0 {
+		rank := func(i int) int {
+			if j := preferenceIndex(prefer, possibilities[i].mediaType); j >= 0 {
+				return j
+			}
+			return len(prefer)
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return rank(order[i]) < rank(order[j])
+		})
+	}
+	offers := make([]accept.Offer, len(order))
+	for i, j := range order {
+		offers[i] = accept.Offer{
+			ContentType: possibilities[j].mediaType,
+			Params:      possibilities[j].typeParams,
+		}
+	}
+	best := acceptHeader.Best(offers)
+	if best < 0 {
+		return &possibilities[0]
+	}
+	return &possibilities[order[best]]
+}
+
+// preferenceIndex returns the index of mediaType in prefer
+// or -1 if it is not present.
+func preferenceIndex(prefer []string, mediaType string) int {
+	for i, t := range prefer {
+		if strings.EqualFold(t, mediaType) {
+			return i
+		}
+	}
+	return -1
+}
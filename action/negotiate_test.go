@@ -0,0 +1,159 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"zombiezen.com/go/bass/accept"
+)
+
+func TestPreferredRepresentation(t *testing.T) {
+	possibilities := []parsedRepresentation{
+		{contentType: "text/html; charset=utf-8", mediaType: "text/html", typeParams: map[string]string{"charset": "utf-8"}},
+		{contentType: "application/json; charset=utf-8", mediaType: "application/json", typeParams: map[string]string{"charset": "utf-8"}},
+		{contentType: "text/plain; charset=utf-8", mediaType: "text/plain", typeParams: map[string]string{"charset": "utf-8"}},
+	}
+	tests := []struct {
+		name   string
+		accept string
+		prefer []string
+		want   string
+	}{
+		{
+			name:   "Empty",
+			accept: "",
+			want:   "text/html",
+		},
+		{
+			name:   "Wildcard",
+			accept: "*/*",
+			want:   "text/html",
+		},
+		{
+			name:   "Quality",
+			accept: "text/html;q=0.5, application/json",
+			want:   "application/json",
+		},
+		{
+			name:   "ExplicitBeatsWildcard",
+			accept: "*/*, application/json",
+			want:   "application/json",
+		},
+		{
+			name:   "ExplicitBeatsTypeWildcard",
+			accept: "text/*, text/plain",
+			want:   "text/plain",
+		},
+		{
+			name:   "ParamsBeatSubtype",
+			accept: "text/html, text/plain;charset=utf-8",
+			want:   "text/plain",
+		},
+		{
+			name:   "WildcardPreference",
+			accept: "*/*",
+			prefer: []string{"application/json"},
+			want:   "application/json",
+		},
+		{
+			name:   "EmptyPreference",
+			accept: "",
+			prefer: []string{"text/plain", "application/json"},
+			want:   "text/plain",
+		},
+		{
+			name:   "PreferenceAfterSpecificity",
+			accept: "text/html, */*",
+			prefer: []string{"application/json"},
+			want:   "text/html",
+		},
+		{
+			name:   "PreferenceAfterQuality",
+			accept: "text/html, */*;q=0.5",
+			prefer: []string{"application/json"},
+			want:   "text/html",
+		},
+		{
+			name:   "PreferenceCaseInsensitive",
+			accept: "text/*",
+			prefer: []string{"Text/Plain"},
+			want:   "text/plain",
+		},
+		{
+			name:   "NoneAcceptable",
+			accept: "image/png, application/json;q=0",
+			prefer: []string{"application/json"},
+			want:   "text/html",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			acceptHeader, err := accept.ParseHeader(test.accept)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := preferredRepresentation(possibilities, acceptHeader, test.prefer)
+			if got == nil || got.mediaType != test.want {
+				t.Errorf("preferredRepresentation(..., %q, %q) = %+v; want %s", test.accept, test.prefer, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTraceNegotiation(t *testing.T) {
+	var got *NegotiationTrace
+	cfg := &Config[*http.Request]{
+		TransformRequest:   identity,
+		PreferContentTypes: []string{"text/plain"},
+		TraceNegotiation: func(ctx context.Context, trace *NegotiationTrace) {
+			got = trace
+		},
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{
+			JSONValue: "hi",
+			Other:     []*Representation{TextRepresentation("hi")},
+		}, nil
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/*, text/plain;q=0.9, */*;q=0.1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got == nil {
+		t.Fatal("TraceNegotiation not called")
+	}
+	want := &NegotiationTrace{
+		Accept: "application/*,text/plain;q=0.9,*/*;q=0.1",
+		Candidates: []NegotiationCandidate{
+			{ContentType: "application/json; charset=utf-8", Quality: 1, Range: "application/*", Preference: -1},
+			{ContentType: "text/plain; charset=utf-8", Quality: 0.9, Range: "text/plain;q=0.9", Preference: 0},
+		},
+		Chosen: 0,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("trace (-want +got):\n%s", diff)
+	}
+	if ct := rec.Result().Header.Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q; want %q", ct, "application/json; charset=utf-8")
+	}
+}
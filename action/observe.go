@@ -0,0 +1,142 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes a request served by a [Handler].
+// It is passed to the [Config] OnRequestEnd callback.
+type RequestInfo struct {
+	// Method is the request's HTTP method.
+	Method string
+	// Path is the request's URL path.
+	Path string
+
+	// StatusCode is the response's HTTP status code.
+	StatusCode int
+	// ContentType is the response's Content-Type header,
+	// which reflects the outcome of content negotiation.
+	// It is empty for responses without a body, like 304 (Not Modified).
+	ContentType string
+	// Template is the name of the template file used to render the response,
+	// if any. For a localized HTMLTemplate, it is the name of the chosen variant.
+	Template string
+	// CacheHit is true if the response was served from the [Config] Cache.
+	CacheHit bool
+	// BytesWritten is the number of body bytes written to the client.
+	BytesWritten int64
+	// Err is the error that the response was rendered from, if any:
+	// either the error returned by the [Func]
+	// or an error raised before calling it, like a CSRF failure.
+	// Errors that occur while rendering are passed to ReportError instead.
+	Err error
+
+	// Duration is the time taken to serve the request.
+	Duration time.Duration
+}
+
+type requestInfoContextKey struct{}
+
+func requestInfoFromContext(ctx context.Context) *RequestInfo {
+	info, _ := ctx.Value(requestInfoContextKey{}).(*RequestInfo)
+	return info
+}
+
+// setTemplate records the template used to render the response.
+// It is a no-op if info is nil.
+func (info *RequestInfo) setTemplate(name string) {
+	if info != nil {
+		info.Template = name
+	}
+}
+
+// setErr records the error that the response was rendered from.
+// It is a no-op if info is nil.
+func (info *RequestInfo) setErr(err error) {
+	if info != nil {
+		info.Err = err
+	}
+}
+
+// observe starts observing a request for the Config's
+// OnRequestStart and OnRequestEnd callbacks.
+// The returned function must be called after the response has been written.
+func (h *Handler[R]) observe(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request, func()) {
+	start := time.Now()
+	ctx := r.Context()
+	if h.cfg.OnRequestStart != nil {
+		ctx = h.cfg.OnRequestStart(ctx, r)
+	}
+	info := &RequestInfo{
+		Method: r.Method,
+		Path:   r.URL.Path,
+	}
+	r = r.WithContext(context.WithValue(ctx, requestInfoContextKey{}, info))
+	ow := &observedResponseWriter{ResponseWriter: w, info: info}
+	return ow, r, func() {
+		info.Duration = time.Since(start)
+		if info.StatusCode == 0 {
+			info.StatusCode = http.StatusOK
+		}
+		if h.cfg.OnRequestEnd != nil {
+			h.cfg.OnRequestEnd(ctx, info)
+		}
+	}
+}
+
+// observedResponseWriter is an [http.ResponseWriter]
+// that records the response in a [RequestInfo].
+type observedResponseWriter struct {
+	http.ResponseWriter
+	info *RequestInfo
+}
+
+func (w *observedResponseWriter) WriteHeader(code int) {
+	if w.info.StatusCode == 0 {
+		w.info.StatusCode = code
+		w.info.ContentType = w.Header().Get(contentTypeHeaderName)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *observedResponseWriter) Write(p []byte) (int, error) {
+	if w.info.StatusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.info.BytesWritten += int64(n)
+	return n, err
+}
+
+// Flush flushes the underlying response writer if it supports flushing.
+func (w *observedResponseWriter) Flush() {
+	flushResponse(w.ResponseWriter)
+}
+
+// FlushError flushes the underlying response writer.
+func (w *observedResponseWriter) FlushError() error {
+	return flushResponse(w.ResponseWriter)
+}
+
+// Unwrap returns the underlying response writer.
+func (w *observedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestRequestHooks(t *testing.T) {
+	type spanKey struct{}
+	var infos []*RequestInfo
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		TemplateFiles: fstest.MapFS{
+			"base.html": {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+			"page.html": {Data: []byte(`{{ define "content" }}<p>Hello</p>{{ end }}`)},
+		},
+		Cache: NewCache(time.Minute),
+		OnRequestStart: func(ctx context.Context, r *http.Request) context.Context {
+			return context.WithValue(ctx, spanKey{}, r.URL.Path)
+		},
+		OnRequestEnd: func(ctx context.Context, info *RequestInfo) {
+			if got, _ := ctx.Value(spanKey{}).(string); got != info.Path {
+				t.Errorf("OnRequestEnd context span = %q; want %q", got, info.Path)
+			}
+			infos = append(infos, info)
+		},
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		if got, _ := ctx.Value(spanKey{}).(string); got != r.URL.Path {
+			t.Errorf("Func context span = %q; want %q", got, r.URL.Path)
+		}
+		if r.URL.Path == "/missing" {
+			return nil, ErrNotFound
+		}
+		return &Response{
+			HTMLTemplate: "page.html",
+			JSONValue:    "Hello",
+		}, nil
+	})
+
+	tests := []struct {
+		path   string
+		accept string
+		want   RequestInfo
+	}{
+		{
+			path:   "/",
+			accept: "text/html",
+			want: RequestInfo{
+				Method:       http.MethodGet,
+				Path:         "/",
+				StatusCode:   http.StatusOK,
+				ContentType:  "text/html; charset=utf-8",
+				Template:     "page.html",
+				BytesWritten: int64(len("<p>Hello</p>")),
+			},
+		},
+		{
+			path:   "/",
+			accept: "text/html",
+			want: RequestInfo{
+				Method:       http.MethodGet,
+				Path:         "/",
+				StatusCode:   http.StatusOK,
+				ContentType:  "text/html; charset=utf-8",
+				CacheHit:     true,
+				BytesWritten: int64(len("<p>Hello</p>")),
+			},
+		},
+		{
+			path:   "/json",
+			accept: "application/json",
+			want: RequestInfo{
+				Method:       http.MethodGet,
+				Path:         "/json",
+				StatusCode:   http.StatusOK,
+				ContentType:  "application/json; charset=utf-8",
+				BytesWritten: int64(len(`"Hello"`)),
+			},
+		},
+		{
+			path:   "/missing",
+			accept: "text/plain",
+			want: RequestInfo{
+				Method:       http.MethodGet,
+				Path:         "/missing",
+				StatusCode:   http.StatusNotFound,
+				ContentType:  "text/plain; charset=utf-8",
+				BytesWritten: int64(len(ErrNotFound.Error())),
+				Err:          ErrNotFound,
+			},
+		},
+	}
+	for _, test := range tests {
+		infos = nil
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		req.Header.Set("Accept", test.accept)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if len(infos) != 1 {
+			t.Errorf("GET %s (Accept: %s): OnRequestEnd called %d times; want 1", test.path, test.accept, len(infos))
+			continue
+		}
+		got := *infos[0]
+		if got.Duration < 0 {
+			t.Errorf("GET %s (Accept: %s): Duration = %v; want >=0", test.path, test.accept, got.Duration)
+		}
+		got.Duration = 0
+		if !errors.Is(got.Err, test.want.Err) {
+			t.Errorf("GET %s (Accept: %s): Err = %v; want %v", test.path, test.accept, got.Err, test.want.Err)
+		}
+		got.Err, test.want.Err = nil, nil
+		if got != test.want {
+			t.Errorf("GET %s (Accept: %s): info = %+v; want %+v", test.path, test.accept, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"net/http"
+	"strings"
+
+	"zombiezen.com/go/bass/accept"
+)
+
+const (
+	preferHeaderName            = "Prefer"
+	preferenceAppliedHeaderName = "Preference-Applied"
+)
+
+// prefersMinimal reports whether r is a write
+// (any method other than GET or HEAD)
+// that asks for a minimal response with "Prefer: return=minimal".
+// A malformed Prefer header is ignored.
+func prefersMinimal(r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return false
+	}
+	values := r.Header.Values(preferHeaderName)
+	if len(values) == 0 {
+		return false
+	}
+	prefer, err := accept.ParsePreferHeader(strings.Join(values, ","))
+	return err == nil && prefer.Return() == accept.ReturnMinimal
+}
+
+// writeMinimal sends a successful response without a body
+// in response to "Prefer: return=minimal".
+// A 200 (OK) status is replaced by 204 (No Content).
+func writeMinimal(w http.ResponseWriter, statusCode int) {
+	h := w.Header()
+	var vary accept.Vary
+	vary.Add(preferHeaderName)
+	vary.Set(h)
+	h.Set(preferenceAppliedHeaderName, "return="+accept.ReturnMinimal)
+	if statusCode == 0 || statusCode == http.StatusOK {
+		statusCode = http.StatusNoContent
+	}
+	if statusCode != http.StatusNoContent {
+		h.Set(contentLengthHeaderName, "0")
+	}
+	w.WriteHeader(statusCode)
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReturnMinimal(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		prefer     string
+		statusCode int
+		wantCode   int
+		wantBody   bool
+	}{
+		{
+			name:     "NoPreference",
+			method:   http.MethodPost,
+			wantCode: http.StatusOK,
+			wantBody: true,
+		},
+		{
+			name:     "Minimal",
+			method:   http.MethodPost,
+			prefer:   "return=minimal",
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:       "MinimalCreated",
+			method:     http.MethodPut,
+			prefer:     "return=minimal",
+			statusCode: http.StatusCreated,
+			wantCode:   http.StatusCreated,
+		},
+		{
+			name:     "Representation",
+			method:   http.MethodPost,
+			prefer:   "return=representation",
+			wantCode: http.StatusOK,
+			wantBody: true,
+		},
+		{
+			name:     "Get",
+			method:   http.MethodGet,
+			prefer:   "return=minimal",
+			wantCode: http.StatusOK,
+			wantBody: true,
+		},
+		{
+			name:       "Error",
+			method:     http.MethodPost,
+			prefer:     "return=minimal",
+			statusCode: http.StatusUnprocessableEntity,
+			wantCode:   http.StatusUnprocessableEntity,
+			wantBody:   true,
+		},
+		{
+			name:     "Malformed",
+			method:   http.MethodPost,
+			prefer:   "return=minimal wait",
+			wantCode: http.StatusOK,
+			wantBody: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config[*http.Request]{TransformRequest: identity}
+			called := false
+			h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+				called = true
+				return &Response{
+					StatusCode: test.statusCode,
+					ETag:       "v2",
+					Other:      []*Representation{TextRepresentation("hello")},
+				}, nil
+			})
+			req := httptest.NewRequest(test.method, "/", nil)
+			if test.prefer != "" {
+				req.Header.Set("Prefer", test.prefer)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if !called {
+				t.Error("Func not called")
+			}
+			if rec.Code != test.wantCode {
+				t.Errorf("status = %d; want %d", rec.Code, test.wantCode)
+			}
+			if got := rec.Header().Get("ETag"); got != `"v2"` {
+				t.Errorf("ETag = %q; want %q", got, `"v2"`)
+			}
+			if test.wantBody {
+				if got, want := rec.Body.String(), "hello"; got != want {
+					t.Errorf("body = %q; want %q", got, want)
+				}
+				if got := rec.Header().Get("Preference-Applied"); got != "" {
+					t.Errorf("Preference-Applied = %q; want empty", got)
+				}
+				return
+			}
+			if rec.Body.Len() > 0 {
+				t.Errorf("body = %q; want empty", rec.Body.String())
+			}
+			if got, want := rec.Header().Get("Preference-Applied"), "return=minimal"; got != want {
+				t.Errorf("Preference-Applied = %q; want %q", got, want)
+			}
+			if got, want := rec.Header().Get("Vary"), "Prefer"; got != want {
+				t.Errorf("Vary = %q; want %q", got, want)
+			}
+		})
+	}
+}
@@ -31,8 +31,12 @@ import (
 	"strconv"
 	"strings"
 	texttemplate "text/template"
+	"time"
 
+	"google.golang.org/protobuf/proto"
 	"zombiezen.com/go/bass/accept"
+	"zombiezen.com/go/bass/flashkv"
+	"zombiezen.com/go/bass/sse"
 	"zombiezen.com/go/bass/templateloader"
 	"zombiezen.com/go/bass/turbostream"
 )
@@ -44,9 +48,11 @@ const (
 )
 
 const (
-	htmlType  = "text/html"
-	plainType = "text/plain"
-	jsonType  = "application/json"
+	htmlType     = "text/html"
+	plainType    = "text/plain"
+	jsonType     = "application/json"
+	msgpackType  = "application/x-msgpack"
+	protobufType = "application/x-protobuf"
 )
 
 const charsetUTF8Params = "; charset=utf-8"
@@ -56,6 +62,10 @@ const charsetUTF8Params = "; charset=utf-8"
 // which will be selected via [content negotiation].
 // A nil or zero Response represents an HTTP 204 (No Content) response.
 //
+// If a request other than GET or HEAD sends "Prefer: return=minimal",
+// a successful Response is sent without a body
+// and a 200 (OK) status is replaced by 204 (No Content).
+//
 // [content negotiation]: https://developer.mozilla.org/en-US/docs/Web/HTTP/Content_negotiation
 type Response struct {
 	// StatusCode is the response's HTTP status code.
@@ -72,21 +82,145 @@ type Response struct {
 	// The provided cookies must have valid names.
 	// Invalid cookies may be silent dropped.
 	SetCookies []*http.Cookie
+	// SetSignedCookies is a list of cookies to add as Set-Cookie headers
+	// after signing their values with the [Config] CookieCodec.
+	// Clients can read but not modify the values;
+	// use [SignedCookie] to read a verified value from a request.
+	// Cookie values may contain any bytes.
+	// If the Handler has no CookieCodec or a value is too large to encode,
+	// the cookie is dropped and an error is reported.
+	SetSignedCookies []*http.Cookie
 
 	// TemplateData is passed to the templates.
 	// See [text/template] for details.
 	TemplateData any
 	// HTMLTemplate names an html/template file to use to present HTML.
+	// If the template files contain localized variants of the template
+	// named with a lowercase language tag before the extension
+	// (like "page.de.html" for "page.html"),
+	// the variant that best matches the request's Accept-Language header is used.
 	HTMLTemplate string
 	// TurboStreamTemplate names an html/template file to use to present Turbo Stream data.
 	TurboStreamTemplate string
+	// TurboStreams is a list of actions to present as Turbo Stream data
+	// to clients that accept text/vnd.turbo-stream.html,
+	// formatted as by [turbostream.Render]
+	// after the output of TurboStreamTemplate, if any.
+	// This allows a handler to return actions
+	// without authoring a separate stream template file.
+	// Each action's template is executed with the same functions
+	// as TurboStreamTemplate (see [turbostream.Action.WithFuncs]).
+	TurboStreams []*turbostream.Action
 	// TextTemplate names a text/template file to use to present plain text.
 	TextTemplate string
 	// JSONValue is a value to marshal to present JSON.
+	// If the [Config] MessagePack option is set,
+	// it is also presented as MessagePack.
 	JSONValue any
+	// ProtoValue is a message to marshal to present
+	// the Protocol Buffers binary format.
+	ProtoValue proto.Message
+	// EventStream is called to present a stream of [Server-Sent Events].
+	// The response headers are sent before EventStream is called,
+	// and each event is flushed to the client as it is sent.
+	// The context is canceled when the client disconnects.
+	// It is offered after the other built-in representations,
+	// so in case of a tie, clients that do not explicitly ask for
+	// text/event-stream (as browsers' EventSource does) get another representation.
+	// Responses with an EventStream are never stored in a [Cache],
+	// and a stream holds its [Config] MaxConcurrent slot until it returns.
+	//
+	// [Server-Sent Events]: https://developer.mozilla.org/en-US/docs/Web/API/Server-sent_events
+	EventStream func(ctx context.Context, w *sse.Writer) error
+
+	// ETag is the response's entity tag, like `"v1"` or `W/"v1"`,
+	// sent in the [ETag header].
+	// A tag that is not enclosed in double quotes is quoted.
+	// If the response has more than one representation,
+	// then the negotiated media type is appended to the tag
+	// (for example, `"v1;text/html"`)
+	// so that each representation has its own tag.
+	// Likewise, if HTMLTemplate has localized variants,
+	// then the language of the chosen variant is appended
+	// (for example, `"v1;de"`).
+	// If the representation may be compressed
+	// (see the [Config] CompressMinSize option),
+	// then a strong tag is sent as a weak tag,
+	// since the compressed and uncompressed bodies differ.
+	// GET and HEAD requests whose If-None-Match header matches the tag
+	// receive a 304 (Not Modified) response without rendering any representation.
+	//
+	// [ETag header]: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/ETag
+	ETag string
+	// ETagKey is a caller-provided key that identifies the data
+	// a response's templates are rendered from,
+	// like a record's ID and update time.
+	// If ETag is empty and ETagKey is not,
+	// then the response is sent with a weak entity tag
+	// derived from a hash of ETagKey and the negotiated representation:
+	// its media type, the name of the template that renders it,
+	// and the language of the template's localized variant, if any.
+	// Templates of other representations do not affect the tag.
+	// This permits cheap 304 (Not Modified) responses
+	// for pages (even authenticated ones) whose data has not changed,
+	// without storing rendered bodies.
+	// The key must change whenever anything that affects the rendered output
+	// other than the template name changes.
+	ETagKey string
+	// CacheControl is the value of the [Cache-Control header].
+	// Responses with a "no-store" or "private" directive
+	// are never stored in a [Cache].
+	//
+	// [Cache-Control header]: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Cache-Control
+	CacheControl string
+	// LastModified is sent in the [Last-Modified header] if it is not zero.
+	// GET and HEAD requests without an If-None-Match header
+	// whose If-Modified-Since header is not before LastModified
+	// receive a 304 (Not Modified) response without rendering any representation.
+	//
+	// [Last-Modified header]: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Last-Modified
+	LastModified time.Time
 
 	// Other lists representations of the response.
 	Other []*Representation
+
+	customReprs []customRepresentation
+}
+
+// A RenderContext holds the information available
+// to a function registered with [*Response.RepresentationFunc].
+type RenderContext struct {
+	// Request is the request value passed to the [Func].
+	// Its type is the type parameter of the [Handler] that produced the response.
+	// It is nil for error responses.
+	Request any
+	// ContentType is the negotiated content type,
+	// as passed to RepresentationFunc.
+	ContentType string
+	// TemplateData is the response's TemplateData.
+	TemplateData any
+	// TemplateFiles is the [Config]'s TemplateFiles.
+	TemplateFiles fs.FS
+	// TemplateFuncs is the set of template functions available to the response.
+	TemplateFuncs template.FuncMap
+}
+
+type customRepresentation struct {
+	contentType string
+	f           func(context.Context, *RenderContext) (*Representation, error)
+}
+
+// RepresentationFunc adds a representation of the response
+// with the given content type that is produced by calling f.
+// f is only called if the content type is chosen by content negotiation,
+// so it can perform expensive work or use encodings that only some clients support.
+// Representations added with RepresentationFunc are preferred
+// over the ones in resp.Other in case of a tie,
+// but not over the built-in template and JSON representations.
+// The representation returned by f should have a Content-Type header;
+// if it does not, then contentType is used.
+func (resp *Response) RepresentationFunc(contentType string, f func(ctx context.Context, rc *RenderContext) (*Representation, error)) {
+	resp.customReprs = append(resp.customReprs, customRepresentation{contentType, f})
 }
 
 // IsEmpty reports whether the response is nil
@@ -97,10 +231,18 @@ func (resp *Response) IsEmpty() bool {
 	}
 	if resp.HTMLTemplate != "" ||
 		resp.TurboStreamTemplate != "" ||
+		len(resp.TurboStreams) > 0 ||
 		resp.TextTemplate != "" ||
-		resp.JSONValue != nil {
+		resp.JSONValue != nil ||
+		resp.ProtoValue != nil ||
+		resp.EventStream != nil {
 		return false
 	}
+	for _, cr := range resp.customReprs {
+		if _, _, err := mime.ParseMediaType(cr.contentType); err == nil {
+			return false
+		}
+	}
 	for _, repr := range resp.Other {
 		if _, _, err := mime.ParseMediaType(repr.Header.Get(contentTypeHeaderName)); err == nil {
 			return false
@@ -136,6 +278,11 @@ func (resp *Response) Close() error {
 type Representation struct {
 	Header http.Header
 	Body   io.ReadCloser
+
+	// file is non-nil for representations created by FileRepresentation.
+	file *fileContent
+	// stream is non-nil for representations created by StreamRepresentation.
+	stream func(context.Context, *StreamWriter) error
 }
 
 // TextRepresentation creates a plain text representation of a string.
@@ -149,41 +296,169 @@ func TextRepresentation(s string) *Representation {
 	}
 }
 
+// BytesRepresentation creates a representation of a byte slice
+// with the given Content-Type.
+// The byte slice must not be modified after calling BytesRepresentation.
+func BytesRepresentation(contentType string, data []byte) *Representation {
+	return &Representation{
+		Header: http.Header{
+			contentTypeHeaderName:   {contentType},
+			contentLengthHeaderName: {strconv.Itoa(len(data))},
+		},
+		Body: io.NopCloser(bytes.NewReader(data)),
+	}
+}
+
 // Write copies the representation to the response writer.
 func (repr *Representation) Write(w http.ResponseWriter, code int) error {
-	return repr.write(w, code, false)
+	return repr.write(context.Background(), w, code, false)
 }
 
-func (repr *Representation) write(w http.ResponseWriter, code int, head bool) error {
+func (repr *Representation) write(ctx context.Context, w http.ResponseWriter, code int, head bool) error {
 	if repr.Header.Get(contentTypeHeaderName) == "" {
 		return fmt.Errorf("write representation: does not have a %s header", contentTypeHeaderName)
 	}
-	h := w.Header()
-	for k, v := range repr.Header {
-		h[k] = append(h[k], v...)
-	}
-	if len(h[contentTypeOptionsHeaderName]) == 0 {
-		h.Set(contentTypeOptionsHeaderName, "nosniff")
-	}
+	repr.setHeaders(w.Header())
 	w.WriteHeader(code)
 	if !head {
 		return nil
 	}
+	if repr.stream != nil {
+		return repr.stream(ctx, &StreamWriter{w: w})
+	}
 	_, err := io.Copy(w, repr.Body)
 	return err
 }
 
+// setHeaders adds the representation's headers to h.
+func (repr *Representation) setHeaders(h http.Header) {
+	for k, v := range repr.Header {
+		if isSecurityHeader(k) {
+			h[k] = append([]string(nil), v...)
+		} else {
+			h[k] = append(h[k], v...)
+		}
+	}
+	if len(h[contentTypeOptionsHeaderName]) == 0 {
+		h.Set(contentTypeOptionsHeaderName, "nosniff")
+	}
+}
+
 type renderOptions struct {
 	reqMethod    string
 	reqPath      string
+	isTLS        bool
 	acceptHeader accept.Header
+	// acceptBuf is storage for acceptHeader
+	// so that parsing typical Accept headers does not allocate.
+	acceptBuf [8]accept.MediaRange
+	// negotiation holds the server's tie-breaking preferences
+	// for choosing among representations.
+	negotiation negotiation
+
+	templateFiles   fs.FS
+	templates       *templateloader.Cache
+	templateFuncs   template.FuncMap
+	reportError     func(context.Context, error)
+	securityHeaders *SecurityHeaders
+	cache           *cacheTarget
+
+	// rejectUnacceptable is true if the response
+	// should be replaced by a 406 (Not Acceptable) error
+	// when none of its representations are acceptable.
+	rejectUnacceptable bool
+
+	// turboStreamJSON is true if TurboStreams
+	// should also be offered as JSON.
+	turboStreamJSON bool
+	// messagePack is true if JSONValue
+	// should also be offered as MessagePack.
+	messagePack bool
+
+	// timing is non-nil if the response should include
+	// a breakdown of how long rendering took.
+	timing *renderTiming
+
+	// request is the request value passed to the Func, if any.
+	request any
+
+	// conditions holds the request's conditional headers.
+	conditions conditions
+
+	// languages is the request's Accept-Language header,
+	// used to choose localized variants of HTMLTemplate.
+	languages accept.LanguageHeader
+	// localized is set to true when rendering a template
+	// that has localized variants,
+	// since the result depends on Accept-Language.
+	localized bool
+	// htmlTemplate is the variant of the response's HTMLTemplate
+	// chosen for languages and htmlLanguage is its language tag, if any.
+	// They are set by localizeHTML.
+	htmlTemplate string
+	htmlLanguage string
+
+	// acceptEncoding is the request's Accept-Encoding header.
+	acceptEncoding string
+	// compressMinSize is the Config's CompressMinSize.
+	compressMinSize int
+	// templateTimeout is the Config's TemplateTimeout.
+	templateTimeout time.Duration
+	// maxTemplateSize is the Config's MaxTemplateSize.
+	maxTemplateSize int64
+	// fileHeader holds the request's range and conditional headers
+	// for serving representations created by FileRepresentation.
+	fileHeader http.Header
+
+	// autoETag is the Config's AutoETag.
+	autoETag bool
 
-	templateFiles fs.FS
-	templateFuncs template.FuncMap
-	reportError   func(context.Context, error)
+	// returnMinimal is true if the request is a write
+	// that asked for a minimal response with "Prefer: return=minimal".
+	returnMinimal bool
+
+	// cookieCodec is the Config's CookieCodec.
+	cookieCodec *flashkv.Codec
+
+	// info is non-nil if the Handler has OnRequestStart or OnRequestEnd callbacks.
+	info *RequestInfo
+
+	// csrf is the request's CSRF state
+	// if the Handler has CSRF protection enabled.
+	csrf *csrfState
+	// session is the request's session
+	// if the Handler has a SessionStore.
+	session *Session
+}
+
+// baseTemplateFuncs returns the template functions
+// available to every response for the request:
+// the formatting functions for the request's locale,
+// the CSRF functions (if enabled), and funcs.
+func (opts *renderOptions) baseTemplateFuncs(funcs template.FuncMap) template.FuncMap {
+	merged := localeTemplateFuncs(requestLocale(opts.languages))
+	if opts.csrf != nil {
+		for name, f := range opts.csrf.templateFuncs() {
+			merged[name] = f
+		}
+	}
+	for name, f := range funcs {
+		merged[name] = f
+	}
+	return merged
 }
 
 func (resp *Response) render(ctx context.Context, w http.ResponseWriter, opts *renderOptions) {
+	opts.securityHeaders.set(w.Header(), opts.isTLS)
+	if cookie, err := opts.session.save(); err != nil {
+		if opts.reportError != nil {
+			opts.reportError(ctx, err)
+		}
+		http.Error(w, "Error while serving page. Check server logs.", http.StatusInternalServerError)
+		return
+	} else if cookie != nil {
+		http.SetCookie(w, cookie)
+	}
 	if resp == nil {
 		w.WriteHeader(http.StatusNoContent)
 		return
@@ -191,6 +466,22 @@ func (resp *Response) render(ctx context.Context, w http.ResponseWriter, opts *r
 	for _, cookie := range resp.SetCookies {
 		http.SetCookie(w, cookie)
 	}
+	for _, cookie := range resp.SetSignedCookies {
+		if opts.cookieCodec == nil {
+			if opts.reportError != nil {
+				opts.reportError(ctx, fmt.Errorf("signed cookie %s dropped: no cookie codec configured", cookie.Name))
+			}
+			continue
+		}
+		signed, err := signCookie(opts.cookieCodec, cookie)
+		if err != nil {
+			if opts.reportError != nil {
+				opts.reportError(ctx, fmt.Errorf("signed cookie dropped: %w", err))
+			}
+			continue
+		}
+		http.SetCookie(w, signed)
+	}
 	if resp.SeeOther != "" {
 		statusCode := http.StatusSeeOther
 		if resp.StatusCode != 0 {
@@ -203,7 +494,13 @@ func (resp *Response) render(ctx context.Context, w http.ResponseWriter, opts *r
 		http.Redirect(w, fakeReq, resp.SeeOther, statusCode)
 		return
 	}
-	possibilities := resp.gatherRepresentations(func(err error) {
+	if opts.returnMinimal && (resp.StatusCode == 0 || 200 <= resp.StatusCode && resp.StatusCode < 300) {
+		resp.setCacheHeaders(w.Header(), "", "", false)
+		writeMinimal(w, resp.StatusCode)
+		return
+	}
+	negotiateStart := time.Now()
+	possibilities := resp.gatherRepresentations(opts.turboStreamJSON, opts.messagePack, func(err error) {
 		if opts.reportError != nil {
 			opts.reportError(ctx, err)
 		}
@@ -212,11 +509,56 @@ func (resp *Response) render(ctx context.Context, w http.ResponseWriter, opts *r
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
-	p := preferredRepresentation(possibilities, opts.acceptHeader)
+	setVary(w.Header(), possibilities, opts.rejectUnacceptable)
+	p := opts.negotiation.choose(ctx, possibilities, opts.acceptHeader)
+	if opts.timing != nil {
+		opts.timing.negotiate = time.Since(negotiateStart)
+		opts.timing.contentType = p.contentType
+	}
+	if opts.rejectUnacceptable && !p.isAcceptable(opts.acceptHeader) {
+		writeNotAcceptable(w, possibilities)
+		return
+	}
+	if p.mediaType == htmlType && resp.HTMLTemplate != "" && opts.templateFiles != nil {
+		// Choose the localized variant up front,
+		// since it determines the entity tag and the Vary header.
+		if err := opts.localizeHTML(resp.HTMLTemplate); err != nil {
+			if opts.reportError != nil {
+				opts.reportError(ctx, err)
+			}
+			http.Error(w, "Error while serving page. Check server logs.", http.StatusInternalServerError)
+			return
+		}
+		if opts.localized {
+			var vary accept.Vary
+			vary.Add(acceptLanguageHeaderName)
+			vary.Set(w.Header())
+		}
+	}
+	negotiated := len(possibilities) > 1
+	resp.setCacheHeaders(w.Header(), p.mediaType, opts.htmlLanguage, negotiated)
+	if contentCoding(p.contentType, opts.acceptEncoding, opts.compressMinSize) != "" {
+		weakenETag(w.Header())
+	}
+	if (resp.StatusCode == 0 || resp.StatusCode == http.StatusOK) && opts.conditions.notModified(w.Header()) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if p.eventStream {
+		resp.writeEventStream(ctx, w, opts)
+		return
+	}
+	if opts.autoETag && opts.reqMethod == http.MethodHead && p.repr == nil {
+		// The body would be discarded,
+		// so don't spend time rendering it just for its validators.
+		headOnly := &Representation{Header: http.Header{contentTypeHeaderName: {p.contentType}}}
+		headOnly.write(ctx, w, resp.statusCode(), false)
+		return
+	}
 	repr := p.repr
 	if repr == nil {
 		var err error
-		repr, err = p.reprFunc(opts)
+		repr, err = p.reprFunc(ctx, opts)
 		if err != nil {
 			if opts.reportError != nil {
 				opts.reportError(ctx, err)
@@ -224,12 +566,75 @@ func (resp *Response) render(ctx context.Context, w http.ResponseWriter, opts *r
 			http.Error(w, "Error while serving page. Check server logs.", http.StatusInternalServerError)
 			return
 		}
+		defer repr.Body.Close()
 	}
-	code := resp.StatusCode
-	if code == 0 {
-		code = http.StatusOK
+	if opts.timing != nil {
+		var err error
+		repr, err = opts.timing.annotate(repr, p.mediaType)
+		if err != nil {
+			if opts.reportError != nil {
+				opts.reportError(ctx, err)
+			}
+			http.Error(w, "Error while serving page. Check server logs.", http.StatusInternalServerError)
+			return
+		}
+	}
+	if opts.autoETag && resp.etag(p.mediaType, opts.htmlLanguage, negotiated) == "" && repr.file == nil && repr.stream == nil && repr.Header.Get(etagHeaderName) == "" {
+		var err error
+		repr, err = withContentETag(repr)
+		if err != nil {
+			if opts.reportError != nil {
+				opts.reportError(ctx, err)
+			}
+			http.Error(w, "Error while serving page. Check server logs.", http.StatusInternalServerError)
+			return
+		}
+		if resp.statusCode() == http.StatusOK && opts.conditions.notModified(repr.Header) {
+			writeNotModified(w, repr.Header)
+			return
+		}
+	}
+	if opts.cache != nil && !opts.csrf.tokenUsed() && !opts.localized && repr.file == nil && repr.stream == nil {
+		body, err := io.ReadAll(repr.Body)
+		if err != nil {
+			if opts.reportError != nil {
+				opts.reportError(ctx, err)
+			}
+			http.Error(w, "Error while serving page. Check server logs.", http.StatusInternalServerError)
+			return
+		}
+		header := repr.Header.Clone()
+		resp.setCacheHeaders(header, p.mediaType, opts.htmlLanguage, negotiated)
+		opts.cache.store(possibilities, p, &cachedRepresentation{
+			header: header,
+			body:   body,
+		})
+		repr = &Representation{
+			Header: repr.Header,
+			Body:   io.NopCloser(bytes.NewReader(body)),
+		}
+	}
+	code := resp.statusCode()
+	if repr.file != nil && code == http.StatusOK {
+		repr.file.serve(w, repr, opts.reqMethod, opts.fileHeader)
+		return
+	}
+	if repr.stream != nil {
+		err := repr.write(ctx, w, code, opts.reqMethod != http.MethodHead)
+		if err != nil && ctx.Err() == nil && opts.reportError != nil {
+			opts.reportError(ctx, fmt.Errorf("stream: %w", err))
+		}
+		return
 	}
-	repr.write(w, code, opts.reqMethod != http.MethodHead)
+	repr, err := compressRepresentation(w.Header(), repr, opts.acceptEncoding, opts.compressMinSize)
+	if err != nil {
+		if opts.reportError != nil {
+			opts.reportError(ctx, err)
+		}
+		http.Error(w, "Error while serving page. Check server logs.", http.StatusInternalServerError)
+		return
+	}
+	repr.write(ctx, w, code, opts.reqMethod != http.MethodHead)
 }
 
 type parsedRepresentation struct {
@@ -237,13 +642,16 @@ type parsedRepresentation struct {
 	mediaType   string
 	typeParams  map[string]string
 	repr        *Representation
-	reprFunc    func(*renderOptions) (*Representation, error)
+	reprFunc    func(context.Context, *renderOptions) (*Representation, error)
+	// eventStream is true if the representation is the response's EventStream,
+	// which is written directly instead of through a Representation.
+	eventStream bool
 }
 
-func (resp *Response) gatherRepresentations(report func(error)) []parsedRepresentation {
+func (resp *Response) gatherRepresentations(turboStreamJSON, messagePack bool, report func(error)) []parsedRepresentation {
 	possibilities := make([]parsedRepresentation, 0, 4+len(resp.Other))
 	utf8Params := map[string]string{"charset": "utf-8"}
-	if resp.TurboStreamTemplate != "" {
+	if resp.TurboStreamTemplate != "" || len(resp.TurboStreams) > 0 {
 		possibilities = append(possibilities, parsedRepresentation{
 			contentType: turbostream.ContentType + charsetUTF8Params,
 			mediaType:   turbostream.ContentType,
@@ -266,6 +674,27 @@ func (resp *Response) gatherRepresentations(report func(error)) []parsedRepresen
 			typeParams:  utf8Params,
 			reprFunc:    resp.jsonRepresentation,
 		})
+		if messagePack {
+			possibilities = append(possibilities, parsedRepresentation{
+				contentType: msgpackType,
+				mediaType:   msgpackType,
+				reprFunc:    resp.msgpackRepresentation,
+			})
+		}
+	} else if turboStreamJSON && resp.TurboStreamTemplate == "" && len(resp.TurboStreams) > 0 {
+		possibilities = append(possibilities, parsedRepresentation{
+			contentType: jsonType + charsetUTF8Params,
+			mediaType:   jsonType,
+			typeParams:  utf8Params,
+			reprFunc:    resp.turboStreamJSONRepresentation,
+		})
+	}
+	if resp.ProtoValue != nil {
+		possibilities = append(possibilities, parsedRepresentation{
+			contentType: protobufType,
+			mediaType:   protobufType,
+			reprFunc:    resp.protobufRepresentation,
+		})
 	}
 	if resp.TextTemplate != "" {
 		possibilities = append(possibilities, parsedRepresentation{
@@ -275,6 +704,26 @@ func (resp *Response) gatherRepresentations(report func(error)) []parsedRepresen
 			reprFunc:    resp.textRepresentation,
 		})
 	}
+	if resp.EventStream != nil {
+		possibilities = append(possibilities, parsedRepresentation{
+			contentType: sse.ContentType,
+			mediaType:   sse.ContentType,
+			eventStream: true,
+		})
+	}
+	for _, cr := range resp.customReprs {
+		mediaType, typeParams, err := mime.ParseMediaType(cr.contentType)
+		if err != nil {
+			report(fmt.Errorf("invalid content type for representation function (skipping): %v", err))
+			continue
+		}
+		possibilities = append(possibilities, parsedRepresentation{
+			contentType: cr.contentType,
+			mediaType:   mediaType,
+			typeParams:  typeParams,
+			reprFunc:    resp.customRepresentationFunc(cr),
+		})
+	}
 	for _, repr := range resp.Other {
 		contentType := repr.Header.Get(contentTypeHeaderName)
 		mediaType, typeParams, err := mime.ParseMediaType(contentType)
@@ -292,116 +741,268 @@ func (resp *Response) gatherRepresentations(report func(error)) []parsedRepresen
 	return possibilities
 }
 
-// preferredRepresentation returns the user's most preferred representation from the list,
-// using representations earlier in the list in case of a tie.
-func preferredRepresentation(possibilities []parsedRepresentation, acceptHeader accept.Header) *parsedRepresentation {
-	if len(possibilities) == 0 {
-		return nil
+// writeEventStream sends the response headers for an event stream
+// and then calls resp.EventStream.
+func (resp *Response) writeEventStream(ctx context.Context, w http.ResponseWriter, opts *renderOptions) {
+	h := w.Header()
+	h.Set(contentTypeHeaderName, sse.ContentType)
+	h.Set("Cache-Control", "no-cache")
+	// Ask reverse proxies like nginx not to buffer the stream.
+	h.Set("X-Accel-Buffering", "no")
+	if len(h[contentTypeOptionsHeaderName]) == 0 {
+		h.Set(contentTypeOptionsHeaderName, "nosniff")
 	}
-	p := &possibilities[0]
-	q := acceptHeader.Quality(p.mediaType, p.typeParams)
-	for i := range possibilities[1:] {
-		pi := &possibilities[1+i]
-		qi := acceptHeader.Quality(pi.mediaType, pi.typeParams)
-		if qi > q {
-			p, q = pi, qi
-		}
+	code := resp.StatusCode
+	if code == 0 {
+		code = http.StatusOK
+	}
+	w.WriteHeader(code)
+	if opts.reqMethod == http.MethodHead {
+		return
+	}
+	sw := sse.NewWriter(w)
+	// Flush the headers so that the client knows the stream has started.
+	if err := sw.Flush(); err != nil {
+		return
+	}
+	if err := resp.EventStream(ctx, sw); err != nil && ctx.Err() == nil && opts.reportError != nil {
+		opts.reportError(ctx, fmt.Errorf("event stream: %w", err))
+	}
+}
+
+// setVary adds Accept to the Vary response header
+// if the choice of representation depends on the Accept request header:
+// either there is more than one representation to choose from
+// or an unacceptable representation would be rejected.
+func setVary(h http.Header, possibilities []parsedRepresentation, rejectUnacceptable bool) {
+	if len(possibilities) < 2 && !rejectUnacceptable {
+		return
+	}
+	var vary accept.Vary
+	vary.Add(acceptHeaderName)
+	vary.Set(h)
+}
+
+// isAcceptable reports whether the representation has a non-zero quality
+// according to the Accept header.
+func (p *parsedRepresentation) isAcceptable(acceptHeader accept.Header) bool {
+	return acceptHeader.Quality(p.mediaType, p.typeParams) > 0
+}
+
+// writeNotAcceptable sends a 406 (Not Acceptable) response
+// that lists the content types of the possible representations.
+func writeNotAcceptable(w http.ResponseWriter, possibilities []parsedRepresentation) {
+	buf := new(bytes.Buffer)
+	buf.WriteString("None of the available content types are acceptable:\n")
+	for i := range possibilities {
+		buf.WriteString(possibilities[i].contentType)
+		buf.WriteString("\n")
 	}
-	return p
+	w.Header().Set(contentTypeHeaderName, "text/plain"+charsetUTF8Params)
+	w.Header().Set(contentLengthHeaderName, strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusNotAcceptable)
+	w.Write(buf.Bytes())
 }
 
-func (resp *Response) htmlRepresentation(opts *renderOptions) (*Representation, error) {
+func (resp *Response) htmlRepresentation(ctx context.Context, opts *renderOptions) (*Representation, error) {
 	if opts.templateFiles == nil {
 		return nil, errNoTemplateFiles
 	}
-	base, err := templateloader.Base(opts.templateFiles, opts.templateFuncs)
-	if err != nil {
+	start := time.Now()
+	if err := opts.localizeHTML(resp.HTMLTemplate); err != nil {
 		return nil, err
 	}
-	tmpl, err := templateloader.Extend(base, opts.templateFiles, resp.HTMLTemplate)
+	name := opts.htmlTemplate
+	tmpl, err := opts.templates.HTML(templateCacheKey{htmlPageTemplate, name}, opts.templateFuncs, func() (*template.Template, error) {
+		base, err := templateloader.Base(opts.templateFiles, opts.templateFuncs)
+		if err != nil {
+			return nil, err
+		}
+		return templateloader.Extend(base, opts.templateFiles, name)
+	})
 	if err != nil {
 		return nil, err
 	}
+	start = opts.timing.parsed(start)
 
 	buf := new(bytes.Buffer)
-	err = tmpl.Execute(buf, resp.TemplateData)
+	err = tmpl.Execute(opts.templateOutput(buf, name), resp.TemplateData)
 	if err != nil {
 		return nil, err
 	}
+	opts.timing.executed(start)
+	opts.info.setTemplate(name)
+	header := http.Header{
+		contentTypeHeaderName:   {htmlType + charsetUTF8Params},
+		contentLengthHeaderName: {strconv.Itoa(buf.Len())},
+	}
+	if opts.htmlLanguage != "" {
+		header.Set(contentLanguageHeaderName, opts.htmlLanguage)
+	}
+	return &Representation{
+		Header: header,
+		Body:   io.NopCloser(buf),
+	}, nil
+}
+
+func (resp *Response) turboStreamRepresentation(ctx context.Context, opts *renderOptions) (*Representation, error) {
+	buf := new(bytes.Buffer)
+	start := time.Now()
+	if resp.TurboStreamTemplate != "" {
+		if opts.templateFiles == nil {
+			return nil, errNoTemplateFiles
+		}
+		tmpl, err := opts.templates.HTML(templateCacheKey{turboStreamTemplate, resp.TurboStreamTemplate}, opts.templateFuncs, func() (*template.Template, error) {
+			tmpl, err := templateloader.ParseFile(
+				template.New(resp.TurboStreamTemplate).Funcs(opts.templateFuncs),
+				opts.templateFiles,
+				resp.TurboStreamTemplate,
+			)
+			if err != nil {
+				return nil, err
+			}
+			return templateloader.AddPartials(tmpl, opts.templateFiles)
+		})
+		if err != nil {
+			return nil, err
+		}
+		start = opts.timing.parsed(start)
+		if err := tmpl.Execute(opts.templateOutput(buf, resp.TurboStreamTemplate), resp.TemplateData); err != nil {
+			return nil, err
+		}
+		opts.info.setTemplate(resp.TurboStreamTemplate)
+	}
+	for _, a := range resp.TurboStreams {
+		if a == nil {
+			continue
+		}
+		a, err := a.WithFuncs(opts.templateFuncs)
+		if err != nil {
+			return nil, err
+		}
+		text, err := a.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 0 && buf.Bytes()[buf.Len()-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+		buf.Write(text)
+		buf.WriteByte('\n')
+	}
+	opts.timing.executed(start)
 	return &Representation{
 		Header: http.Header{
-			contentTypeHeaderName:   {htmlType + charsetUTF8Params},
+			contentTypeHeaderName:   {turbostream.ContentType + charsetUTF8Params},
 			contentLengthHeaderName: {strconv.Itoa(buf.Len())},
 		},
 		Body: io.NopCloser(buf),
 	}, nil
 }
 
-func (resp *Response) turboStreamRepresentation(opts *renderOptions) (*Representation, error) {
-	if opts.templateFiles == nil {
-		return nil, errNoTemplateFiles
+func (resp *Response) turboStreamJSONRepresentation(ctx context.Context, opts *renderOptions) (*Representation, error) {
+	start := time.Now()
+	actions := make([]*turbostream.Action, 0, len(resp.TurboStreams))
+	for _, a := range resp.TurboStreams {
+		if a == nil {
+			continue
+		}
+		a, err := a.WithFuncs(opts.templateFuncs)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, a)
 	}
-	tmpl, err := templateloader.ParseFile(
-		template.New(resp.TurboStreamTemplate).Funcs(opts.templateFuncs),
-		opts.templateFiles,
-		resp.TurboStreamTemplate,
-	)
+	jsonData, err := json.Marshal(actions)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := templateloader.AddPartials(tmpl, opts.templateFiles); err != nil {
+	opts.timing.executed(start)
+	return &Representation{
+		Header: http.Header{
+			contentTypeHeaderName:   {jsonType + charsetUTF8Params},
+			contentLengthHeaderName: {strconv.Itoa(len(jsonData))},
+		},
+		Body: io.NopCloser(bytes.NewReader(jsonData)),
+	}, nil
+}
+
+func (resp *Response) jsonRepresentation(ctx context.Context, opts *renderOptions) (*Representation, error) {
+	start := time.Now()
+	jsonData, err := json.Marshal(resp.JSONValue)
+	if err != nil {
 		return nil, err
 	}
+	opts.timing.executed(start)
+	return &Representation{
+		Header: http.Header{
+			contentTypeHeaderName:   {jsonType + charsetUTF8Params},
+			contentLengthHeaderName: {strconv.Itoa(len(jsonData))},
+		},
+		Body: io.NopCloser(bytes.NewReader(jsonData)),
+	}, nil
+}
 
-	buf := new(bytes.Buffer)
-	err = tmpl.Execute(buf, resp.TemplateData)
+func (resp *Response) msgpackRepresentation(ctx context.Context, opts *renderOptions) (*Representation, error) {
+	start := time.Now()
+	data, err := marshalMsgpack(resp.JSONValue)
 	if err != nil {
 		return nil, err
 	}
+	opts.timing.executed(start)
 	return &Representation{
 		Header: http.Header{
-			contentTypeHeaderName:   {turbostream.ContentType + charsetUTF8Params},
-			contentLengthHeaderName: {strconv.Itoa(buf.Len())},
+			contentTypeHeaderName:   {msgpackType},
+			contentLengthHeaderName: {strconv.Itoa(len(data))},
 		},
-		Body: io.NopCloser(buf),
+		Body: io.NopCloser(bytes.NewReader(data)),
 	}, nil
 }
 
-func (resp *Response) jsonRepresentation(opts *renderOptions) (*Representation, error) {
-	jsonData, err := json.Marshal(resp.JSONValue)
+func (resp *Response) protobufRepresentation(ctx context.Context, opts *renderOptions) (*Representation, error) {
+	start := time.Now()
+	data, err := proto.Marshal(resp.ProtoValue)
 	if err != nil {
 		return nil, err
 	}
+	opts.timing.executed(start)
 	return &Representation{
 		Header: http.Header{
-			contentTypeHeaderName:   {jsonType + charsetUTF8Params},
-			contentLengthHeaderName: {strconv.Itoa(len(jsonData))},
+			contentTypeHeaderName:   {protobufType},
+			contentLengthHeaderName: {strconv.Itoa(len(data))},
 		},
-		Body: io.NopCloser(bytes.NewReader(jsonData)),
+		Body: io.NopCloser(bytes.NewReader(data)),
 	}, nil
 }
 
-func (resp *Response) textRepresentation(opts *renderOptions) (*Representation, error) {
+func (resp *Response) textRepresentation(ctx context.Context, opts *renderOptions) (*Representation, error) {
 	if opts.templateFiles == nil {
 		return nil, errNoTemplateFiles
 	}
-	tmpl, err := templateloader.ParseTextFile(
-		texttemplate.New(resp.TextTemplate).Funcs(texttemplate.FuncMap(opts.templateFuncs)),
-		opts.templateFiles,
-		resp.TextTemplate,
-	)
+	start := time.Now()
+	tmpl, err := opts.templates.Text(templateCacheKey{textTemplate, resp.TextTemplate}, opts.templateFuncs, func() (*texttemplate.Template, error) {
+		tmpl, err := templateloader.ParseTextFile(
+			texttemplate.New(resp.TextTemplate).Funcs(texttemplate.FuncMap(opts.templateFuncs)),
+			opts.templateFiles,
+			resp.TextTemplate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return templateloader.AddTextPartials(tmpl, opts.templateFiles)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if _, err := templateloader.AddTextPartials(tmpl, opts.templateFiles); err != nil {
-		return nil, err
-	}
+	start = opts.timing.parsed(start)
 
 	buf := new(bytes.Buffer)
-	err = tmpl.Execute(buf, resp.TemplateData)
+	err = tmpl.Execute(opts.templateOutput(buf, resp.TextTemplate), resp.TemplateData)
 	if err != nil {
 		return nil, err
 	}
+	opts.info.setTemplate(resp.TextTemplate)
+	opts.timing.executed(start)
 	return &Representation{
 		Header: http.Header{
 			contentTypeHeaderName:   {plainType + charsetUTF8Params},
@@ -411,6 +1012,36 @@ func (resp *Response) textRepresentation(opts *renderOptions) (*Representation,
 	}, nil
 }
 
+func (resp *Response) customRepresentationFunc(cr customRepresentation) func(context.Context, *renderOptions) (*Representation, error) {
+	return func(ctx context.Context, opts *renderOptions) (*Representation, error) {
+		start := time.Now()
+		defer opts.timing.executed(start)
+		repr, err := cr.f(ctx, &RenderContext{
+			Request:       opts.request,
+			ContentType:   cr.contentType,
+			TemplateData:  resp.TemplateData,
+			TemplateFiles: opts.templateFiles,
+			TemplateFuncs: opts.templateFuncs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if repr == nil {
+			return nil, fmt.Errorf("representation function for %s returned nil", cr.contentType)
+		}
+		if repr.Header.Get(contentTypeHeaderName) == "" {
+			if repr.Header == nil {
+				repr.Header = make(http.Header)
+			}
+			repr.Header.Set(contentTypeHeaderName, cr.contentType)
+		}
+		if repr.Body == nil {
+			repr.Body = http.NoBody
+		}
+		return repr, nil
+	}
+}
+
 var errNoTemplateFiles = errors.New("render: TemplateFiles missing from Handler")
 
 // ForceAccept is an HTTP middleware
@@ -18,6 +18,8 @@ package action
 
 import (
 	"context"
+	"fmt"
+	"html/template"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -28,7 +30,10 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"zombiezen.com/go/bass/accept"
+	"zombiezen.com/go/bass/sse"
+	"zombiezen.com/go/bass/turbostream"
 )
 
 func TestResponseRender(t *testing.T) {
@@ -239,6 +244,7 @@ func TestResponseRender(t *testing.T) {
 			wantHeader: http.Header{
 				"Content-Type":           {"text/vnd.turbo-stream.html; charset=utf-8"},
 				"Content-Length":         {"59"},
+				"Vary":                   {"Accept"},
 				"X-Content-Type-Options": {"nosniff"},
 			},
 			wantBody: `<turbo-stream action="remove" target="junk"></turbo-stream>`,
@@ -266,6 +272,7 @@ func TestResponseRender(t *testing.T) {
 			wantHeader: http.Header{
 				"Content-Type":           {"text/html; charset=utf-8"},
 				"Content-Length":         {"29"},
+				"Vary":                   {"Accept"},
 				"X-Content-Type-Options": {"nosniff"},
 			},
 			wantBody: "<!DOCTYPE html>\nHello, World!",
@@ -294,6 +301,7 @@ func TestResponseRender(t *testing.T) {
 			wantHeader: http.Header{
 				"Content-Type":           {"text/html; charset=utf-8"},
 				"Content-Length":         {"29"},
+				"Vary":                   {"Accept"},
 				"X-Content-Type-Options": {"nosniff"},
 			},
 			wantBody: "<!DOCTYPE html>\nHello, World!",
@@ -360,6 +368,26 @@ func TestResponseRender(t *testing.T) {
 			},
 			wantBody: "Hello, World!\n",
 		},
+		{
+			name: "Bytes",
+			resp: &Response{
+				Other: []*Representation{BytesRepresentation("image/png", []byte("\x89PNG\r\n"))},
+			},
+			opts: &renderOptions{
+				reqMethod: http.MethodGet,
+				reqPath:   "/",
+				acceptHeader: accept.Header{
+					{Range: "*/*", Quality: 1.0},
+				},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type":           {"image/png"},
+				"Content-Length":         {"6"},
+				"X-Content-Type-Options": {"nosniff"},
+			},
+			wantBody: "\x89PNG\r\n",
+		},
 		{
 			name: "HTMLAndText/Equal",
 			resp: &Response{
@@ -381,6 +409,7 @@ func TestResponseRender(t *testing.T) {
 			wantHeader: http.Header{
 				"Content-Type":           {"text/html; charset=utf-8"},
 				"Content-Length":         {"29"},
+				"Vary":                   {"Accept"},
 				"X-Content-Type-Options": {"nosniff"},
 			},
 			wantBody: "<!DOCTYPE html>\nHello, World!",
@@ -407,6 +436,7 @@ func TestResponseRender(t *testing.T) {
 			wantHeader: http.Header{
 				"Content-Type":           {"text/plain; charset=utf-8"},
 				"Content-Length":         {"14"},
+				"Vary":                   {"Accept"},
 				"X-Content-Type-Options": {"nosniff"},
 			},
 			wantBody: "Hello, World!\n",
@@ -432,6 +462,7 @@ func TestResponseRender(t *testing.T) {
 			wantHeader: http.Header{
 				"Content-Type":           {"text/html; charset=utf-8"},
 				"Content-Length":         {"29"},
+				"Vary":                   {"Accept"},
 				"X-Content-Type-Options": {"nosniff"},
 			},
 			wantBody: "<!DOCTYPE html>\nHello, World!",
@@ -466,6 +497,7 @@ func TestResponseRender(t *testing.T) {
 			wantHeader: http.Header{
 				"Content-Type":           {"text/csv"},
 				"Content-Length":         {"13"},
+				"Vary":                   {"Accept"},
 				"X-Content-Type-Options": {"nosniff"},
 			},
 			wantBody: "Hello,World\r\n",
@@ -550,6 +582,113 @@ func TestResponseRender(t *testing.T) {
 			},
 			wantBody: "Hello, World!\n",
 		},
+		{
+			name: "TurboStreams",
+			resp: &Response{
+				TurboStreams: []*turbostream.Action{{
+					Type:     turbostream.Append,
+					TargetID: "list",
+					Template: template.Must(template.New("item").Funcs(template.FuncMap{
+						"greet": func() string { return "" },
+					}).Parse(`{{ greet }}, {{ . }}`)),
+					Data: "World",
+				}},
+			},
+			opts: &renderOptions{
+				reqMethod: http.MethodGet,
+				reqPath:   "/",
+				acceptHeader: accept.Header{
+					{Range: turbostream.ContentType, Quality: 1.0},
+				},
+				templateFuncs: template.FuncMap{
+					"greet": func() string { return "Hello" },
+				},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type":           {"text/vnd.turbo-stream.html; charset=utf-8"},
+				"Content-Length":         {"96"},
+				"X-Content-Type-Options": {"nosniff"},
+			},
+			wantBody: "<turbo-stream action=\"append\" target=\"list\">\n\t<template>Hello, World</template>\n</turbo-stream>\n",
+		},
+		{
+			name: "Msgpack",
+			resp: &Response{
+				JSONValue: map[string]any{"a": 1},
+			},
+			opts: &renderOptions{
+				reqMethod: http.MethodGet,
+				reqPath:   "/",
+				acceptHeader: accept.Header{
+					{Range: "application/x-msgpack", Quality: 1.0},
+				},
+				messagePack: true,
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type":           {"application/x-msgpack"},
+				"Content-Length":         {"4"},
+				"Vary":                   {"Accept"},
+				"X-Content-Type-Options": {"nosniff"},
+			},
+			wantBody: "\x81\xa1a\x01",
+		},
+		{
+			name: "Protobuf",
+			resp: &Response{
+				JSONValue:  "Hello",
+				ProtoValue: wrapperspb.String("Hello"),
+			},
+			opts: &renderOptions{
+				reqMethod: http.MethodGet,
+				reqPath:   "/",
+				acceptHeader: accept.Header{
+					{Range: "application/x-protobuf", Quality: 1.0},
+					{Range: "application/json", Quality: 0.5},
+				},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type":           {"application/x-protobuf"},
+				"Content-Length":         {"7"},
+				"Vary":                   {"Accept"},
+				"X-Content-Type-Options": {"nosniff"},
+			},
+			wantBody: "\x0a\x05Hello",
+		},
+		{
+			name: "RepresentationFunc",
+			resp: func() *Response {
+				resp := &Response{
+					TemplateData: "World",
+					JSONValue:    "ignored",
+				}
+				resp.RepresentationFunc("application/x-greeting", func(ctx context.Context, rc *RenderContext) (*Representation, error) {
+					s := fmt.Sprintf("%s to %v as %s", rc.Request, rc.TemplateData, rc.ContentType)
+					return &Representation{
+						Body: io.NopCloser(strings.NewReader(s)),
+					}, nil
+				})
+				return resp
+			}(),
+			opts: &renderOptions{
+				reqMethod: http.MethodGet,
+				reqPath:   "/",
+				acceptHeader: accept.Header{
+					{Range: "application/x-greeting", Quality: 1.0},
+					{Range: "*/*", Quality: 0.5},
+				},
+				request: "Hello",
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type":           {"application/x-greeting"},
+				"Vary":                   {"Accept"},
+				"X-Content-Type-Options": {"nosniff"},
+			},
+			wantBody: "Hello to World as application/x-greeting",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -637,6 +776,55 @@ func TestForceAccept(t *testing.T) {
 	})
 }
 
+func TestEventStream(t *testing.T) {
+	h := NewHandler(nil, func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{
+			JSONValue: []string{"a", "b"},
+			EventStream: func(ctx context.Context, w *sse.Writer) error {
+				for _, data := range []string{"a", "b"} {
+					if err := w.Send(&sse.Event{Data: data}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}, nil
+	})
+
+	t.Run("EventSource", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", sse.ContentType)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get("Content-Type"); got != sse.ContentType {
+			t.Errorf("Content-Type = %q; want %q", got, sse.ContentType)
+		}
+		if got, want := rec.Header().Get("Cache-Control"), "no-cache"; got != want {
+			t.Errorf("Cache-Control = %q; want %q", got, want)
+		}
+		if !rec.Flushed {
+			t.Error("response was not flushed")
+		}
+		const want = "data: a\n\ndata: b\n\n"
+		if got := rec.Body.String(); got != want {
+			t.Errorf("body = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("Wildcard", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", "*/*")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+			t.Errorf("Content-Type = %q; want application/json", got)
+		}
+	})
+}
+
 func readAllString(r io.Reader) (string, error) {
 	sb := new(strings.Builder)
 	_, err := io.Copy(sb, r)
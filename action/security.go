@@ -0,0 +1,112 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	frameOptionsHeaderName            = "X-Frame-Options"
+	contentSecurityPolicyHeaderName   = "Content-Security-Policy"
+	referrerPolicyHeaderName          = "Referrer-Policy"
+	strictTransportSecurityHeaderName = "Strict-Transport-Security"
+	forwardedProtoHeaderName          = "X-Forwarded-Proto"
+)
+
+// SecurityHeaders is a set of security-related HTTP response headers
+// sent with every response from a [Handler].
+// Empty fields are not sent.
+// Headers set on a [Representation] take precedence.
+type SecurityHeaders struct {
+	// FrameOptions is the value of the [X-Frame-Options header].
+	//
+	// [X-Frame-Options header]: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/X-Frame-Options
+	FrameOptions string
+	// FrameAncestors is the list of sources
+	// sent in a Content-Security-Policy [frame-ancestors directive].
+	//
+	// [frame-ancestors directive]: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Content-Security-Policy/frame-ancestors
+	FrameAncestors []string
+	// ReferrerPolicy is the value of the [Referrer-Policy header].
+	//
+	// [Referrer-Policy header]: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Referrer-Policy
+	ReferrerPolicy string
+	// StrictTransportSecurity is the value of the [Strict-Transport-Security header].
+	// It is only sent for requests received over TLS
+	// or, if the [Config] TrustProxyHeaders option is set,
+	// with an X-Forwarded-Proto header of "https".
+	//
+	// [Strict-Transport-Security header]: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Strict-Transport-Security
+	StrictTransportSecurity string
+}
+
+// DefaultSecurityHeaders returns a new [SecurityHeaders]
+// that disallows framing, limits referrer information to the origin
+// for cross-origin requests, and enables HTTP Strict Transport Security for two years.
+func DefaultSecurityHeaders() *SecurityHeaders {
+	return &SecurityHeaders{
+		FrameOptions:            "DENY",
+		FrameAncestors:          []string{"'none'"},
+		ReferrerPolicy:          "strict-origin-when-cross-origin",
+		StrictTransportSecurity: "max-age=63072000; includeSubDomains",
+	}
+}
+
+// set adds the headers to h that are not already present.
+func (sh *SecurityHeaders) set(h http.Header, isTLS bool) {
+	if sh == nil {
+		return
+	}
+	setDefault := func(k, v string) {
+		if v != "" && len(h[k]) == 0 {
+			h.Set(k, v)
+		}
+	}
+	setDefault(frameOptionsHeaderName, sh.FrameOptions)
+	if len(sh.FrameAncestors) > 0 {
+		setDefault(contentSecurityPolicyHeaderName, "frame-ancestors "+strings.Join(sh.FrameAncestors, " "))
+	}
+	setDefault(referrerPolicyHeaderName, sh.ReferrerPolicy)
+	if isTLS {
+		setDefault(strictTransportSecurityHeaderName, sh.StrictTransportSecurity)
+	}
+}
+
+// isSecurityHeader reports whether k is a header that may be set by [SecurityHeaders].
+func isSecurityHeader(k string) bool {
+	switch k {
+	case frameOptionsHeaderName,
+		contentSecurityPolicyHeaderName,
+		referrerPolicyHeaderName,
+		strictTransportSecurityHeaderName:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTLSRequest reports whether r was received over TLS.
+// The X-Forwarded-Proto header is only consulted if trustProxy is true,
+// since any client can send it.
+func isTLSRequest(r *http.Request, trustProxy bool) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return trustProxy && strings.EqualFold(r.Header.Get(forwardedProtoHeaderName), "https")
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"zombiezen.com/go/bass/flashkv"
+)
+
+// A SessionStore loads and saves the sessions of a [Handler]'s requests.
+// See [Config] Sessions.
+type SessionStore interface {
+	// Load returns the values of the session associated with r.
+	// It should return an empty map if r does not have a session
+	// (or its session has expired).
+	Load(r *http.Request) (map[string]string, error)
+
+	// Save persists the session values for r
+	// and returns the cookie that identifies the session,
+	// or nil if the client's cookie does not need to change.
+	// Save is only called if the session was modified while serving r.
+	// An empty values map means that the session should be removed,
+	// typically by returning an expired cookie.
+	Save(r *http.Request, values map[string]string) (*http.Cookie, error)
+}
+
+// CookieSessions returns a [SessionStore] that keeps session values
+// in the client's cookie using store.
+// A cookie that is invalid or expired is treated as an empty session.
+func CookieSessions(store *flashkv.Store) SessionStore {
+	return cookieSessionStore{store}
+}
+
+type cookieSessionStore struct {
+	store *flashkv.Store
+}
+
+func (s cookieSessionStore) Load(r *http.Request) (map[string]string, error) {
+	values, err := s.store.Load(r)
+	if errors.Is(err, flashkv.ErrInvalid) || errors.Is(err, flashkv.ErrExpired) {
+		return make(map[string]string), nil
+	}
+	return values, err
+}
+
+func (s cookieSessionStore) Save(r *http.Request, values map[string]string) (*http.Cookie, error) {
+	return s.store.Save(values)
+}
+
+// A Session holds the session values of a request
+// served by a [Handler] with a [SessionStore].
+// It is safe to call its methods from multiple goroutines simultaneously.
+// Reading or modifying a session prevents the response
+// from being stored in a [Cache].
+type Session struct {
+	r     *http.Request
+	store SessionStore
+
+	mu       sync.Mutex
+	values   map[string]string
+	used     bool
+	modified bool
+}
+
+type sessionContextKey struct{}
+
+// SessionFromContext returns the session of the request being served.
+// It returns nil if ctx is not from a [Handler]
+// with a [Config] Sessions store.
+func SessionFromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionContextKey{}).(*Session)
+	return s
+}
+
+// loadSession loads the session for r from store
+// and returns a copy of r whose context carries the session.
+func loadSession(store SessionStore, r *http.Request) (*http.Request, error) {
+	values, err := store.Load(r)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = make(map[string]string)
+	}
+	s := &Session{
+		r:      r,
+		store:  store,
+		values: values,
+	}
+	return r.WithContext(context.WithValue(r.Context(), sessionContextKey{}, s)), nil
+}
+
+// Get returns the value associated with key
+// or the empty string if there is none.
+// Get returns the empty string if s is nil.
+func (s *Session) Get(key string) string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used = true
+	return s.values[key]
+}
+
+// Set associates key with value in the session.
+// Set does nothing if s is nil.
+func (s *Session) Set(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used = true
+	s.modified = true
+	s.values[key] = value
+}
+
+// Delete removes the value associated with key from the session.
+// Delete does nothing if s is nil.
+func (s *Session) Delete(key string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used = true
+	if _, ok := s.values[key]; ok {
+		s.modified = true
+		delete(s.values, key)
+	}
+}
+
+// Clear removes all values from the session,
+// which causes it to be removed from the store.
+// It is intended for signing out.
+// Clear does nothing if s is nil.
+func (s *Session) Clear() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used = true
+	s.modified = true
+	s.values = make(map[string]string)
+}
+
+// wasUsed reports whether the session has been read or modified.
+// It returns false if s is nil.
+func (s *Session) wasUsed() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.used
+}
+
+// save calls the store's Save method if the session was modified
+// and returns the cookie to send, if any.
+// It returns (nil, nil) if s is nil.
+func (s *Session) save() (*http.Cookie, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.modified {
+		return nil, nil
+	}
+	values := make(map[string]string, len(s.values))
+	for k, v := range s.values {
+		values[k] = v
+	}
+	c, err := s.store.Save(s.r, values)
+	if err != nil {
+		return nil, err
+	}
+	s.modified = false
+	return c, nil
+}
@@ -0,0 +1,282 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"zombiezen.com/go/bass/flashkv"
+)
+
+// memorySessionStore is a [SessionStore] that keeps sessions in a map
+// keyed by the value of the "session" cookie.
+type memorySessionStore struct {
+	sessions map[string]map[string]string
+	saves    int
+	loadErr  error
+	saveErr  error
+}
+
+func (store *memorySessionStore) Load(r *http.Request) (map[string]string, error) {
+	if store.loadErr != nil {
+		return nil, store.loadErr
+	}
+	c, err := r.Cookie("session")
+	if err != nil {
+		return nil, nil
+	}
+	values := make(map[string]string)
+	for k, v := range store.sessions[c.Value] {
+		values[k] = v
+	}
+	return values, nil
+}
+
+func (store *memorySessionStore) Save(r *http.Request, values map[string]string) (*http.Cookie, error) {
+	if store.saveErr != nil {
+		return nil, store.saveErr
+	}
+	store.saves++
+	if len(values) == 0 {
+		return &http.Cookie{Name: "session", MaxAge: -1}, nil
+	}
+	if store.sessions == nil {
+		store.sessions = make(map[string]map[string]string)
+	}
+	id := strconv.Itoa(store.saves)
+	store.sessions[id] = values
+	return &http.Cookie{Name: "session", Value: id}, nil
+}
+
+func TestSessions(t *testing.T) {
+	store := new(memorySessionStore)
+	var got string
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		Sessions:         store,
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		s := SessionFromContext(ctx)
+		switch r.URL.Path {
+		case "/login":
+			s.Set("user", "alice")
+			return &Response{SeeOther: "/"}, nil
+		case "/logout":
+			s.Clear()
+			return nil, nil
+		case "/fail":
+			s.Set("flash", "oops")
+			return nil, errors.New("bork")
+		default:
+			got = s.Get("user")
+			return &Response{Other: []*Representation{TextRepresentation("hi")}}, nil
+		}
+	})
+	do := func(path string, cookie *http.Cookie) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	resp := do("/login", nil)
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("GET /login = %d; want %d", resp.StatusCode, http.StatusSeeOther)
+	}
+	cookies := resp.Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "session" {
+		t.Fatalf("GET /login cookies = %v; want session cookie", cookies)
+	}
+	session := cookies[0]
+
+	resp = do("/", session)
+	if got != "alice" {
+		t.Errorf("user = %q; want %q", got, "alice")
+	}
+	if cookies := resp.Cookies(); len(cookies) != 0 {
+		t.Errorf("GET / with unmodified session sent cookies %v", cookies)
+	}
+	if store.saves != 1 {
+		t.Errorf("store.saves = %d; want 1", store.saves)
+	}
+
+	resp = do("/fail", session)
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("GET /fail = %d; want %d", resp.StatusCode, http.StatusInternalServerError)
+	}
+	if cookies := resp.Cookies(); len(cookies) != 1 {
+		t.Errorf("GET /fail cookies = %v; want session cookie on error response", cookies)
+	}
+
+	resp = do("/logout", session)
+	if cookies := resp.Cookies(); len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Errorf("GET /logout cookies = %v; want expired session cookie", cookies)
+	}
+
+	do("/", nil)
+	if got != "" {
+		t.Errorf("user without session = %q; want empty", got)
+	}
+}
+
+func TestSessionErrors(t *testing.T) {
+	t.Run("Load", func(t *testing.T) {
+		called := false
+		cfg := &Config[*http.Request]{
+			TransformRequest: identity,
+			Sessions:         &memorySessionStore{loadErr: errors.New("database down")},
+		}
+		h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			called = true
+			return nil, nil
+		})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusInternalServerError)
+		}
+		if called {
+			t.Error("Func called after session failed to load")
+		}
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		var reported error
+		cfg := &Config[*http.Request]{
+			TransformRequest: identity,
+			Sessions:         &memorySessionStore{saveErr: errors.New("database down")},
+			ReportError: func(ctx context.Context, err error) {
+				reported = err
+			},
+		}
+		h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			SessionFromContext(ctx).Set("user", "alice")
+			return &Response{Other: []*Representation{TextRepresentation("hi")}}, nil
+		})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusInternalServerError)
+		}
+		if reported == nil {
+			t.Error("save error not reported")
+		}
+	})
+}
+
+func TestSessionNotCached(t *testing.T) {
+	store := &memorySessionStore{
+		sessions: map[string]map[string]string{
+			"a": {"user": "alice"},
+			"b": {"user": "bob"},
+		},
+	}
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		Sessions:         store,
+		Cache:            NewCache(time.Minute),
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		user := SessionFromContext(ctx).Get("user")
+		return &Response{Other: []*Representation{TextRepresentation("hello " + user)}}, nil
+	})
+	for _, id := range []string{"a", "b"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "session", Value: id})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if want := "hello " + store.sessions[id]["user"]; rec.Body.String() != want {
+			t.Errorf("session %s body = %q; want %q", id, rec.Body.String(), want)
+		}
+	}
+}
+
+func TestSessionFromContextNil(t *testing.T) {
+	s := SessionFromContext(context.Background())
+	if s != nil {
+		t.Fatalf("SessionFromContext(context.Background()) = %v; want nil", s)
+	}
+	if got := s.Get("user"); got != "" {
+		t.Errorf("nil Session Get = %q; want empty", got)
+	}
+	// Modifying a nil session must not panic.
+	s.Set("user", "alice")
+	s.Delete("user")
+	s.Clear()
+}
+
+func TestCookieSessions(t *testing.T) {
+	codec, err := flashkv.NewCodec(&flashkv.Options{
+		Keys: []flashkv.Key{{Version: 1, Secret: bytes.Repeat([]byte{1}, 32)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		Sessions: CookieSessions(&flashkv.Store{
+			Codec:  codec,
+			Cookie: http.Cookie{Name: "session", Path: "/"},
+		}),
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		s := SessionFromContext(ctx)
+		if r.URL.Path == "/login" {
+			s.Set("user", "alice")
+			return nil, nil
+		}
+		got = s.Get("user")
+		return nil, nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/login", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "session" {
+		t.Fatalf("GET /login cookies = %v; want session cookie", cookies)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "alice" {
+		t.Errorf("user = %q; want %q", got, "alice")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "garbage"})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("GET / with invalid cookie = %d; want %d", rec.Code, http.StatusNoContent)
+	}
+	if got != "" {
+		t.Errorf("user with invalid cookie = %q; want empty", got)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// redactedValue replaces the values of redacted fields in a [BodySnapshot].
+const redactedValue = "REDACTED"
+
+// A BodySnapshot is a prefix of a request body
+// recorded for error reporting.
+type BodySnapshot struct {
+	// ContentType is the request's Content-Type header.
+	ContentType string
+	// Data is the recorded portion of the body
+	// with any redacted fields replaced.
+	// It is nil if the body could not be safely redacted.
+	Data []byte
+	// Truncated is true if the request body was longer than the recorded portion.
+	Truncated bool
+}
+
+type bodySnapshotContextKey struct{}
+
+// BodySnapshotFromContext returns the request body snapshot
+// attached to a context passed to [Config.ReportError].
+// It returns nil if the [Config] did not enable body snapshots
+// or the request had no body.
+func BodySnapshotFromContext(ctx context.Context) *BodySnapshot {
+	rec, _ := ctx.Value(bodySnapshotContextKey{}).(*bodyRecorder)
+	if rec == nil {
+		return nil
+	}
+	return rec.snapshot()
+}
+
+// bodyRecorder is an [io.ReadCloser] that records
+// the first bytes read from a request body.
+type bodyRecorder struct {
+	r           io.ReadCloser
+	contentType string
+	redact      []string
+	limit       int
+	buf         []byte
+	truncated   bool
+}
+
+func (rec *bodyRecorder) Read(p []byte) (int, error) {
+	n, err := rec.r.Read(p)
+	if room := rec.limit - len(rec.buf); n > room {
+		rec.buf = append(rec.buf, p[:room]...)
+		rec.truncated = true
+	} else {
+		rec.buf = append(rec.buf, p[:n]...)
+	}
+	return n, err
+}
+
+func (rec *bodyRecorder) Close() error {
+	return rec.r.Close()
+}
+
+func (rec *bodyRecorder) snapshot() *BodySnapshot {
+	snap := &BodySnapshot{
+		ContentType: rec.contentType,
+		Truncated:   rec.truncated,
+	}
+	if len(rec.redact) == 0 {
+		snap.Data = append([]byte(nil), rec.buf...)
+		return snap
+	}
+	mediaType, _, _ := mime.ParseMediaType(rec.contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		snap.Data = redactForm(rec.buf, rec.redact)
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if !rec.truncated {
+			snap.Data = redactJSON(rec.buf, rec.redact)
+		}
+	}
+	return snap
+}
+
+// redactForm replaces the values of the named fields
+// in a URL-encoded form, preserving the order of fields.
+func redactForm(data []byte, redact []string) []byte {
+	fields := bytes.Split(data, []byte("&"))
+	for i, field := range fields {
+		key := field
+		if eq := bytes.IndexByte(field, '='); eq >= 0 {
+			key = field[:eq]
+		}
+		name, err := url.QueryUnescape(string(key))
+		if err != nil || containsFold(redact, name) {
+			fields[i] = append(append(key[:len(key):len(key)], '='), redactedValue...)
+		}
+	}
+	return bytes.Join(fields, []byte("&"))
+}
+
+// redactJSON replaces the values of object members with the given names
+// at any depth of a JSON document.
+// It returns nil if data is not valid JSON.
+func redactJSON(data []byte, redact []string) []byte {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil
+	}
+	redacted, err := json.Marshal(redactJSONValue(v, redact))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+func redactJSONValue(v any, redact []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, elem := range v {
+			if containsFold(redact, k) {
+				v[k] = redactedValue
+			} else {
+				v[k] = redactJSONValue(elem, redact)
+			}
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = redactJSONValue(elem, redact)
+		}
+	}
+	return v
+}
+
+func containsFold(list []string, s string) bool {
+	for _, elem := range list {
+		if strings.EqualFold(elem, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBodySnapshot(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		size        int
+		redact      []string
+		want        *BodySnapshot
+	}{
+		{
+			name:        "Plain",
+			contentType: "text/plain",
+			body:        "Hello, World!",
+			size:        100,
+			want: &BodySnapshot{
+				ContentType: "text/plain",
+				Data:        []byte("Hello, World!"),
+			},
+		},
+		{
+			name:        "Truncated",
+			contentType: "text/plain",
+			body:        "Hello, World!",
+			size:        5,
+			want: &BodySnapshot{
+				ContentType: "text/plain",
+				Data:        []byte("Hello"),
+				Truncated:   true,
+			},
+		},
+		{
+			name:        "RedactForm",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "user=alice&Password=hunter2&pass%77ord=x&remember",
+			size:        100,
+			redact:      []string{"password"},
+			want: &BodySnapshot{
+				ContentType: "application/x-www-form-urlencoded",
+				Data:        []byte("user=alice&Password=REDACTED&pass%77ord=REDACTED&remember"),
+			},
+		},
+		{
+			name:        "RedactJSON",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"user":"alice","auth":{"token":"xyzzy"},"items":[{"token":1}]}`,
+			size:        100,
+			redact:      []string{"token"},
+			want: &BodySnapshot{
+				ContentType: "application/json; charset=utf-8",
+				Data:        []byte(`{"auth":{"token":"REDACTED"},"items":[{"token":"REDACTED"}],"user":"alice"}`),
+			},
+		},
+		{
+			name:        "RedactTruncatedJSON",
+			contentType: "application/json",
+			body:        `{"user":"alice","token":"xyzzy"}`,
+			size:        10,
+			redact:      []string{"token"},
+			want: &BodySnapshot{
+				ContentType: "application/json",
+				Truncated:   true,
+			},
+		},
+		{
+			name:        "RedactUnknownType",
+			contentType: "application/octet-stream",
+			body:        "token=xyzzy",
+			size:        100,
+			redact:      []string{"token"},
+			want: &BodySnapshot{
+				ContentType: "application/octet-stream",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got *BodySnapshot
+			reported := false
+			h := (&Config[*http.Request]{
+				BodySnapshotSize:   test.size,
+				BodySnapshotRedact: test.redact,
+				ReportError: func(ctx context.Context, err error) {
+					reported = true
+					got = BodySnapshotFromContext(ctx)
+				},
+			}).NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+				if BodySnapshotFromContext(ctx) != nil {
+					t.Error("snapshot available in handler context")
+				}
+				if _, err := io.ReadAll(r.Body); err != nil {
+					return nil, err
+				}
+				return nil, errors.New("bork")
+			})
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			if !reported {
+				t.Fatal("ReportError not called")
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("BodySnapshotFromContext(ctx) (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		reported := false
+		h := (&Config[*http.Request]{
+			ReportError: func(ctx context.Context, err error) {
+				reported = true
+				if got := BodySnapshotFromContext(ctx); got != nil {
+					t.Errorf("BodySnapshotFromContext(ctx) = %+v; want <nil>", got)
+				}
+			},
+		}).NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			return nil, errors.New("bork")
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("Hello"))
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if !reported {
+			t.Error("ReportError not called")
+		}
+	})
+}
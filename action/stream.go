@@ -0,0 +1,82 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"net/http"
+)
+
+// StreamRepresentation creates a representation of the given content type
+// whose body is written by f directly to the client,
+// so that a long-running render (like a progress page or a large table)
+// can send its output as it is produced
+// by calling [*StreamWriter.Flush].
+// f is called after the response header has been sent,
+// so an error returned by f cannot change the status code:
+// it is passed to the [Config] ReportError
+// and the client receives a truncated body.
+// f is not called for HEAD requests.
+//
+// Stream representations are never stored in a [Cache] or compressed.
+func StreamRepresentation(contentType string, f func(ctx context.Context, w *StreamWriter) error) *Representation {
+	return &Representation{
+		Header: http.Header{
+			contentTypeHeaderName: {contentType},
+			// Ask reverse proxies like nginx not to buffer the stream.
+			"X-Accel-Buffering": {"no"},
+		},
+		stream: f,
+	}
+}
+
+// A StreamWriter writes the body of a representation
+// created by [StreamRepresentation].
+type StreamWriter struct {
+	w http.ResponseWriter
+}
+
+// Write writes p to the response body.
+// The data may be buffered until the next call to Flush.
+func (sw *StreamWriter) Write(p []byte) (int, error) {
+	return sw.w.Write(p)
+}
+
+// Flush sends any buffered data to the client.
+// Like [http.ResponseController], it unwraps response writers
+// that have an Unwrap() http.ResponseWriter method
+// to find one that supports flushing.
+// If none does, Flush returns [http.ErrNotSupported].
+func (sw *StreamWriter) Flush() error {
+	return flushResponse(sw.w)
+}
+
+func flushResponse(w http.ResponseWriter) error {
+	for {
+		switch t := w.(type) {
+		case interface{ FlushError() error }:
+			return t.FlushError()
+		case http.Flusher:
+			t.Flush()
+			return nil
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return http.ErrNotSupported
+		}
+	}
+}
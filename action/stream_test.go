@@ -0,0 +1,146 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStreamRepresentation(t *testing.T) {
+	t.Run("Flush", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		var reported []error
+		cfg := &Config[*http.Request]{
+			TransformRequest: identity,
+			ReportError: func(ctx context.Context, err error) {
+				reported = append(reported, err)
+			},
+		}
+		h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			return &Response{
+				Other: []*Representation{
+					StreamRepresentation("text/plain; charset=utf-8", func(ctx context.Context, w *StreamWriter) error {
+						io.WriteString(w, "Working...\n")
+						if err := w.Flush(); err != nil {
+							return err
+						}
+						if !rec.Flushed || rec.Body.String() != "Working...\n" {
+							t.Errorf("after Flush: Flushed = %t, body = %q; want true, %q",
+								rec.Flushed, rec.Body.String(), "Working...\n")
+						}
+						io.WriteString(w, "Done.\n")
+						return nil
+					}),
+				},
+			}, nil
+		})
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusOK)
+		}
+		if got, want := rec.Body.String(), "Working...\nDone.\n"; got != want {
+			t.Errorf("body = %q; want %q", got, want)
+		}
+		if got := rec.Header().Get("Content-Length"); got != "" {
+			t.Errorf("Content-Length = %q; want empty", got)
+		}
+		if len(reported) > 0 {
+			t.Errorf("reported errors: %v", reported)
+		}
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		cfg := &Config[*http.Request]{TransformRequest: identity}
+		h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			return &Response{
+				Other: []*Representation{
+					StreamRepresentation("text/plain; charset=utf-8", func(ctx context.Context, w *StreamWriter) error {
+						t.Error("stream called for HEAD request")
+						return nil
+					}),
+				},
+			}, nil
+		})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		var reported []error
+		cfg := &Config[*http.Request]{
+			TransformRequest: identity,
+			ReportError: func(ctx context.Context, err error) {
+				reported = append(reported, err)
+			},
+		}
+		errBork := errors.New("bork")
+		h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+			return &Response{
+				Other: []*Representation{
+					StreamRepresentation("text/plain; charset=utf-8", func(ctx context.Context, w *StreamWriter) error {
+						io.WriteString(w, "partial")
+						return errBork
+					}),
+				},
+			}, nil
+		})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got, want := rec.Body.String(), "partial"; got != want {
+			t.Errorf("body = %q; want %q", got, want)
+		}
+		if len(reported) != 1 || !errors.Is(reported[0], errBork) {
+			t.Errorf("reported errors = %v; want [%v]", reported, errBork)
+		}
+	})
+}
+
+type unwrappingResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w unwrappingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type nonFlushingResponseWriter struct {
+	http.ResponseWriter
+}
+
+func TestStreamWriterFlush(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sw := &StreamWriter{w: unwrappingResponseWriter{rec}}
+	if err := sw.Flush(); err != nil {
+		t.Error("Flush through Unwrap:", err)
+	}
+	if !rec.Flushed {
+		t.Error("underlying ResponseRecorder not flushed")
+	}
+
+	sw = &StreamWriter{w: nonFlushingResponseWriter{httptest.NewRecorder()}}
+	if err := sw.Flush(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("Flush on non-Flusher = %v; want %v", err, http.ErrNotSupported)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// countingFS is a file system that counts how many times each file is opened.
+type countingFS struct {
+	fs.FS
+
+	mu    sync.Mutex
+	opens map[string]int
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.mu.Lock()
+	if c.opens == nil {
+		c.opens = make(map[string]int)
+	}
+	c.opens[name]++
+	c.mu.Unlock()
+	return c.FS.Open(name)
+}
+
+func (c *countingFS) openCount(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.opens[name]
+}
+
+func TestTemplateCache(t *testing.T) {
+	tests := []struct {
+		name            string
+		reloadTemplates bool
+		wantParses      int
+	}{
+		{name: "Cached", wantParses: 1},
+		{name: "ReloadTemplates", reloadTemplates: true, wantParses: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			templateFiles := &countingFS{FS: fstest.MapFS{
+				"base.html":     {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+				"_partial.html": {Data: []byte(`!`)},
+				"page.html":     {Data: []byte(`{{ define "content" }}Hello, {{ user }}{{ template "partial" }}{{ end }}`)},
+			}}
+			cfg := &Config[*http.Request]{
+				TransformRequest: identity,
+				TemplateFiles:    templateFiles,
+				MakeRequestTemplateFuncs: func(ctx context.Context, r *http.Request) template.FuncMap {
+					user := r.URL.Query().Get("user")
+					return template.FuncMap{
+						"user": func() string { return user },
+					}
+				},
+				ReloadTemplates: test.reloadTemplates,
+			}
+			h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+				return &Response{HTMLTemplate: "page.html"}, nil
+			})
+			for _, user := range []string{"Alice", "Bob", "Carol"} {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?user="+user, nil))
+				if rec.Code != http.StatusOK {
+					t.Fatalf("GET /?user=%s status = %d; want %d", user, rec.Code, http.StatusOK)
+				}
+				if got, want := rec.Body.String(), "Hello, "+user+"!"; got != want {
+					t.Errorf("GET /?user=%s body = %q; want %q", user, got, want)
+				}
+			}
+			if got := templateFiles.openCount("page.html"); got != test.wantParses {
+				t.Errorf("page.html opened %d times; want %d", got, test.wantParses)
+			}
+			if got := templateFiles.openCount("base.html"); got != test.wantParses {
+				t.Errorf("base.html opened %d times; want %d", got, test.wantParses)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// templateOutput returns the writer to execute the named template into
+// that appends to buf while enforcing
+// the Config's TemplateTimeout and MaxTemplateSize.
+func (opts *renderOptions) templateOutput(buf *bytes.Buffer, name string) io.Writer {
+	if opts.templateTimeout <= 0 && opts.maxTemplateSize <= 0 {
+		return buf
+	}
+	w := &limitedTemplateWriter{
+		buf:   buf,
+		name:  name,
+		limit: opts.maxTemplateSize,
+	}
+	if opts.templateTimeout > 0 {
+		w.timeout = opts.templateTimeout
+		w.deadline = time.Now().Add(opts.templateTimeout)
+	}
+	return w
+}
+
+// limitedTemplateWriter is an [io.Writer] that fails
+// once its output exceeds a size limit or its deadline has passed.
+// Template execution stops at the first write error.
+type limitedTemplateWriter struct {
+	buf      *bytes.Buffer
+	name     string
+	limit    int64
+	timeout  time.Duration
+	deadline time.Time
+	n        int64
+}
+
+func (w *limitedTemplateWriter) Write(p []byte) (int, error) {
+	if !w.deadline.IsZero() && time.Now().After(w.deadline) {
+		return 0, fmt.Errorf("template %s: execution took longer than %v", w.name, w.timeout)
+	}
+	if w.limit > 0 && w.n+int64(len(p)) > w.limit {
+		return 0, fmt.Errorf("template %s: output exceeds %d bytes", w.name, w.limit)
+	}
+	w.n += int64(len(p))
+	return w.buf.Write(p)
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestTemplateLimits(t *testing.T) {
+	templateFiles := fstest.MapFS{
+		"base.html": {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+		"loop.html": {Data: []byte(`{{ define "content" }}{{ range . }}<p>{{ . }}</p>{{ end }}{{ end }}`)},
+		"slow.html": {Data: []byte(`{{ define "content" }}{{ range . }}{{ sleep }}<p>{{ . }}</p>{{ end }}{{ end }}`)},
+	}
+	funcs := template.FuncMap{
+		"sleep": func() string {
+			time.Sleep(5 * time.Millisecond)
+			return ""
+		},
+	}
+	tests := []struct {
+		name     string
+		cfg      Config[*http.Request]
+		template string
+		data     any
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:     "NoLimits",
+			template: "loop.html",
+			data:     make([]int, 1000),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "UnderSize",
+			cfg:      Config[*http.Request]{MaxTemplateSize: 1000},
+			template: "loop.html",
+			data:     make([]int, 10),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "OverSize",
+			cfg:      Config[*http.Request]{MaxTemplateSize: 1000},
+			template: "loop.html",
+			data:     make([]int, 1000),
+			wantCode: http.StatusInternalServerError,
+			wantErr:  "output exceeds 1000 bytes",
+		},
+		{
+			name:     "Timeout",
+			cfg:      Config[*http.Request]{TemplateTimeout: 20 * time.Millisecond},
+			template: "slow.html",
+			data:     make([]int, 100),
+			wantCode: http.StatusInternalServerError,
+			wantErr:  "execution took longer than 20ms",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var reported []error
+			cfg := test.cfg
+			cfg.TransformRequest = identity
+			cfg.TemplateFiles = templateFiles
+			cfg.TemplateFuncs = funcs
+			cfg.ReportError = func(ctx context.Context, err error) {
+				reported = append(reported, err)
+			}
+			h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+				return &Response{
+					HTMLTemplate: test.template,
+					TemplateData: test.data,
+				}, nil
+			})
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			if rec.Code != test.wantCode {
+				t.Errorf("status = %d; want %d", rec.Code, test.wantCode)
+			}
+			if test.wantErr == "" {
+				if len(reported) > 0 {
+					t.Errorf("reported errors: %v", reported)
+				}
+				return
+			}
+			if len(reported) != 1 {
+				t.Fatalf("reported %d errors; want 1", len(reported))
+			}
+			if got := reported[0].Error(); !strings.Contains(got, test.wantErr) {
+				t.Errorf("reported error = %q; want to contain %q", got, test.wantErr)
+			}
+			if strings.Contains(rec.Body.String(), "<p>") {
+				t.Errorf("body contains partial template output: %q", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// renderTiming records how long the phases of rendering a response took.
+// A nil *renderTiming records nothing.
+type renderTiming struct {
+	negotiate   time.Duration
+	parse       time.Duration
+	execute     time.Duration
+	contentType string
+}
+
+// parsed adds the time since start to the template parse phase
+// and returns the current time.
+func (t *renderTiming) parsed(start time.Time) time.Time {
+	now := time.Now()
+	if t != nil {
+		t.parse += now.Sub(start)
+	}
+	return now
+}
+
+// executed adds the time since start to the execute phase
+// and returns the current time.
+func (t *renderTiming) executed(start time.Time) time.Time {
+	now := time.Now()
+	if t != nil {
+		t.execute += now.Sub(start)
+	}
+	return now
+}
+
+// serverTiming formats t as a Server-Timing header value.
+func (t *renderTiming) serverTiming() string {
+	return fmt.Sprintf("negotiate;dur=%s, parse;dur=%s, execute;dur=%s",
+		formatMillis(t.negotiate), formatMillis(t.parse), formatMillis(t.execute))
+}
+
+// comment formats t as an HTML comment.
+func (t *renderTiming) comment() string {
+	// The content type may come from a RepresentationFunc,
+	// so make sure it can't end the comment early.
+	contentType := strings.ReplaceAll(t.contentType, "--", "- -")
+	contentType = strings.ReplaceAll(contentType, ">", "")
+	return fmt.Sprintf("<!-- render timing: negotiate=%v (%s) parse=%v execute=%v -->\n",
+		t.negotiate, contentType, t.parse, t.execute)
+}
+
+// annotate returns a copy of repr with t's measurements added.
+// Every representation gets a Server-Timing header.
+// HTML bodies get a trailing comment
+// and JSON object bodies get a "_timing" member.
+// The mediaType is the media type that repr was chosen for.
+func (t *renderTiming) annotate(repr *Representation, mediaType string) (*Representation, error) {
+	repr2 := &Representation{
+		Header: repr.Header.Clone(),
+		Body:   repr.Body,
+	}
+	if repr2.Header == nil {
+		repr2.Header = make(http.Header)
+	}
+	repr2.Header.Add("Server-Timing", t.serverTiming())
+	if repr.file != nil || repr.stream != nil {
+		// The body is not buffered, so only the header can be annotated.
+		repr2.file = repr.file
+		repr2.stream = repr.stream
+		return repr2, nil
+	}
+	if mediaType != htmlType && mediaType != jsonType {
+		return repr2, nil
+	}
+	body, err := io.ReadAll(repr.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch mediaType {
+	case htmlType:
+		body = append(body, t.comment()...)
+	case jsonType:
+		body = t.addJSONMember(body)
+	}
+	repr2.Header.Set(contentLengthHeaderName, strconv.Itoa(len(body)))
+	repr2.Body = io.NopCloser(bytes.NewReader(body))
+	return repr2, nil
+}
+
+// addJSONMember inserts a "_timing" member at the start of a JSON object.
+// Bodies that are not JSON objects are returned unchanged.
+func (t *renderTiming) addJSONMember(body []byte) []byte {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return body
+	}
+	rest := bytes.TrimLeft(trimmed[1:], " \t\r\n")
+	contentType, _ := json.Marshal(t.contentType)
+	buf := new(bytes.Buffer)
+	buf.Grow(len(body) + 128)
+	fmt.Fprintf(buf, `{"_timing":{"contentType":%s,"negotiateMillis":%s,"parseMillis":%s,"executeMillis":%s}`,
+		contentType, formatMillis(t.negotiate), formatMillis(t.parse), formatMillis(t.execute))
+	if len(rest) > 0 && rest[0] != '}' {
+		buf.WriteByte(',')
+	}
+	buf.Write(rest)
+	return buf.Bytes()
+}
+
+func formatMillis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDebugTiming(t *testing.T) {
+	templateFiles := fstest.MapFS{
+		"base.html": {
+			Data: []byte("<!DOCTYPE html>\n{{ block \"content\" . }}{{ end }}"),
+		},
+		"page.html": {
+			Data: []byte("{{ define \"content\" }}Hello, {{ .Subject }}!{{ end }}"),
+		},
+	}
+	cfg := &Config[*http.Request]{
+		TemplateFiles: templateFiles,
+		DebugTiming: func(r *http.Request) bool {
+			return r.Header.Get("X-Debug-Timing") == "1"
+		},
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		return &Response{
+			HTMLTemplate: "page.html",
+			TemplateData: map[string]any{"Subject": "World"},
+			JSONValue:    map[string]string{"subject": "World"},
+		}, nil
+	})
+	serve := func(accept string, debug bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		if debug {
+			req.Header.Set("X-Debug-Timing", "1")
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Accept: %s: StatusCode = %d; want %d", accept, rec.Code, http.StatusOK)
+		}
+		if got, want := rec.Header().Get("Content-Length"), strconv.Itoa(rec.Body.Len()); got != want {
+			t.Errorf("Accept: %s: Content-Length = %s; want %s", accept, got, want)
+		}
+		return rec
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		rec := serve("text/html", false)
+		if got := rec.Header().Get("Server-Timing"); got != "" {
+			t.Errorf("Server-Timing = %q; want empty", got)
+		}
+		if got, want := rec.Body.String(), "<!DOCTYPE html>\nHello, World!"; got != want {
+			t.Errorf("body = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		rec := serve("text/html", true)
+		if got := rec.Header().Get("Server-Timing"); !strings.Contains(got, "execute;dur=") {
+			t.Errorf("Server-Timing = %q; want to contain execute duration", got)
+		}
+		got := rec.Body.String()
+		const wantPrefix = "<!DOCTYPE html>\nHello, World!<!-- render timing: negotiate="
+		if !strings.HasPrefix(got, wantPrefix) || !strings.HasSuffix(got, " -->\n") {
+			t.Errorf("body = %q; want timing comment after content", got)
+		}
+		if !strings.Contains(got, "(text/html; charset=utf-8)") {
+			t.Errorf("body = %q; want to contain chosen content type", got)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		rec := serve("application/json", true)
+		var got struct {
+			Subject string
+			Timing  struct {
+				ContentType     string
+				ExecuteMillis   float64
+				NegotiateMillis float64
+			} `json:"_timing"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("body = %q: %v", rec.Body, err)
+		}
+		if got.Subject != "World" {
+			t.Errorf("subject = %q; want %q", got.Subject, "World")
+		}
+		if want := "application/json; charset=utf-8"; got.Timing.ContentType != want {
+			t.Errorf("_timing.contentType = %q; want %q", got.Timing.ContentType, want)
+		}
+	})
+}
+
+func TestAddJSONMember(t *testing.T) {
+	timing := &renderTiming{contentType: "application/json"}
+	const member = `{"_timing":{"contentType":"application/json","negotiateMillis":0.000,"parseMillis":0.000,"executeMillis":0.000}`
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{}`, member + `}`},
+		{` { "a": 1 }`, member + `,"a": 1 }`},
+		{`[1, 2]`, `[1, 2]`},
+		{`"foo"`, `"foo"`},
+		{``, ``},
+	}
+	for _, test := range tests {
+		if got := string(timing.addJSONMember([]byte(test.body))); got != test.want {
+			t.Errorf("addJSONMember(%q) = %q; want %q", test.body, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const problemJSONType = "application/problem+json"
+
+// A ValidationError reports problems with the fields of a request,
+// such as a submitted form.
+// [ErrorStatusCode] returns 422 (Unprocessable Entity) for a ValidationError,
+// and when a [Config] has no TransformError,
+// it is served as HTML by re-rendering HTMLTemplate (if set),
+// as a JSON problem details object (RFC 9457),
+// or as plain text, depending on the request's Accept header.
+type ValidationError struct {
+	// Fields maps field names to messages about the field's value.
+	Fields map[string][]string
+	// Messages are messages about the request as a whole.
+	Messages []string
+
+	// HTMLTemplate names an html/template file
+	// used to present the error as HTML,
+	// typically the template that rendered the form.
+	// It is executed with a [*ValidationTemplateData].
+	HTMLTemplate string
+	// TemplateData is passed to HTMLTemplate as the Data field
+	// of the [*ValidationTemplateData].
+	// It typically holds the submitted values
+	// so the form can be filled in again.
+	TemplateData any
+}
+
+// ValidationTemplateData is the TemplateData
+// for a [ValidationError]'s HTMLTemplate.
+type ValidationTemplateData struct {
+	// Data is the ValidationError's TemplateData.
+	Data any
+	// Errors is the ValidationError.
+	// Templates can call {{ .Errors.Get "email" }}
+	// to get the messages for a field.
+	Errors *ValidationError
+}
+
+// Add adds a message for the named field.
+// If field is empty, the message is added to Messages.
+func (e *ValidationError) Add(field, message string) {
+	if field == "" {
+		e.Messages = append(e.Messages, message)
+		return
+	}
+	if e.Fields == nil {
+		e.Fields = make(map[string][]string)
+	}
+	e.Fields[field] = append(e.Fields[field], message)
+}
+
+// Get returns the messages for the named field.
+// It is safe to call on a nil ValidationError.
+func (e *ValidationError) Get(field string) []string {
+	if e == nil {
+		return nil
+	}
+	return e.Fields[field]
+}
+
+// Has reports whether the named field has any messages.
+// It is safe to call on a nil ValidationError.
+func (e *ValidationError) Has(field string) bool {
+	return len(e.Get(field)) > 0
+}
+
+// Err returns e if it has any messages or nil otherwise.
+// This allows a handler to accumulate messages with [*ValidationError.Add]
+// and then return Err() unconditionally.
+func (e *ValidationError) Err() error {
+	if e == nil || len(e.Fields) == 0 && len(e.Messages) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error returns the messages joined into a single line,
+// with fields in sorted order.
+func (e *ValidationError) Error() string {
+	parts := append([]string(nil), e.Messages...)
+	for _, field := range e.fieldNames() {
+		for _, msg := range e.Fields[field] {
+			parts = append(parts, field+": "+msg)
+		}
+	}
+	if len(parts) == 0 {
+		return "validation failed"
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) fieldNames() []string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// problemDetails is the JSON form of a ValidationError.
+type problemDetails struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Errors   map[string][]string `json:"errors,omitempty"`
+	Messages []string            `json:"messages,omitempty"`
+}
+
+func (e *ValidationError) problemDetails() *problemDetails {
+	return &problemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(http.StatusUnprocessableEntity),
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   strings.Join(e.Messages, " "),
+		Errors:   e.Fields,
+		Messages: e.Messages,
+	}
+}
+
+// response returns the default response for err,
+// whose chain contains e.
+func (e *ValidationError) response(err error) *Response {
+	resp := &Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		JSONValue:  e.problemDetails(),
+	}
+	if e.HTMLTemplate != "" {
+		resp.HTMLTemplate = e.HTMLTemplate
+		resp.TemplateData = &ValidationTemplateData{
+			Data:   e.TemplateData,
+			Errors: e,
+		}
+	}
+	if problem, err := json.Marshal(resp.JSONValue); err == nil {
+		resp.Other = append(resp.Other, &Representation{
+			Header: http.Header{
+				contentTypeHeaderName:   {problemJSONType},
+				contentLengthHeaderName: {strconv.Itoa(len(problem))},
+			},
+			Body: io.NopCloser(bytes.NewReader(problem)),
+		})
+	}
+	resp.Other = append(resp.Other, TextRepresentation(err.Error()))
+	return resp
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidationError(t *testing.T) {
+	cfg := &Config[*http.Request]{
+		TransformRequest: identity,
+		TemplateFiles: fstest.MapFS{
+			"base.html": {Data: []byte(`{{ block "content" . }}{{ end }}`)},
+			"signup.html": {Data: []byte(`{{ define "content" }}` +
+				`<input name="email" value="{{ .Data }}">` +
+				`{{ range .Errors.Get "email" }}<p>{{ . }}</p>{{ end }}` +
+				`{{ end }}`)},
+		},
+	}
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*Response, error) {
+		ve := &ValidationError{
+			HTMLTemplate: "signup.html",
+			TemplateData: r.FormValue("email"),
+		}
+		if r.FormValue("email") == "" {
+			ve.Add("email", "must not be empty")
+		}
+		if err := ve.Err(); err != nil {
+			return nil, fmt.Errorf("sign up: %w", err)
+		}
+		return &Response{SeeOther: "/"}, nil
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Accept", "text/html")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusUnprocessableEntity)
+		}
+		const want = `<input name="email" value=""><p>must not be empty</p>`
+		if got := rec.Body.String(); got != want {
+			t.Errorf("body = %q; want %q", got, want)
+		}
+	})
+
+	for _, contentType := range []string{"application/json", problemJSONType} {
+		t.Run(contentType, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("Accept", contentType)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status = %d; want %d", rec.Code, http.StatusUnprocessableEntity)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, contentType) {
+				t.Errorf("Content-Type = %q; want %q", got, contentType)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]any{
+				"type":   "about:blank",
+				"title":  "Unprocessable Entity",
+				"status": float64(http.StatusUnprocessableEntity),
+				"errors": map[string]any{
+					"email": []any{"must not be empty"},
+				},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("body (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("PlainText", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Accept", "text/plain")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusUnprocessableEntity)
+		}
+		const want = "sign up: validation failed: email: must not be empty"
+		if got := rec.Body.String(); got != want {
+			t.Errorf("body = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/?email=foo@example.com", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusSeeOther {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusSeeOther)
+		}
+	})
+}
+
+func TestValidationErrorStatusCode(t *testing.T) {
+	ve := new(ValidationError)
+	ve.Add("name", "too long")
+	if got, want := ErrorStatusCode(fmt.Errorf("wrap: %w", ve)), http.StatusUnprocessableEntity; got != want {
+		t.Errorf("ErrorStatusCode(wrapped) = %d; want %d", got, want)
+	}
+	if got, want := ErrorStatusCode(WithStatusCode(http.StatusConflict, ve)), http.StatusConflict; got != want {
+		t.Errorf("ErrorStatusCode(WithStatusCode(409, ...)) = %d; want %d", got, want)
+	}
+	if err := new(ValidationError).Err(); err != nil {
+		t.Errorf("empty ValidationError.Err() = %v; want <nil>", err)
+	}
+}
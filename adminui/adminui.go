@@ -0,0 +1,246 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package adminui provides an embedded operational dashboard for bass applications.
+// The dashboard shows the application's routes, recent errors, and runtime statistics.
+//
+// The handler is meant to be mounted under a prefix:
+//
+//	router.PathPrefix("/admin/").Handler(http.StripPrefix("/admin", adminui.NewHandler(opts)))
+package adminui
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"io/fs"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"zombiezen.com/go/bass/action"
+	"zombiezen.com/go/bass/static"
+)
+
+//go:embed templates static
+var embeddedFiles embed.FS
+
+// Options holds the arguments to [NewHandler].
+type Options struct {
+	// Router is an optional router whose routes are listed on the dashboard.
+	Router *mux.Router
+	// Errors is an optional log of recent errors to display.
+	Errors *ErrorLog
+	// Authorize reports whether the request may view the dashboard.
+	// If nil, all requests are denied.
+	// The dashboard exposes internal details of the application,
+	// so Authorize should check the request's credentials
+	// rather than its remote address,
+	// which is the proxy's address behind a reverse proxy.
+	Authorize func(*http.Request) bool
+}
+
+type handler struct {
+	opts      Options
+	started   time.Time
+	dashboard http.Handler
+	static    http.Handler
+}
+
+// NewHandler returns a new handler that serves the dashboard at "/"
+// and its assets under "/static/".
+func NewHandler(opts *Options) http.Handler {
+	h := &handler{started: time.Now()}
+	if opts != nil {
+		h.opts = *opts
+	}
+	templateFiles, err := fs.Sub(embeddedFiles, "templates")
+	if err != nil {
+		panic(err)
+	}
+	staticFiles, err := fs.Sub(embeddedFiles, "static")
+	if err != nil {
+		panic(err)
+	}
+	cfg := action.NewConfig[*http.Request](templateFiles)
+	cfg.ReportError = h.opts.Errors.Report
+	h.dashboard = cfg.NewHandler(h.serveDashboard)
+	h.static = http.StripPrefix("/static", static.NewHandler(staticFiles))
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.opts.Authorize == nil || !h.opts.Authorize(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	switch {
+	case r.URL.Path == "/" || r.URL.Path == "":
+		h.dashboard.ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/static/"):
+		h.static.ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Dashboard is the data presented on the dashboard.
+type Dashboard struct {
+	Runtime RuntimeStats `json:"runtime"`
+	Routes  []Route      `json:"routes"`
+	Errors  []ErrorEntry `json:"errors"`
+}
+
+// RuntimeStats is a summary of the Go runtime's state.
+type RuntimeStats struct {
+	GoVersion  string        `json:"goVersion"`
+	Uptime     time.Duration `json:"uptime"`
+	Goroutines int           `json:"goroutines"`
+	HeapAlloc  uint64        `json:"heapAlloc"`
+	Sys        uint64        `json:"sys"`
+	NumGC      uint32        `json:"numGC"`
+}
+
+// Route is a route registered in a [mux.Router].
+type Route struct {
+	Path    string   `json:"path"`
+	Methods []string `json:"methods,omitempty"`
+	Name    string   `json:"name,omitempty"`
+}
+
+func (h *handler) serveDashboard(ctx context.Context, r *http.Request) (*action.Response, error) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return nil, action.WithStatusCode(http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	d := &Dashboard{
+		Runtime: RuntimeStats{
+			GoVersion:  runtime.Version(),
+			Uptime:     time.Since(h.started).Round(time.Second),
+			Goroutines: runtime.NumGoroutine(),
+			HeapAlloc:  mem.HeapAlloc,
+			Sys:        mem.Sys,
+			NumGC:      mem.NumGC,
+		},
+		Errors: h.opts.Errors.Recent(),
+	}
+	if h.opts.Router != nil {
+		var err error
+		d.Routes, err = listRoutes(h.opts.Router)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &action.Response{
+		HTMLTemplate: "index.html",
+		TemplateData: d,
+		JSONValue:    d,
+	}, nil
+}
+
+func listRoutes(router *mux.Router) ([]Route, error) {
+	var routes []Route
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			// Routes without paths (like host matchers) are skipped.
+			return nil
+		}
+		methods, _ := route.GetMethods()
+		routes = append(routes, Route{
+			Path:    path,
+			Methods: methods,
+			Name:    route.GetName(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Path < routes[j].Path
+	})
+	return routes, nil
+}
+
+// An ErrorLog records the most recent errors reported by an application.
+// It is safe to use from multiple goroutines.
+// A nil ErrorLog discards all errors.
+type ErrorLog struct {
+	mu      sync.Mutex
+	entries []ErrorEntry
+	next    int
+	full    bool
+}
+
+// ErrorEntry is an error recorded in an [ErrorLog].
+type ErrorEntry struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// NewErrorLog returns a new [ErrorLog] that keeps the last n errors.
+func NewErrorLog(n int) *ErrorLog {
+	if n <= 0 {
+		n = 1
+	}
+	return &ErrorLog{entries: make([]ErrorEntry, n)}
+}
+
+// Report records an error.
+// Its signature matches [action.Config.ReportError],
+// so it can be used to collect errors from handlers.
+func (l *ErrorLog) Report(ctx context.Context, err error) {
+	if l == nil || err == nil {
+		return
+	}
+	ent := ErrorEntry{Time: time.Now(), Message: err.Error()}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[l.next] = ent
+	l.next++
+	if l.next == len(l.entries) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// Recent returns the recorded errors, most recent first.
+func (l *ErrorLog) Recent() []ErrorEntry {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := l.next
+	if l.full {
+		n = len(l.entries)
+	}
+	recent := make([]ErrorEntry, 0, n)
+	for i := 0; i < n; i++ {
+		j := l.next - 1 - i
+		if j < 0 {
+			j += len(l.entries)
+		}
+		recent = append(recent, l.entries[j])
+	}
+	return recent
+}
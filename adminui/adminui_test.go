@@ -0,0 +1,165 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package adminui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/gorilla/mux"
+)
+
+func TestHandler(t *testing.T) {
+	router := mux.NewRouter()
+	router.Handle("/", http.NotFoundHandler()).Methods(http.MethodGet).Name("home")
+	router.Handle("/items/{id}", http.NotFoundHandler())
+	errLog := NewErrorLog(10)
+	errLog.Report(context.Background(), errors.New("bork"))
+	h := NewHandler(&Options{
+		Router:    router,
+		Errors:    errLog,
+		Authorize: func(*http.Request) bool { return true },
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", "text/html")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d; want %d. Body:\n%s", rec.Code, http.StatusOK, rec.Body)
+		}
+		body := rec.Body.String()
+		for _, want := range []string{"/items/{id}", "home", "bork"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("body does not contain %q. Body:\n%s", want, body)
+			}
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d; want %d. Body:\n%s", rec.Code, http.StatusOK, rec.Body)
+		}
+		got := new(Dashboard)
+		if err := json.Unmarshal(rec.Body.Bytes(), got); err != nil {
+			t.Fatal(err)
+		}
+		want := []Route{
+			{Path: "/", Methods: []string{http.MethodGet}, Name: "home"},
+			{Path: "/items/{id}"},
+		}
+		if diff := cmp.Diff(want, got.Routes); diff != "" {
+			t.Errorf("routes (-want +got):\n%s", diff)
+		}
+		if len(got.Errors) != 1 || got.Errors[0].Message != "bork" {
+			t.Errorf("errors = %+v; want [bork]", got.Errors)
+		}
+	})
+
+	t.Run("Static", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/static/admin.css", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/bogus", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d; want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+
+}
+
+func TestHandlerNilAuthorize(t *testing.T) {
+	h := NewHandler(nil)
+	for _, path := range []string{"/", "/static/admin.css"} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = "127.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("GET %s from loopback: status = %d; want %d", path, rec.Code, http.StatusForbidden)
+		}
+	}
+}
+
+func TestHandlerAuthorize(t *testing.T) {
+	h := NewHandler(&Options{
+		Authorize: func(r *http.Request) bool {
+			return r.Header.Get("X-Admin") == "yes"
+		},
+	})
+	for _, admin := range []bool{false, true} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if admin {
+			r.Header.Set("X-Admin", "yes")
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		want := http.StatusForbidden
+		if admin {
+			want = http.StatusOK
+		}
+		if rec.Code != want {
+			t.Errorf("admin=%t: status = %d; want %d", admin, rec.Code, want)
+		}
+	}
+}
+
+func TestErrorLog(t *testing.T) {
+	l := NewErrorLog(3)
+	if got := l.Recent(); len(got) != 0 {
+		t.Errorf("Recent() on empty log = %v; want []", got)
+	}
+	for i := 1; i <= 5; i++ {
+		l.Report(context.Background(), fmt.Errorf("error %d", i))
+	}
+	l.Report(context.Background(), nil)
+	var got []string
+	for _, ent := range l.Recent() {
+		got = append(got, ent.Message)
+	}
+	want := []string{"error 5", "error 4", "error 3"}
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Recent() (-want +got):\n%s", diff)
+	}
+
+	var nilLog *ErrorLog
+	nilLog.Report(context.Background(), errors.New("ignored"))
+	if got := nilLog.Recent(); got != nil {
+		t.Errorf("(*ErrorLog)(nil).Recent() = %v; want nil", got)
+	}
+}
@@ -0,0 +1,407 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package antibot provides lightweight spam protection for public HTML forms.
+//
+// A [Guard] adds hidden fields to a form with a template function
+// and checks them when the form is submitted.
+// Submissions are rejected if they fill in a honeypot field
+// that humans never see, arrive too soon after the form was rendered,
+// or (optionally) lack a proof-of-work solution computed by the browser.
+// None of these stop a determined attacker,
+// but they filter out most unsophisticated form spam
+// without a CAPTCHA.
+package antibot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"math/bits"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"zombiezen.com/go/bass/action"
+	"zombiezen.com/go/bass/clock"
+)
+
+// Default form field names used by a [Guard].
+const (
+	DefaultTokenField    = "antibot_token"
+	DefaultHoneypotField = "website"
+	DefaultSolutionField = "antibot_solution"
+)
+
+// DefaultMaxAge is the maximum age of a form
+// used by a [Guard] with a zero MaxAge.
+const DefaultMaxAge = 1 * time.Hour
+
+const (
+	// minKeySize is the minimum length of a Guard's Key in bytes.
+	minKeySize = 32
+	nonceSize  = 16
+	// maxFieldSize is the largest form field value
+	// that is read from a multipart body.
+	maxFieldSize = 1 << 10
+)
+
+// ErrRejected is returned (wrapped) by [*Guard.Check]
+// for submissions that appear to come from a bot.
+var ErrRejected = errors.New("antibot: submission rejected")
+
+// A Guard protects forms from automated submissions.
+//
+// Templates call the function returned by [*Guard.TemplateFuncs]
+// inside a <form> element to emit the Guard's hidden fields:
+// a signed token recording when the form was rendered
+// and a honeypot text field positioned off-screen.
+// For multipart/form-data forms, the fields must be the first in the form.
+//
+// If Difficulty is greater than zero,
+// the form must also include a proof-of-work solution:
+// the solution field carries data-antibot-challenge
+// and data-antibot-difficulty attributes,
+// and client-side script must set its value to a decimal number n
+// such that the SHA-256 hash of challenge + ":" + n
+// begins with difficulty zero bits (see [Solve]).
+//
+// Pages that render the fields should not be stored in an [action.Cache]
+// for longer than MaxAge.
+//
+// By itself, a Guard does not prevent a token from being submitted
+// more than once before it expires:
+// a bot can render the form once and replay the token
+// (and its proof-of-work solution) in many submissions.
+// Set UseNonce to reject replayed tokens.
+type Guard struct {
+	// Key is the secret used to sign tokens.
+	// It must be at least 32 bytes long
+	// and should be the same across all servers handling requests.
+	Key []byte
+
+	// TokenField is the name of the hidden field that holds the token.
+	// If it is empty, then [DefaultTokenField] is used.
+	TokenField string
+	// HoneypotField is the name of the field that must be left empty.
+	// It should be a name that form-filling bots find tempting.
+	// If it is empty, then [DefaultHoneypotField] is used.
+	HoneypotField string
+	// SolutionField is the name of the field that holds
+	// the proof-of-work solution.
+	// If it is empty, then [DefaultSolutionField] is used.
+	SolutionField string
+
+	// MinSubmitTime is the minimum time between rendering the form
+	// and submitting it.
+	// Humans take at least a few seconds to fill in a form.
+	MinSubmitTime time.Duration
+	// MaxAge is the maximum time between rendering the form
+	// and submitting it.
+	// If it is zero, then [DefaultMaxAge] is used.
+	// If it is negative, forms do not expire.
+	MaxAge time.Duration
+	// Difficulty is the number of leading zero bits
+	// required in the proof-of-work hash.
+	// Each additional bit doubles the expected work for the client.
+	// If it is zero or negative, no proof of work is required.
+	Difficulty int
+
+	// UseNonce is called with the unique nonce of each submitted token
+	// that passes all other checks
+	// and the time the token expires
+	// (or the zero time if forms do not expire).
+	// It should record the nonce until it expires
+	// and report whether the nonce had not been used before.
+	// If UseNonce returns false, the submission is rejected.
+	// If UseNonce is nil, a token can be submitted any number of times
+	// until it expires.
+	UseNonce func(ctx context.Context, nonce string, expires time.Time) bool
+
+	// Clock is the source of the current time.
+	// If it is nil, then [clock.Real] is used.
+	Clock clock.Clock
+}
+
+// TemplateFuncs returns template functions for rendering forms.
+// antibotFields returns the HTML for the Guard's hidden fields.
+// It can be added to an [action.Config] TemplateFuncs.
+func (g *Guard) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"antibotFields": g.Fields,
+	}
+}
+
+// Fields returns the HTML for the Guard's hidden fields
+// with a new token.
+func (g *Guard) Fields() (template.HTML, error) {
+	if len(g.Key) < minKeySize {
+		return "", fmt.Errorf("antibot: key must be at least %d bytes", minKeySize)
+	}
+	token, err := g.newToken()
+	if err != nil {
+		return "", err
+	}
+	sb := new(strings.Builder)
+	sb.WriteString(`<input type="hidden" name="`)
+	sb.WriteString(template.HTMLEscapeString(g.tokenField()))
+	sb.WriteString(`" value="`)
+	sb.WriteString(token)
+	sb.WriteString(`">`)
+	sb.WriteString(`<div style="position:absolute;left:-10000px;top:auto;overflow:hidden" aria-hidden="true">`)
+	sb.WriteString(`<input type="text" name="`)
+	sb.WriteString(template.HTMLEscapeString(g.honeypotField()))
+	sb.WriteString(`" value="" tabindex="-1" autocomplete="off">`)
+	sb.WriteString(`</div>`)
+	if g.Difficulty > 0 {
+		sb.WriteString(`<input type="hidden" name="`)
+		sb.WriteString(template.HTMLEscapeString(g.solutionField()))
+		sb.WriteString(`" value="" data-antibot-challenge="`)
+		sb.WriteString(token)
+		sb.WriteString(`" data-antibot-difficulty="`)
+		sb.WriteString(strconv.Itoa(g.Difficulty))
+		sb.WriteString(`">`)
+	}
+	return template.HTML(sb.String()), nil
+}
+
+// Protect returns a function suitable for an [action.Config] TransformRequest
+// that checks the request with [*Guard.Check] before calling transform.
+// If transform is nil and R is [*http.Request],
+// the request is passed through unchanged.
+// Rejected requests are served a 400 (Bad Request) error.
+func Protect[R any](g *Guard, transform func(*http.Request) (R, func(), error)) func(*http.Request) (R, func(), error) {
+	return func(r *http.Request) (R, func(), error) {
+		var zero R
+		if err := g.Check(r); err != nil {
+			return zero, nil, err
+		}
+		if transform == nil {
+			req, ok := any(r).(R)
+			if !ok {
+				return zero, nil, errors.New("antibot: no transform function provided")
+			}
+			return req, func() {}, nil
+		}
+		return transform(r)
+	}
+}
+
+// Check verifies the antibot fields of a form submission.
+// Requests with methods GET, HEAD, OPTIONS, and TRACE are not checked.
+// The fields are read from URL-encoded bodies with [*http.Request.ParseForm]
+// and from the leading parts of multipart bodies,
+// in which case the consumed bytes are replayed
+// so the rest of the request can read the whole body.
+// Check returns an error wrapping [ErrRejected]
+// with a status code of 400 (Bad Request) if the submission is rejected.
+func (g *Guard) Check(r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return nil
+	}
+	fields, err := g.readFields(r)
+	if err != nil {
+		return err
+	}
+	if fields[g.honeypotField()] != "" {
+		return reject("honeypot field filled in")
+	}
+	token := fields[g.tokenField()]
+	issued, nonce, ok := g.verifyToken(token)
+	if !ok {
+		return reject("missing or invalid token")
+	}
+	age := clock.Or(g.Clock).Now().Sub(issued)
+	if age < g.MinSubmitTime {
+		return reject(fmt.Sprintf("submitted %v after rendering", age.Round(time.Millisecond)))
+	}
+	maxAge := g.maxAge()
+	if maxAge > 0 && age > maxAge {
+		return reject("form expired")
+	}
+	if g.Difficulty > 0 && !verifySolution(token, fields[g.solutionField()], g.Difficulty) {
+		return reject("missing or invalid proof of work")
+	}
+	if g.UseNonce != nil {
+		var expires time.Time
+		if maxAge > 0 {
+			expires = issued.Add(maxAge)
+		}
+		if !g.UseNonce(r.Context(), nonce, expires) {
+			return reject("token already used")
+		}
+	}
+	return nil
+}
+
+func reject(reason string) error {
+	return action.WithStatusCode(http.StatusBadRequest, fmt.Errorf("%w: %s", ErrRejected, reason))
+}
+
+// readFields returns the values of the Guard's fields in r's form.
+func (g *Guard) readFields(r *http.Request) (map[string]string, error) {
+	names := []string{g.tokenField(), g.honeypotField(), g.solutionField()}
+	fields := make(map[string]string, len(names))
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, action.WithStatusCode(http.StatusBadRequest, fmt.Errorf("antibot: %w", err))
+		}
+		for _, name := range names {
+			fields[name] = r.PostForm.Get(name)
+		}
+	case "multipart/form-data":
+		if r.Body == nil || params["boundary"] == "" {
+			return fields, nil
+		}
+		consumed := new(bytes.Buffer)
+		mr := multipart.NewReader(io.TeeReader(r.Body, consumed), params["boundary"])
+		for range names {
+			part, err := mr.NextPart()
+			if err != nil || !containsString(names, part.FormName()) {
+				break
+			}
+			value, _ := io.ReadAll(io.LimitReader(part, maxFieldSize))
+			if _, seen := fields[part.FormName()]; !seen {
+				fields[part.FormName()] = string(value)
+			}
+		}
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(consumed, r.Body), r.Body}
+	}
+	return fields, nil
+}
+
+// newToken returns a signed token holding the current time
+// and a random nonce.
+func (g *Guard) newToken() (string, error) {
+	payload := make([]byte, 8+nonceSize, 8+nonceSize+sha256.Size)
+	binary.BigEndian.PutUint64(payload, uint64(clock.Or(g.Clock).Now().UnixNano()))
+	if _, err := rand.Read(payload[8:]); err != nil {
+		return "", fmt.Errorf("antibot: %w", err)
+	}
+	mac := hmac.New(sha256.New, g.Key)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(payload)), nil
+}
+
+// verifyToken returns the time a token was issued and its encoded nonce
+// if it was signed with g.Key.
+func (g *Guard) verifyToken(token string) (issued time.Time, nonce string, ok bool) {
+	if len(g.Key) < minKeySize {
+		return time.Time{}, "", false
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) != 8+nonceSize+sha256.Size {
+		return time.Time{}, "", false
+	}
+	payload, sig := buf[:8+nonceSize], buf[8+nonceSize:]
+	mac := hmac.New(sha256.New, g.Key)
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return time.Time{}, "", false
+	}
+	issued = time.Unix(0, int64(binary.BigEndian.Uint64(payload)))
+	return issued, base64.RawURLEncoding.EncodeToString(payload[8:]), true
+}
+
+// Solve returns a proof-of-work solution for the given challenge:
+// the smallest decimal number n such that the SHA-256 hash
+// of challenge + ":" + n begins with difficulty zero bits.
+// It is intended for tests and non-browser clients;
+// browsers compute the same value in script.
+func Solve(challenge string, difficulty int) string {
+	for n := uint64(0); ; n++ {
+		solution := strconv.FormatUint(n, 10)
+		if verifySolution(challenge, solution, difficulty) {
+			return solution
+		}
+	}
+}
+
+func verifySolution(challenge, solution string, difficulty int) bool {
+	if solution == "" {
+		return false
+	}
+	if _, err := strconv.ParseUint(solution, 10, 64); err != nil {
+		return false
+	}
+	sum := sha256.Sum256([]byte(challenge + ":" + solution))
+	return leadingZeroBits(sum[:]) >= difficulty
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, c := range b {
+		if c != 0 {
+			return n + bits.LeadingZeros8(c)
+		}
+		n += 8
+	}
+	return n
+}
+
+func (g *Guard) maxAge() time.Duration {
+	if g.MaxAge == 0 {
+		return DefaultMaxAge
+	}
+	return g.MaxAge
+}
+
+func (g *Guard) tokenField() string {
+	if g.TokenField == "" {
+		return DefaultTokenField
+	}
+	return g.TokenField
+}
+
+func (g *Guard) honeypotField() string {
+	if g.HoneypotField == "" {
+		return DefaultHoneypotField
+	}
+	return g.HoneypotField
+}
+
+func (g *Guard) solutionField() string {
+	if g.SolutionField == "" {
+		return DefaultSolutionField
+	}
+	return g.SolutionField
+}
+
+func containsString(list []string, s string) bool {
+	for _, elem := range list {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
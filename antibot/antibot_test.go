@@ -0,0 +1,275 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package antibot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"zombiezen.com/go/bass/action"
+	"zombiezen.com/go/bass/clock"
+)
+
+var tokenPattern = regexp.MustCompile(`name="antibot_token" value="([^"]+)"`)
+
+func TestGuard(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, time.May, 1, 9, 0, 0, 0, time.UTC))
+	g := &Guard{
+		Key:           bytes.Repeat([]byte{0x17}, 32),
+		MinSubmitTime: 3 * time.Second,
+		MaxAge:        time.Hour,
+		Clock:         clk,
+	}
+	fields, err := g.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := tokenPattern.FindStringSubmatch(string(fields))
+	if m == nil {
+		t.Fatalf("Fields() = %q; want token field", fields)
+	}
+	token := m[1]
+	if !strings.Contains(string(fields), `name="website"`) {
+		t.Errorf("Fields() = %q; want honeypot field", fields)
+	}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		form    url.Values
+		wantErr bool
+	}{
+		{
+			name:    "TooFast",
+			advance: time.Second,
+			form:    url.Values{"antibot_token": {token}},
+			wantErr: true,
+		},
+		{
+			name:    "Human",
+			advance: 10 * time.Second,
+			form:    url.Values{"antibot_token": {token}, "website": {""}},
+		},
+		{
+			name:    "Honeypot",
+			form:    url.Values{"antibot_token": {token}, "website": {"http://spam.example.com/"}},
+			wantErr: true,
+		},
+		{
+			name:    "MissingToken",
+			form:    url.Values{"message": {"hi"}},
+			wantErr: true,
+		},
+		{
+			name:    "ForgedToken",
+			form:    url.Values{"antibot_token": {"A" + token[1:]}},
+			wantErr: true,
+		},
+		{
+			name:    "Expired",
+			advance: 2 * time.Hour,
+			form:    url.Values{"antibot_token": {token}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		clk.Advance(test.advance)
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		err := g.Check(r)
+		if test.wantErr {
+			if !errors.Is(err, ErrRejected) {
+				t.Errorf("%s: Check(...) = %v; want %v", test.name, err, ErrRejected)
+			}
+			if code := action.ErrorStatusCode(err); code != http.StatusBadRequest {
+				t.Errorf("%s: ErrorStatusCode(Check(...)) = %d; want %d", test.name, code, http.StatusBadRequest)
+			}
+		} else if err != nil {
+			t.Errorf("%s: Check(...) = %v; want <nil>", test.name, err)
+		}
+	}
+
+	if err := g.Check(httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+		t.Errorf("Check(GET) = %v; want <nil>", err)
+	}
+}
+
+func TestGuardProofOfWork(t *testing.T) {
+	g := &Guard{
+		Key:        bytes.Repeat([]byte{0x17}, 32),
+		Difficulty: 8,
+	}
+	fields, err := g.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(fields), `data-antibot-difficulty="8"`) {
+		t.Errorf("Fields() = %q; want difficulty attribute", fields)
+	}
+	token := tokenPattern.FindStringSubmatch(string(fields))[1]
+	solution := Solve(token, g.Difficulty)
+
+	for _, test := range []struct {
+		solution string
+		wantErr  bool
+	}{
+		{solution: solution},
+		{solution: "", wantErr: true},
+		{solution: "not-a-number", wantErr: true},
+	} {
+		form := url.Values{"antibot_token": {token}, "antibot_solution": {test.solution}}
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if err := g.Check(r); (err != nil) != test.wantErr {
+			t.Errorf("Check(solution=%q) = %v; want error = %t", test.solution, err, test.wantErr)
+		}
+	}
+}
+
+func TestGuardDefaultMaxAge(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, time.May, 1, 9, 0, 0, 0, time.UTC))
+	g := &Guard{
+		Key:   bytes.Repeat([]byte{0x17}, 32),
+		Clock: clk,
+	}
+	fields, err := g.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := tokenPattern.FindStringSubmatch(string(fields))[1]
+	clk.Advance(DefaultMaxAge + time.Second)
+	form := url.Values{"antibot_token": {token}}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := g.Check(r); !errors.Is(err, ErrRejected) {
+		t.Errorf("Check(...) after %v = %v; want %v", DefaultMaxAge+time.Second, err, ErrRejected)
+	}
+}
+
+func TestGuardUseNonce(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 9, 0, 0, 0, time.UTC)
+	used := make(map[string]time.Time)
+	g := &Guard{
+		Key:    bytes.Repeat([]byte{0x17}, 32),
+		MaxAge: time.Hour,
+		Clock:  clock.NewFake(now),
+		UseNonce: func(ctx context.Context, nonce string, expires time.Time) bool {
+			if _, dup := used[nonce]; dup {
+				return false
+			}
+			used[nonce] = expires
+			return true
+		},
+	}
+	fields, err := g.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := tokenPattern.FindStringSubmatch(string(fields))[1]
+	for i, wantErr := range []bool{false, true} {
+		form := url.Values{"antibot_token": {token}}
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if err := g.Check(r); (err != nil) != wantErr {
+			t.Errorf("Check(...) #%d = %v; want error = %t", i+1, err, wantErr)
+		}
+	}
+	if len(used) != 1 {
+		t.Fatalf("UseNonce recorded %d nonces; want 1", len(used))
+	}
+	for _, expires := range used {
+		if want := now.Add(time.Hour); !expires.Equal(want) {
+			t.Errorf("expires = %v; want %v", expires, want)
+		}
+	}
+}
+
+func TestGuardShortKey(t *testing.T) {
+	g := &Guard{Key: bytes.Repeat([]byte{0x17}, 16)}
+	if _, err := g.Fields(); err == nil {
+		t.Error("Fields() with 16-byte key did not return an error")
+	}
+	token, err := g.newToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := g.verifyToken(token); ok {
+		t.Error("verifyToken with 16-byte key succeeded")
+	}
+}
+
+func TestProtect(t *testing.T) {
+	g := &Guard{Key: bytes.Repeat([]byte{0x17}, 32)}
+	cfg := &action.Config[*http.Request]{
+		TransformRequest: Protect[*http.Request](g, nil),
+		TemplateFuncs:    g.TemplateFuncs(),
+	}
+	var gotMessage string
+	h := cfg.NewHandler(func(ctx context.Context, r *http.Request) (*action.Response, error) {
+		form, cleanup, err := action.ParseMultipartForm(r, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		gotMessage = form.Value.Get("message")
+		return nil, nil
+	})
+
+	tmpl := template.Must(template.New("form").Funcs(g.TemplateFuncs()).Parse(`{{ antibotFields }}`))
+	rendered := new(strings.Builder)
+	if err := tmpl.Execute(rendered, nil); err != nil {
+		t.Fatal(err)
+	}
+	token := tokenPattern.FindStringSubmatch(rendered.String())[1]
+
+	post := func(honeypot string) *httptest.ResponseRecorder {
+		body := new(bytes.Buffer)
+		mw := multipart.NewWriter(body)
+		mw.WriteField("antibot_token", token)
+		mw.WriteField("website", honeypot)
+		mw.WriteField("message", "Hello")
+		mw.Close()
+		r := httptest.NewRequest(http.MethodPost, "/", body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+	if rec := post(""); rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d; want %d", rec.Code, http.StatusNoContent)
+	}
+	if gotMessage != "Hello" {
+		t.Errorf("message = %q; want %q", gotMessage, "Hello")
+	}
+	gotMessage = ""
+	if rec := post("spam"); rec.Code != http.StatusBadRequest {
+		t.Errorf("with honeypot, status = %d; want %d", rec.Code, http.StatusBadRequest)
+	}
+	if gotMessage != "" {
+		t.Error("with honeypot, Func was called")
+	}
+}
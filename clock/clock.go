@@ -0,0 +1,227 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package clock provides an injectable source of time,
+// so that code which depends on the current time
+// can be tested deterministically with a [Fake].
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// A Clock tells the current time and creates timers.
+// Implementations must be safe to use from multiple goroutines.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// A Timer is a single event, like a [time.Timer].
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// A Ticker delivers ticks at intervals, like a [time.Ticker].
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// Real is a [Clock] that uses the functions in the time package.
+var Real Clock = realClock{}
+
+// Or returns c if it is not nil or [Real] otherwise.
+// It is intended for optional Clock fields.
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time        { return t.t.C }
+func (t realTimer) Stop() bool                 { return t.t.Stop() }
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+type realTicker struct{ t *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time   { return t.t.C }
+func (t realTicker) Stop()                 { t.t.Stop() }
+func (t realTicker) Reset(d time.Duration) { t.t.Reset(d) }
+
+// A Fake is a [Clock] whose time only changes when Advance is called.
+// Timers and tickers created by a Fake fire during calls to Advance.
+// The zero value is not usable; create a Fake with [NewFake].
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+// NewFake returns a new [Fake] whose current time is now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the fake's current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the fake's current time forward by d,
+// firing any timers and tickers that expire along the way in order.
+// Like the time package's tickers,
+// a fake ticker drops ticks if its channel has not been drained.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	end := f.now.Add(d)
+	for {
+		sort.SliceStable(f.waiters, func(i, j int) bool {
+			return f.waiters[i].when.Before(f.waiters[j].when)
+		})
+		if len(f.waiters) == 0 || f.waiters[0].when.After(end) {
+			break
+		}
+		t := f.waiters[0]
+		f.now = t.when
+		select {
+		case t.c <- t.when:
+		default:
+		}
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+		} else {
+			f.remove(t)
+		}
+	}
+	f.now = end
+}
+
+// Waiters returns the number of active timers and tickers.
+// Tests can use it to wait until a goroutine has created a timer
+// before calling Advance.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// NewTimer returns a [Timer] that fires once
+// after the fake's time has advanced by d.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{fake: f, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// NewTicker returns a [Ticker] that fires
+// every time the fake's time advances by d.
+// NewTicker panics if d is not positive.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	t := &fakeTimer{fake: f, c: make(chan time.Time, 1), period: d}
+	t.Reset(d)
+	return fakeTicker{t}
+}
+
+// fakeTimer is a timer or ticker created by a [Fake].
+// Its fields are guarded by fake.mu.
+type fakeTimer struct {
+	fake   *Fake
+	c      chan time.Time
+	when   time.Time
+	period time.Duration // zero for timers
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	return t.fake.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	active := t.fake.remove(t)
+	t.when = t.fake.now.Add(d)
+	t.fake.waiters = append(t.fake.waiters, t)
+	return active
+}
+
+type fakeTicker struct{ t *fakeTimer }
+
+func (t fakeTicker) C() <-chan time.Time {
+	return t.t.c
+}
+
+func (t fakeTicker) Stop() {
+	t.t.Stop()
+}
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	t.t.fake.mu.Lock()
+	t.t.period = d
+	t.t.fake.mu.Unlock()
+	t.t.Reset(d)
+}
+
+// remove removes t from f's waiters,
+// reporting whether it was present.
+// The caller must hold f.mu.
+func (f *Fake) remove(t *fakeTimer) bool {
+	for i, w := range f.waiters {
+		if w == t {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
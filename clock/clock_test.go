@@ -0,0 +1,127 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeTimer(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(10 * time.Second)
+	if got := f.Waiters(); got != 1 {
+		t.Errorf("Waiters() = %d; want 1", got)
+	}
+
+	f.Advance(9 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+	f.Advance(2 * time.Second)
+	select {
+	case got := <-timer.C():
+		if want := epoch.Add(10 * time.Second); !got.Equal(want) {
+			t.Errorf("timer fired with %v; want %v", got, want)
+		}
+	default:
+		t.Fatal("timer did not fire")
+	}
+	if got, want := f.Now(), epoch.Add(11*time.Second); !got.Equal(want) {
+		t.Errorf("Now() = %v; want %v", got, want)
+	}
+	if timer.Stop() {
+		t.Error("Stop() = true after timer fired")
+	}
+	if timer.Reset(time.Second) {
+		t.Error("Reset() = true after timer fired")
+	}
+	if !timer.Stop() {
+		t.Error("Stop() = false for active timer")
+	}
+	f.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Error("stopped timer fired")
+	default:
+	}
+	if got := f.Waiters(); got != 0 {
+		t.Errorf("Waiters() = %d; want 0", got)
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	f := NewFake(epoch)
+	ticker := f.NewTicker(time.Minute)
+	var ticks []time.Time
+	for i := 0; i < 3; i++ {
+		f.Advance(time.Minute)
+		select {
+		case tick := <-ticker.C():
+			ticks = append(ticks, tick)
+		default:
+		}
+	}
+	if len(ticks) != 3 {
+		t.Fatalf("got %d ticks; want 3", len(ticks))
+	}
+	for i, tick := range ticks {
+		if want := epoch.Add(time.Duration(i+1) * time.Minute); !tick.Equal(want) {
+			t.Errorf("tick %d = %v; want %v", i, tick, want)
+		}
+	}
+
+	// Undrained ticks are dropped.
+	f.Advance(5 * time.Minute)
+	if got, want := <-ticker.C(), epoch.Add(4*time.Minute); !got.Equal(want) {
+		t.Errorf("after long advance, tick = %v; want %v", got, want)
+	}
+	select {
+	case tick := <-ticker.C():
+		t.Errorf("extra tick %v", tick)
+	default:
+	}
+
+	ticker.Reset(time.Hour)
+	f.Advance(59 * time.Minute)
+	select {
+	case tick := <-ticker.C():
+		t.Errorf("tick %v before reset interval", tick)
+	default:
+	}
+	ticker.Stop()
+	f.Advance(time.Hour)
+	select {
+	case tick := <-ticker.C():
+		t.Errorf("tick %v after Stop", tick)
+	default:
+	}
+}
+
+func TestOr(t *testing.T) {
+	if got := Or(nil); got != Real {
+		t.Errorf("Or(nil) = %v; want Real", got)
+	}
+	f := NewFake(epoch)
+	if got := Or(f); got != f {
+		t.Errorf("Or(f) = %v; want f", got)
+	}
+}
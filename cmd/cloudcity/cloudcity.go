@@ -42,7 +42,10 @@ func main() {
 	}
 	rootCmd.AddCommand(
 		newInitCmd(),
+		newInfoCmd(),
 		newServerCmd(),
+		newWatchCmd(),
+		newSelfUpdateCmd(),
 	)
 
 	clientCmd := &cobra.Command{
@@ -65,9 +68,43 @@ func main() {
 	}
 	routesCmd.AddCommand(
 		newListRoutesCmd(),
+		newRoutesCoverageCmd(),
 	)
 	rootCmd.AddCommand(routesCmd)
 
+	generateCmd := &cobra.Command{
+		Use:           "generate",
+		Short:         "Generate code",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	generateCmd.AddCommand(
+		newGenerateTestsCmd(),
+	)
+	rootCmd.AddCommand(generateCmd)
+
+	migrateCmd := &cobra.Command{
+		Use:           "migrate",
+		Short:         "Migrate application code to newer APIs",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	migrateCmd.AddCommand(
+		newMigrateRouterCmd(),
+	)
+	rootCmd.AddCommand(migrateCmd)
+
+	versionCmd := &cobra.Command{
+		Use:           "version",
+		Short:         "Manage the application version",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	versionCmd.AddCommand(
+		newBumpVersionCmd(),
+	)
+	rootCmd.AddCommand(versionCmd)
+
 	err := rootCmd.ExecuteContext(ctx)
 	cancel()
 	if err != nil {
@@ -79,18 +116,12 @@ func main() {
 // findGoModuleDir locates the root Go module directory.
 func findGoModuleDir(ctx context.Context, dir string) (string, error) {
 	c := exec.Command("go", "env", "GOMOD")
-	stdout := new(strings.Builder)
-	c.Stdout = stdout
-	stderr := new(strings.Builder)
-	c.Stderr = stderr
 	c.Dir = dir
-	if err := sigterm.Run(ctx, c); err != nil {
-		if stderr.Len() == 0 {
-			return "", fmt.Errorf("find go module directory for %s: %w", dir, err)
-		}
-		return "", fmt.Errorf("find go module directory for %s: %w; stderr:\n%s", dir, err, strings.TrimSuffix(stderr.String(), "\n"))
+	result, err := sigterm.Output(ctx, c)
+	if err != nil {
+		return "", fmt.Errorf("find go module directory for %s: %w", dir, err)
 	}
-	gomod := strings.TrimSuffix(stdout.String(), "\n")
+	gomod := strings.TrimSuffix(string(result.Stdout), "\n")
 	if gomod == "" || gomod == "/dev/null" || gomod == "NUL" {
 		return "", fmt.Errorf("find go module directory for %s: not found", gomod)
 	}
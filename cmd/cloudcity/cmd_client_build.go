@@ -25,11 +25,13 @@ import (
 
 	"github.com/spf13/cobra"
 	"zombiezen.com/go/bass/sigterm"
+	"zombiezen.com/go/bass/static"
 )
 
 type buildClientCmd struct {
-	compile bool
-	install bool
+	compile     bool
+	install     bool
+	precompress bool
 }
 
 func newBuildClientCmd() *cobra.Command {
@@ -44,6 +46,7 @@ func newBuildClientCmd() *cobra.Command {
 	}
 	c.Flags().BoolVarP(&cmd.compile, "compile", "c", true, "perform type-checking")
 	c.Flags().BoolVarP(&cmd.install, "install", "i", false, "install dependencies")
+	c.Flags().BoolVar(&cmd.precompress, "precompress", false, "write gzip-compressed copies of the bundled files")
 	return c
 }
 
@@ -64,7 +67,7 @@ func (cmd *buildClientCmd) build(ctx context.Context, root string) error {
 		npmInstallCmd.Dir = clientDir
 		npmInstallCmd.Stdout = os.Stderr
 		npmInstallCmd.Stderr = os.Stderr
-		if err := sigterm.Run(ctx, npmInstallCmd); err != nil {
+		if _, err := sigterm.Output(ctx, npmInstallCmd); err != nil {
 			return fmt.Errorf("build client: npm install: %w", err)
 		}
 	}
@@ -75,7 +78,7 @@ func (cmd *buildClientCmd) build(ctx context.Context, root string) error {
 		npmCompileCmd.Dir = clientDir
 		npmCompileCmd.Stdout = os.Stderr
 		npmCompileCmd.Stderr = os.Stderr
-		if err := sigterm.Run(ctx, npmCompileCmd); err != nil {
+		if _, err := sigterm.Output(ctx, npmCompileCmd); err != nil {
 			return fmt.Errorf("build client: npm run compile: %w", err)
 		}
 	}
@@ -85,9 +88,17 @@ func (cmd *buildClientCmd) build(ctx context.Context, root string) error {
 	npmBuildCmd.Dir = clientDir
 	npmBuildCmd.Stdout = os.Stderr
 	npmBuildCmd.Stderr = os.Stderr
-	if err := sigterm.Run(ctx, npmBuildCmd); err != nil {
+	if _, err := sigterm.Output(ctx, npmBuildCmd); err != nil {
 		return fmt.Errorf("build client: npm run build: %w", err)
 	}
 
+	if cmd.precompress {
+		fmt.Fprintln(os.Stderr, "## precompress ##")
+		distDir := filepath.Join(clientDir, "dist")
+		if err := static.Precompress(os.DirFS(distDir), distDir, static.Gzip); err != nil {
+			return fmt.Errorf("build client: %w", err)
+		}
+	}
+
 	return nil
 }
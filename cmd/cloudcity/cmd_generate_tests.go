@@ -0,0 +1,217 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+type generateTestsCmd struct {
+	routesFile string
+	output     string
+}
+
+func newGenerateTestsCmd() *cobra.Command {
+	cmd := new(generateTestsCmd)
+	c := &cobra.Command{
+		Use:   "tests [options]",
+		Short: "Generate test skeletons for routes",
+		Long: "Generate a table-driven test for the routes printed by `cloudcity routes list --json`.\n\n" +
+			"The generated test sends a request for each route to the application\n" +
+			"and checks the response's status code. Review the expected status codes\n" +
+			"and path variables before committing the file.",
+		Args: cobra.NoArgs,
+		RunE: func(cc *cobra.Command, args []string) error {
+			return cmd.run(cc.Context())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().StringVar(&cmd.routesFile, "routes", "-", "`file` containing JSON routes list (- for stdin)")
+	c.Flags().StringVarP(&cmd.output, "output", "o", "", "write test to `file` instead of stdout (must not exist)")
+	return c
+}
+
+func (cmd *generateTestsCmd) run(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("generate tests: %w", err)
+		}
+	}()
+
+	var input []byte
+	if cmd.routesFile == "-" {
+		input, err = io.ReadAll(os.Stdin)
+	} else {
+		input, err = os.ReadFile(cmd.routesFile)
+	}
+	if err != nil {
+		return err
+	}
+	var routes []route
+	if err := json.Unmarshal(input, &routes); err != nil {
+		return fmt.Errorf("parse routes: %w", err)
+	}
+	src, err := generateRouteTests(routes)
+	if err != nil {
+		return err
+	}
+
+	if cmd.output == "" {
+		_, err := os.Stdout.Write(src)
+		return err
+	}
+	f, err := os.OpenFile(cmd.output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(src)
+	closeErr := f.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// routeTestCase is a row in a generated route test.
+type routeTestCase struct {
+	Method     string
+	Path       string
+	StatusCode string
+	Note       string
+}
+
+// generateRouteTests returns the Go source for a test of the given routes.
+func generateRouteTests(routes []route) ([]byte, error) {
+	cases := make([]routeTestCase, 0, len(routes))
+	for _, r := range routes {
+		method := r.Method
+		if method == "*" {
+			method = http.MethodGet
+		}
+		path, hasVars := fillPathVars(r.Path)
+		tc := routeTestCase{
+			Method:     methodConstant(method),
+			Path:       strconv.Quote(path),
+			StatusCode: "http.StatusOK",
+		}
+		switch method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPost:
+			tc.StatusCode = "http.StatusSeeOther"
+			tc.Note = "TODO: send a valid form"
+		default:
+			tc.Note = "TODO: check expected status"
+		}
+		if hasVars {
+			tc.Note = "TODO: use a valid path"
+		}
+		cases = append(cases, tc)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := routeTestTemplate.Execute(buf, cases); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// pathVarPattern matches a gorilla/mux path variable like "{id}" or "{id:[0-9]+}".
+var pathVarPattern = regexp.MustCompile(`\{[^{}]*(?:\{[^{}]*\}[^{}]*)*\}`)
+
+// fillPathVars replaces path variables with placeholder values.
+func fillPathVars(path string) (_ string, hasVars bool) {
+	filled := pathVarPattern.ReplaceAllString(path, "1")
+	return filled, filled != path
+}
+
+func methodConstant(method string) string {
+	switch method {
+	case http.MethodGet:
+		return "http.MethodGet"
+	case http.MethodHead:
+		return "http.MethodHead"
+	case http.MethodPost:
+		return "http.MethodPost"
+	case http.MethodPut:
+		return "http.MethodPut"
+	case http.MethodPatch:
+		return "http.MethodPatch"
+	case http.MethodDelete:
+		return "http.MethodDelete"
+	case http.MethodOptions:
+		return "http.MethodOptions"
+	default:
+		return strconv.Quote(method)
+	}
+}
+
+var routeTestTemplate = template.Must(template.New("routes_test.go").Parse(`// Test skeleton generated by cloudcity generate tests.
+// Review the expected status codes and paths marked TODO.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoutes(t *testing.T) {
+	app := &application{
+		clientFiles: mustSubFS(embeddedFiles, "client"),
+	}
+	tests := []struct {
+		method         string
+		path           string
+		wantStatusCode int
+	}{
+{{- range . }}
+		{
+			method:         {{ .Method }},
+			path:           {{ .Path }},
+			wantStatusCode: {{ .StatusCode }},{{ with .Note }} // {{ . }}{{ end }}
+		},
+{{- end }}
+	}
+	for _, test := range tests {
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, nil)
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, req)
+			if rec.Code != test.wantStatusCode {
+				t.Errorf("%s %s status = %d; want %d", test.method, test.path, rec.Code, test.wantStatusCode)
+			}
+		})
+	}
+}
+`))
@@ -0,0 +1,68 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGenerateRouteTests(t *testing.T) {
+	routes := []route{
+		{Method: "GET", Path: "/"},
+		{Method: "POST", Path: "/"},
+		{Method: "*", Path: "/about"},
+		{Method: "DELETE", Path: "/items/{id:[0-9]+}"},
+	}
+	src, err := generateRouteTests(routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "routes_test.go", src, 0); err != nil {
+		t.Errorf("generated code does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"method:         http.MethodGet,\n\t\t\tpath:           \"/\",\n\t\t\twantStatusCode: http.StatusOK,",
+		"method:         http.MethodPost,\n\t\t\tpath:           \"/\",\n\t\t\twantStatusCode: http.StatusSeeOther,",
+		"path:           \"/about\",",
+		"path:           \"/items/1\",\n\t\t\twantStatusCode: http.StatusOK, // TODO: use a valid path",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestFillPathVars(t *testing.T) {
+	tests := []struct {
+		path        string
+		want        string
+		wantHasVars bool
+	}{
+		{"/", "/", false},
+		{"/items/{id}", "/items/1", true},
+		{"/items/{id:[0-9]{2}}/edit", "/items/1/edit", true},
+	}
+	for _, test := range tests {
+		got, hasVars := fillPathVars(test.path)
+		if got != test.want || hasVars != test.wantHasVars {
+			t.Errorf("fillPathVars(%q) = %q, %t; want %q, %t", test.path, got, hasVars, test.want, test.wantHasVars)
+		}
+	}
+}
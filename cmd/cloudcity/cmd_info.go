@@ -0,0 +1,357 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+	"zombiezen.com/go/bass/sigterm"
+)
+
+// migrationsDirectoryName is the directory relative to the module root
+// that `cloudcity info` searches for SQL migration files.
+const migrationsDirectoryName = "migrations"
+
+type infoCmd struct {
+	json bool
+}
+
+func newInfoCmd() *cobra.Command {
+	cmd := new(infoCmd)
+	c := &cobra.Command{
+		Use:   "info [options]",
+		Short: "Show project information",
+		Long: "Print the project's configuration as cloudcity sees it:\n" +
+			"the module, its routes, migrations, client toolchain, templates,\n" +
+			"and the versions of bass packages it depends on.\n" +
+			"Include this output when reporting bugs.",
+		Args: cobra.NoArgs,
+		RunE: func(cc *cobra.Command, args []string) error {
+			return cmd.run(cc.Context())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().BoolVar(&cmd.json, "json", false, "show output in JSON format")
+	return c
+}
+
+type projectInfo struct {
+	Cloudcity   string            `json:"cloudcity"`
+	GoVersion   string            `json:"goVersion,omitempty"`
+	ModulePath  string            `json:"modulePath"`
+	ModuleGo    string            `json:"moduleGo,omitempty"`
+	Bass        []moduleVersion   `json:"bass"`
+	Routes      *int              `json:"routes,omitempty"`
+	Migrations  migrationInfo     `json:"migrations"`
+	Toolchain   map[string]string `json:"toolchain,omitempty"`
+	ClientDeps  map[string]string `json:"clientDependencies,omitempty"`
+	Templates   []string          `json:"templates"`
+	Diagnostics []string          `json:"diagnostics,omitempty"`
+}
+
+type moduleVersion struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Replace string `json:"replace,omitempty"`
+}
+
+type migrationInfo struct {
+	Count  int    `json:"count"`
+	Latest string `json:"latest,omitempty"`
+}
+
+func (cmd *infoCmd) run(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("info: %w", err)
+		}
+	}()
+
+	root, err := findGoModuleDir(ctx, ".")
+	if err != nil {
+		return err
+	}
+	info := &projectInfo{
+		Cloudcity: installedVersion(),
+	}
+	if v, err := goEnv(ctx, "GOVERSION"); err == nil {
+		info.GoVersion = v
+	} else {
+		info.Diagnostics = append(info.Diagnostics, err.Error())
+	}
+
+	gomod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return err
+	}
+	f, err := modfile.Parse("go.mod", gomod, nil)
+	if err != nil {
+		return err
+	}
+	if f.Module != nil {
+		info.ModulePath = f.Module.Mod.Path
+	}
+	if f.Go != nil {
+		info.ModuleGo = f.Go.Version
+	}
+	info.Bass = bassRequirements(f)
+
+	// Routes and the remaining sections are best-effort:
+	// a project that doesn't build is when this output is most useful.
+	if pkg, err := loadRouterPackage(ctx, packages.NeedSyntax|packages.NeedTypes|packages.NeedTypesInfo); err != nil {
+		info.Diagnostics = append(info.Diagnostics, fmt.Sprintf("routes: %v", err))
+	} else if routes, err := findRoutes(pkg); err != nil {
+		info.Diagnostics = append(info.Diagnostics, fmt.Sprintf("routes: %v", err))
+	} else {
+		n := len(routes)
+		info.Routes = &n
+	}
+	info.Migrations, err = findMigrations(os.DirFS(filepath.Join(root, migrationsDirectoryName)))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		info.Diagnostics = append(info.Diagnostics, fmt.Sprintf("migrations: %v", err))
+	}
+	clientDir := filepath.Join(root, clientDirectoryName)
+	info.Toolchain = make(map[string]string)
+	for _, tool := range []string{"node", "npm"} {
+		if v, err := toolVersion(ctx, tool); err == nil {
+			info.Toolchain[tool] = v
+		} else {
+			info.Toolchain[tool] = "not found"
+		}
+	}
+	info.ClientDeps, err = clientDependencies(os.DirFS(clientDir))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		info.Diagnostics = append(info.Diagnostics, fmt.Sprintf("client: %v", err))
+	}
+	info.Templates, err = findTemplates(os.DirFS(clientDir))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		info.Diagnostics = append(info.Diagnostics, fmt.Sprintf("templates: %v", err))
+	}
+
+	if cmd.json {
+		out, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		out = append(out, '\n')
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	info.print()
+	return nil
+}
+
+func (info *projectInfo) print() {
+	fmt.Printf("cloudcity:  %s\n", info.Cloudcity)
+	if info.GoVersion != "" {
+		fmt.Printf("go:         %s\n", info.GoVersion)
+	}
+	fmt.Printf("module:     %s", info.ModulePath)
+	if info.ModuleGo != "" {
+		fmt.Printf(" (go %s)", info.ModuleGo)
+	}
+	fmt.Println()
+	if info.Routes != nil {
+		fmt.Printf("routes:     %d\n", *info.Routes)
+	} else {
+		fmt.Printf("routes:     unknown\n")
+	}
+	fmt.Printf("migrations: %d", info.Migrations.Count)
+	if info.Migrations.Latest != "" {
+		fmt.Printf(" (latest %s)", info.Migrations.Latest)
+	}
+	fmt.Println()
+
+	fmt.Println("\nbass:")
+	if len(info.Bass) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, m := range info.Bass {
+		fmt.Printf("  %s %s", m.Path, m.Version)
+		if m.Replace != "" {
+			fmt.Printf(" => %s", m.Replace)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("\nclient toolchain:")
+	for _, name := range sortedKeys(info.Toolchain) {
+		fmt.Printf("  %s %s\n", name, info.Toolchain[name])
+	}
+	for _, name := range sortedKeys(info.ClientDeps) {
+		fmt.Printf("  %s %s\n", name, info.ClientDeps[name])
+	}
+
+	fmt.Println("\ntemplates:")
+	if len(info.Templates) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, name := range info.Templates {
+		fmt.Printf("  %s\n", name)
+	}
+
+	if len(info.Diagnostics) > 0 {
+		fmt.Println("\ndiagnostics:")
+		for _, d := range info.Diagnostics {
+			fmt.Printf("  %s\n", d)
+		}
+	}
+}
+
+// bassRequirements returns the modules in go.mod
+// that are part of bass, with any replacements applied.
+func bassRequirements(f *modfile.File) []moduleVersion {
+	var mods []moduleVersion
+	for _, req := range f.Require {
+		if !isBassModule(req.Mod.Path) {
+			continue
+		}
+		mods = append(mods, moduleVersion{
+			Path:    req.Mod.Path,
+			Version: req.Mod.Version,
+		})
+	}
+	for _, rep := range f.Replace {
+		for i := range mods {
+			if mods[i].Path != rep.Old.Path || (rep.Old.Version != "" && rep.Old.Version != mods[i].Version) {
+				continue
+			}
+			mods[i].Replace = rep.New.Path
+			if rep.New.Version != "" {
+				mods[i].Replace += " " + rep.New.Version
+			}
+		}
+	}
+	sort.Slice(mods, func(i, j int) bool {
+		return mods[i].Path < mods[j].Path
+	})
+	return mods
+}
+
+func isBassModule(path string) bool {
+	return path == bassModulePath || strings.HasPrefix(path, bassModulePath+"/")
+}
+
+// findMigrations counts the SQL files at the top of fsys.
+// Migrations are applied in lexical order of their file names,
+// so the latest migration's version is the name of the last file
+// without its extension.
+func findMigrations(fsys fs.FS) (migrationInfo, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return migrationInfo{}, err
+	}
+	if len(names) == 0 {
+		// Report a missing directory to the caller.
+		if _, err := fs.Stat(fsys, "."); err != nil {
+			return migrationInfo{}, err
+		}
+		return migrationInfo{}, nil
+	}
+	// fs.Glob returns names in lexical order.
+	return migrationInfo{
+		Count:  len(names),
+		Latest: strings.TrimSuffix(names[len(names)-1], ".sql"),
+	}, nil
+}
+
+// findTemplates returns the paths of HTML files in the client directory,
+// skipping build output and installed packages.
+func findTemplates(fsys fs.FS) ([]string, error) {
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != "." && (d.Name() == "dist" || d.Name() == "node_modules" || strings.HasPrefix(d.Name(), ".")) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".html") {
+			names = append(names, path)
+		}
+		return nil
+	})
+	return names, err
+}
+
+// clientDependencies returns the version of each dependency
+// listed in the client's package.json.
+// Versions are read from node_modules if the dependency is installed.
+// Otherwise, the version range from package.json is returned.
+func clientDependencies(fsys fs.FS) (map[string]string, error) {
+	data, err := fs.ReadFile(fsys, "package.json")
+	if err != nil {
+		return nil, err
+	}
+	var manifest struct {
+		Dependencies    map[string]string
+		DevDependencies map[string]string
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("package.json: %w", err)
+	}
+	deps := make(map[string]string, len(manifest.Dependencies)+len(manifest.DevDependencies))
+	for _, m := range []map[string]string{manifest.Dependencies, manifest.DevDependencies} {
+		for name, versionRange := range m {
+			deps[name] = versionRange
+			data, err := fs.ReadFile(fsys, "node_modules/"+name+"/package.json")
+			if err != nil {
+				continue
+			}
+			var installed struct {
+				Version string
+			}
+			if json.Unmarshal(data, &installed) == nil && installed.Version != "" {
+				deps[name] = installed.Version
+			}
+		}
+	}
+	return deps, nil
+}
+
+// toolVersion returns the output of `tool --version`.
+func toolVersion(ctx context.Context, tool string) (string, error) {
+	result, err := sigterm.Output(ctx, exec.Command(tool, "--version"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(result.Stdout)), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/mod/modfile"
+)
+
+func TestBassRequirements(t *testing.T) {
+	const gomod = "module example.com/app\n" +
+		"\n" +
+		"go 1.18\n" +
+		"\n" +
+		"require (\n" +
+		"\tgithub.com/gorilla/mux v1.8.0\n" +
+		"\tzombiezen.com/go/bass v0.5.0\n" +
+		"\tzombiezen.com/go/bass/sigterm v0.1.0\n" +
+		"\tzombiezen.com/go/bassoon v1.0.0\n" +
+		")\n" +
+		"\n" +
+		"replace zombiezen.com/go/bass => ../bass\n"
+	f, err := modfile.Parse("go.mod", []byte(gomod), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := bassRequirements(f)
+	want := []moduleVersion{
+		{Path: "zombiezen.com/go/bass", Version: "v0.5.0", Replace: "../bass"},
+		{Path: "zombiezen.com/go/bass/sigterm", Version: "v0.1.0"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("bassRequirements(...) (-want +got):\n%s", diff)
+	}
+}
+
+func TestFindMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0001_init.sql":  {},
+		"0002_users.sql": {},
+		"0010_posts.sql": {},
+		"README.md":      {},
+	}
+	got, err := findMigrations(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := migrationInfo{Count: 3, Latest: "0010_posts"}
+	if got != want {
+		t.Errorf("findMigrations(...) = %+v; want %+v", got, want)
+	}
+
+	if _, err := findMigrations(fstest.MapFS{}); err != nil {
+		t.Errorf("findMigrations(empty) error: %v", err)
+	}
+}
+
+func TestFindMigrationsMissing(t *testing.T) {
+	_, err := findMigrations(missingFS{})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("findMigrations(missing) error = %v; want %v", err, fs.ErrNotExist)
+	}
+}
+
+type missingFS struct{}
+
+func (missingFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func TestFindTemplates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base.html":                   {},
+		"index.html":                  {},
+		"users/show.html":             {},
+		"app.ts":                      {},
+		"dist/app.html":               {},
+		"node_modules/foo/index.html": {},
+		".cache/page.html":            {},
+	}
+	got, err := findTemplates(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"base.html", "index.html", "users/show.html"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("findTemplates(...) (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientDependencies(t *testing.T) {
+	fsys := fstest.MapFS{
+		"package.json": {Data: []byte(`{
+			"dependencies": {"esbuild": "^0.9.2", "stimulus": "^2.0.0"},
+			"devDependencies": {"typescript": "^4.1.3"}
+		}`)},
+		"node_modules/esbuild/package.json": {Data: []byte(`{"name": "esbuild", "version": "0.9.7"}`)},
+	}
+	got, err := clientDependencies(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"esbuild":    "0.9.7",
+		"stimulus":   "^2.0.0",
+		"typescript": "^4.1.3",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("clientDependencies(...) (-want +got):\n%s", diff)
+	}
+}
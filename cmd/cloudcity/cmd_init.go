@@ -94,7 +94,7 @@ func (cmd *initCmd) run(ctx context.Context) (err error) {
 	modInitCmd.Dir = dir
 	modInitCmd.Stdout = os.Stderr
 	modInitCmd.Stderr = os.Stderr
-	if err := sigterm.Run(ctx, modInitCmd); err != nil {
+	if _, err := sigterm.Output(ctx, modInitCmd); err != nil {
 		return fmt.Errorf("go mod init: %w", err)
 	}
 
@@ -191,15 +191,15 @@ func (cmd *initCmd) run(ctx context.Context) (err error) {
 	getCmd.Dir = dir
 	getCmd.Stdout = os.Stderr
 	getCmd.Stderr = os.Stderr
-	if err := sigterm.Run(ctx, getCmd); err != nil {
-		return err
+	if _, err := sigterm.Output(ctx, getCmd); err != nil {
+		return fmt.Errorf("go get: %w", err)
 	}
 	tidyCmd := exec.Command("go", "mod", "tidy")
 	tidyCmd.Dir = dir
 	tidyCmd.Stdout = os.Stderr
 	tidyCmd.Stderr = os.Stderr
-	if err := sigterm.Run(ctx, tidyCmd); err != nil {
-		return err
+	if _, err := sigterm.Output(ctx, tidyCmd); err != nil {
+		return fmt.Errorf("go mod tidy: %w", err)
 	}
 
 	// Install JavaScript dependencies and build.
@@ -216,17 +216,16 @@ func (cmd *initCmd) run(ctx context.Context) (err error) {
 func readModulePath(ctx context.Context, dir string) (string, error) {
 	listCmd := exec.Command("go", "list", "-m", "-json")
 	listCmd.Dir = dir
-	out := new(bytes.Buffer)
-	listCmd.Stdout = out
 	listCmd.Stderr = os.Stderr
 
-	if err := sigterm.Run(ctx, listCmd); err != nil {
+	result, err := sigterm.Output(ctx, listCmd)
+	if err != nil {
 		return "", fmt.Errorf("read module path: go list: %w", err)
 	}
 	var module struct {
 		Path string
 	}
-	if err := json.Unmarshal(out.Bytes(), &module); err != nil {
+	if err := json.Unmarshal(result.Stdout, &module); err != nil {
 		return "", fmt.Errorf("read module path: parse go list output: %w", err)
 	}
 	return module.Path, nil
@@ -0,0 +1,572 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+const gorillaMuxImportPath = "github.com/gorilla/mux"
+
+// serveMuxGoVersion is the minimum go directive in go.mod
+// that enables method and wildcard patterns in [net/http.ServeMux].
+const serveMuxGoVersion = "1.22"
+
+type migrateRouterCmd struct {
+	dryRun bool
+}
+
+func newMigrateRouterCmd() *cobra.Command {
+	cmd := new(migrateRouterCmd)
+	c := &cobra.Command{
+		Use:   "router [options]",
+		Short: "Migrate routes from gorilla/mux to net/http.ServeMux",
+		Long: "Rewrite route registrations on a gorilla/mux router in the package\n" +
+			"in the current directory to use net/http.ServeMux patterns\n" +
+			"(Go 1.22 or later), with methods and path wildcards in the pattern.\n\n" +
+			"Registrations that cannot be converted automatically\n" +
+			"(like middleware or regular expressions in path variables)\n" +
+			"are left alone or reported as warnings. Review the changes before committing.",
+		Args: cobra.NoArgs,
+		RunE: func(cc *cobra.Command, args []string) error {
+			return cmd.run(cc.Context())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().BoolVarP(&cmd.dryRun, "dry-run", "n", false, "report changes without writing files")
+	return c
+}
+
+func (cmd *migrateRouterCmd) run(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("migrate router: %w", err)
+		}
+	}()
+	root, err := findGoModuleDir(ctx, ".")
+	if err != nil {
+		return err
+	}
+	pkg, err := loadRouterPackage(ctx, packages.NeedFiles|packages.NeedSyntax|packages.NeedTypes|packages.NeedTypesInfo)
+	if err != nil {
+		return err
+	}
+	m := &routerMigration{
+		pkg:      pkg,
+		modified: make(map[*ast.File]bool),
+	}
+	m.rewrite()
+	for _, w := range m.warnings {
+		fmt.Fprintf(os.Stderr, "%v: warning: %s\n", w.pos, w.msg)
+	}
+
+	// Format and write modified files.
+	// packageImportsMux records whether each file in the package
+	// imports gorilla/mux after the migration
+	// so that go.mod can be updated without writing files in a dry run.
+	packageImportsMux := make(map[string]bool)
+	for _, f := range pkg.Syntax {
+		filename := pkg.Fset.File(f.Pos()).Name()
+		if m.modified[f] {
+			fixRouterImports(pkg.Fset, f)
+			buf := new(bytes.Buffer)
+			if err := format.Node(buf, pkg.Fset, f); err != nil {
+				return fmt.Errorf("%s: %w", filename, err)
+			}
+			if !cmd.dryRun {
+				if err := os.WriteFile(filename, buf.Bytes(), 0o666); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(os.Stderr, "cloudcity: updated %s\n", displayPath(root, filename))
+		}
+		packageImportsMux[filename] = importsPath(f, gorillaMuxImportPath)
+	}
+	if len(m.modified) == 0 {
+		fmt.Fprintln(os.Stderr, "cloudcity: no gorilla/mux routes found")
+		return nil
+	}
+
+	gomodPath := filepath.Join(root, "go.mod")
+	gomod, err := os.ReadFile(gomodPath)
+	if err != nil {
+		return err
+	}
+	stillUsed, err := moduleImportsPath(root, gorillaMuxImportPath, packageImportsMux)
+	if err != nil {
+		return err
+	}
+	newGomod, err := migrateGoMod(gomodPath, gomod, !stillUsed)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(gomod, newGomod) {
+		if !cmd.dryRun {
+			if err := os.WriteFile(gomodPath, newGomod, 0o666); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(os.Stderr, "cloudcity: updated %s\n", displayPath(root, gomodPath))
+	}
+	return nil
+}
+
+// routerMigration holds the state of rewriting a package
+// from gorilla/mux to net/http.ServeMux.
+type routerMigration struct {
+	pkg      *packages.Package
+	modified map[*ast.File]bool
+	warnings []migrationWarning
+}
+
+type migrationWarning struct {
+	pos token.Position
+	msg string
+}
+
+func (m *routerMigration) warnf(pos token.Pos, format string, args ...interface{}) {
+	m.warnings = append(m.warnings, migrationWarning{
+		pos: m.pkg.Fset.Position(pos),
+		msg: fmt.Sprintf(format, args...),
+	})
+}
+
+// muxRegistration is a route registered on a gorilla/mux router
+// by a chain of method calls like
+// router.HandleFunc(path, f).Methods(http.MethodGet).
+type muxRegistration struct {
+	router   ast.Expr
+	funcName string // "Handle" or "HandleFunc"
+	path     ast.Expr
+	prefix   bool
+	handler  ast.Expr
+	methods  []string
+}
+
+// rewrite rewrites the syntax trees of m.pkg in place.
+func (m *routerMigration) rewrite() {
+	info := m.pkg.TypesInfo
+	for _, f := range m.pkg.Syntax {
+		astutil.Apply(f, func(c *astutil.Cursor) bool {
+			switch node := c.Node().(type) {
+			case *ast.ExprStmt:
+				call, ok := node.X.(*ast.CallExpr)
+				if !ok || !m.isMuxMethodCall(call) {
+					return true
+				}
+				if _, obj := resolveName(info, call.Fun); obj.Name() == "ServeHTTP" {
+					// Also a method of net/http.ServeMux.
+					return true
+				}
+				reg, err := m.parseRegistration(call)
+				if err != nil {
+					m.warnf(call.Pos(), "%v; leaving as-is", err)
+					return false
+				}
+				stmts, ok := m.convertRegistration(reg)
+				if !ok {
+					return false
+				}
+				if len(stmts) > 1 && c.Index() < 0 {
+					m.warnf(call.Pos(), "route needs one registration per method, but is not in a statement list; leaving as-is")
+					return false
+				}
+				c.Replace(stmts[0])
+				for i := len(stmts) - 1; i > 0; i-- {
+					c.InsertAfter(stmts[i])
+				}
+				m.modified[f] = true
+				return false
+			case *ast.CallExpr:
+				if isGorillaMuxFunc(info, node.Fun, "NewRouter") || m.isMuxMethodCall(node) && isGorillaMuxFunc(info, innermostCall(node).Fun, "NewRouter") {
+					c.Replace(&ast.CallExpr{
+						Fun: &ast.SelectorExpr{
+							X:   ast.NewIdent(httpImportName(m.pkg.Fset, f)),
+							Sel: ast.NewIdent("NewServeMux"),
+						},
+						Lparen: node.Pos(),
+					})
+					m.modified[f] = true
+					return false
+				}
+				if isGorillaMuxFunc(info, node.Fun, "Vars") {
+					m.warnf(node.Pos(), "mux.Vars has no net/http equivalent; use r.PathValue(name) for each path variable")
+				}
+				return true
+			case *ast.SelectorExpr:
+				if obj, ok := info.Uses[node.Sel].(*types.TypeName); ok && obj.Pkg() != nil &&
+					obj.Pkg().Path() == gorillaMuxImportPath && obj.Name() == "Router" {
+					c.Replace(&ast.SelectorExpr{
+						X:   &ast.Ident{NamePos: node.Pos(), Name: httpImportName(m.pkg.Fset, f)},
+						Sel: ast.NewIdent("ServeMux"),
+					})
+					m.modified[f] = true
+				}
+				return true
+			default:
+				return true
+			}
+		}, nil)
+	}
+}
+
+// isMuxMethodCall reports whether call is a method call
+// on a gorilla/mux Router or Route.
+func (m *routerMigration) isMuxMethodCall(call *ast.CallExpr) bool {
+	recvType, obj := resolveName(m.pkg.TypesInfo, call.Fun)
+	if recvType == nil || obj == nil {
+		return false
+	}
+	pkgPath, name := typeName(recvType)
+	return pkgPath == gorillaMuxImportPath && (name == "Router" || name == "Route")
+}
+
+// innermostCall returns the first call in a chain of method calls.
+func innermostCall(call *ast.CallExpr) *ast.CallExpr {
+	for {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return call
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return call
+		}
+		call = inner
+	}
+}
+
+func isGorillaMuxFunc(info *types.Info, expr ast.Expr, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	obj, ok := info.Uses[sel.Sel].(*types.Func)
+	return ok && obj.Pkg() != nil && obj.Pkg().Path() == gorillaMuxImportPath && obj.Name() == name
+}
+
+// parseRegistration parses a chain of gorilla/mux method calls
+// that registers a route.
+func (m *routerMigration) parseRegistration(call *ast.CallExpr) (*muxRegistration, error) {
+	reg := new(muxRegistration)
+	for {
+		_, obj := resolveName(m.pkg.TypesInfo, call.Fun)
+		sel := call.Fun.(*ast.SelectorExpr)
+		switch name := obj.Name(); name {
+		case "Methods":
+			methods := make([]string, 0, len(call.Args))
+			for _, arg := range call.Args {
+				v := m.pkg.TypesInfo.Types[resolveExpr(m.pkg, arg)].Value
+				if v == nil || v.Kind() != constant.String {
+					return nil, fmt.Errorf("method %s is not a constant", formatExpr(arg))
+				}
+				methods = append(methods, strings.ToUpper(constant.StringVal(v)))
+			}
+			reg.methods = append(methods, reg.methods...)
+		case "Handler", "HandlerFunc":
+			if len(call.Args) != 1 {
+				return nil, fmt.Errorf("unexpected arguments to %s", name)
+			}
+			// Route.Handler corresponds to ServeMux.Handle
+			// and Route.HandlerFunc to ServeMux.HandleFunc.
+			reg.funcName = "Handle" + strings.TrimPrefix(name, "Handler")
+			reg.handler = call.Args[0]
+		case "Path", "PathPrefix":
+			if len(call.Args) != 1 {
+				return nil, fmt.Errorf("unexpected arguments to %s", name)
+			}
+			reg.path = call.Args[0]
+			reg.prefix = name == "PathPrefix"
+			if reg.handler == nil {
+				return nil, fmt.Errorf("%s without a handler", name)
+			}
+			reg.router = sel.X
+			return reg, nil
+		case "Handle", "HandleFunc":
+			if len(call.Args) != 2 {
+				return nil, fmt.Errorf("unexpected arguments to %s", name)
+			}
+			reg.funcName = name
+			reg.path = call.Args[0]
+			reg.handler = call.Args[1]
+			reg.router = sel.X
+			return reg, nil
+		default:
+			return nil, fmt.Errorf("gorilla/mux %s is not supported by net/http.ServeMux", name)
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok || !m.isMuxMethodCall(inner) {
+			return nil, fmt.Errorf("gorilla/mux %s must follow a route registration", obj.Name())
+		}
+		call = inner
+	}
+}
+
+// convertRegistration returns the statements that register reg
+// on a net/http.ServeMux.
+func (m *routerMigration) convertRegistration(reg *muxRegistration) ([]ast.Stmt, bool) {
+	pathValue := m.pkg.TypesInfo.Types[resolveExpr(m.pkg, reg.path)].Value
+	if pathValue == nil || pathValue.Kind() != constant.String {
+		m.warnf(reg.path.Pos(), "path %s is not a constant; leaving as-is", formatExpr(reg.path))
+		return nil, false
+	}
+	pattern, warnings, err := convertMuxPath(constant.StringVal(pathValue), reg.prefix)
+	if err != nil {
+		m.warnf(reg.path.Pos(), "%v; leaving as-is", err)
+		return nil, false
+	}
+	for _, w := range warnings {
+		m.warnf(reg.path.Pos(), "%s", w)
+	}
+	methods := methodPatterns(reg.methods)
+	if len(methods) > 1 {
+		if _, isIdent := reg.handler.(*ast.Ident); !isIdent {
+			if _, isSel := reg.handler.(*ast.SelectorExpr); !isSel {
+				m.warnf(reg.handler.Pos(), "handler is registered once per method, so %s is evaluated %d times", formatExpr(reg.handler), len(methods))
+			}
+		}
+	}
+	stmts := make([]ast.Stmt, 0, len(methods))
+	for _, method := range methods {
+		p := pattern
+		if method != "" {
+			p = method + " " + pattern
+		}
+		stmts = append(stmts, &ast.ExprStmt{X: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{
+				X:   reg.router,
+				Sel: ast.NewIdent(reg.funcName),
+			},
+			Args: []ast.Expr{
+				&ast.BasicLit{ValuePos: reg.path.Pos(), Kind: token.STRING, Value: strconv.Quote(p)},
+				reg.handler,
+			},
+		}})
+	}
+	return stmts, true
+}
+
+// convertMuxPath converts a gorilla/mux path template
+// to a net/http.ServeMux pattern (without a method).
+// If prefix is true, the path is from a PathPrefix route.
+func convertMuxPath(path string, prefix bool) (pattern string, warnings []string, err error) {
+	if !strings.HasPrefix(path, "/") {
+		return "", nil, fmt.Errorf("path %q does not start with a slash", path)
+	}
+	sb := new(strings.Builder)
+	for i := 0; i < len(path); {
+		if path[i] != '{' {
+			if path[i] == '}' {
+				return "", nil, fmt.Errorf("path %q has unbalanced braces", path)
+			}
+			sb.WriteByte(path[i])
+			i++
+			continue
+		}
+		end := strings.IndexByte(path[i:], '}')
+		if end == -1 {
+			return "", nil, fmt.Errorf("path %q has unbalanced braces", path)
+		}
+		end += i
+		if path[i-1] != '/' || end+1 < len(path) && path[end+1] != '/' {
+			return "", nil, fmt.Errorf("path %q has a variable that is not a full path segment", path)
+		}
+		name, re, hasRE := strings.Cut(path[i+1:end], ":")
+		isLast := end+1 == len(path)
+		switch {
+		case !hasRE:
+			sb.WriteString("{" + name + "}")
+		case isLast && !prefix && (re == ".*" || re == ".+"):
+			sb.WriteString("{" + name + "...}")
+		default:
+			sb.WriteString("{" + name + "}")
+			warnings = append(warnings, fmt.Sprintf("pattern %q for variable %s dropped; validate it in the handler", re, name))
+		}
+		i = end + 1
+	}
+	pattern = sb.String()
+	switch {
+	case prefix && !strings.HasSuffix(pattern, "/"):
+		warnings = append(warnings, fmt.Sprintf("path prefix %q now only matches %q and paths below it", path, pattern+"/"))
+		pattern += "/"
+	case !prefix && strings.HasSuffix(pattern, "/"):
+		// A trailing slash in a ServeMux pattern matches the whole subtree.
+		pattern += "{$}"
+	}
+	return pattern, warnings, nil
+}
+
+// methodPatterns returns the methods to put in ServeMux patterns
+// for a route that is restricted to the given methods.
+// A ServeMux pattern for GET also matches HEAD requests,
+// so HEAD is dropped if GET is present.
+// If methods is empty, methodPatterns returns a single empty string
+// to represent a pattern without a method.
+func methodPatterns(methods []string) []string {
+	if len(methods) == 0 {
+		return []string{""}
+	}
+	hasGet := false
+	for _, method := range methods {
+		if method == "GET" {
+			hasGet = true
+			break
+		}
+	}
+	var result []string
+	seen := make(map[string]bool)
+	for _, method := range methods {
+		if seen[method] || hasGet && method == "HEAD" {
+			continue
+		}
+		seen[method] = true
+		result = append(result, method)
+	}
+	return result
+}
+
+// httpImportName returns the name that f uses for the net/http package,
+// adding an import if necessary.
+func httpImportName(fset *token.FileSet, f *ast.File) string {
+	for _, imp := range f.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == "net/http" {
+			if imp.Name != nil {
+				return imp.Name.Name
+			}
+			return "http"
+		}
+	}
+	astutil.AddImport(fset, f, "net/http")
+	return "http"
+}
+
+// fixRouterImports removes the gorilla/mux import from f if it is no longer used.
+func fixRouterImports(fset *token.FileSet, f *ast.File) {
+	if !astutil.UsesImport(f, gorillaMuxImportPath) {
+		astutil.DeleteImport(fset, f, gorillaMuxImportPath)
+	}
+}
+
+func importsPath(f *ast.File, path string) bool {
+	for _, imp := range f.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleImportsPath reports whether any Go file in the module rooted at root
+// imports the given package.
+// overrides records whether files (by absolute path) import the package,
+// taking precedence over the files' contents on disk.
+func moduleImportsPath(root string, path string, overrides map[string]bool) (bool, error) {
+	fset := token.NewFileSet()
+	found := false
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if p != root && (name == "vendor" || name == "testdata" || name == "node_modules" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") {
+			return nil
+		}
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return err
+		}
+		if imports, ok := overrides[abs]; ok {
+			found = found || imports
+			return nil
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.ImportsOnly)
+		if err != nil {
+			// Files that don't parse are left for the compiler to report.
+			return nil
+		}
+		found = found || importsPath(f, path)
+		return nil
+	})
+	return found, err
+}
+
+// migrateGoMod returns the go.mod file with the go directive
+// raised to at least serveMuxGoVersion
+// and, if dropMux is true, without a requirement on gorilla/mux.
+func migrateGoMod(filename string, gomod []byte, dropMux bool) ([]byte, error) {
+	f, err := modfile.Parse(filename, gomod, nil)
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+	if f.Go == nil || semver.Compare("v"+f.Go.Version, "v"+serveMuxGoVersion) < 0 {
+		if err := f.AddGoStmt(serveMuxGoVersion); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if dropMux {
+		for _, r := range f.Require {
+			if r.Mod.Path == gorillaMuxImportPath {
+				if err := f.DropRequire(gorillaMuxImportPath); err != nil {
+					return nil, err
+				}
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		return gomod, nil
+	}
+	f.Cleanup()
+	return f.Format()
+}
+
+// displayPath returns path relative to root if possible.
+func displayPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
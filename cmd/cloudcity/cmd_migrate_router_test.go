@@ -0,0 +1,343 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestConvertMuxPath(t *testing.T) {
+	tests := []struct {
+		path         string
+		prefix       bool
+		want         string
+		wantWarnings int
+		wantErr      bool
+	}{
+		{path: "/", want: "/{$}"},
+		{path: "/healthz", want: "/healthz"},
+		{path: "/posts/", want: "/posts/{$}"},
+		{path: "/posts/{id}", want: "/posts/{id}"},
+		{path: "/posts/{id}/edit", want: "/posts/{id}/edit"},
+		{path: "/posts/{id:[0-9]+}", want: "/posts/{id}", wantWarnings: 1},
+		{path: "/files/{rest:.*}", want: "/files/{rest...}"},
+		{path: "/files/{name}.txt", wantErr: true},
+		{path: "/files/{name", wantErr: true},
+		{path: "files", wantErr: true},
+		{path: "/client/", prefix: true, want: "/client/"},
+		{path: "/client", prefix: true, want: "/client/", wantWarnings: 1},
+	}
+	for _, test := range tests {
+		got, warnings, err := convertMuxPath(test.path, test.prefix)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("convertMuxPath(%q, %t): %v", test.path, test.prefix, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("convertMuxPath(%q, %t) = %q, <nil>; want error", test.path, test.prefix, got)
+			continue
+		}
+		if got != test.want || len(warnings) != test.wantWarnings {
+			t.Errorf("convertMuxPath(%q, %t) = %q, %q; want %q with %d warnings",
+				test.path, test.prefix, got, warnings, test.want, test.wantWarnings)
+		}
+	}
+}
+
+func TestMethodPatterns(t *testing.T) {
+	tests := []struct {
+		methods []string
+		want    []string
+	}{
+		{methods: nil, want: []string{""}},
+		{methods: []string{"GET"}, want: []string{"GET"}},
+		{methods: []string{"GET", "HEAD"}, want: []string{"GET"}},
+		{methods: []string{"HEAD"}, want: []string{"HEAD"}},
+		{methods: []string{"POST", "GET", "POST"}, want: []string{"POST", "GET"}},
+	}
+	for _, test := range tests {
+		if got := methodPatterns(test.methods); !cmp.Equal(got, test.want) {
+			t.Errorf("methodPatterns(%q) = %q; want %q", test.methods, got, test.want)
+		}
+	}
+}
+
+// fakeMuxSource declares the subset of github.com/gorilla/mux
+// used by TestMigrateRouter.
+const fakeMuxSource = `package mux
+
+import "net/http"
+
+type Router struct{}
+
+type Route struct{}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+func NewRouter() *Router { return nil }
+
+func Vars(r *http.Request) map[string]string { return nil }
+
+func (r *Router) StrictSlash(bool) *Router { return r }
+
+func (r *Router) Handle(string, http.Handler) *Route { return nil }
+
+func (r *Router) HandleFunc(string, func(http.ResponseWriter, *http.Request)) *Route { return nil }
+
+func (r *Router) Path(string) *Route { return nil }
+
+func (r *Router) PathPrefix(string) *Route { return nil }
+
+func (r *Router) Use(...MiddlewareFunc) {}
+
+func (r *Router) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+func (r *Route) Methods(...string) *Route { return r }
+
+func (r *Route) Handler(http.Handler) *Route { return r }
+
+func (r *Route) HandlerFunc(func(http.ResponseWriter, *http.Request)) *Route { return r }
+`
+
+const migrateRouterInput = `package main
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+type application struct {
+	routerOnce sync.Once
+	router     *mux.Router
+}
+
+func (app *application) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	app.routerOnce.Do(app.initRouter)
+	app.router.ServeHTTP(w, r)
+}
+
+const postPath = "/posts/{id:[0-9]+}"
+
+func (app *application) initRouter() {
+	app.router = mux.NewRouter().StrictSlash(true)
+	app.router.HandleFunc("/healthz", healthz).Methods(http.MethodGet, http.MethodHead)
+	app.router.Handle(postPath, http.NotFoundHandler()).Methods("GET", "DELETE")
+	app.router.Handle("/", http.NotFoundHandler())
+	app.router.PathPrefix("/client/").Handler(http.StripPrefix("/client", http.NotFoundHandler()))
+	app.router.Path("/files/{rest:.*}").HandlerFunc(healthz)
+	app.router.Use(func(h http.Handler) http.Handler { return h })
+}
+
+func healthz(w http.ResponseWriter, r *http.Request) {}
+
+func pathVars(r *http.Request) map[string]string {
+	return mux.Vars(r)
+}
+`
+
+const migrateRouterWant = `package main
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+type application struct {
+	routerOnce sync.Once
+	router     *http.ServeMux
+}
+
+func (app *application) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	app.routerOnce.Do(app.initRouter)
+	app.router.ServeHTTP(w, r)
+}
+
+const postPath = "/posts/{id:[0-9]+}"
+
+func (app *application) initRouter() {
+	app.router = http.NewServeMux()
+	app.router.HandleFunc("GET /healthz", healthz)
+	app.router.Handle("GET /posts/{id}", http.NotFoundHandler())
+	app.router.Handle("DELETE /posts/{id}", http.NotFoundHandler())
+	app.router.Handle("/{$}", http.NotFoundHandler())
+	app.router.Handle("/client/", http.StripPrefix("/client", http.NotFoundHandler()))
+	app.router.HandleFunc("/files/{rest...}", healthz)
+	app.router.Use(func(h http.Handler) http.Handler { return h })
+}
+
+func healthz(w http.ResponseWriter, r *http.Request) {}
+
+func pathVars(r *http.Request) map[string]string {
+	return mux.Vars(r)
+}
+`
+
+func TestMigrateRouter(t *testing.T) {
+	fset := token.NewFileSet()
+	muxFile, err := parser.ParseFile(fset, "mux.go", fakeMuxSource, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdImporter := importer.Default()
+	muxPkg, err := (&types.Config{Importer: stdImporter}).Check(gorillaMuxImportPath, fset, []*ast.File{muxFile}, nil)
+	if err != nil {
+		t.Skip("Could not type-check stub gorilla/mux package:", err)
+	}
+	mainFile, err := parser.ParseFile(fset, "main.go", migrateRouterInput, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	typesConfig := &types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if path == gorillaMuxImportPath {
+				return muxPkg, nil
+			}
+			return stdImporter.Import(path)
+		}),
+	}
+	mainPkg, err := typesConfig.Check("example.com/app", fset, []*ast.File{mainFile}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &routerMigration{
+		pkg: &packages.Package{
+			Fset:      fset,
+			Syntax:    []*ast.File{mainFile},
+			Types:     mainPkg,
+			TypesInfo: info,
+		},
+		modified: make(map[*ast.File]bool),
+	}
+	m.rewrite()
+	if !m.modified[mainFile] {
+		t.Error("main.go not marked as modified")
+	}
+	fixRouterImports(fset, mainFile)
+	buf := new(bytes.Buffer)
+	if err := format.Node(buf, fset, mainFile); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(migrateRouterWant, buf.String()); diff != "" {
+		t.Errorf("migrated source (-want +got):\n%s", diff)
+	}
+
+	var warnings []string
+	for _, w := range m.warnings {
+		warnings = append(warnings, w.msg)
+	}
+	for _, want := range []string{"[0-9]+", "Use", "mux.Vars"} {
+		found := false
+		for _, w := range warnings {
+			if strings.Contains(w, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("no warning mentions %q; warnings:\n%s", want, strings.Join(warnings, "\n"))
+		}
+	}
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+func TestMigrateGoMod(t *testing.T) {
+	const input = "module example.com/app\n" +
+		"\n" +
+		"go 1.18\n" +
+		"\n" +
+		"require (\n" +
+		"\tgithub.com/gorilla/mux v1.8.0\n" +
+		"\tzombiezen.com/go/bass v0.1.0\n" +
+		")\n"
+	tests := []struct {
+		name    string
+		dropMux bool
+		want    string
+	}{
+		{
+			name: "KeepMux",
+			want: "module example.com/app\n" +
+				"\n" +
+				"go 1.22\n" +
+				"\n" +
+				"require (\n" +
+				"\tgithub.com/gorilla/mux v1.8.0\n" +
+				"\tzombiezen.com/go/bass v0.1.0\n" +
+				")\n",
+		},
+		{
+			name:    "DropMux",
+			dropMux: true,
+			want: "module example.com/app\n" +
+				"\n" +
+				"go 1.22\n" +
+				"\n" +
+				"require zombiezen.com/go/bass v0.1.0\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := migrateGoMod("go.mod", []byte(input), test.dropMux)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, string(got)); diff != "" {
+				t.Errorf("go.mod (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("AlreadyNewer", func(t *testing.T) {
+		const input = "module example.com/app\n\ngo 1.23\n"
+		got, err := migrateGoMod("go.mod", []byte(input), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != input {
+			t.Errorf("go.mod = %q; want unchanged %q", got, input)
+		}
+	})
+}
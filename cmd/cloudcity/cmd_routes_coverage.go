@@ -0,0 +1,328 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/packages"
+	"zombiezen.com/go/bass/sigterm"
+)
+
+type routesCoverageCmd struct {
+	coverProfile string
+	json         bool
+	uncovered    bool
+}
+
+func newRoutesCoverageCmd() *cobra.Command {
+	cmd := new(routesCoverageCmd)
+	c := &cobra.Command{
+		Use:   "coverage [options]",
+		Short: "Report test coverage of route handlers",
+		Args:  cobra.NoArgs,
+		RunE: func(cc *cobra.Command, args []string) error {
+			return cmd.run(cc.Context())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().StringVar(&cmd.coverProfile, "coverprofile", "", "read coverage from an existing `file` instead of running go test")
+	c.Flags().BoolVar(&cmd.json, "json", false, "show output in JSON format")
+	c.Flags().BoolVar(&cmd.uncovered, "uncovered", false, "only show routes whose handlers have no test coverage")
+	return c
+}
+
+type routeCoverage struct {
+	route
+	Handler    string `json:"handler,omitempty"`
+	Statements int    `json:"statements"`
+	Covered    int    `json:"covered"`
+}
+
+func (rc *routeCoverage) percent() string {
+	if rc.Handler == "" {
+		return "?"
+	}
+	if rc.Statements == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(100*float64(rc.Covered)/float64(rc.Statements), 'f', 1, 64) + "%"
+}
+
+func (cmd *routesCoverageCmd) run(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("routes coverage: %w", err)
+		}
+	}()
+	pkg, err := loadRouterPackage(ctx, packages.NeedName|packages.NeedSyntax|packages.NeedTypes|packages.NeedTypesInfo)
+	if err != nil {
+		return err
+	}
+	routes, err := findRoutes(pkg)
+	if err != nil {
+		return err
+	}
+
+	profilePath := cmd.coverProfile
+	if profilePath == "" {
+		dir, err := os.MkdirTemp("", "cloudcity-coverage")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		profilePath = filepath.Join(dir, "cover.out")
+		testCmd := exec.Command("go", "test", "-coverprofile="+profilePath, ".")
+		testCmd.Stdout = os.Stderr
+		testCmd.Stderr = os.Stderr
+		fmt.Fprintf(os.Stderr, "## go test -coverprofile=%s . ##\n", profilePath)
+		if err := sigterm.Run(ctx, testCmd); err != nil {
+			// Failing tests still produce a useful profile.
+			fmt.Fprintf(os.Stderr, "cloudcity: go test: %v\n", err)
+		}
+	}
+	f, err := os.Open(profilePath)
+	if err != nil {
+		return err
+	}
+	blocks, err := parseCoverProfile(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", profilePath, err)
+	}
+
+	var results []*routeCoverage
+	for _, r := range routes {
+		rc := &routeCoverage{route: r}
+		if decl := findHandlerFunc(pkg, r.handler); decl != nil {
+			rc.Handler = funcDeclName(decl)
+			start := pkg.Fset.Position(decl.Pos())
+			end := pkg.Fset.Position(decl.End())
+			profileName := pkg.PkgPath + "/" + filepath.Base(start.Filename)
+			rc.Statements, rc.Covered = blocks.coverage(profileName, start.Line, end.Line)
+		}
+		if cmd.uncovered && (rc.Handler == "" || rc.Covered > 0) {
+			continue
+		}
+		results = append(results, rc)
+	}
+	if cmd.json {
+		return printJSONList(results)
+	}
+	for _, rc := range results {
+		handler := rc.Handler
+		if handler == "" {
+			handler = rc.Expr
+		}
+		fmt.Printf("%-7s %-20s %6s %s\n", rc.Method, rc.Path, rc.percent(), handler)
+	}
+	return nil
+}
+
+// findHandlerFunc returns the declaration of the package-level function or method
+// that handles a route.
+// Function values referenced by the handler expression (like app.index in
+// app.newHTMLHandler(app.index)) take precedence over functions that are called.
+func findHandlerFunc(pkg *packages.Package, expr ast.Expr) *ast.FuncDecl {
+	if expr == nil {
+		return nil
+	}
+	var called, values []*types.Func
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if fn := funcObject(pkg, n.Fun); fn != nil {
+				called = append(called, fn)
+			} else {
+				ast.Inspect(n.Fun, visit)
+			}
+			for _, arg := range n.Args {
+				ast.Inspect(arg, visit)
+			}
+			return false
+		case *ast.Ident, *ast.SelectorExpr:
+			if fn := funcObject(pkg, n.(ast.Expr)); fn != nil {
+				values = append(values, fn)
+				return false
+			}
+		}
+		return true
+	}
+	ast.Inspect(expr, visit)
+
+	var fn *types.Func
+	switch {
+	case len(values) > 0:
+		fn = values[0]
+	case len(called) > 0:
+		fn = called[len(called)-1]
+	default:
+		return nil
+	}
+	for _, f := range pkg.Syntax {
+		for _, decl := range f.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && pkg.TypesInfo.Defs[funcDecl.Name] == fn {
+				return funcDecl
+			}
+		}
+	}
+	return nil
+}
+
+// funcObject returns the function or method that expr refers to
+// if it is declared in pkg.
+func funcObject(pkg *packages.Package, expr ast.Expr) *types.Func {
+	var id *ast.Ident
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		id = expr
+	case *ast.SelectorExpr:
+		id = expr.Sel
+	default:
+		return nil
+	}
+	fn, ok := pkg.TypesInfo.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() != pkg.Types {
+		return nil
+	}
+	return fn
+}
+
+func funcDeclName(decl *ast.FuncDecl) string {
+	if recv := astReceiverTypeName(decl); recv != "" {
+		return "(*" + recv + ")." + decl.Name.Name
+	}
+	return decl.Name.Name
+}
+
+// A coverBlock is a single line of a coverage profile
+// as written by go test -coverprofile.
+type coverBlock struct {
+	filename  string
+	pos       string
+	startLine int
+	endLine   int
+	numStmt   int
+	count     int
+}
+
+type coverProfile []coverBlock
+
+// parseCoverProfile parses the output of go test -coverprofile.
+func parseCoverProfile(r io.Reader) (coverProfile, error) {
+	var blocks coverProfile
+	s := bufio.NewScanner(r)
+	lineno := 0
+	for s.Scan() {
+		lineno++
+		line := s.Text()
+		if lineno == 1 {
+			if !strings.HasPrefix(line, "mode:") {
+				return nil, fmt.Errorf("line 1: missing mode")
+			}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		// Format is name.go:line.column,line.column numberOfStatements count
+		colon := strings.LastIndex(line, ":")
+		if colon == -1 {
+			return nil, fmt.Errorf("line %d: missing filename", lineno)
+		}
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 fields, found %d", lineno, len(fields))
+		}
+		start, end, ok := strings.Cut(fields[0], ",")
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid block %q", lineno, fields[0])
+		}
+		b := coverBlock{filename: line[:colon], pos: fields[0]}
+		var err error
+		if b.startLine, err = parseCoverPosLine(start); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineno, err)
+		}
+		if b.endLine, err = parseCoverPosLine(end); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineno, err)
+		}
+		if b.numStmt, err = strconv.Atoi(fields[1]); err != nil {
+			return nil, fmt.Errorf("line %d: statement count: %w", lineno, err)
+		}
+		if b.count, err = strconv.Atoi(fields[2]); err != nil {
+			return nil, fmt.Errorf("line %d: count: %w", lineno, err)
+		}
+		blocks = append(blocks, b)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if lineno == 0 {
+		return nil, fmt.Errorf("empty profile")
+	}
+	return blocks, nil
+}
+
+func parseCoverPosLine(pos string) (int, error) {
+	line, _, _ := strings.Cut(pos, ".")
+	n, err := strconv.Atoi(line)
+	if err != nil {
+		return 0, fmt.Errorf("invalid position %q", pos)
+	}
+	return n, nil
+}
+
+// coverage returns the number of statements and covered statements
+// in the given file between the given lines, inclusive.
+// Profiles may contain the same block multiple times
+// (e.g. when merged from several test runs),
+// so blocks are deduplicated.
+func (p coverProfile) coverage(filename string, startLine, endLine int) (statements, covered int) {
+	seen := make(map[string]bool)
+	for _, b := range p {
+		if b.filename != filename || b.startLine < startLine || b.endLine > endLine {
+			continue
+		}
+		k := b.pos
+		hit := b.count > 0
+		if prevHit, dupe := seen[k]; dupe {
+			if hit && !prevHit {
+				covered += b.numStmt
+				seen[k] = true
+			}
+			continue
+		}
+		seen[k] = hit
+		statements += b.numStmt
+		if hit {
+			covered += b.numStmt
+		}
+	}
+	return statements, covered
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCoverProfile(t *testing.T) {
+	const profile = "mode: set\n" +
+		"example.com/foo/routes.go:10.40,12.2 2 1\n" +
+		"example.com/foo/routes.go:12.2,14.3 1 0\n" +
+		"example.com/foo/routes.go:20.40,25.2 4 0\n" +
+		"example.com/foo/index.go:10.40,12.2 3 1\n" +
+		// Duplicate block from a merged profile.
+		"example.com/foo/routes.go:12.2,14.3 1 1\n"
+	blocks, err := parseCoverProfile(strings.NewReader(profile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		filename    string
+		startLine   int
+		endLine     int
+		wantStmts   int
+		wantCovered int
+	}{
+		{"example.com/foo/routes.go", 10, 15, 3, 3},
+		{"example.com/foo/routes.go", 20, 25, 4, 0},
+		{"example.com/foo/routes.go", 1, 100, 7, 3},
+		{"example.com/foo/index.go", 10, 12, 3, 3},
+		{"example.com/foo/missing.go", 1, 100, 0, 0},
+	}
+	for _, test := range tests {
+		stmts, covered := blocks.coverage(test.filename, test.startLine, test.endLine)
+		if stmts != test.wantStmts || covered != test.wantCovered {
+			t.Errorf("coverage(%q, %d, %d) = %d, %d; want %d, %d",
+				test.filename, test.startLine, test.endLine, stmts, covered, test.wantStmts, test.wantCovered)
+		}
+	}
+}
+
+func TestParseCoverProfileErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"example.com/foo/routes.go:10.40,12.2 2 1\n",
+		"mode: set\nexample.com/foo/routes.go 2 1\n",
+		"mode: set\nexample.com/foo/routes.go:10.40 2 1\n",
+		"mode: set\nexample.com/foo/routes.go:10.40,12.2 x 1\n",
+	}
+	for _, profile := range tests {
+		if _, err := parseCoverProfile(strings.NewReader(profile)); err == nil {
+			t.Errorf("parseCoverProfile(%q) did not return an error", profile)
+		}
+	}
+}
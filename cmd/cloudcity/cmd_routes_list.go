@@ -55,31 +55,78 @@ func (cmd *listRoutesCmd) run(ctx context.Context) (err error) {
 			err = fmt.Errorf("routes list: %w", err)
 		}
 	}()
+	pkg, err := loadRouterPackage(ctx, packages.NeedSyntax|packages.NeedTypes|packages.NeedTypesInfo)
+	if err != nil {
+		return err
+	}
+	routes, err := findRoutes(pkg)
+	if err != nil {
+		return err
+	}
+	if cmd.json {
+		return printJSONList(routes)
+	}
+	for _, r := range routes {
+		fmt.Printf("%-7s %-20s %s\n", r.Method, r.Path, r.Expr)
+	}
+	return nil
+}
+
+// printJSONList prints a JSON array to stdout with one element per line.
+func printJSONList[T any](list []T) error {
+	fmt.Println("[")
+	for i, elem := range list {
+		line, err := json.Marshal(elem)
+		if err != nil {
+			return err
+		}
+		if i < len(list)-1 {
+			line = append(line, ',')
+		}
+		line = append(line, '\n')
+		os.Stdout.Write(line)
+	}
+	fmt.Println("]")
+	return nil
+}
+
+type jsonPosition struct {
+	Filename string `json:"filename"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+type route struct {
+	Method   string       `json:"method"`
+	Path     string       `json:"path"`
+	Expr     string       `json:"expr"`
+	Position jsonPosition `json:"position"`
+
+	// handler is the resolved expression for the route's handler.
+	handler ast.Expr
+}
+
+// loadRouterPackage loads the Go package in the current directory.
+func loadRouterPackage(ctx context.Context, mode packages.LoadMode) (*packages.Package, error) {
 	pkgs, err := packages.Load(&packages.Config{
 		Context: ctx,
-		Mode:    packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Mode:    mode,
 	}, ".")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(pkgs) == 0 {
-		return fmt.Errorf("current directory is not a Go package")
+		return nil, fmt.Errorf("current directory is not a Go package")
 	}
-	pkg := pkgs[0]
+	return pkgs[0], nil
+}
+
+// findRoutes returns the routes registered in the package's
+// (*application).initRouter method.
+func findRoutes(pkg *packages.Package) ([]route, error) {
 	routingFunc := findInitRouterFunction(pkg)
 	if routingFunc == nil {
-		return fmt.Errorf("could not find (*application).initRouter")
-	}
-	type jsonPosition struct {
-		Filename string `json:"filename"`
-		Line     int    `json:"line,omitempty"`
-		Column   int    `json:"column,omitempty"`
-	}
-	type route struct {
-		Method   string       `json:"method"`
-		Path     string       `json:"path"`
-		Expr     string       `json:"expr"`
-		Position jsonPosition `json:"position"`
+		return nil, fmt.Errorf("could not find (*application).initRouter")
 	}
 	var routes []route
 	astutil.Apply(routingFunc.Body, func(c *astutil.Cursor) bool {
@@ -90,6 +137,12 @@ func (cmd *listRoutesCmd) run(ctx context.Context) (err error) {
 				return false
 			}
 			recvPkgPath, recvName := typeName(recvType)
+			if recvPkgPath == "zombiezen.com/go/bass/action" && recvName == "Mux" && obj.Name() == "Handle" && len(node.Args) == 3 {
+				if r, ok := actionMuxRoute(pkg, node); ok {
+					routes = append(routes, r)
+				}
+				return false
+			}
 			if recvPkgPath != "github.com/gorilla/mux" || recvName != "Router" || obj.Name() != "Handle" || len(node.Args) < 2 {
 				return false
 			}
@@ -99,15 +152,25 @@ func (cmd *listRoutesCmd) run(ctx context.Context) (err error) {
 				return false
 			}
 			handlerExpr := resolveExpr(pkg, node.Args[1])
-			if lit := extractMethodHandler(pkg.TypesInfo, handlerExpr); lit != nil {
+			if lit, isActionMethods := extractMethodHandler(pkg.TypesInfo, handlerExpr); lit != nil {
 				for _, elem := range lit.Elts {
 					kv, ok := elem.(*ast.KeyValueExpr)
 					if !ok {
 						continue
 					}
-					httpMethod := pkg.TypesInfo.Types[resolveExpr(pkg, kv.Key)].Value
-					if httpMethod == nil || httpMethod.Kind() != constant.String {
-						continue
+					var httpMethod string
+					if isActionMethods {
+						field, ok := kv.Key.(*ast.Ident)
+						if !ok || field.Name == "Config" {
+							continue
+						}
+						httpMethod = strings.ToUpper(field.Name)
+					} else {
+						v := pkg.TypesInfo.Types[resolveExpr(pkg, kv.Key)].Value
+						if v == nil || v.Kind() != constant.String {
+							continue
+						}
+						httpMethod = constant.StringVal(v)
 					}
 					handlerPos := pkg.Fset.Position(kv.Value.Pos())
 					routePos := jsonPosition{
@@ -115,11 +178,13 @@ func (cmd *listRoutesCmd) run(ctx context.Context) (err error) {
 						Line:     handlerPos.Line,
 						Column:   handlerPos.Column,
 					}
+					methodHandlerExpr := resolveExpr(pkg, kv.Value)
 					routes = append(routes, route{
-						Method:   constant.StringVal(httpMethod),
+						Method:   httpMethod,
 						Path:     constant.StringVal(pathValue),
-						Expr:     formatExpr(resolveExpr(pkg, kv.Value)),
+						Expr:     formatExpr(methodHandlerExpr),
 						Position: routePos,
+						handler:  methodHandlerExpr,
 					})
 				}
 			} else {
@@ -134,6 +199,7 @@ func (cmd *listRoutesCmd) run(ctx context.Context) (err error) {
 					Path:     constant.StringVal(pathValue),
 					Expr:     formatExpr(handlerExpr),
 					Position: routePos,
+					handler:  handlerExpr,
 				})
 			}
 			return false
@@ -144,26 +210,31 @@ func (cmd *listRoutesCmd) run(ctx context.Context) (err error) {
 			return true
 		}
 	}, nil)
-	if cmd.json {
-		fmt.Println("[")
-		for i, r := range routes {
-			line, err := json.Marshal(r)
-			if err != nil {
-				return err
-			}
-			if i < len(routes)-1 {
-				line = append(line, ',')
-			}
-			line = append(line, '\n')
-			os.Stdout.Write(line)
-		}
-		fmt.Println("]")
-		return nil
-	}
-	for _, r := range routes {
-		fmt.Printf("%-7s %-20s %s\n", r.Method, r.Path, r.Expr)
+	return routes, nil
+}
+
+// actionMuxRoute returns the route registered by a call to
+// zombiezen.com/go/bass/action.(*Mux).Handle.
+func actionMuxRoute(pkg *packages.Package, call *ast.CallExpr) (route, bool) {
+	methodValue := pkg.TypesInfo.Types[resolveExpr(pkg, call.Args[0])].Value
+	pathValue := pkg.TypesInfo.Types[resolveExpr(pkg, call.Args[1])].Value
+	if methodValue == nil || methodValue.Kind() != constant.String ||
+		pathValue == nil || pathValue.Kind() != constant.String {
+		return route{}, false
 	}
-	return nil
+	handlerPos := pkg.Fset.Position(call.Args[2].Pos())
+	handlerExpr := resolveExpr(pkg, call.Args[2])
+	return route{
+		Method: constant.StringVal(methodValue),
+		Path:   constant.StringVal(pathValue),
+		Expr:   formatExpr(handlerExpr),
+		Position: jsonPosition{
+			Filename: handlerPos.Filename,
+			Line:     handlerPos.Line,
+			Column:   handlerPos.Column,
+		},
+		handler: handlerExpr,
+	}, true
 }
 
 func findInitRouterFunction(pkg *packages.Package) *ast.FuncDecl {
@@ -228,18 +299,27 @@ func typeName(typ types.Type) (pkgPath string, typeName string) {
 }
 
 // extractMethodHandler returns the composite literal for a
-// github.com/gorilla/handlers.MethodHandler or nil if expr does not represent
-// a MethodHandler.
-func extractMethodHandler(info *types.Info, expr ast.Expr) *ast.CompositeLit {
+// github.com/gorilla/handlers.MethodHandler
+// or a (pointer to a) zombiezen.com/go/bass/action.Methods
+// or nil if expr does not represent either.
+// isActionMethods reports whether the literal is an action.Methods,
+// whose keys are field names rather than method strings.
+func extractMethodHandler(info *types.Info, expr ast.Expr) (lit *ast.CompositeLit, isActionMethods bool) {
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		expr = u.X
+	}
 	lit, ok := expr.(*ast.CompositeLit)
 	if !ok {
-		return nil
+		return nil, false
 	}
-	typePkgPath, typeName := typeName(info.Types[expr].Type)
-	if typePkgPath != "github.com/gorilla/handlers" || typeName != "MethodHandler" {
-		return nil
+	switch typePkgPath, typeName := typeName(info.Types[expr].Type); {
+	case typePkgPath == "github.com/gorilla/handlers" && typeName == "MethodHandler":
+		return lit, false
+	case typePkgPath == "zombiezen.com/go/bass/action" && typeName == "Methods":
+		return lit, true
+	default:
+		return nil, false
 	}
-	return lit
 }
 
 // resolveExpr descends into the innermost expression, following simple variable
@@ -0,0 +1,278 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+	"zombiezen.com/go/bass/sigterm"
+)
+
+// Module and package that `cloudcity self-update` installs.
+const (
+	bassModulePath    = "zombiezen.com/go/bass"
+	cloudcityPackage  = bassModulePath + "/cmd/cloudcity"
+	cloudcityBaseName = "cloudcity"
+)
+
+type selfUpdateCmd struct {
+	version string
+	check   bool
+}
+
+func newSelfUpdateCmd() *cobra.Command {
+	cmd := new(selfUpdateCmd)
+	c := &cobra.Command{
+		Use:   "self-update [options]",
+		Short: "Install the latest release of cloudcity",
+		Long: "Check the Go module proxy for a newer release of cloudcity and install it\n" +
+			"to GOBIN with go install. The module's checksum is verified against the\n" +
+			"Go checksum database, and the installed binary is checked to have been\n" +
+			"built from the verified module.",
+		Args: cobra.NoArgs,
+		RunE: func(cc *cobra.Command, args []string) error {
+			return cmd.run(cc.Context())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().StringVar(&cmd.version, "version", "latest", "version to install")
+	c.Flags().BoolVar(&cmd.check, "check", false, "only report whether an update is available")
+	return c
+}
+
+func (cmd *selfUpdateCmd) run(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("self-update: %w", err)
+		}
+	}()
+
+	installed := installedVersion()
+	if sumdb, err := goEnv(ctx, "GOSUMDB"); err != nil {
+		return err
+	} else if sumdb == "off" {
+		return errors.New("GOSUMDB=off: cannot verify module checksums")
+	}
+	target, err := resolveModuleVersion(ctx, cmd.version)
+	if err != nil {
+		return err
+	}
+	if cmd.version == "latest" && semver.IsValid(installed) && semver.Compare(installed, target) >= 0 {
+		fmt.Fprintf(os.Stderr, "cloudcity: %s is up to date\n", installed)
+		return nil
+	}
+	if installed == target {
+		fmt.Fprintf(os.Stderr, "cloudcity: %s is already installed\n", installed)
+		return nil
+	}
+
+	// go mod download verifies the module against the checksum database.
+	mod, err := downloadModule(ctx, target)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "cloudcity: %s -> %s\n", installed, target)
+	printChangesSince(mod.Dir, installed, target)
+	if cmd.check {
+		return nil
+	}
+
+	installCmd := exec.Command("go", "install", cloudcityPackage+"@"+target)
+	installCmd.Stdout = os.Stderr
+	installCmd.Stderr = os.Stderr
+	if _, err := sigterm.Output(ctx, installCmd); err != nil {
+		return fmt.Errorf("go install: %w", err)
+	}
+	exe, err := installedBinaryPath(ctx)
+	if err != nil {
+		return err
+	}
+	versionCmd := exec.Command("go", "version", "-m", exe)
+	result, err := sigterm.Output(ctx, versionCmd)
+	if err != nil {
+		return fmt.Errorf("go version: %w", err)
+	}
+	if got := moduleSumFromBuildInfo(result.Stdout, bassModulePath); got != mod.Sum {
+		return fmt.Errorf("%s was built from %s with checksum %q; want %q", exe, bassModulePath, got, mod.Sum)
+	}
+	fmt.Fprintf(os.Stderr, "cloudcity: installed %s to %s\n", target, exe)
+	return nil
+}
+
+// installedVersion returns the module version of the running binary,
+// or "(devel)" if it was not installed from a released version.
+func installedVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Path != bassModulePath || info.Main.Version == "" {
+		return "(devel)"
+	}
+	return info.Main.Version
+}
+
+// resolveModuleVersion asks the module proxy
+// for the version of the bass module that query refers to,
+// like "latest" or "v0.5.0".
+func resolveModuleVersion(ctx context.Context, query string) (string, error) {
+	listCmd := exec.Command("go", "list", "-m", "-json", bassModulePath+"@"+query)
+	listCmd.Dir = os.TempDir() // Not inside any module.
+	result, err := sigterm.Output(ctx, listCmd)
+	if err != nil {
+		return "", fmt.Errorf("find %s@%s: %w", bassModulePath, query, err)
+	}
+	var module struct {
+		Version string
+	}
+	if err := json.Unmarshal(result.Stdout, &module); err != nil {
+		return "", fmt.Errorf("find %s@%s: parse go list output: %w", bassModulePath, query, err)
+	}
+	return module.Version, nil
+}
+
+type downloadedModule struct {
+	Dir   string
+	Sum   string
+	Error string
+}
+
+func downloadModule(ctx context.Context, version string) (*downloadedModule, error) {
+	downloadCmd := exec.Command("go", "mod", "download", "-json", bassModulePath+"@"+version)
+	downloadCmd.Dir = os.TempDir()
+	result, err := sigterm.Output(ctx, downloadCmd)
+	if result == nil {
+		return nil, fmt.Errorf("download %s@%s: %w", bassModulePath, version, err)
+	}
+	// go mod download -json reports errors in its output.
+	mod := new(downloadedModule)
+	if jsonErr := json.Unmarshal(result.Stdout, mod); jsonErr != nil {
+		if err == nil {
+			err = jsonErr
+		}
+		return nil, fmt.Errorf("download %s@%s: %w", bassModulePath, version, err)
+	}
+	if mod.Error != "" {
+		return nil, fmt.Errorf("download %s@%s: %s", bassModulePath, version, mod.Error)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("download %s@%s: %w", bassModulePath, version, err)
+	}
+	return mod, nil
+}
+
+// printChangesSince prints the changelog entries
+// in the downloaded module directory between the two versions.
+func printChangesSince(dir, installed, target string) {
+	changelog, err := os.ReadFile(filepath.Join(dir, changelogFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "See https://pkg.go.dev/%s@%s for details.\n", bassModulePath, target)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cloudcity: reading changes: %v\n", err)
+		return
+	}
+	if changes := changelogSince(changelog, installed); len(changes) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%s\n", bytes.TrimSpace(changes))
+	}
+}
+
+// changelogSince returns the entries of a changelog
+// (in the format written by `cloudcity version bump --changelog`)
+// that are newer than the given version.
+// If the version has no entry, then all entries are returned.
+func changelogSince(changelog []byte, version string) []byte {
+	start := 0
+	if !bytes.HasPrefix(changelog, []byte("## ")) {
+		start = bytes.Index(changelog, []byte("\n## "))
+		if start == -1 {
+			return nil
+		}
+		start++
+	}
+	entries := changelog[start:]
+	for i := 0; i < len(entries); {
+		line := entries[i:]
+		if j := bytes.IndexByte(line, '\n'); j >= 0 {
+			line = line[:j+1]
+		}
+		if bytes.HasPrefix(line, []byte("## ")) {
+			fields := strings.Fields(string(line[len("## "):]))
+			if len(fields) > 0 && fields[0] == version {
+				return entries[:i]
+			}
+		}
+		i += len(line)
+	}
+	return entries
+}
+
+// installedBinaryPath returns the path that go install writes cloudcity to.
+func installedBinaryPath(ctx context.Context) (string, error) {
+	dir, err := goEnv(ctx, "GOBIN")
+	if err != nil {
+		return "", err
+	}
+	if dir == "" {
+		gopath, err := goEnv(ctx, "GOPATH")
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(filepath.SplitList(gopath)[0], "bin")
+	}
+	name := cloudcityBaseName
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func goEnv(ctx context.Context, name string) (string, error) {
+	c := exec.Command("go", "env", name)
+	result, err := sigterm.Output(ctx, c)
+	if err != nil {
+		return "", fmt.Errorf("go env %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(result.Stdout)), nil
+}
+
+// moduleSumFromBuildInfo returns the checksum recorded for the given module
+// in the output of `go version -m`.
+// The main module is listed on a "mod" line
+// and dependencies are listed on "dep" lines.
+func moduleSumFromBuildInfo(out []byte, modulePath string) string {
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) >= 4 && (fields[0] == "mod" || fields[0] == "dep") && fields[1] == modulePath {
+			return fields[3]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "testing"
+
+func TestChangelogSince(t *testing.T) {
+	const changelog = "# Changelog\n\n" +
+		"## v0.3.0 - 2026-03-01\n\n- Add self-update\n\n" +
+		"## v0.2.0 - 2026-02-01\n\n- Add routes coverage\n\n" +
+		"## v0.1.0 - 2026-01-01\n\n- Initial release\n"
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{
+			version: "v0.2.0",
+			want:    "## v0.3.0 - 2026-03-01\n\n- Add self-update\n\n",
+		},
+		{
+			version: "v0.1.0",
+			want: "## v0.3.0 - 2026-03-01\n\n- Add self-update\n\n" +
+				"## v0.2.0 - 2026-02-01\n\n- Add routes coverage\n\n",
+		},
+		{version: "v0.3.0", want: ""},
+		{
+			version: "(devel)",
+			want: "## v0.3.0 - 2026-03-01\n\n- Add self-update\n\n" +
+				"## v0.2.0 - 2026-02-01\n\n- Add routes coverage\n\n" +
+				"## v0.1.0 - 2026-01-01\n\n- Initial release\n",
+		},
+	}
+	for _, test := range tests {
+		if got := string(changelogSince([]byte(changelog), test.version)); got != test.want {
+			t.Errorf("changelogSince(changelog, %q) = %q; want %q", test.version, got, test.want)
+		}
+	}
+}
+
+func TestModuleSumFromBuildInfo(t *testing.T) {
+	const out = "/home/me/go/bin/cloudcity: go1.22.0\n" +
+		"\tpath\tzombiezen.com/go/bass/cmd/cloudcity\n" +
+		"\tmod\tzombiezen.com/go/bass\tv0.3.0\th1:abc=\n" +
+		"\tdep\tgithub.com/spf13/cobra\tv1.1.3\th1:def=\n"
+	if got, want := moduleSumFromBuildInfo([]byte(out), "zombiezen.com/go/bass"), "h1:abc="; got != want {
+		t.Errorf("moduleSumFromBuildInfo(out, %q) = %q; want %q", "zombiezen.com/go/bass", got, want)
+	}
+	if got, want := moduleSumFromBuildInfo([]byte(out), "github.com/spf13/cobra"), "h1:def="; got != want {
+		t.Errorf("moduleSumFromBuildInfo(out, %q) = %q; want %q", "github.com/spf13/cobra", got, want)
+	}
+	if got := moduleSumFromBuildInfo([]byte(out), "example.com/missing"); got != "" {
+		t.Errorf("moduleSumFromBuildInfo(out, %q) = %q; want \"\"", "example.com/missing", got)
+	}
+}
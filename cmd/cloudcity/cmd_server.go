@@ -87,8 +87,8 @@ func (cmd *serverCmd) run(ctx context.Context) (err error) {
 	buildCmd.Stdout = os.Stderr
 	buildCmd.Stderr = os.Stderr
 	fmt.Fprintf(os.Stderr, "## go build -o %s %s ##\n", relProgramPath, serverPackage)
-	if err := sigterm.Run(ctx, buildCmd); err != nil {
-		return err
+	if _, err := sigterm.Output(ctx, buildCmd); err != nil {
+		return fmt.Errorf("go build: %w", err)
 	}
 
 	absProgramPath, err := filepath.Abs(relProgramPath)
@@ -106,12 +106,11 @@ func (cmd *serverCmd) run(ctx context.Context) (err error) {
 
 func listPackages(ctx context.Context, pattern string) ([]string, error) {
 	c := exec.Command("go", "list", "--", pattern)
-	stdout := new(strings.Builder)
-	c.Stdout = stdout
 	c.Stderr = os.Stderr
-	if err := sigterm.Run(ctx, c); err != nil {
+	result, err := sigterm.Output(ctx, c)
+	if err != nil {
 		return nil, fmt.Errorf("list go packages: %w", err)
 	}
-	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	lines := strings.Split(strings.TrimSuffix(string(result.Stdout), "\n"), "\n")
 	return lines, nil
 }
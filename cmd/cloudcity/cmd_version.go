@@ -0,0 +1,247 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"zombiezen.com/go/bass/sigterm"
+)
+
+// Files updated by `cloudcity version bump`,
+// relative to the module root.
+const (
+	versionFileName   = "version.go"
+	changelogFileName = "CHANGELOG.md"
+)
+
+type bumpVersionCmd struct {
+	part      string
+	tag       bool
+	changelog bool
+}
+
+func newBumpVersionCmd() *cobra.Command {
+	cmd := new(bumpVersionCmd)
+	c := &cobra.Command{
+		Use:   "bump [options] major|minor|patch",
+		Short: "Increment the application version",
+		Long: "Increment the version variable in " + versionFileName + " at the module root.\n\n" +
+			"By default, the change is committed and tagged with git.\n" +
+			"With --changelog, an entry listing the commits since the previous version\n" +
+			"is added to " + changelogFileName + ". Pass --tag=false to edit the entry\n" +
+			"before committing and tagging yourself.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cc *cobra.Command, args []string) error {
+			cmd.part = args[0]
+			return cmd.run(cc.Context())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().BoolVar(&cmd.tag, "tag", true, "commit the change and create a git tag")
+	c.Flags().BoolVar(&cmd.changelog, "changelog", false, "add an entry to "+changelogFileName)
+	return c
+}
+
+func (cmd *bumpVersionCmd) run(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("version bump: %w", err)
+		}
+	}()
+
+	root, err := findGoModuleDir(ctx, ".")
+	if err != nil {
+		return err
+	}
+	versionPath := filepath.Join(root, versionFileName)
+	src, err := os.ReadFile(versionPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s not found (it should declare `var version = \"v0.1.0\"`)", versionPath)
+	}
+	if err != nil {
+		return err
+	}
+	oldVersion, err := readVersionVar(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", versionPath, err)
+	}
+	newVersion, err := bumpVersion(oldVersion, cmd.part)
+	if err != nil {
+		return err
+	}
+	src = replaceVersionVar(src, newVersion)
+	if err := os.WriteFile(versionPath, src, 0o666); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "cloudcity: %s -> %s\n", oldVersion, newVersion)
+	changedFiles := []string{versionFileName}
+
+	if cmd.changelog {
+		entries, err := commitsSince(ctx, root, oldVersion)
+		if err != nil {
+			return err
+		}
+		changelogPath := filepath.Join(root, changelogFileName)
+		changelog, err := os.ReadFile(changelogPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		changelog = addChangelogEntry(changelog, newVersion, time.Now().Format("2006-01-02"), entries)
+		if err := os.WriteFile(changelogPath, changelog, 0o666); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "cloudcity: updated %s\n", changelogPath)
+		changedFiles = append(changedFiles, changelogFileName)
+	}
+
+	if !cmd.tag {
+		return nil
+	}
+	// Standard error is reported in the returned errors.
+	addCmd := exec.Command("git", append([]string{"add", "--"}, changedFiles...)...)
+	addCmd.Dir = root
+	if _, err := sigterm.Output(ctx, addCmd); err != nil {
+		return fmt.Errorf("git add: %w", err)
+	}
+	commitCmd := exec.Command("git", append([]string{"commit", "--quiet", "--message=Release " + newVersion, "--"}, changedFiles...)...)
+	commitCmd.Dir = root
+	if _, err := sigterm.Output(ctx, commitCmd); err != nil {
+		return fmt.Errorf("git commit: %w", err)
+	}
+	tagCmd := exec.Command("git", "tag", "--annotate", "--message="+newVersion, "--", newVersion)
+	tagCmd.Dir = root
+	if _, err := sigterm.Output(ctx, tagCmd); err != nil {
+		return fmt.Errorf("git tag: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "cloudcity: tagged %s\n", newVersion)
+	return nil
+}
+
+// versionVarPattern matches the version variable declaration in version.go.
+var versionVarPattern = regexp.MustCompile(`(?m)^(var version\s*=\s*)"([^"]*)"`)
+
+func readVersionVar(src []byte) (string, error) {
+	m := versionVarPattern.FindSubmatch(src)
+	if m == nil {
+		return "", errors.New("no `var version = \"...\"` declaration")
+	}
+	return string(m[2]), nil
+}
+
+func replaceVersionVar(src []byte, newVersion string) []byte {
+	return versionVarPattern.ReplaceAll(src, []byte("${1}"+strconv.Quote(newVersion)))
+}
+
+// bumpVersion increments the given part of a semantic version
+// of the form "vMAJOR.MINOR.PATCH".
+// Any prerelease or build metadata suffix is dropped.
+func bumpVersion(v string, part string) (string, error) {
+	core := strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	fields := strings.Split(core, ".")
+	if !strings.HasPrefix(v, "v") || len(fields) != 3 {
+		return "", fmt.Errorf("version %q is not of the form vMAJOR.MINOR.PATCH", v)
+	}
+	var nums [3]int
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("version %q is not of the form vMAJOR.MINOR.PATCH", v)
+		}
+		nums[i] = n
+	}
+	switch part {
+	case "major":
+		nums = [3]int{nums[0] + 1, 0, 0}
+	case "minor":
+		nums = [3]int{nums[0], nums[1] + 1, 0}
+	case "patch":
+		nums[2]++
+	default:
+		return "", fmt.Errorf("unknown version part %q (must be major, minor, or patch)", part)
+	}
+	return fmt.Sprintf("v%d.%d.%d", nums[0], nums[1], nums[2]), nil
+}
+
+// commitsSince returns the subjects of the commits since the given tag,
+// or of all commits if the tag does not exist.
+func commitsSince(ctx context.Context, dir string, tag string) ([]string, error) {
+	logCmd := exec.Command("git", "log", "--format=%s")
+	verifyCmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+tag)
+	verifyCmd.Dir = dir
+	if _, err := sigterm.Output(ctx, verifyCmd); err == nil {
+		logCmd.Args = append(logCmd.Args, tag+"..HEAD")
+	}
+	logCmd.Dir = dir
+	result, err := sigterm.Output(ctx, logCmd)
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	out := strings.TrimSuffix(string(result.Stdout), "\n")
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// addChangelogEntry adds a section for the given version
+// above the first existing version section of a Markdown changelog.
+// If changelog is empty, then a new changelog is started.
+func addChangelogEntry(changelog []byte, version, date string, entries []string) []byte {
+	entry := new(bytes.Buffer)
+	fmt.Fprintf(entry, "## %s - %s\n\n", version, date)
+	if len(entries) == 0 {
+		entry.WriteString("- TODO: describe changes\n")
+	}
+	for _, e := range entries {
+		fmt.Fprintf(entry, "- %s\n", e)
+	}
+	entry.WriteString("\n")
+
+	if len(bytes.TrimSpace(changelog)) == 0 {
+		return append([]byte("# Changelog\n\n"), entry.Bytes()...)
+	}
+	i := 0
+	if !bytes.HasPrefix(changelog, []byte("## ")) {
+		i = bytes.Index(changelog, []byte("\n## "))
+		if i == -1 {
+			changelog = append(bytes.TrimRight(changelog, "\n"), "\n\n"...)
+			return append(changelog, entry.Bytes()...)
+		}
+		i++
+	}
+	result := make([]byte, 0, len(changelog)+entry.Len())
+	result = append(result, changelog[:i]...)
+	result = append(result, entry.Bytes()...)
+	result = append(result, changelog[i:]...)
+	return result
+}
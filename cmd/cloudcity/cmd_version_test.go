@@ -0,0 +1,109 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		v       string
+		part    string
+		want    string
+		wantErr bool
+	}{
+		{v: "v0.1.0", part: "patch", want: "v0.1.1"},
+		{v: "v0.1.9", part: "minor", want: "v0.2.0"},
+		{v: "v1.2.3", part: "major", want: "v2.0.0"},
+		{v: "v1.2.3-rc.1", part: "patch", want: "v1.2.4"},
+		{v: "1.2.3", part: "patch", wantErr: true},
+		{v: "v1.2", part: "patch", wantErr: true},
+		{v: "v1.2.x", part: "patch", wantErr: true},
+		{v: "v1.2.3", part: "bogus", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := bumpVersion(test.v, test.part)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("bumpVersion(%q, %q) = _, %v; want %q, <nil>", test.v, test.part, err, test.want)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("bumpVersion(%q, %q) = %q, <nil>; want error", test.v, test.part, got)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("bumpVersion(%q, %q) = %q; want %q", test.v, test.part, got, test.want)
+		}
+	}
+}
+
+func TestVersionVar(t *testing.T) {
+	const src = "package main\n\n// version is the release.\nvar version = \"v0.1.0\"\n"
+	got, err := readVersionVar([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "v0.1.0"; got != want {
+		t.Errorf("readVersionVar(...) = %q; want %q", got, want)
+	}
+	const want = "package main\n\n// version is the release.\nvar version = \"v0.2.0\"\n"
+	if got := string(replaceVersionVar([]byte(src), "v0.2.0")); got != want {
+		t.Errorf("replaceVersionVar(...) = %q; want %q", got, want)
+	}
+	if _, err := readVersionVar([]byte("package main\n")); err == nil {
+		t.Error("readVersionVar(no declaration) did not return an error")
+	}
+}
+
+func TestAddChangelogEntry(t *testing.T) {
+	tests := []struct {
+		name      string
+		changelog string
+		entries   []string
+		want      string
+	}{
+		{
+			name:    "New",
+			entries: []string{"Add widgets"},
+			want:    "# Changelog\n\n## v0.2.0 - 2026-01-02\n\n- Add widgets\n\n",
+		},
+		{
+			name:      "Existing",
+			changelog: "# Changelog\n\nIntro.\n\n## v0.1.0 - 2025-12-01\n\n- First\n",
+			entries:   []string{"Add widgets", "Fix gadgets"},
+			want:      "# Changelog\n\nIntro.\n\n## v0.2.0 - 2026-01-02\n\n- Add widgets\n- Fix gadgets\n\n## v0.1.0 - 2025-12-01\n\n- First\n",
+		},
+		{
+			name:      "NoSections",
+			changelog: "# Changelog\n",
+			want:      "# Changelog\n\n## v0.2.0 - 2026-01-02\n\n- TODO: describe changes\n\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := string(addChangelogEntry([]byte(test.changelog), "v0.2.0", "2026-01-02", test.entries))
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("addChangelogEntry(...) (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
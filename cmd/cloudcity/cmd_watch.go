@@ -0,0 +1,267 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	slashpath "path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"zombiezen.com/go/bass/sigterm"
+)
+
+type watchCmd struct {
+	globs    []string
+	debounce time.Duration
+	interval time.Duration
+	args     []string
+}
+
+func newWatchCmd() *cobra.Command {
+	cmd := new(watchCmd)
+	c := &cobra.Command{
+		Use:   "watch [options] [--] COMMAND [ARG [...]]",
+		Short: "Rerun a command when files change",
+		Long: "Run a command and restart it whenever files matching the glob patterns change.\n\n" +
+			"Patterns are matched against slash-separated paths relative to the current directory.\n" +
+			"A \"**\" path segment matches any number of directories.\n" +
+			"Hidden directories and node_modules are not watched.\n" +
+			"The command runs in its own process group,\n" +
+			"so subprocesses are terminated along with it.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cc *cobra.Command, args []string) error {
+			cmd.args = args
+			return cmd.run(cc.Context())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().SetInterspersed(false)
+	c.Flags().StringArrayVar(&cmd.globs, "glob", []string{"**/*.go"}, "`pattern` of files to watch (can be repeated)")
+	c.Flags().DurationVar(&cmd.debounce, "debounce", 200*time.Millisecond, "time to wait for changes to settle before restarting")
+	c.Flags().DurationVar(&cmd.interval, "interval", 500*time.Millisecond, "time between checks for changes")
+	return c
+}
+
+func (cmd *watchCmd) run(ctx context.Context) error {
+	for _, pattern := range cmd.globs {
+		if err := validateGlob(pattern); err != nil {
+			return fmt.Errorf("watch: %w", err)
+		}
+	}
+	if cmd.interval <= 0 {
+		return fmt.Errorf("watch: --interval must be positive")
+	}
+	snap, err := snapshotFiles(".", cmd.globs)
+	if err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+	for {
+		stop := cmd.start(ctx)
+		snap, err = waitForChange(ctx, ".", cmd.globs, snap, cmd.interval, cmd.debounce)
+		stop()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("watch: %w", err)
+		}
+		fmt.Fprintln(os.Stderr, "## files changed; restarting ##")
+	}
+}
+
+// start runs the command in the background.
+// Calling the returned function terminates the command
+// and waits for it to exit.
+func (cmd *watchCmd) start(ctx context.Context) (stop func()) {
+	procCtx, cancel := context.WithCancel(ctx)
+	c := exec.Command(cmd.args[0], cmd.args[1:]...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	sigterm.SetProcessGroup(c)
+	fmt.Fprintf(os.Stderr, "## %s ##\n", strings.Join(c.Args, " "))
+	wait, err := sigterm.Start(procCtx, c)
+	if err != nil {
+		cancel()
+		fmt.Fprintln(os.Stderr, "cloudcity:", err)
+		return func() {}
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := wait()
+		if procCtx.Err() != nil {
+			// Stopped by the watcher.
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "## %s: %v; waiting for changes ##\n", c.Args[0], err)
+		} else {
+			fmt.Fprintln(os.Stderr, "## exited; waiting for changes ##")
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// fileStamp is the information used to detect whether a file has changed.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshotFiles returns the stamps of the files under root
+// that match any of the glob patterns,
+// keyed by slash-separated path relative to root.
+func snapshotFiles(root string, globs []string) (map[string]fileStamp, error) {
+	snap := make(map[string]fileStamp)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// Deleted during the walk.
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !matchAnyGlob(globs, rel) {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		snap[rel] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// waitForChange polls the files under root every interval
+// until they differ from prev and then stay the same for the debounce duration.
+// It returns the latest snapshot.
+func waitForChange(ctx context.Context, root string, globs []string, prev map[string]fileStamp, interval, debounce time.Duration) (map[string]fileStamp, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return prev, ctx.Err()
+		case now := <-ticker.C:
+			curr, err := snapshotFiles(root, globs)
+			if err != nil {
+				return prev, err
+			}
+			if !equalSnapshots(prev, curr) {
+				prev = curr
+				lastChange = now
+			} else if !lastChange.IsZero() && now.Sub(lastChange) >= debounce {
+				return curr, nil
+			}
+		}
+	}
+}
+
+func equalSnapshots(snap1, snap2 map[string]fileStamp) bool {
+	if len(snap1) != len(snap2) {
+		return false
+	}
+	for path, stamp1 := range snap1 {
+		stamp2, ok := snap2[path]
+		if !ok || !stamp1.modTime.Equal(stamp2.modTime) || stamp1.size != stamp2.size {
+			return false
+		}
+	}
+	return true
+}
+
+func validateGlob(pattern string) error {
+	for _, seg := range strings.Split(pattern, "/") {
+		if _, err := slashpath.Match(seg, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func matchAnyGlob(globs []string, name string) bool {
+	for _, pattern := range globs {
+		if matchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether the slash-separated name matches pattern.
+// Pattern segments use [path.Match] syntax,
+// except that a "**" segment matches zero or more path segments.
+func matchGlob(pattern, name string) bool {
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range name {
+				if matchGlobSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := slashpath.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
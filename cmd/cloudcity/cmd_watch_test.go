@@ -0,0 +1,105 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/foo/main.go", true},
+		{"**/*.go", "main.ts", false},
+		{"cmd/**", "cmd/foo/main.go", true},
+		{"cmd/**", "other/main.go", false},
+		{"cmd/**/*_test.go", "cmd/main_test.go", true},
+		{"cmd/**/*_test.go", "cmd/a/b/main_test.go", true},
+		{"cmd/**/*_test.go", "cmd/a/b/main.go", false},
+		{"templates/*.html", "templates/base.html", true},
+	}
+	for _, test := range tests {
+		if got := matchGlob(test.pattern, test.name); got != test.want {
+			t.Errorf("matchGlob(%q, %q) = %t; want %t", test.pattern, test.name, got, test.want)
+		}
+	}
+}
+
+func TestValidateGlob(t *testing.T) {
+	if err := validateGlob("**/*.go"); err != nil {
+		t.Errorf("validateGlob(%q) = %v; want <nil>", "**/*.go", err)
+	}
+	if err := validateGlob("foo/[bar"); err == nil {
+		t.Errorf("validateGlob(%q) = <nil>; want error", "foo/[bar")
+	}
+}
+
+func TestSnapshotFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"main.go",
+		"sub/foo.go",
+		"sub/foo.txt",
+		".git/hooks.go",
+		"client/node_modules/x.go",
+	}
+	for _, name := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snap, err := snapshotFiles(dir, []string{"**/*.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap) != 2 {
+		t.Errorf("snapshotFiles(...) = %v; want main.go and sub/foo.go", snap)
+	}
+	for _, name := range []string{"main.go", "sub/foo.go"} {
+		if _, ok := snap[name]; !ok {
+			t.Errorf("snapshotFiles(...) missing %s", name)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		os.WriteFile(filepath.Join(dir, "new.go"), []byte("package x\n"), 0o666)
+	}()
+	got, err := waitForChange(ctx, dir, []string{"**/*.go"}, snap, 5*time.Millisecond, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal("waitForChange:", err)
+	}
+	if _, ok := got["new.go"]; !ok {
+		t.Errorf("waitForChange(...) = %v; want to include new.go", got)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package events provides an in-process publish/subscribe event bus
+// with typed topics. It allows domain code to announce changes
+// without knowing whether they are delivered to Turbo Stream broadcasts,
+// webhooks, or background jobs.
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned by [*Topic.Publish]
+// after the topic's [Bus] has been shut down.
+var ErrClosed = errors.New("events: bus closed")
+
+// A Bus owns a set of topics and the goroutines that deliver their events.
+// The zero value is not usable; create a Bus with [NewBus].
+type Bus struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	drain  chan struct{}
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewBus returns a new empty [Bus].
+func NewBus() *Bus {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bus{
+		ctx:    ctx,
+		cancel: cancel,
+		drain:  make(chan struct{}),
+	}
+}
+
+// Shutdown stops the bus from accepting new events
+// and waits for subscribers to handle events that are already buffered.
+// If ctx is done before the subscribers finish,
+// Shutdown cancels the context passed to subscriber functions
+// and returns ctx.Err().
+// Calling Shutdown more than once waits on the same subscribers.
+func (b *Bus) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.drain)
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// A Topic is a named stream of events of type T.
+// It is safe to use a Topic from multiple goroutines.
+type Topic[T any] struct {
+	bus  *Bus
+	name string
+
+	mu   sync.Mutex
+	subs map[*subscriber[T]]struct{}
+}
+
+// NewTopic returns a new [Topic] on the given bus.
+// The name is used for identification only:
+// two topics with the same name do not share subscribers.
+func NewTopic[T any](bus *Bus, name string) *Topic[T] {
+	return &Topic[T]{
+		bus:  bus,
+		name: name,
+		subs: make(map[*subscriber[T]]struct{}),
+	}
+}
+
+// Name returns the name of the topic.
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// Publish sends v to every current subscriber of the topic.
+// If a subscriber's buffer is full, Publish blocks until there is room
+// or ctx is done, in which case Publish returns ctx.Err()
+// after having delivered v to some subset of the subscribers.
+func (t *Topic[T]) Publish(ctx context.Context, v T) error {
+	t.bus.mu.Lock()
+	closed := t.bus.closed
+	t.bus.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+
+	t.mu.Lock()
+	subs := make([]*subscriber[T], 0, len(t.subs))
+	for s := range t.subs {
+		subs = append(subs, s)
+	}
+	t.mu.Unlock()
+	for _, s := range subs {
+		select {
+		case s.c <- v:
+		case <-s.stopped:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe calls f in a new goroutine for each event published to the topic
+// after Subscribe returns. If the bus has been shut down, f is never called. Events are delivered to f one at a time,
+// in the order they were published.
+// Up to bufferSize events are queued while f is running
+// before [*Topic.Publish] blocks.
+//
+// The context passed to f is canceled when the bus's Shutdown deadline expires.
+// It is not derived from the context passed to Publish,
+// since the publisher may have finished by the time f is called.
+func (t *Topic[T]) Subscribe(bufferSize int, f func(context.Context, T)) *Subscription {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+	s := &subscriber[T]{
+		c:       make(chan T, bufferSize),
+		cancel:  make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	bus := t.bus
+	bus.mu.Lock()
+	if bus.closed {
+		bus.mu.Unlock()
+		close(s.stopped)
+		return &Subscription{cancel: s.cancel, stopped: s.stopped}
+	}
+	bus.wg.Add(1)
+	bus.mu.Unlock()
+	t.mu.Lock()
+	t.subs[s] = struct{}{}
+	t.mu.Unlock()
+
+	go func() {
+		defer func() {
+			t.mu.Lock()
+			delete(t.subs, s)
+			t.mu.Unlock()
+			close(s.stopped)
+			bus.wg.Done()
+		}()
+		for {
+			select {
+			case v := <-s.c:
+				f(bus.ctx, v)
+			case <-s.cancel:
+				return
+			case <-bus.ctx.Done():
+				return
+			case <-bus.drain:
+				for {
+					select {
+					case v := <-s.c:
+						f(bus.ctx, v)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+	return &Subscription{
+		cancel:  s.cancel,
+		stopped: s.stopped,
+	}
+}
+
+type subscriber[T any] struct {
+	c       chan T
+	cancel  chan struct{}
+	stopped chan struct{}
+}
+
+// A Subscription is a handle to a subscriber created by [*Topic.Subscribe].
+type Subscription struct {
+	cancel  chan struct{}
+	once    sync.Once
+	stopped chan struct{}
+}
+
+// Cancel stops the subscriber from receiving further events
+// and waits for any in-progress call to the subscriber function to return.
+// Events buffered for the subscriber are discarded.
+// Cancel must not be called from the subscriber function.
+func (s *Subscription) Cancel() {
+	s.once.Do(func() { close(s.cancel) })
+	<-s.stopped
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTopic(t *testing.T) {
+	ctx := context.Background()
+	bus := NewBus()
+	topic := NewTopic[int](bus, "numbers")
+
+	var mu sync.Mutex
+	var got1, got2 []int
+	topic.Subscribe(10, func(ctx context.Context, n int) {
+		mu.Lock()
+		got1 = append(got1, n)
+		mu.Unlock()
+	})
+	topic.Subscribe(0, func(ctx context.Context, n int) {
+		mu.Lock()
+		got2 = append(got2, n)
+		mu.Unlock()
+	})
+	for i := 1; i <= 5; i++ {
+		if err := topic.Publish(ctx, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bus.Shutdown(ctx); err != nil {
+		t.Fatal("Shutdown:", err)
+	}
+
+	want := []int{1, 2, 3, 4, 5}
+	if diff := cmp.Diff(want, got1); diff != "" {
+		t.Errorf("buffered subscriber (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, got2); diff != "" {
+		t.Errorf("unbuffered subscriber (-want +got):\n%s", diff)
+	}
+	if err := topic.Publish(ctx, 6); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish after Shutdown = %v; want %v", err, ErrClosed)
+	}
+}
+
+func TestSubscriptionCancel(t *testing.T) {
+	ctx := context.Background()
+	bus := NewBus()
+	defer bus.Shutdown(ctx)
+	topic := NewTopic[string](bus, "strings")
+
+	got := make(chan string, 10)
+	sub := topic.Subscribe(0, func(ctx context.Context, s string) {
+		got <- s
+	})
+	if err := topic.Publish(ctx, "before"); err != nil {
+		t.Fatal(err)
+	}
+	if s := <-got; s != "before" {
+		t.Errorf("received %q; want %q", s, "before")
+	}
+	sub.Cancel()
+	sub.Cancel() // idempotent
+	if err := topic.Publish(ctx, "after"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case s := <-got:
+		t.Errorf("received %q after Cancel", s)
+	default:
+	}
+}
+
+func TestPublishBlocks(t *testing.T) {
+	bus := NewBus()
+	topic := NewTopic[int](bus, "numbers")
+	release := make(chan struct{})
+	topic.Subscribe(0, func(ctx context.Context, n int) {
+		<-release
+	})
+
+	// The first event occupies the subscriber.
+	if err := topic.Publish(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := topic.Publish(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Publish to busy subscriber = %v; want %v", err, context.DeadlineExceeded)
+	}
+	close(release)
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Error("Shutdown:", err)
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	bus := NewBus()
+	topic := NewTopic[int](bus, "numbers")
+	canceled := make(chan struct{})
+	topic.Subscribe(0, func(ctx context.Context, n int) {
+		<-ctx.Done()
+		close(canceled)
+	})
+	if err := topic.Publish(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bus.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v; want %v", err, context.DeadlineExceeded)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Error("subscriber context not canceled after Shutdown deadline")
+	}
+}
+
+func TestSubscribeAfterShutdown(t *testing.T) {
+	bus := NewBus()
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	topic := NewTopic[int](bus, "numbers")
+	sub := topic.Subscribe(1, func(ctx context.Context, n int) {
+		t.Error("subscriber called after Shutdown")
+	})
+	sub.Cancel()
+}
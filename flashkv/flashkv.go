@@ -0,0 +1,325 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package flashkv provides tamper-proof key-value data stored in HTTP cookies.
+// Values are authenticated with HMAC-SHA256 (or optionally encrypted with AES-GCM)
+// using versioned keys, so keys can be rotated without invalidating existing cookies.
+// This is useful for flash messages, OAuth state, and other small pieces of
+// client-held data.
+package flashkv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"zombiezen.com/go/bass/clock"
+)
+
+// MinSecretSize is the minimum number of bytes in a [Key] secret.
+const MinSecretSize = 32
+
+// DefaultMaxSize is the default maximum size of an encoded value in bytes.
+const DefaultMaxSize = 4000
+
+// Errors returned by [*Codec.Decode].
+var (
+	ErrInvalid = errors.New("flashkv: invalid or tampered value")
+	ErrExpired = errors.New("flashkv: value expired")
+)
+
+// ErrTooLarge is returned by [*Codec.Encode]
+// when the encoded value exceeds the maximum size.
+var ErrTooLarge = errors.New("flashkv: value too large")
+
+// Values is a set of key-value pairs.
+type Values map[string]string
+
+// A Key is a versioned secret used to sign or encrypt values.
+type Key struct {
+	// Version identifies the key in encoded values.
+	Version uint8
+	// Secret is random data at least [MinSecretSize] bytes long.
+	Secret []byte
+}
+
+// Options holds the arguments to [NewCodec].
+type Options struct {
+	// Keys is the list of keys to accept.
+	// The first key is used to encode new values.
+	// Older keys should be placed after it during rotation.
+	Keys []Key
+	// If Encrypt is true, then values are encrypted in addition to being signed.
+	Encrypt bool
+	// MaxSize is the maximum number of bytes in an encoded value.
+	// If it is zero, then DefaultMaxSize is used.
+	MaxSize int
+	// If MaxAge is positive, then values older than MaxAge are rejected.
+	MaxAge time.Duration
+	// Clock is the source of the current time for timestamps and MaxAge.
+	// If it is nil, then [clock.Real] is used.
+	Clock clock.Clock
+}
+
+// A Codec converts [Values] to and from authenticated strings.
+// It is safe to use a Codec from multiple goroutines.
+type Codec struct {
+	keys    []derivedKey
+	encrypt bool
+	maxSize int
+	maxAge  time.Duration
+	clock   clock.Clock
+}
+
+type derivedKey struct {
+	version uint8
+	mac     []byte
+	aead    cipher.AEAD
+}
+
+// NewCodec returns a new [Codec] with the given options.
+func NewCodec(opts *Options) (*Codec, error) {
+	if opts == nil || len(opts.Keys) == 0 {
+		return nil, errors.New("flashkv: no keys")
+	}
+	c := &Codec{
+		encrypt: opts.Encrypt,
+		maxSize: opts.MaxSize,
+		maxAge:  opts.MaxAge,
+		clock:   clock.Or(opts.Clock),
+	}
+	if c.maxSize <= 0 {
+		c.maxSize = DefaultMaxSize
+	}
+	seen := make(map[uint8]bool)
+	for _, k := range opts.Keys {
+		if len(k.Secret) < MinSecretSize {
+			return nil, fmt.Errorf("flashkv: key version %d: secret must be at least %d bytes", k.Version, MinSecretSize)
+		}
+		if seen[k.Version] {
+			return nil, fmt.Errorf("flashkv: duplicate key version %d", k.Version)
+		}
+		seen[k.Version] = true
+		block, err := aes.NewCipher(deriveKey(k.Secret, "flashkv encryption"))
+		if err != nil {
+			return nil, fmt.Errorf("flashkv: key version %d: %v", k.Version, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("flashkv: key version %d: %v", k.Version, err)
+		}
+		c.keys = append(c.keys, derivedKey{
+			version: k.Version,
+			mac:     deriveKey(k.Secret, "flashkv authentication"),
+			aead:    aead,
+		})
+	}
+	return c, nil
+}
+
+func deriveKey(secret []byte, purpose string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(purpose))
+	return h.Sum(nil)
+}
+
+// Encoded value layout:
+//
+//	version (1 byte)
+//	flags (1 byte)
+//	if signed:    timestamp (8 bytes) || data || HMAC-SHA256 (32 bytes)
+//	if encrypted: nonce || AES-GCM(timestamp (8 bytes) || data)
+//
+// The name, version, and flags are authenticated in both forms.
+const (
+	headerSize    = 2
+	timestampSize = 8
+	flagEncrypted = 1 << 0
+)
+
+// Encode returns an authenticated, URL-safe encoding of v.
+// The name (typically the cookie name) is bound to the value,
+// so a value encoded under one name cannot be decoded under another.
+func (c *Codec) Encode(name string, v Values) (string, error) {
+	k := &c.keys[0]
+	payload := make([]byte, timestampSize, timestampSize+64)
+	binary.BigEndian.PutUint64(payload, uint64(c.clock.Now().Unix()))
+	payload = append(payload, encodeValues(v)...)
+
+	var buf []byte
+	if c.encrypt {
+		header := []byte{k.version, flagEncrypted}
+		nonce := make([]byte, k.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", fmt.Errorf("flashkv: encode: %w", err)
+		}
+		buf = append(header, nonce...)
+		buf = k.aead.Seal(buf, nonce, payload, additionalData(header, name))
+	} else {
+		buf = append([]byte{k.version, 0}, payload...)
+		buf = append(buf, signature(k.mac, name, buf)...)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(buf)
+	if len(encoded) > c.maxSize {
+		return "", ErrTooLarge
+	}
+	return encoded, nil
+}
+
+// Decode verifies and decodes a string returned by [*Codec.Encode].
+// It returns [ErrInvalid] if the value was not produced
+// by Encode with one of the codec's keys and the same name,
+// or [ErrExpired] if the value is older than the codec's MaxAge.
+func (c *Codec) Decode(name string, s string) (Values, error) {
+	if len(s) > c.maxSize {
+		return nil, ErrInvalid
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(buf) < headerSize {
+		return nil, ErrInvalid
+	}
+	var k *derivedKey
+	for i := range c.keys {
+		if c.keys[i].version == buf[0] {
+			k = &c.keys[i]
+			break
+		}
+	}
+	if k == nil {
+		return nil, ErrInvalid
+	}
+	var payload []byte
+	switch flags := buf[1]; flags {
+	case 0:
+		if len(buf) < headerSize+timestampSize+sha256.Size {
+			return nil, ErrInvalid
+		}
+		signed, sig := buf[:len(buf)-sha256.Size], buf[len(buf)-sha256.Size:]
+		if !hmac.Equal(sig, signature(k.mac, name, signed)) {
+			return nil, ErrInvalid
+		}
+		payload = signed[headerSize:]
+	case flagEncrypted:
+		nonceSize := k.aead.NonceSize()
+		if len(buf) < headerSize+nonceSize {
+			return nil, ErrInvalid
+		}
+		nonce := buf[headerSize : headerSize+nonceSize]
+		payload, err = k.aead.Open(nil, nonce, buf[headerSize+nonceSize:], additionalData(buf[:headerSize], name))
+		if err != nil || len(payload) < timestampSize {
+			return nil, ErrInvalid
+		}
+	default:
+		return nil, ErrInvalid
+	}
+
+	if c.maxAge > 0 {
+		created := time.Unix(int64(binary.BigEndian.Uint64(payload)), 0)
+		if c.clock.Now().Sub(created) > c.maxAge {
+			return nil, ErrExpired
+		}
+	}
+	v, err := decodeValues(payload[timestampSize:])
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	return v, nil
+}
+
+func signature(key []byte, name string, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(additionalData(nil, name))
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// additionalData returns the header followed by the length-prefixed name.
+func additionalData(header []byte, name string) []byte {
+	ad := make([]byte, len(header)+binary.MaxVarintLen64, len(header)+binary.MaxVarintLen64+len(name))
+	copy(ad, header)
+	n := binary.PutUvarint(ad[len(header):], uint64(len(name)))
+	ad = ad[:len(header)+n]
+	return append(ad, name...)
+}
+
+func encodeValues(v Values) []byte {
+	q := make(url.Values, len(v))
+	for k, val := range v {
+		q.Set(k, val)
+	}
+	return []byte(q.Encode())
+}
+
+func decodeValues(data []byte) (Values, error) {
+	q, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, err
+	}
+	v := make(Values, len(q))
+	for k := range q {
+		v[k] = q.Get(k)
+	}
+	return v, nil
+}
+
+// A Store reads and writes [Values] in an HTTP cookie.
+type Store struct {
+	Codec *Codec
+	// Cookie is the template for cookies returned by [*Store.Save].
+	// Its Name must be set.
+	Cookie http.Cookie
+}
+
+// Load returns the values stored in the request's cookie.
+// If the cookie is not present, Load returns empty values and a nil error.
+func (s *Store) Load(r *http.Request) (Values, error) {
+	cookie, err := r.Cookie(s.Cookie.Name)
+	if errors.Is(err, http.ErrNoCookie) {
+		return make(Values), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.Codec.Decode(s.Cookie.Name, cookie.Value)
+}
+
+// Save returns a cookie that stores v.
+// If v is empty, then the returned cookie deletes the stored values.
+func (s *Store) Save(v Values) (*http.Cookie, error) {
+	cookie := new(http.Cookie)
+	*cookie = s.Cookie
+	if len(v) == 0 {
+		cookie.Value = ""
+		cookie.MaxAge = -1
+		cookie.Expires = time.Time{}
+		return cookie, nil
+	}
+	var err error
+	cookie.Value, err = s.Codec.Encode(s.Cookie.Name, v)
+	if err != nil {
+		return nil, err
+	}
+	return cookie, nil
+}
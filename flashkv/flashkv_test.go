@@ -0,0 +1,203 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flashkv
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"zombiezen.com/go/bass/clock"
+)
+
+func testKey(version uint8) Key {
+	return Key{
+		Version: version,
+		Secret:  []byte(strings.Repeat(string(rune('a'+version)), MinSecretSize)),
+	}
+}
+
+func TestCodec(t *testing.T) {
+	values := Values{"flash": "Saved!", "state": "xyzzy"}
+	for _, encrypt := range []bool{false, true} {
+		name := "Signed"
+		if encrypt {
+			name = "Encrypted"
+		}
+		t.Run(name, func(t *testing.T) {
+			c, err := NewCodec(&Options{Keys: []Key{testKey(1)}, Encrypt: encrypt})
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded, err := c.Encode("session", values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(encoded, "xyzzy"); got {
+				t.Errorf("Encode(...) = %q; contains plaintext", encoded)
+			}
+			got, err := c.Decode("session", encoded)
+			if err != nil {
+				t.Fatal("Decode:", err)
+			}
+			if diff := cmp.Diff(values, got); diff != "" {
+				t.Errorf("Decode(...) (-want +got):\n%s", diff)
+			}
+
+			if _, err := c.Decode("other", encoded); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Decode(\"other\", ...) error = %v; want %v", err, ErrInvalid)
+			}
+			tampered := []byte(encoded)
+			if tampered[5] == 'A' {
+				tampered[5] = 'B'
+			} else {
+				tampered[5] = 'A'
+			}
+			if _, err := c.Decode("session", string(tampered)); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Decode(tampered) error = %v; want %v", err, ErrInvalid)
+			}
+		})
+	}
+}
+
+func TestCodecRotation(t *testing.T) {
+	oldCodec, err := NewCodec(&Options{Keys: []Key{testKey(1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := oldCodec.Encode("session", Values{"x": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newCodec, err := NewCodec(&Options{Keys: []Key{testKey(2), testKey(1)}, Encrypt: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := newCodec.Decode("session", encoded); err != nil {
+		t.Errorf("Decode with rotated keys: %v", err)
+	} else if got["x"] != "1" {
+		t.Errorf("Decode with rotated keys = %v; want x=1", got)
+	}
+
+	retiredCodec, err := NewCodec(&Options{Keys: []Key{testKey(2)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := retiredCodec.Decode("session", encoded); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Decode with retired key error = %v; want %v", err, ErrInvalid)
+	}
+}
+
+func TestCodecMaxAge(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC))
+	c, err := NewCodec(&Options{Keys: []Key{testKey(1)}, MaxAge: time.Hour, Clock: clk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := c.Encode("state", Values{"x": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(30 * time.Minute)
+	if _, err := c.Decode("state", encoded); err != nil {
+		t.Errorf("Decode after 30m: %v", err)
+	}
+	clk.Advance(time.Hour)
+	if _, err := c.Decode("state", encoded); !errors.Is(err, ErrExpired) {
+		t.Errorf("Decode after 90m error = %v; want %v", err, ErrExpired)
+	}
+}
+
+func TestCodecMaxSize(t *testing.T) {
+	c, err := NewCodec(&Options{Keys: []Key{testKey(1)}, MaxSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Encode("x", Values{"big": strings.Repeat("x", 100)}); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Encode(big) error = %v; want %v", err, ErrTooLarge)
+	}
+}
+
+func TestNewCodecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *Options
+	}{
+		{"Nil", nil},
+		{"NoKeys", &Options{}},
+		{"ShortSecret", &Options{Keys: []Key{{Version: 1, Secret: []byte("short")}}}},
+		{"DuplicateVersion", &Options{Keys: []Key{testKey(1), testKey(1)}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewCodec(test.opts); err == nil {
+				t.Error("NewCodec(...) did not return an error")
+			}
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	c, err := NewCodec(&Options{Keys: []Key{testKey(1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Store{
+		Codec:  c,
+		Cookie: http.Cookie{Name: "flash", Path: "/", HttpOnly: true},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	got, err := s.Load(r)
+	if err != nil {
+		t.Fatal("Load without cookie:", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Load without cookie = %v; want empty", got)
+	}
+
+	want := Values{"msg": "Hello"}
+	cookie, err := s.Save(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cookie.Name != "flash" || cookie.Path != "/" || !cookie.HttpOnly {
+		t.Errorf("Save(...) = %v; want template attributes preserved", cookie)
+	}
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookie)
+	got, err = s.Load(r)
+	if err != nil {
+		t.Fatal("Load:", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load(...) (-want +got):\n%s", diff)
+	}
+
+	cookie, err = s.Save(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cookie.MaxAge >= 0 || cookie.Value != "" {
+		t.Errorf("Save(nil) = %v; want deletion cookie", cookie)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package geoip resolves the IP address of the client that made a request,
+// even when the request passed through one or more reverse proxies.
+//
+// Proxy headers are only believed when the connection comes from
+// an address in [Middleware.TrustedProxies],
+// since any client can send them.
+package geoip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Common headers that proxies use to report the client address.
+const (
+	// Forwarded is the standard header defined in RFC 7239.
+	Forwarded = "Forwarded"
+	// XForwardedFor is the de facto standard header
+	// containing a comma-separated list of addresses.
+	XForwardedFor = "X-Forwarded-For"
+	// CFConnectingIP is the header set by Cloudflare
+	// containing the single address that connected to Cloudflare.
+	CFConnectingIP = "CF-Connecting-IP"
+)
+
+// Middleware determines the client IP address of each request
+// and attaches it to the request context.
+type Middleware struct {
+	// TrustedProxies is the set of addresses whose proxy headers are believed.
+	// If empty, then proxy headers are ignored
+	// and the client address is always the connection's remote address.
+	TrustedProxies []netip.Prefix
+	// Headers is the list of headers to consult, in order of preference.
+	// The first header present in the request is used.
+	// Forwarded and X-Forwarded-For are parsed as lists of hops;
+	// any other header is expected to hold a single address.
+	// If nil, then Forwarded and X-Forwarded-For are used.
+	Headers []string
+}
+
+// Wrap returns a handler that attaches the client IP address
+// to the request context before calling next.
+// Handlers can retrieve it with [FromContext].
+func (m *Middleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ip := m.ClientIP(r); ip.IsValid() {
+			r = r.WithContext(NewContext(r.Context(), ip))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClientIP returns the IP address of the client that made r.
+// It returns the zero Addr if r.RemoteAddr is not an IP address.
+func (m *Middleware) ClientIP(r *http.Request) netip.Addr {
+	remote := parseAddr(r.RemoteAddr)
+	if !remote.IsValid() || !m.isTrusted(remote) {
+		return remote
+	}
+	headers := m.Headers
+	if headers == nil {
+		headers = []string{Forwarded, XForwardedFor}
+	}
+	for _, name := range headers {
+		values := r.Header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		var hops []string
+		switch http.CanonicalHeaderKey(name) {
+		case Forwarded:
+			hops = forwardedHops(values)
+		case XForwardedFor:
+			hops = listHops(values)
+		default:
+			hops = values[len(values)-1:]
+		}
+		if ip := m.walk(hops); ip.IsValid() {
+			return ip
+		}
+		return remote
+	}
+	return remote
+}
+
+// walk returns the rightmost address in hops that is not a trusted proxy.
+// If every hop is trusted, walk returns the leftmost.
+// walk returns the zero Addr if it encounters an unparseable hop.
+func (m *Middleware) walk(hops []string) netip.Addr {
+	var ip netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip = parseAddr(hops[i])
+		if !ip.IsValid() {
+			return netip.Addr{}
+		}
+		if !m.isTrusted(ip) {
+			return ip
+		}
+	}
+	return ip
+}
+
+func (m *Middleware) isTrusted(ip netip.Addr) bool {
+	for _, p := range m.TrustedProxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// listHops splits the comma-separated values of X-Forwarded-For.
+func listHops(values []string) []string {
+	var hops []string
+	for _, v := range values {
+		for _, hop := range strings.Split(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	return hops
+}
+
+// forwardedHops returns the "for" parameter of each element
+// of the Forwarded header values.
+// Elements without a "for" parameter are returned as empty strings.
+func forwardedHops(values []string) []string {
+	var hops []string
+	for _, elem := range listHops(values) {
+		var hop string
+		for _, pair := range strings.Split(elem, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(k), "for") {
+				hop = strings.Trim(strings.TrimSpace(v), `"`)
+				break
+			}
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+// parseAddr parses an IP address with an optional port,
+// like "192.0.2.1", "192.0.2.1:8080", "[2001:db8::1]:8080", or "2001:db8::1".
+// IPv4-mapped IPv6 addresses are converted to IPv4.
+func parseAddr(s string) netip.Addr {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	} else {
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	}
+	ip, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return ip.Unmap()
+}
+
+type contextKey struct{}
+
+// NewContext returns a new context with the given client IP address attached.
+func NewContext(parent context.Context, ip netip.Addr) context.Context {
+	return context.WithValue(parent, contextKey{}, ip)
+}
+
+// FromContext returns the client IP address attached to ctx by [Middleware].
+// If ctx does not have an address, FromContext returns the zero Addr.
+func FromContext(ctx context.Context) netip.Addr {
+	ip, _ := ctx.Value(contextKey{}).(netip.Addr)
+	return ip
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package geoip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8:ffff::/48"),
+	}
+	tests := []struct {
+		name       string
+		headers    []string
+		remoteAddr string
+		header     http.Header
+		want       string
+	}{
+		{
+			name:       "NoHeaders",
+			remoteAddr: "192.0.2.1:1234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "UntrustedRemote",
+			remoteAddr: "192.0.2.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.7"}},
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "XForwardedFor",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.7"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "XForwardedForSpoofed",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"203.0.113.9, 198.51.100.7, 10.1.2.3"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "XForwardedForMultipleLines",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.7", "10.1.2.3"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "XForwardedForAllTrusted",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"10.9.9.9, 10.1.2.3"}},
+			want:       "10.9.9.9",
+		},
+		{
+			name:       "XForwardedForGarbage",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"bork, 10.1.2.3"}},
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "Forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			header: http.Header{"Forwarded": {
+				`for=198.51.100.7;proto=https, For="[2001:db8:ffff::1]:4711"`,
+			}},
+			want: "198.51.100.7",
+		},
+		{
+			name:       "ForwardedIPv6",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"Forwarded": {`for="[2001:db8:cafe::17]:4711"`}},
+			want:       "2001:db8:cafe::17",
+		},
+		{
+			name:       "ForwardedPreferred",
+			remoteAddr: "10.0.0.1:1234",
+			header: http.Header{
+				"Forwarded":       {"for=198.51.100.7"},
+				"X-Forwarded-For": {"203.0.113.9"},
+			},
+			want: "198.51.100.7",
+		},
+		{
+			name:       "CFConnectingIP",
+			headers:    []string{CFConnectingIP},
+			remoteAddr: "10.0.0.1:1234",
+			header: http.Header{
+				"Cf-Connecting-Ip": {"198.51.100.7"},
+				"X-Forwarded-For":  {"203.0.113.9"},
+			},
+			want: "198.51.100.7",
+		},
+		{
+			name:       "IPv4MappedRemote",
+			remoteAddr: "[::ffff:192.0.2.1]:1234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "BadRemote",
+			remoteAddr: "pipe",
+			want:       "invalid IP",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &Middleware{
+				TrustedProxies: trusted,
+				Headers:        test.headers,
+			}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = test.remoteAddr
+			for k, v := range test.header {
+				r.Header[k] = v
+			}
+			if got := m.ClientIP(r); got.String() != test.want {
+				t.Errorf("ClientIP(...) = %v; want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	m := &Middleware{
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}
+	var got netip.Addr
+	h := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if want := netip.MustParseAddr("198.51.100.7"); got != want {
+		t.Errorf("FromContext(...) = %v; want %v", got, want)
+	}
+}
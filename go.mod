@@ -3,19 +3,19 @@ module zombiezen.com/go/bass
 go 1.18
 
 require (
-	github.com/google/go-cmp v0.3.1
-	github.com/gorilla/handlers v1.5.1
+	github.com/google/go-cmp v0.5.5
 	github.com/gorilla/mux v1.8.0
 	github.com/spf13/cobra v1.1.3
+	golang.org/x/mod v0.6.0-dev.0.20220419223038-86c51ed26bb4
 	golang.org/x/net v0.7.0
 	golang.org/x/sys v0.5.0
-	golang.org/x/tools v0.1.0
+	golang.org/x/text v0.7.0
+	golang.org/x/tools v0.1.12
+	google.golang.org/protobuf v1.33.0
 )
 
 require (
-	github.com/felixge/httpsnoop v1.0.1 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/mod v0.4.1 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 )
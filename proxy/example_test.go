@@ -0,0 +1,64 @@
+// Copyright 2021 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"zombiezen.com/go/bass/proxy"
+	"zombiezen.com/go/bass/runhttp"
+)
+
+func ExampleProxy() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	// Forward API requests to an internal service
+	// and everything else to the client development server,
+	// including its hot-reload WebSocket.
+	apiURL, _ := url.Parse("http://localhost:9000/")
+	devServerURL, _ := url.Parse("http://localhost:5173/")
+	p, err := proxy.New(
+		proxy.Route{
+			Prefix:      "/api",
+			Target:      apiURL,
+			StripPrefix: true,
+			Header:      http.Header{"X-Internal-Token": {os.Getenv("INTERNAL_TOKEN")}},
+			Timeout:     30 * time.Second,
+		},
+		proxy.Route{Prefix: "/", Target: devServerURL},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Close proxied WebSockets when the server shuts down,
+	// since http.Server.Shutdown does not wait for them.
+	srv := &http.Server{Addr: ":8080", Handler: p}
+	err = runhttp.Serve(ctx, srv, &runhttp.Options{
+		OnShutdown: p.Shutdown,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2021 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package proxy provides a reverse proxy that forwards requests
+// to upstream services by path prefix.
+// It is intended for fronting internal services
+// or a client development server from an application's HTTP server.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A Route forwards requests whose path begins with a prefix
+// to an upstream server.
+type Route struct {
+	// Prefix is the path prefix of the requests that the route handles.
+	// It must begin with a slash.
+	// A prefix matches whole path segments:
+	// "/api" matches "/api" and "/api/users" but not "/apix".
+	Prefix string
+	// Target is the base URL of the upstream server.
+	// Its path is prepended to the forwarded request's path
+	// and its query is combined with the request's query.
+	Target *url.URL
+	// If StripPrefix is true, Prefix is removed
+	// from the request's path before it is forwarded.
+	StripPrefix bool
+	// Header is a set of headers to set on forwarded requests,
+	// replacing any values sent by the client.
+	Header http.Header
+	// If PreserveHost is true, the forwarded request
+	// keeps the client's Host header.
+	// Otherwise, the Host header is set to Target's host.
+	PreserveHost bool
+	// If Timeout is greater than zero,
+	// it is the maximum time to wait for the upstream server
+	// to finish responding.
+	// Requests that exceed it are served a 504 (Gateway Timeout) error.
+	// It does not apply to upgraded connections like WebSockets.
+	Timeout time.Duration
+}
+
+// A Proxy is an [http.Handler] that forwards requests according to its routes.
+// Requests that do not match any route are served a 404 (Not Found) error.
+// Connection upgrades, such as WebSockets, are passed through.
+type Proxy struct {
+	routes      []*route
+	transport   http.RoundTripper
+	reportError func(context.Context, error)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type route struct {
+	Route
+	rp *httputil.ReverseProxy
+}
+
+// New returns a new [Proxy] with the given routes.
+// When more than one route matches a request,
+// the route with the longest prefix is used.
+func New(routes ...Route) (*Proxy, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Proxy{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for i := range routes {
+		rt := &route{Route: routes[i]}
+		if !strings.HasPrefix(rt.Prefix, "/") {
+			return nil, fmt.Errorf("new proxy: route prefix %q does not begin with a slash", rt.Prefix)
+		}
+		if rt.Target == nil || rt.Target.Scheme == "" || rt.Target.Host == "" {
+			return nil, fmt.Errorf("new proxy: route %s: target must be an absolute URL", rt.Prefix)
+		}
+		rt.rp = &httputil.ReverseProxy{
+			Director:     rt.direct,
+			Transport:    transportFunc(p.roundTrip),
+			ErrorHandler: p.serveError,
+		}
+		p.routes = append(p.routes, rt)
+	}
+	sort.SliceStable(p.routes, func(i, j int) bool {
+		return len(p.routes[i].Prefix) > len(p.routes[j].Prefix)
+	})
+	return p, nil
+}
+
+// SetTransport sets the transport used to send requests to upstream servers.
+// By default, a Proxy uses [http.DefaultTransport].
+//
+// SetTransport must not be called concurrently with ServeHTTP.
+func (p *Proxy) SetTransport(rt http.RoundTripper) {
+	p.transport = rt
+}
+
+// SetReportError sets a callback for errors
+// that occur while forwarding requests.
+// A nil function removes the callback.
+//
+// SetReportError must not be called concurrently with ServeHTTP.
+func (p *Proxy) SetReportError(f func(context.Context, error)) {
+	p.reportError = f
+}
+
+// ServeHTTP forwards the request to the upstream server of the matching route.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt := p.match(r.URL.Path)
+	if rt == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if isUpgrade(r.Header) {
+		// Upgraded connections are hijacked,
+		// so http.Server.Shutdown does not wait for them or close them.
+		// Tie them to the Proxy's lifetime instead.
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			select {
+			case <-p.ctx.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		rt.rp.ServeHTTP(w, r.WithContext(ctx))
+		return
+	}
+	if rt.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), rt.Timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+	rt.rp.ServeHTTP(w, r)
+}
+
+// Shutdown closes any upgraded connections that the Proxy is passing through
+// and closes idle connections to upstream servers.
+// Requests that are not upgraded are unaffected,
+// so that the server can drain them.
+// Its signature matches the runhttp package's Options.OnShutdown
+// so that it can be used directly as that callback.
+// After Shutdown, new upgraded connections are closed immediately.
+func (p *Proxy) Shutdown(ctx context.Context) {
+	p.cancel()
+	if ci, ok := p.roundTripper().(interface{ CloseIdleConnections() }); ok {
+		ci.CloseIdleConnections()
+	}
+}
+
+func (p *Proxy) match(path string) *route {
+	for _, rt := range p.routes {
+		if matchPrefix(rt.Prefix, path) {
+			return rt
+		}
+	}
+	return nil
+}
+
+// matchPrefix reports whether prefix matches path
+// on a path segment boundary.
+func matchPrefix(prefix, path string) bool {
+	if strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// direct rewrites an outgoing request for the route.
+func (rt *route) direct(req *http.Request) {
+	path := req.URL.EscapedPath()
+	if rt.StripPrefix {
+		path = "/" + strings.TrimPrefix(path[len(strings.TrimSuffix(rt.Prefix, "/")):], "/")
+	}
+	path = joinPaths(rt.Target.EscapedPath(), path)
+
+	req.URL.Scheme = rt.Target.Scheme
+	req.URL.Host = rt.Target.Host
+	req.URL.RawPath = path
+	req.URL.Path, _ = url.PathUnescape(path)
+	switch {
+	case rt.Target.RawQuery == "":
+	case req.URL.RawQuery == "":
+		req.URL.RawQuery = rt.Target.RawQuery
+	default:
+		req.URL.RawQuery = rt.Target.RawQuery + "&" + req.URL.RawQuery
+	}
+
+	req.Header.Set("X-Forwarded-Host", req.Host)
+	if req.TLS != nil {
+		req.Header.Set("X-Forwarded-Proto", "https")
+	} else {
+		req.Header.Set("X-Forwarded-Proto", "http")
+	}
+	if !rt.PreserveHost {
+		req.Host = rt.Target.Host
+	}
+	for k, v := range rt.Header {
+		req.Header[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+	}
+	if _, ok := req.Header["User-Agent"]; !ok {
+		// Prevent the default Go User-Agent from being sent.
+		req.Header.Set("User-Agent", "")
+	}
+}
+
+// joinPaths joins a target's path and a request path with a single slash.
+func joinPaths(a, b string) string {
+	switch aslash, bslash := strings.HasSuffix(a, "/"), strings.HasPrefix(b, "/"); {
+	case a == "":
+		return b
+	case aslash && bslash:
+		return a + b[1:]
+	case !aslash && !bslash:
+		return a + "/" + b
+	default:
+		return a + b
+	}
+}
+
+func (p *Proxy) roundTripper() http.RoundTripper {
+	if p.transport == nil {
+		return http.DefaultTransport
+	}
+	return p.transport
+}
+
+func (p *Proxy) roundTrip(req *http.Request) (*http.Response, error) {
+	return p.roundTripper().RoundTrip(req)
+}
+
+func (p *Proxy) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, context.Canceled) && r.Context().Err() != nil {
+		// The client went away or the Proxy was shut down.
+		return
+	}
+	if p.reportError != nil {
+		p.reportError(r.Context(), fmt.Errorf("proxy %s: %w", r.URL.Redacted(), err))
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		http.Error(w, "upstream server timed out", http.StatusGatewayTimeout)
+		return
+	}
+	http.Error(w, "upstream server unavailable", http.StatusBadGateway)
+}
+
+// isUpgrade reports whether a request header asks for a protocol upgrade.
+func isUpgrade(h http.Header) bool {
+	if h.Get("Upgrade") == "" {
+		return false
+	}
+	for _, v := range h.Values("Connection") {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type transportFunc func(*http.Request) (*http.Response, error)
+
+func (f transportFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
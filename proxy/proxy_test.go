@@ -0,0 +1,202 @@
+// Copyright 2021 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "path=%s query=%s host=%s token=%s fwd=%s",
+			r.URL.EscapedPath(), r.URL.RawQuery, r.Host, r.Header.Get("X-Token"), r.Header.Get("X-Forwarded-Host"))
+	}))
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := *target
+	base.Path = "/v1"
+	base.RawQuery = "key=abc"
+	p, err := New(
+		Route{Prefix: "/", Target: target, PreserveHost: true},
+		Route{
+			Prefix:      "/api",
+			Target:      &base,
+			StripPrefix: true,
+			Header:      http.Header{"X-Token": {"secret"}},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{
+			target: "http://example.com/api/users?page=2",
+			want:   "path=/v1/users query=key=abc&page=2 host=" + target.Host + " token=secret fwd=example.com",
+		},
+		{
+			target: "http://example.com/api",
+			want:   "path=/v1/ query=key=abc host=" + target.Host + " token=secret fwd=example.com",
+		},
+		{
+			target: "http://example.com/apix/a%2Fb",
+			want:   "path=/apix/a%2Fb query= host=example.com token=from-client fwd=example.com",
+		},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.target, nil)
+		req.Header.Set("X-Token", "from-client")
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d; want %d", test.target, rec.Code, http.StatusOK)
+		}
+		if got := rec.Body.String(); got != test.want {
+			t.Errorf("GET %s body = %q; want %q", test.target, got, test.want)
+		}
+	}
+}
+
+func TestProxyNoRoute(t *testing.T) {
+	target, _ := url.Parse("http://127.0.0.1:1")
+	p, err := New(Route{Prefix: "/api/", Target: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d; want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestProxyInvalidRoutes(t *testing.T) {
+	target, _ := url.Parse("http://localhost:8080")
+	for _, rt := range []Route{
+		{Prefix: "api", Target: target},
+		{Prefix: "/api"},
+		{Prefix: "/api", Target: &url.URL{Path: "/relative"}},
+	} {
+		if _, err := New(rt); err == nil {
+			t.Errorf("New(%+v) did not return an error", rt)
+		}
+	}
+}
+
+func TestProxyTimeout(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer upstream.Close()
+	defer close(release)
+	target, _ := url.Parse(upstream.URL)
+	p, err := New(Route{Prefix: "/", Target: target, Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reported error
+	p.SetReportError(func(ctx context.Context, err error) {
+		reported = err
+	})
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d; want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+	if reported == nil {
+		t.Error("error not reported")
+	}
+}
+
+func TestProxyUpgrade(t *testing.T) {
+	// The upstream speaks a line-based echo protocol after upgrading.
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "echo" {
+			http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		brw.Flush()
+		for {
+			line, err := brw.ReadString('\n')
+			if err != nil {
+				return
+			}
+			brw.WriteString(line)
+			brw.Flush()
+		}
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+	p, err := New(Route{Prefix: "/ws", Target: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	front := httptest.NewServer(p)
+	defer front.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(front.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d; want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	io.WriteString(conn, "hello\n")
+	if line, err := br.ReadString('\n'); err != nil || line != "hello\n" {
+		t.Fatalf("echo = %q, %v; want %q, <nil>", line, err, "hello\n")
+	}
+
+	p.Shutdown(context.Background())
+	if _, err := br.ReadString('\n'); err == nil {
+		t.Error("connection still open after Shutdown")
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package robots
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"zombiezen.com/go/bass/clock"
+)
+
+// A RateLimit allows a number of requests per period from a single address.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Middleware classifies requests and attaches the [Class] to the request context.
+type Middleware struct {
+	// Classifier determines the class of each request.
+	// If nil, a zero Classifier is used.
+	Classifier *Classifier
+	// RateLimits maps classes to their rate limit.
+	// Classes without an entry are not limited.
+	// Requests over the limit receive a 429 (Too Many Requests) response.
+	RateLimits map[Class]RateLimit
+	// Clock is the source of the current time for rate limiting.
+	// If it is nil, then [clock.Real] is used.
+	Clock clock.Clock
+}
+
+// maxBuckets is the number of addresses a rate limiter tracks
+// before discarding idle entries.
+const maxBuckets = 10000
+
+// Wrap returns a handler that classifies requests before calling next.
+func (m *Middleware) Wrap(next http.Handler) http.Handler {
+	h := &handler{
+		classifier: m.Classifier,
+		next:       next,
+		limiters:   make(map[Class]*limiter),
+		clock:      clock.Or(m.Clock),
+	}
+	if h.classifier == nil {
+		h.classifier = new(Classifier)
+	}
+	for class, limit := range m.RateLimits {
+		h.limiters[class] = &limiter{
+			limit:   limit,
+			buckets: make(map[string]*bucket),
+		}
+	}
+	return h
+}
+
+type handler struct {
+	classifier *Classifier
+	next       http.Handler
+	limiters   map[Class]*limiter
+	clock      clock.Clock
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	class := h.classifier.Classify(r)
+	if l := h.limiters[class]; l != nil {
+		if wait, ok := l.allow(remoteIP(r), h.clock.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+	}
+	h.next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), class)))
+}
+
+// limiter is a set of token buckets keyed by address.
+type limiter struct {
+	limit RateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow reports whether a request from the given address is permitted.
+// If not, it returns the time until the next request will be permitted.
+func (l *limiter) allow(addr string, now time.Time) (time.Duration, bool) {
+	if l.limit.Requests <= 0 || l.limit.Per <= 0 {
+		return l.limit.Per, false
+	}
+	capacity := float64(l.limit.Requests)
+	rate := capacity / float64(l.limit.Per)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.buckets[addr]
+	if b == nil {
+		if len(l.buckets) >= maxBuckets {
+			l.prune(now, capacity, rate)
+		}
+		b = &bucket{tokens: capacity, last: now}
+		l.buckets[addr] = b
+	}
+	b.tokens += rate * float64(now.Sub(b.last))
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / rate), false
+	}
+	b.tokens--
+	return 0, true
+}
+
+// prune removes buckets that have refilled completely,
+// since they are equivalent to new buckets.
+// If none have, it removes all buckets.
+// The caller must hold l.mu.
+func (l *limiter) prune(now time.Time, capacity, rate float64) {
+	for addr, b := range l.buckets {
+		if b.tokens+rate*float64(now.Sub(b.last)) >= capacity {
+			delete(l.buckets, addr)
+		}
+	}
+	if len(l.buckets) >= maxBuckets {
+		l.buckets = make(map[string]*bucket)
+	}
+}
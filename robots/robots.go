@@ -0,0 +1,323 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package robots provides HTTP middleware that classifies requests
+// as coming from humans, search engine bots, or other crawlers.
+// Handlers can use [FromContext] to skip expensive work for bots,
+// and [Middleware] can apply separate rate limits to each class.
+package robots
+
+import (
+	"container/list"
+	"context"
+	"html/template"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"zombiezen.com/go/bass/geoip"
+)
+
+// Class is a category of HTTP client.
+type Class int
+
+// Request classes.
+const (
+	// Human is a client that does not identify itself as a bot.
+	Human Class = iota
+	// Crawler is an automated client that is not a verified search bot.
+	// Clients that claim to be a search bot but fail verification
+	// are classified as crawlers.
+	Crawler
+	// SearchBot is a search engine crawler
+	// whose address has been verified with DNS.
+	SearchBot
+)
+
+// String returns the lowercase name of the class.
+func (c Class) String() string {
+	switch c {
+	case Human:
+		return "human"
+	case Crawler:
+		return "crawler"
+	case SearchBot:
+		return "searchbot"
+	default:
+		return "unknown"
+	}
+}
+
+// IsBot reports whether c is an automated client.
+func (c Class) IsBot() bool {
+	return c == Crawler || c == SearchBot
+}
+
+// A SearchBotRule identifies a search engine crawler.
+type SearchBotRule struct {
+	// Token is a case-insensitive substring of the User-Agent header
+	// that the bot sends.
+	Token string
+	// Domains is the list of domains that the bot's
+	// reverse DNS name must be in.
+	Domains []string
+}
+
+// DefaultSearchBots is the list of search bots used
+// when [Classifier.SearchBots] is nil.
+var DefaultSearchBots = []SearchBotRule{
+	{Token: "Googlebot", Domains: []string{"googlebot.com", "google.com", "googleusercontent.com"}},
+	{Token: "bingbot", Domains: []string{"search.msn.com"}},
+	{Token: "DuckDuckBot", Domains: []string{"duckduckgo.com"}},
+	{Token: "Applebot", Domains: []string{"applebot.apple.com"}},
+	{Token: "YandexBot", Domains: []string{"yandex.ru", "yandex.net", "yandex.com"}},
+	{Token: "Baiduspider", Domains: []string{"baidu.com", "baidu.jp"}},
+}
+
+// defaultCrawlerTokens are lowercase User-Agent substrings
+// that indicate an automated client.
+var defaultCrawlerTokens = []string{
+	"bot",
+	"crawl",
+	"spider",
+	"slurp",
+	"curl/",
+	"wget/",
+	"python-requests",
+	"go-http-client",
+	"headlesschrome",
+	"httpclient",
+}
+
+// A Resolver performs the DNS lookups used to verify search bots.
+// [*net.Resolver] implements Resolver.
+type Resolver interface {
+	LookupAddr(ctx context.Context, addr string) (names []string, err error)
+	LookupHost(ctx context.Context, host string) (addrs []string, err error)
+}
+
+// maxVerifyCacheSize is the number of verification results
+// a [Classifier] keeps.
+// Once it is full, the least recently used result is discarded.
+const maxVerifyCacheSize = 4096
+
+// DefaultLookupTimeout is the default time limit
+// for the DNS lookups that verify a search bot.
+const DefaultLookupTimeout = 2 * time.Second
+
+// A Classifier determines the [Class] of HTTP requests.
+// The zero value uses [DefaultSearchBots] and [net.DefaultResolver].
+// A Classifier is safe to use from multiple goroutines
+// and must not be copied after first use.
+type Classifier struct {
+	// SearchBots is the list of search bots to recognize.
+	// If nil, DefaultSearchBots is used.
+	SearchBots []SearchBotRule
+	// Resolver is used to verify search bots.
+	// If nil, net.DefaultResolver is used.
+	Resolver Resolver
+	// LookupTimeout limits the time spent verifying a search bot.
+	// A request whose lookups do not finish in time is classified as a Crawler.
+	// If zero, DefaultLookupTimeout is used.
+	LookupTimeout time.Duration
+
+	mu       sync.Mutex
+	verified map[verifyKey]*list.Element
+	lru      list.List // of *verifyEntry, most recently used first
+}
+
+type verifyKey struct {
+	ip    string
+	token string
+}
+
+type verifyEntry struct {
+	key verifyKey
+	ok  bool
+}
+
+// Classify returns the class of r.
+// Requests that claim to be a search bot
+// are verified with a reverse DNS lookup of the remote address
+// followed by a forward lookup of the resulting name.
+// The most recently used results are cached.
+func (c *Classifier) Classify(r *http.Request) Class {
+	ua := r.Header.Get("User-Agent")
+	if ua == "" {
+		return Crawler
+	}
+	rules := c.SearchBots
+	if rules == nil {
+		rules = DefaultSearchBots
+	}
+	for i := range rules {
+		rule := &rules[i]
+		if !containsFold(ua, rule.Token) {
+			continue
+		}
+		if c.verify(r.Context(), remoteIP(r), rule) {
+			return SearchBot
+		}
+		return Crawler
+	}
+	lowerUA := strings.ToLower(ua)
+	for _, token := range defaultCrawlerTokens {
+		if strings.Contains(lowerUA, token) {
+			return Crawler
+		}
+	}
+	return Human
+}
+
+func (c *Classifier) verify(ctx context.Context, ip string, rule *SearchBotRule) bool {
+	if ip == "" {
+		return false
+	}
+	key := verifyKey{ip: ip, token: rule.Token}
+	c.mu.Lock()
+	elem := c.verified[key]
+	if elem != nil {
+		c.lru.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+	if elem != nil {
+		return elem.Value.(*verifyEntry).ok
+	}
+
+	resolver := c.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	timeout := c.LookupTimeout
+	if timeout == 0 {
+		timeout = DefaultLookupTimeout
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, timeout)
+	ok := verifyAddr(lookupCtx, resolver, ip, rule.Domains)
+	lookupErr := lookupCtx.Err()
+	cancel()
+	if lookupErr != nil {
+		// Don't cache a result caused by the request ending
+		// or a slow DNS server.
+		return ok
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem := c.verified[key]; elem != nil {
+		c.lru.MoveToFront(elem)
+		return ok
+	}
+	if c.verified == nil {
+		c.verified = make(map[verifyKey]*list.Element)
+	}
+	c.verified[key] = c.lru.PushFront(&verifyEntry{key: key, ok: ok})
+	if c.lru.Len() > maxVerifyCacheSize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.verified, oldest.Value.(*verifyEntry).key)
+	}
+	return ok
+}
+
+func verifyAddr(ctx context.Context, resolver Resolver, ip string, domains []string) bool {
+	names, err := resolver.LookupAddr(ctx, ip)
+	if err != nil {
+		return false
+	}
+	for _, name := range names {
+		name = strings.TrimSuffix(name, ".")
+		if !inDomains(name, domains) {
+			continue
+		}
+		addrs, err := resolver.LookupHost(ctx, name)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if addr == ip {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func inDomains(name string, domains []string) bool {
+	for _, domain := range domains {
+		if strings.EqualFold(name, domain) ||
+			len(name) > len(domain) && name[len(name)-len(domain)-1] == '.' && strings.EqualFold(name[len(name)-len(domain):], domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(s, substr string) bool {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns the client IP address attached by [geoip.Middleware]
+// or the IP address portion of r.RemoteAddr if there is none.
+func remoteIP(r *http.Request) string {
+	if ip := geoip.FromContext(r.Context()); ip.IsValid() {
+		return ip.String()
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if net.ParseIP(host) == nil {
+		return ""
+	}
+	return host
+}
+
+type contextKey struct{}
+
+// NewContext returns a new context with the given class attached.
+func NewContext(parent context.Context, class Class) context.Context {
+	return context.WithValue(parent, contextKey{}, class)
+}
+
+// FromContext returns the class attached to ctx by [Middleware].
+// If ctx does not have a class, FromContext returns [Human].
+func FromContext(ctx context.Context) Class {
+	class, _ := ctx.Value(contextKey{}).(Class)
+	return class
+}
+
+// TemplateFuncs returns template functions
+// that report the class stored in ctx:
+//
+//	robotClass returns the Class.
+//	isBot reports whether the Class is a bot.
+//
+// TemplateFuncs can be used to implement
+// the MakeRequestTemplateFuncs field of an action.Config.
+func TemplateFuncs(ctx context.Context) template.FuncMap {
+	class := FromContext(ctx)
+	return template.FuncMap{
+		"robotClass": func() Class { return class },
+		"isBot":      class.IsBot,
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package robots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"zombiezen.com/go/bass/clock"
+)
+
+type fakeResolver struct {
+	addrs map[string][]string
+	hosts map[string][]string
+	calls int
+}
+
+func (r *fakeResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	r.calls++
+	names, ok := r.addrs[addr]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return names, nil
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, ok := r.hosts[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+const googlebotUA = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+
+func TestClassify(t *testing.T) {
+	resolver := &fakeResolver{
+		addrs: map[string][]string{
+			"66.249.66.1": {"crawl-66-249-66-1.googlebot.com."},
+			"192.0.2.1":   {"evil.example.com."},
+			"192.0.2.2":   {"crawl-fake.googlebot.com."},
+		},
+		hosts: map[string][]string{
+			"crawl-66-249-66-1.googlebot.com": {"66.249.66.1"},
+			"evil.example.com":                {"192.0.2.1"},
+			"crawl-fake.googlebot.com":        {"66.249.66.99"},
+		},
+	}
+	tests := []struct {
+		userAgent  string
+		remoteAddr string
+		want       Class
+	}{
+		{"Mozilla/5.0 (X11; Linux x86_64) Firefox/120.0", "192.0.2.1:1234", Human},
+		{"", "192.0.2.1:1234", Crawler},
+		{"curl/8.0.1", "192.0.2.1:1234", Crawler},
+		{"Mozilla/5.0 (compatible; AhrefsBot/7.0)", "192.0.2.1:1234", Crawler},
+		{googlebotUA, "66.249.66.1:1234", SearchBot},
+		{googlebotUA, "192.0.2.1:1234", Crawler},
+		{googlebotUA, "192.0.2.2:1234", Crawler},
+		{googlebotUA, "203.0.113.1:1234", Crawler},
+	}
+	c := &Classifier{Resolver: resolver}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = test.remoteAddr
+		r.Header.Set("User-Agent", test.userAgent)
+		if got := c.Classify(r); got != test.want {
+			t.Errorf("Classify(User-Agent=%q, RemoteAddr=%q) = %v; want %v", test.userAgent, test.remoteAddr, got, test.want)
+		}
+	}
+
+	t.Run("Cache", func(t *testing.T) {
+		resolver.calls = 0
+		for i := 0; i < 3; i++ {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = "66.249.66.1:1234"
+			r.Header.Set("User-Agent", googlebotUA)
+			c.Classify(r)
+		}
+		if resolver.calls != 0 {
+			t.Errorf("LookupAddr called %d times for cached address; want 0", resolver.calls)
+		}
+	})
+}
+
+func TestClassifyCacheEviction(t *testing.T) {
+	resolver := new(fakeResolver)
+	c := &Classifier{Resolver: resolver}
+	classify := func(i int) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = fmt.Sprintf("10.%d.%d.%d:1234", i>>16&0xff, i>>8&0xff, i&0xff)
+		r.Header.Set("User-Agent", googlebotUA)
+		c.Classify(r)
+	}
+	for i := 0; i < maxVerifyCacheSize; i++ {
+		classify(i)
+	}
+	// Use the oldest entry so that the second-oldest is evicted instead.
+	classify(0)
+	classify(maxVerifyCacheSize)
+
+	resolver.calls = 0
+	classify(0)
+	classify(2)
+	if resolver.calls != 0 {
+		t.Errorf("LookupAddr called %d times for recently used addresses; want 0", resolver.calls)
+	}
+	classify(1)
+	if resolver.calls != 1 {
+		t.Errorf("LookupAddr called %d times for evicted address; want 1", resolver.calls)
+	}
+}
+
+// slowResolver is a [Resolver] whose lookups block until the context is done.
+type slowResolver struct {
+	calls int
+}
+
+func (r *slowResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	r.calls++
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (r *slowResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestClassifyLookupTimeout(t *testing.T) {
+	resolver := new(slowResolver)
+	c := &Classifier{
+		Resolver:      resolver,
+		LookupTimeout: 10 * time.Millisecond,
+	}
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = "66.249.66.1:1234"
+		r.Header.Set("User-Agent", googlebotUA)
+		if got := c.Classify(r); got != Crawler {
+			t.Errorf("Classify(...) with slow DNS = %v; want %v", got, Crawler)
+		}
+	}
+	if resolver.calls != 2 {
+		t.Errorf("LookupAddr called %d times; want 2 (timeouts are not cached)", resolver.calls)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))
+	m := &Middleware{
+		RateLimits: map[Class]RateLimit{
+			Crawler: {Requests: 2, Per: time.Minute},
+		},
+		Clock: clk,
+	}
+	var gotClass Class
+	h := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotClass = FromContext(r.Context())
+	}))
+
+	do := func(userAgent string) int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		r.Header.Set("User-Agent", userAgent)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	for i := 0; i < 2; i++ {
+		if got := do("curl/8.0.1"); got != http.StatusOK {
+			t.Fatalf("crawler request #%d status = %d; want %d", i+1, got, http.StatusOK)
+		}
+		if gotClass != Crawler {
+			t.Errorf("FromContext(...) = %v; want %v", gotClass, Crawler)
+		}
+	}
+	if got := do("curl/8.0.1"); got != http.StatusTooManyRequests {
+		t.Errorf("crawler request #3 status = %d; want %d", got, http.StatusTooManyRequests)
+	}
+	if got := do("Mozilla/5.0 Firefox/120.0"); got != http.StatusOK {
+		t.Errorf("human request status = %d; want %d", got, http.StatusOK)
+	}
+	if gotClass != Human {
+		t.Errorf("FromContext(...) = %v; want %v", gotClass, Human)
+	}
+	clk.Advance(30 * time.Second)
+	if got := do("curl/8.0.1"); got != http.StatusOK {
+		t.Errorf("crawler request after 30s status = %d; want %d", got, http.StatusOK)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package runhttp
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// defaultDrainProgressInterval is the default value of
+// [Options.DrainProgressInterval].
+const defaultDrainProgressInterval = 1 * time.Second
+
+// An InflightCounter counts the requests that a server is handling.
+// The zero value is a counter with no requests.
+// Pass an InflightCounter in [Options] to have [Serve] maintain it.
+type InflightCounter struct {
+	n int64 // accessed atomically
+}
+
+// Count returns the number of requests being handled.
+// It is safe to call Count from multiple goroutines.
+func (c *InflightCounter) Count() int64 {
+	return atomic.LoadInt64(&c.n)
+}
+
+// add adds delta to the number of requests being handled.
+func (c *InflightCounter) add(delta int64) {
+	atomic.AddInt64(&c.n, delta)
+}
+
+// reportDrainProgress calls f with the number of in-flight requests
+// immediately and then every interval until the returned function is called.
+// The returned function calls f a final time before returning.
+func reportDrainProgress(ctx context.Context, c *InflightCounter, interval time.Duration, f func(context.Context, int64)) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		f(ctx, c.Count())
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				f(ctx, c.Count())
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+		f(ctx, c.Count())
+	}
+}
@@ -54,6 +54,11 @@ func ExampleServe() {
 		OnShutdown: func(ctx context.Context) {
 			log.Printf("Shutting down...")
 		},
+		OnDrainProgress: func(ctx context.Context, inflight int64) {
+			if inflight > 0 {
+				log.Printf("Waiting for %d requests to finish...", inflight)
+			}
+		},
 		OnShutdownError: func(ctx context.Context, err error) {
 			log.Printf("During shutdown: %v", err)
 		},
@@ -0,0 +1,154 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package runhttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// saturatedResponse is sent to connections rejected by a limitListener.
+const saturatedResponse = "HTTP/1.1 503 Service Unavailable\r\n" +
+	"Connection: close\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Length: 16\r\n" +
+	"Retry-After: 1\r\n" +
+	"\r\n" +
+	"server is busy\r\n"
+
+// rejectTimeout is the maximum time spent writing saturatedResponse.
+const rejectTimeout = 5 * time.Second
+
+// limitListener is a [net.Listener] that limits the number of open connections.
+// Slots are released through the server's ConnState hook
+// rather than by wrapping each [net.Conn],
+// so that the server still sees the listener's concrete connection types
+// (for example, *tls.Conn).
+type limitListener struct {
+	net.Listener
+	sem    chan struct{}
+	reject bool
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// limitConnections returns listeners that together accept
+// at most n connections at once
+// and the semaphore that tracks their connections.
+// The server must release a connection's slot with
+// [*serverHooks.releaseConnSlot] when it closes the connection.
+func limitConnections(ls []net.Listener, n int, reject bool) ([]net.Listener, chan struct{}) {
+	sem := make(chan struct{}, n)
+	limited := make([]net.Listener, 0, len(ls))
+	for _, l := range ls {
+		limited = append(limited, &limitListener{
+			Listener: l,
+			sem:      sem,
+			reject:   reject,
+			done:     make(chan struct{}),
+		})
+	}
+	return limited, sem
+}
+
+// releaseConnSlot releases a connection's slot in h.connSlots
+// when the server transitions the connection to the given state,
+// if the state means the server is done with the connection.
+func (h *serverHooks) releaseConnSlot(state http.ConnState) {
+	if h.connSlots != nil && (state == http.StateClosed || state == http.StateHijacked) {
+		<-h.connSlots
+	}
+}
+
+func (ll *limitListener) Accept() (net.Conn, error) {
+	for {
+		if ll.reject {
+			select {
+			case ll.sem <- struct{}{}:
+			default:
+				c, err := ll.Listener.Accept()
+				if err != nil {
+					return nil, err
+				}
+				go rejectConn(c)
+				continue
+			}
+		} else {
+			select {
+			case ll.sem <- struct{}{}:
+			case <-ll.done:
+				return nil, net.ErrClosed
+			}
+		}
+		c, err := ll.Listener.Accept()
+		if err != nil {
+			<-ll.sem
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+func (ll *limitListener) Close() error {
+	ll.closeOnce.Do(func() { close(ll.done) })
+	return ll.Listener.Close()
+}
+
+func rejectConn(c net.Conn) {
+	c.SetWriteDeadline(time.Now().Add(rejectTimeout))
+	c.Write([]byte(saturatedResponse))
+	c.Close()
+}
+
+type connInfoContextKey struct{}
+
+// connInfo is the per-connection state used by limitKeepAlive.
+type connInfo struct {
+	start    time.Time
+	requests int64 // accessed atomically
+}
+
+// trackConn returns a copy of a new connection's context
+// that records the state needed by limitKeepAlive,
+// if h limits keep-alive connections.
+func (h *serverHooks) trackConn(ctx context.Context) context.Context {
+	if h.maxRequestsPerConn <= 0 && h.maxConnAge <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, connInfoContextKey{}, &connInfo{start: time.Now()})
+}
+
+// limitKeepAlive asks the server to close r's connection after the response
+// if the connection has served h.maxRequestsPerConn requests
+// or is older than h.maxConnAge.
+func (h *serverHooks) limitKeepAlive(w http.ResponseWriter, r *http.Request) {
+	info, _ := r.Context().Value(connInfoContextKey{}).(*connInfo)
+	if info == nil {
+		return
+	}
+	n := atomic.AddInt64(&info.requests, 1)
+	if h.maxRequestsPerConn > 0 && n >= int64(h.maxRequestsPerConn) || h.maxConnAge > 0 && time.Since(info.start) >= h.maxConnAge {
+		// The server closes the connection after the response.
+		// For HTTP/2, this triggers a graceful shutdown of the connection.
+		w.Header().Set("Connection", "close")
+	}
+}
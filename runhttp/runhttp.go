@@ -22,6 +22,7 @@ import (
 	"errors"
 	"net"
 	"net/http"
+	"time"
 )
 
 // Options holds the optional arguments to [Serve].
@@ -29,24 +30,72 @@ type Options struct {
 	// Listener will be used if non-nil to serve on.
 	// Otherwise, the [*http.Server.Addr] will be used to listen for TCP connections.
 	Listener net.Listener
-	// OnStartup will be called after the listener is ready,
+	// AdditionalListeners are served at the same time as Listener
+	// (or the listener for [*http.Server.Addr]) with the same handler,
+	// like a Unix socket for local administration alongside a public TCP port.
+	// They are shut down along with the server,
+	// and if serving on any listener fails,
+	// the server is closed and Serve returns the error.
+	// Serve closes all the listeners before returning.
+	AdditionalListeners []net.Listener
+	// OnStartup will be called after the listeners are ready,
 	// but before serving starts.
+	// It is called once for each listener.
 	OnStartup func(context.Context, net.Addr)
 	// OnShutdown will be called after the Context is Done,
 	// but before [*http.Server.Shutdown] starts.
 	OnShutdown func(context.Context)
 	// OnShutdownError will be called if [*http.Server.Shutdown] returns a non-nil error.
 	OnShutdownError func(context.Context, error)
+
+	// If MaxConnections is greater than zero,
+	// then the server will have at most that many connections open at once.
+	// By default, connections beyond the limit wait
+	// in the operating system's accept queue until a connection closes.
+	MaxConnections int
+	// If RejectWhenSaturated is true and MaxConnections is reached,
+	// then new connections are accepted, sent a 503 (Service Unavailable) response,
+	// and closed instead of waiting.
+	// This gives clients fast feedback during a connection flood
+	// at the cost of accepting every incoming connection.
+	RejectWhenSaturated bool
+
+	// If MaxRequestsPerConn is greater than zero,
+	// then a connection is closed after serving that many requests.
+	MaxRequestsPerConn int
+	// If MaxConnAge is greater than zero,
+	// then a connection is closed after serving the first request
+	// that arrives once the connection has been open for MaxConnAge.
+	// Unlike [http.Server.IdleTimeout], this bounds the lifetime of busy connections,
+	// which helps spread load after a deploy or behind a load balancer.
+	MaxConnAge time.Duration
+
+	// If Inflight is not nil, then Serve counts the requests
+	// that the server is handling in it.
+	Inflight *InflightCounter
+	// OnDrainProgress will be called with the number of requests still in flight
+	// while [*http.Server.Shutdown] waits for them to finish:
+	// once when shutdown starts, every DrainProgressInterval after that,
+	// and once more after Shutdown returns.
+	OnDrainProgress func(ctx context.Context, inflight int64)
+	// DrainProgressInterval is the time between calls to OnDrainProgress.
+	// If it is not positive, then one second is used.
+	DrainProgressInterval time.Duration
 }
 
 // Serve runs the given HTTP server until the context is Done.
+// If srv.BaseContext is nil, the server's base context is ctx.
+//
+// An [*http.Server] reads its hooks from its own fields,
+// so to implement the connection and keep-alive limits
+// and the in-flight request counting in [Options],
+// Serve replaces srv.BaseContext, srv.ConnState, srv.ConnContext, and srv.Handler
+// with functions that chain to their original values.
+// The original values are remembered,
+// so passing srv to Serve again does not wrap them a second time.
+// srv must not be passed to concurrent calls to Serve:
+// use [Options.AdditionalListeners] to serve on more than one listener.
 func Serve(ctx context.Context, srv *http.Server, opts *Options) error {
-	if srv.BaseContext == nil {
-		srv2 := new(http.Server)
-		*srv2 = *srv
-		srv2.BaseContext = func(net.Listener) context.Context { return ctx }
-	}
-
 	var l net.Listener
 	if opts != nil {
 		l = opts.Listener
@@ -59,10 +108,34 @@ func Serve(ctx context.Context, srv *http.Server, opts *Options) error {
 		var err error
 		l, err = net.Listen("tcp", addr)
 		if err != nil {
+			if opts != nil {
+				for _, al := range opts.AdditionalListeners {
+					al.Close()
+				}
+			}
 			return err
 		}
 		// [*http.Server.Serve] will close l.
 	}
+	listeners := []net.Listener{l}
+	hooks := newServerHooks(ctx, srv)
+	if opts != nil {
+		listeners = append(listeners, opts.AdditionalListeners...)
+		if opts.MaxConnections > 0 {
+			listeners, hooks.connSlots = limitConnections(listeners, opts.MaxConnections, opts.RejectWhenSaturated)
+		}
+		hooks.maxRequestsPerConn = opts.MaxRequestsPerConn
+		hooks.maxConnAge = opts.MaxConnAge
+	}
+	var inflight *InflightCounter
+	if opts != nil {
+		inflight = opts.Inflight
+		if inflight == nil && opts.OnDrainProgress != nil {
+			inflight = new(InflightCounter)
+		}
+	}
+	hooks.inflight = inflight
+	hooks.install(srv)
 
 	serveFinished := make(chan struct{})
 	idleConnsClosed := make(chan struct{})
@@ -73,7 +146,16 @@ func Serve(ctx context.Context, srv *http.Server, opts *Options) error {
 			if opts != nil && opts.OnShutdown != nil {
 				opts.OnShutdown(ctx)
 			}
+			stopProgress := func() {}
+			if opts != nil && opts.OnDrainProgress != nil {
+				interval := opts.DrainProgressInterval
+				if interval <= 0 {
+					interval = defaultDrainProgressInterval
+				}
+				stopProgress = reportDrainProgress(ctx, inflight, interval, opts.OnDrainProgress)
+			}
 			err := srv.Shutdown(context.Background())
+			stopProgress()
 			if err != nil && opts != nil && opts.OnShutdownError != nil {
 				opts.OnShutdownError(ctx, err)
 			}
@@ -82,13 +164,108 @@ func Serve(ctx context.Context, srv *http.Server, opts *Options) error {
 	}()
 
 	if opts != nil && opts.OnStartup != nil {
-		opts.OnStartup(ctx, l.Addr())
+		for _, l := range listeners {
+			opts.OnStartup(ctx, l.Addr())
+		}
 	}
-	err := srv.Serve(l)
-	if errors.Is(err, http.ErrServerClosed) {
-		err = nil
+	serveErrors := make(chan error, len(listeners))
+	for _, l := range listeners {
+		go func(l net.Listener) {
+			serveErrors <- srv.Serve(l)
+		}(l)
+	}
+	var err error
+	for range listeners {
+		if serveErr := <-serveErrors; !errors.Is(serveErr, http.ErrServerClosed) && err == nil {
+			err = serveErr
+			// Stop serving on the other listeners.
+			srv.Close()
+		}
 	}
 	close(serveFinished)
 	<-idleConnsClosed
 	return err
 }
+
+// serverHooks holds the hooks that [Serve] installs in an [*http.Server].
+// It keeps the server's original hooks and handler to chain to,
+// so that serving the same server again does not wrap them twice.
+type serverHooks struct {
+	baseContext func(net.Listener) context.Context
+	connState   func(net.Conn, http.ConnState)
+	connContext func(context.Context, net.Conn) context.Context
+	handler     http.Handler
+
+	ctx context.Context
+	// connSlots is the semaphore shared by the listeners
+	// returned from limitConnections
+	// or nil if the number of connections is not limited.
+	connSlots          chan struct{}
+	maxRequestsPerConn int
+	maxConnAge         time.Duration
+	// inflight counts the requests being handled if it is not nil.
+	inflight *InflightCounter
+}
+
+// newServerHooks returns hooks for serving srv with the given base context
+// that chain to srv's original hooks.
+func newServerHooks(ctx context.Context, srv *http.Server) *serverHooks {
+	if prev, ok := srv.Handler.(*serverHooks); ok {
+		return &serverHooks{
+			baseContext: prev.baseContext,
+			connState:   prev.connState,
+			connContext: prev.connContext,
+			handler:     prev.handler,
+			ctx:         ctx,
+		}
+	}
+	handler := srv.Handler
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	return &serverHooks{
+		baseContext: srv.BaseContext,
+		connState:   srv.ConnState,
+		connContext: srv.ConnContext,
+		handler:     handler,
+		ctx:         ctx,
+	}
+}
+
+// install sets srv's hooks and handler to h.
+func (h *serverHooks) install(srv *http.Server) {
+	srv.BaseContext = h.serveBaseContext
+	srv.ConnState = h.serveConnState
+	srv.ConnContext = h.serveConnContext
+	srv.Handler = h
+}
+
+func (h *serverHooks) serveBaseContext(l net.Listener) context.Context {
+	if h.baseContext != nil {
+		return h.baseContext(l)
+	}
+	return h.ctx
+}
+
+func (h *serverHooks) serveConnState(c net.Conn, state http.ConnState) {
+	h.releaseConnSlot(state)
+	if h.connState != nil {
+		h.connState(c, state)
+	}
+}
+
+func (h *serverHooks) serveConnContext(ctx context.Context, c net.Conn) context.Context {
+	if h.connContext != nil {
+		ctx = h.connContext(ctx, c)
+	}
+	return h.trackConn(ctx)
+}
+
+func (h *serverHooks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.limitKeepAlive(w, r)
+	if h.inflight != nil {
+		h.inflight.add(1)
+		defer h.inflight.add(-1)
+	}
+	h.handler.ServeHTTP(w, r)
+}
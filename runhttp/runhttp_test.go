@@ -0,0 +1,359 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package runhttp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startServer runs Serve in the background and returns the server's address.
+func startServer(t *testing.T, opts *Options) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Listener = l
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "Hello, World!\n")
+		}),
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, srv, opts)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Error("Serve:", err)
+		}
+	})
+	return l.Addr().String()
+}
+
+// sendRequest sends a GET request on c and reads the response.
+func sendRequest(t *testing.T, c net.Conn, br *bufio.Reader) *http.Response {
+	t.Helper()
+	c.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.WriteString(c, "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp
+}
+
+func TestMaxConnections(t *testing.T) {
+	t.Run("Reject", func(t *testing.T) {
+		addr := startServer(t, &Options{
+			MaxConnections:      1,
+			RejectWhenSaturated: true,
+		})
+		c1, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c1.Close()
+		if resp := sendRequest(t, c1, bufio.NewReader(c1)); resp.StatusCode != http.StatusOK {
+			t.Fatalf("first connection status = %d; want %d", resp.StatusCode, http.StatusOK)
+		}
+
+		c2, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c2.Close()
+		if resp := sendRequest(t, c2, bufio.NewReader(c2)); resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("second connection status = %d; want %d", resp.StatusCode, http.StatusServiceUnavailable)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		addr := startServer(t, &Options{MaxConnections: 1})
+		c1, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp := sendRequest(t, c1, bufio.NewReader(c1)); resp.StatusCode != http.StatusOK {
+			t.Fatalf("first connection status = %d; want %d", resp.StatusCode, http.StatusOK)
+		}
+
+		c2, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c2.Close()
+		if _, err := io.WriteString(c2, "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"); err != nil {
+			t.Fatal(err)
+		}
+		c2.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		if n, err := c2.Read(make([]byte, 1)); err == nil {
+			t.Fatalf("second connection read %d bytes while first connection open", n)
+		}
+
+		c1.Close()
+		c2.SetDeadline(time.Now().Add(5 * time.Second))
+		resp, err := http.ReadResponse(bufio.NewReader(c2), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("second connection status = %d; want %d", resp.StatusCode, http.StatusOK)
+		}
+	})
+}
+
+func TestMaxRequestsPerConn(t *testing.T) {
+	addr := startServer(t, &Options{MaxRequestsPerConn: 2})
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	br := bufio.NewReader(c)
+	if resp := sendRequest(t, c, br); resp.Close {
+		t.Error("first response closed connection")
+	}
+	if resp := sendRequest(t, c, br); !resp.Close {
+		t.Error("second response did not close connection")
+	}
+}
+
+func TestDrainProgress(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			io.WriteString(w, "Hello, World!\n")
+		}),
+	}
+	inflight := new(InflightCounter)
+	var mu sync.Mutex
+	var reports []int64
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, srv, &Options{
+			Listener: l,
+			Inflight: inflight,
+			OnDrainProgress: func(ctx context.Context, n int64) {
+				mu.Lock()
+				reports = append(reports, n)
+				mu.Unlock()
+			},
+			DrainProgressInterval: 10 * time.Millisecond,
+		})
+	}()
+
+	respDone := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + l.Addr().String() + "/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		respDone <- err
+	}()
+	for inflight.Count() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	for {
+		mu.Lock()
+		n := len(reports)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	if err := <-respDone; err != nil {
+		t.Error("GET:", err)
+	}
+	if err := <-done; err != nil {
+		t.Error("Serve:", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if reports[0] != 1 {
+		t.Errorf("first drain report = %d; want 1", reports[0])
+	}
+	if last := reports[len(reports)-1]; last != 0 {
+		t.Errorf("last drain report = %d; want 0", last)
+	}
+	if got := inflight.Count(); got != 0 {
+		t.Errorf("inflight.Count() = %d after Serve returned; want 0", got)
+	}
+}
+
+func TestAdditionalListeners(t *testing.T) {
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := os.MkdirTemp("", "runhttp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "admin.sock")
+	unixListener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skip("Unix sockets not supported:", err)
+	}
+
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "Hello, World!\n")
+		}),
+	}
+	var mu sync.Mutex
+	var startupAddrs []string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, srv, &Options{
+			Listener:            tcpListener,
+			AdditionalListeners: []net.Listener{unixListener},
+			MaxConnections:      2,
+			OnStartup: func(ctx context.Context, addr net.Addr) {
+				mu.Lock()
+				startupAddrs = append(startupAddrs, addr.Network())
+				mu.Unlock()
+			},
+		})
+	}()
+
+	for _, addr := range []net.Addr{tcpListener.Addr(), unixListener.Addr()} {
+		c, err := net.Dial(addr.Network(), addr.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp := sendRequest(t, c, bufio.NewReader(c))
+		c.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: status = %d; want %d", addr.Network(), resp.StatusCode, http.StatusOK)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Error("Serve:", err)
+	}
+	mu.Lock()
+	if got := strings.Join(startupAddrs, ","); got != "tcp,unix" {
+		t.Errorf("OnStartup networks = %s; want tcp,unix", got)
+	}
+	mu.Unlock()
+	if _, err := os.Stat(socketPath); !os.IsNotExist(err) {
+		t.Errorf("socket file still exists after Serve returned (err = %v)", err)
+	}
+}
+
+func TestServeChainsHooks(t *testing.T) {
+	type baseKey struct{}
+	type connKey struct{}
+	var mu sync.Mutex
+	var states []http.ConnState
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if ctx.Value(baseKey{}) == nil || ctx.Value(connKey{}) == nil {
+			http.Error(w, "missing context values", http.StatusInternalServerError)
+			return
+		}
+		io.WriteString(w, "Hello, World!\n")
+	})
+	srv := &http.Server{
+		Handler: handler,
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), baseKey{}, true)
+		},
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, connKey{}, true)
+		},
+		ConnState: func(c net.Conn, state http.ConnState) {
+			mu.Lock()
+			states = append(states, state)
+			mu.Unlock()
+		},
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, srv, &Options{
+			Listener:           l,
+			MaxConnections:     1,
+			MaxRequestsPerConn: 1,
+		})
+	}()
+	c, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := sendRequest(t, c, bufio.NewReader(c))
+	c.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d; want %d", resp.StatusCode, http.StatusOK)
+	}
+	if !resp.Close {
+		t.Error("response did not close connection")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Error("Serve:", err)
+	}
+	mu.Lock()
+	if len(states) == 0 || states[0] != http.StateNew {
+		t.Errorf("ConnState states = %v; want to start with %v", states, http.StateNew)
+	}
+	mu.Unlock()
+
+	// Serving srv again must chain to the original hooks,
+	// not to the ones installed by the first call.
+	hooks := newServerHooks(context.Background(), srv)
+	if _, wrapped := hooks.handler.(*serverHooks); wrapped {
+		t.Error("second Serve would wrap the first Serve's handler")
+	}
+}
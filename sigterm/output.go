@@ -0,0 +1,147 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigterm
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// StderrTailSize is the maximum number of bytes of standard error
+// retained in a [RunResult].
+const StderrTailSize = 4096
+
+// RunResult is the outcome of a command run by [Output].
+type RunResult struct {
+	// Stdout is the command's standard output.
+	// It is only populated if the command's Stdout field was nil.
+	Stdout []byte
+	// ExitCode is the command's exit code,
+	// or -1 if the process did not exit normally.
+	ExitCode int
+	// Signal is the signal that terminated the process, if any.
+	Signal os.Signal
+	// StderrTail is the last [StderrTailSize] bytes (or fewer)
+	// of the command's standard error,
+	// trimmed to start at a line boundary when possible.
+	StderrTail []byte
+}
+
+// Output is like [Run], but returns the command's result.
+// If c.Stdout is nil, then standard output is captured in the result.
+// Standard error is always captured in the result's StderrTail,
+// and is also written to c.Stderr if it is not nil.
+//
+// If the command runs but does not exit successfully,
+// then Output returns both the result and an [*ExitError]
+// whose message includes the tail of standard error.
+func Output(ctx context.Context, c *exec.Cmd) (*RunResult, error) {
+	var stdout *bytes.Buffer
+	if c.Stdout == nil {
+		stdout = new(bytes.Buffer)
+		c.Stdout = stdout
+	}
+	tail := new(tailBuffer)
+	if c.Stderr == nil {
+		c.Stderr = tail
+	} else {
+		c.Stderr = io.MultiWriter(c.Stderr, tail)
+	}
+	runErr := Run(ctx, c)
+	if c.ProcessState == nil {
+		// The command failed to start.
+		return nil, runErr
+	}
+	result := &RunResult{
+		ExitCode:   c.ProcessState.ExitCode(),
+		Signal:     exitSignal(c.ProcessState),
+		StderrTail: tail.bytes(),
+	}
+	if stdout != nil {
+		result.Stdout = stdout.Bytes()
+	}
+	if runErr != nil {
+		return result, &ExitError{
+			Args:   c.Args,
+			Result: result,
+			Err:    runErr,
+		}
+	}
+	return result, nil
+}
+
+// ExitError is returned by [Output] when a command does not exit successfully.
+type ExitError struct {
+	// Args is the command's arguments, including the program name.
+	Args []string
+	// Result is the outcome of the command.
+	Result *RunResult
+	// Err is the error returned by [Run],
+	// usually an [*exec.ExitError].
+	Err error
+}
+
+// Error returns a message containing the command's exit status
+// and the tail of its standard error.
+func (e *ExitError) Error() string {
+	sb := new(strings.Builder)
+	sb.WriteString(e.Err.Error())
+	if stderr := bytes.TrimRight(e.Result.StderrTail, "\n"); len(stderr) > 0 {
+		sb.WriteString("; stderr:\n")
+		sb.Write(stderr)
+	}
+	return sb.String()
+}
+
+// Unwrap returns e.Err.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// tailBuffer is an [io.Writer] that retains the last StderrTailSize bytes written.
+type tailBuffer struct {
+	buf       []byte
+	truncated bool
+}
+
+func (tb *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) >= StderrTailSize {
+		tb.buf = append(tb.buf[:0], p[len(p)-StderrTailSize:]...)
+		tb.truncated = true
+		return n, nil
+	}
+	if over := len(tb.buf) + len(p) - StderrTailSize; over > 0 {
+		tb.buf = append(tb.buf[:0], tb.buf[over:]...)
+		tb.truncated = true
+	}
+	tb.buf = append(tb.buf, p...)
+	return n, nil
+}
+
+func (tb *tailBuffer) bytes() []byte {
+	b := tb.buf
+	if tb.truncated {
+		// Drop the partial first line.
+		if i := bytes.IndexByte(b, '\n'); i >= 0 && i+1 < len(b) {
+			b = b[i+1:]
+		}
+	}
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigterm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+const helperEnv = "SIGTERM_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(helperEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "ok":
+		fmt.Println("Hello, World!")
+		os.Exit(0)
+	case "fail":
+		for i := 0; i < 1000; i++ {
+			fmt.Fprintf(os.Stderr, "line %d\n", i)
+		}
+		fmt.Fprintln(os.Stderr, "something went wrong")
+		os.Exit(3)
+	}
+}
+
+func helperCommand(mode string) *exec.Cmd {
+	c := exec.Command(os.Args[0])
+	c.Env = append(os.Environ(), helperEnv+"="+mode)
+	return c
+}
+
+func TestOutput(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Success", func(t *testing.T) {
+		result, err := Output(ctx, helperCommand("ok"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(result.Stdout), "Hello, World!\n"; got != want {
+			t.Errorf("Stdout = %q; want %q", got, want)
+		}
+		if result.ExitCode != 0 {
+			t.Errorf("ExitCode = %d; want 0", result.ExitCode)
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		stderr := new(strings.Builder)
+		c := helperCommand("fail")
+		c.Stderr = stderr
+		result, err := Output(ctx, c)
+		var exitErr *ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("Output(...) error = %v; want *ExitError", err)
+		}
+		if result == nil || result.ExitCode != 3 {
+			t.Fatalf("result = %+v; want ExitCode 3", result)
+		}
+		if result.Signal != nil {
+			t.Errorf("Signal = %v; want <nil>", result.Signal)
+		}
+		if len(result.StderrTail) > StderrTailSize {
+			t.Errorf("len(StderrTail) = %d; want <=%d", len(result.StderrTail), StderrTailSize)
+		}
+		if strings.HasPrefix(string(result.StderrTail), "line 0\n") {
+			t.Error("StderrTail contains beginning of output")
+		}
+		if !strings.HasPrefix(string(result.StderrTail), "line ") {
+			t.Errorf("StderrTail does not start at a line boundary: %.20q", result.StderrTail)
+		}
+		if msg := err.Error(); !strings.Contains(msg, "something went wrong") {
+			t.Errorf("error message %q does not contain stderr tail", msg)
+		}
+		if !strings.Contains(stderr.String(), "line 0\n") {
+			t.Error("stderr not passed through to Cmd.Stderr")
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		result, err := Output(ctx, exec.Command("this-command-does-not-exist-xyzzy"))
+		if err == nil {
+			t.Fatal("Output(...) did not return an error")
+		}
+		if result != nil {
+			t.Errorf("result = %+v; want <nil>", result)
+		}
+	})
+}
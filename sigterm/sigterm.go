@@ -28,6 +28,8 @@ func Signals() []os.Signal {
 
 // Start is like calling Start on os/exec.CommandContext but uses
 // SIGTERM on Unix-based systems.
+// If c was passed to [SetProcessGroup],
+// then the signal is sent to the command's entire process group.
 func Start(ctx context.Context, c *exec.Cmd) (wait func() error, err error) {
 	if err := c.Start(); err != nil {
 		return nil, err
@@ -36,7 +38,7 @@ func Start(ctx context.Context, c *exec.Cmd) (wait func() error, err error) {
 	go func() {
 		select {
 		case <-ctx.Done():
-			terminate(c.Process)
+			terminate(c)
 		case <-waitDone:
 		}
 	}()
@@ -55,3 +57,12 @@ func Run(ctx context.Context, c *exec.Cmd) error {
 	}
 	return wait()
 }
+
+// SetProcessGroup configures c to start in a new process group
+// so that [Start] and [Run] terminate any subprocesses it spawns as well.
+// This is useful for commands like shell scripts or "go run"
+// that do not forward signals to their children.
+// SetProcessGroup has no effect on systems without process groups.
+func SetProcessGroup(c *exec.Cmd) {
+	setProcessGroup(c)
+}
@@ -18,10 +18,18 @@ package sigterm
 
 import (
 	"os"
+	"os/exec"
 )
 
 var signals = []os.Signal{os.Interrupt}
 
-func terminate(proc *os.Process) error {
-	return proc.Kill()
+func terminate(c *exec.Cmd) error {
+	return c.Process.Kill()
+}
+
+func setProcessGroup(c *exec.Cmd) {}
+
+// exitSignal returns the signal that terminated the process or nil.
+func exitSignal(ps *os.ProcessState) os.Signal {
+	return nil
 }
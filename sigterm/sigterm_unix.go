@@ -18,12 +18,32 @@ package sigterm
 
 import (
 	"os"
+	"os/exec"
+	"syscall"
 
 	"golang.org/x/sys/unix"
 )
 
 var signals = []os.Signal{unix.SIGTERM, unix.SIGINT}
 
-func terminate(proc *os.Process) error {
-	return proc.Signal(unix.SIGTERM)
+func terminate(c *exec.Cmd) error {
+	if c.SysProcAttr != nil && c.SysProcAttr.Setpgid {
+		return unix.Kill(-c.Process.Pid, unix.SIGTERM)
+	}
+	return c.Process.Signal(unix.SIGTERM)
+}
+
+func setProcessGroup(c *exec.Cmd) {
+	if c.SysProcAttr == nil {
+		c.SysProcAttr = new(syscall.SysProcAttr)
+	}
+	c.SysProcAttr.Setpgid = true
+}
+
+// exitSignal returns the signal that terminated the process or nil.
+func exitSignal(ps *os.ProcessState) os.Signal {
+	if ws, ok := ps.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return ws.Signal()
+	}
+	return nil
 }
@@ -0,0 +1,130 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sse provides a writer for the Server-Sent Events format.
+// Read more at https://html.spec.whatwg.org/multipage/server-sent-events.html
+package sse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the MIME type of a Server-Sent Events stream.
+const ContentType = "text/event-stream"
+
+// An Event is a single message in an event stream.
+type Event struct {
+	// ID sets the client's last event ID,
+	// which is sent back in the Last-Event-ID header when the client reconnects.
+	// It must not contain newlines.
+	ID string
+	// Type is the name of the event, as passed to addEventListener.
+	// If empty, the client dispatches a "message" event.
+	// It must not contain newlines.
+	Type string
+	// Data is the event's payload.
+	// It may contain newlines.
+	Data string
+	// Retry, if positive, sets the time the client waits before reconnecting.
+	Retry time.Duration
+}
+
+// A Writer writes events to an event stream.
+// If the underlying writer implements [http.Flusher],
+// then the Writer flushes after every event
+// so that the client receives it immediately.
+type Writer struct {
+	w   *bufio.Writer
+	dst io.Writer
+}
+
+// NewWriter returns a new [Writer] that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w), dst: w}
+}
+
+// Send writes an event to the stream and flushes it.
+// Send returns an error if the event's ID or Type contains a newline.
+func (w *Writer) Send(ev *Event) error {
+	if strings.ContainsAny(ev.ID, "\r\n\x00") {
+		return fmt.Errorf("send event: invalid ID %q", ev.ID)
+	}
+	if strings.ContainsAny(ev.Type, "\r\n") {
+		return fmt.Errorf("send event: invalid type %q", ev.Type)
+	}
+	if ev.ID != "" {
+		w.field("id", ev.ID)
+	}
+	if ev.Type != "" {
+		w.field("event", ev.Type)
+	}
+	if ev.Retry > 0 {
+		w.field("retry", strconv.FormatInt(ev.Retry.Milliseconds(), 10))
+	}
+	data := strings.ReplaceAll(ev.Data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		w.field("data", line)
+	}
+	w.w.WriteString("\n")
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("send event: %w", err)
+	}
+	return nil
+}
+
+// Comment writes a comment line to the stream and flushes it.
+// Clients ignore comments, so they are useful as keep-alive messages
+// for proxies that close idle connections.
+// Newlines in text are replaced with spaces.
+func (w *Writer) Comment(text string) error {
+	text = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(text)
+	w.w.WriteString(":")
+	if text != "" {
+		w.w.WriteString(" ")
+		w.w.WriteString(text)
+	}
+	w.w.WriteString("\n\n")
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("send comment: %w", err)
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the underlying writer
+// and flushes it if it implements [http.Flusher].
+func (w *Writer) Flush() error {
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	if f, ok := w.dst.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (w *Writer) field(name, value string) {
+	w.w.WriteString(name)
+	w.w.WriteString(": ")
+	w.w.WriteString(value)
+	w.w.WriteString("\n")
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sse
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   *Event
+		want string
+	}{
+		{
+			name: "Data",
+			ev:   &Event{Data: "hello"},
+			want: "data: hello\n\n",
+		},
+		{
+			name: "AllFields",
+			ev:   &Event{ID: "42", Type: "update", Data: "hi", Retry: 3 * time.Second},
+			want: "id: 42\nevent: update\nretry: 3000\ndata: hi\n\n",
+		},
+		{
+			name: "MultilineData",
+			ev:   &Event{Data: "a\nb\r\nc\rd"},
+			want: "data: a\ndata: b\ndata: c\ndata: d\n\n",
+		},
+		{
+			name: "EmptyData",
+			ev:   &Event{Type: "ping"},
+			want: "event: ping\ndata: \n\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			w := NewWriter(rec)
+			if err := w.Send(test.ev); err != nil {
+				t.Fatal("Send:", err)
+			}
+			if !rec.Flushed {
+				t.Error("Send did not flush the response")
+			}
+			if got := rec.Body.String(); got != test.want {
+				t.Errorf("body = %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriterInvalid(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewWriter(rec)
+	if err := w.Send(&Event{ID: "a\nb"}); err == nil {
+		t.Error("Send with newline in ID did not return an error")
+	}
+	if err := w.Send(&Event{Type: "a\nb"}); err == nil {
+		t.Error("Send with newline in Type did not return an error")
+	}
+	if got := rec.Body.String(); got != "" {
+		t.Errorf("body = %q; want \"\"", got)
+	}
+}
+
+func TestComment(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewWriter(rec)
+	if err := w.Comment("keep\nalive"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Comment(""); err != nil {
+		t.Fatal(err)
+	}
+	const want = ": keep alive\n\n:\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package static
+
+import (
+	"mime"
+	slashpath "path"
+	"strings"
+)
+
+// defaultContentTypes are Content-Types for extensions
+// that are often missing or wrong in the system's MIME tables.
+// Browsers refuse to run ES modules or compile WebAssembly
+// served with the wrong Content-Type.
+var defaultContentTypes = map[string]string{
+	".avif":  "image/avif",
+	".mjs":   "text/javascript; charset=utf-8",
+	".wasm":  "application/wasm",
+	".woff2": "font/woff2",
+}
+
+// SetContentTypes sets the Content-Types to serve
+// for files with the given extensions (including the leading dot, like ".mjs").
+// Extensions are matched case-insensitively.
+// The Handler has defaults for ".avif", ".mjs", ".wasm", and ".woff2"
+// that are used unless overridden by m.
+// An empty value in m removes the default for that extension.
+// Files whose extension has no entry get a Content-Type
+// from the mime package or, failing that, by sniffing the content.
+//
+// SetContentTypes must not be called concurrently with ServeHTTP.
+func (h *Handler) SetContentTypes(m map[string]string) {
+	h.contentTypes = make(map[string]string, len(m))
+	for ext, ctype := range m {
+		h.contentTypes[strings.ToLower(ext)] = ctype
+	}
+}
+
+// overrideContentType returns the Content-Type configured for path's extension
+// or the empty string if the extension has no entry.
+func (h *Handler) overrideContentType(path string) string {
+	ext := strings.ToLower(slashpath.Ext(path))
+	if ext == "" {
+		return ""
+	}
+	if ctype, ok := h.contentTypes[ext]; ok {
+		return ctype
+	}
+	return defaultContentTypes[ext]
+}
+
+// contentTypeByExtension returns the Content-Type for path
+// based only on its extension, or the empty string if it is unknown.
+func (h *Handler) contentTypeByExtension(path string) string {
+	if ctype := h.overrideContentType(path); ctype != "" {
+		return ctype
+	}
+	return mime.TypeByExtension(slashpath.Ext(path))
+}
@@ -0,0 +1,308 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package static
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	slashpath "path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"zombiezen.com/go/bass/accept"
+)
+
+// widthQueryParam is the name of the query parameter
+// that requests a resized image.
+const widthQueryParam = "w"
+
+// maxImagePixels is the largest source image (in pixels) that will be resized.
+const maxImagePixels = 50_000_000
+
+// ImageOptions configures on-the-fly image resizing for a [Handler].
+// When image resizing is enabled,
+// a request for a JPEG, PNG, or GIF file with a "w" query parameter
+// (like "/photo.jpg?w=320") is served a copy of the image
+// scaled down to the given width, preserving its aspect ratio.
+// Images are never scaled up.
+type ImageOptions struct {
+	// Widths is the list of widths that may be requested.
+	// Requests for other widths receive a 400 (Bad Request) response.
+	// Limiting the widths prevents clients from making the server
+	// produce an unbounded number of derived images.
+	Widths []int
+
+	// CacheDir is an optional directory for storing derived images.
+	// If it is empty, derived images are produced on every request.
+	CacheDir string
+
+	// Encoders maps media types (like "image/webp" or "image/avif")
+	// to functions that encode images in that format.
+	// If a request's Accept header prefers one of these types
+	// over the source image's format, then the encoder is used.
+	Encoders map[string]ImageEncoder
+}
+
+// An ImageEncoder writes an image in a particular format.
+type ImageEncoder func(w io.Writer, m image.Image) error
+
+// SetImageOptions enables on-the-fly image resizing for the Handler.
+// Passing nil disables image resizing.
+//
+// SetImageOptions must not be called concurrently with ServeHTTP.
+func (h *Handler) SetImageOptions(opts *ImageOptions) {
+	if opts == nil {
+		h.images = nil
+		return
+	}
+	h.images = &imageOptions{
+		widths:   append([]int(nil), opts.Widths...),
+		cacheDir: opts.CacheDir,
+		encoders: make(map[string]ImageEncoder, len(opts.Encoders)),
+	}
+	for typ, enc := range opts.Encoders {
+		h.images.encoders[typ] = enc
+		h.images.encoderTypes = append(h.images.encoderTypes, typ)
+	}
+	sort.Strings(h.images.encoderTypes)
+}
+
+type imageOptions struct {
+	widths       []int
+	cacheDir     string
+	encoders     map[string]ImageEncoder
+	encoderTypes []string
+}
+
+// imageFormats maps source file extensions
+// to the media type that resized copies are written in by default.
+var imageFormats = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/png",
+}
+
+// isResizeRequest reports whether r asks for a resized copy of the file at path.
+func (opts *imageOptions) isResizeRequest(r *http.Request, path string) bool {
+	if opts == nil {
+		return false
+	}
+	if _, ok := imageFormats[strings.ToLower(slashpath.Ext(path))]; !ok {
+		return false
+	}
+	_, ok := r.URL.Query()[widthQueryParam]
+	return ok
+}
+
+// serveImage serves a resized copy of the image in src.
+// srcHash is the SHA-256 hash of the source file's content.
+func (h *Handler) serveImage(w http.ResponseWriter, r *http.Request, path string, src io.Reader, srcHash []byte) {
+	ctx := r.Context()
+	width, err := strconv.Atoi(r.URL.Query().Get(widthQueryParam))
+	if err != nil || !containsInt(h.images.widths, width) {
+		http.Error(w, "unsupported image width", http.StatusBadRequest)
+		return
+	}
+	defaultType := imageFormats[strings.ToLower(slashpath.Ext(path))]
+	contentType, err := h.images.negotiate(r.Header.Get("Accept"), defaultType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	keyHash := sha256.New()
+	keyHash.Write(srcHash)
+	fmt.Fprintf(keyHash, "\x00%d\x00%s", width, contentType)
+	key := hex.EncodeToString(keyHash.Sum(nil))
+
+	data, err := h.images.readCache(key)
+	if err != nil {
+		data, err = h.images.resize(src, width, contentType)
+		if err != nil {
+			h.error(ctx, w, path, err)
+			return
+		}
+		if err := h.images.writeCache(key, data); err != nil {
+			h.errFunc(ctx, path, err)
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	w.Header().Set("ETag", `"`+key+`"`)
+	http.ServeContent(w, r, path, time.Time{}, bytes.NewReader(data))
+}
+
+// negotiate returns the media type to encode a resized image in.
+func (opts *imageOptions) negotiate(acceptHeader string, defaultType string) (string, error) {
+	if len(opts.encoderTypes) == 0 {
+		return defaultType, nil
+	}
+	offers := make([]string, 0, len(opts.encoderTypes)+1)
+	offers = append(offers, opts.encoderTypes...)
+	offers = append(offers, defaultType)
+	n, err := accept.NewNegotiator(offers...)
+	if err != nil {
+		return "", err
+	}
+	if acceptHeader == "" {
+		return defaultType, nil
+	}
+	best, err := n.Best(acceptHeader)
+	if err != nil {
+		return "", err
+	}
+	if best == "" {
+		return defaultType, nil
+	}
+	return best, nil
+}
+
+func (opts *imageOptions) resize(src io.Reader, width int, contentType string) ([]byte, error) {
+	srcData, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("resize image: %w", err)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(srcData))
+	if err != nil {
+		return nil, fmt.Errorf("resize image: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("resize image: %dx%d image too large", cfg.Width, cfg.Height)
+	}
+	m, _, err := image.Decode(bytes.NewReader(srcData))
+	if err != nil {
+		return nil, fmt.Errorf("resize image: %w", err)
+	}
+	m = scaleToWidth(m, width)
+
+	buf := new(bytes.Buffer)
+	switch contentType {
+	case "image/jpeg":
+		err = jpeg.Encode(buf, m, &jpeg.Options{Quality: 85})
+	case "image/png":
+		err = png.Encode(buf, m)
+	case "image/gif":
+		err = gif.Encode(buf, m, nil)
+	default:
+		enc := opts.encoders[contentType]
+		if enc == nil {
+			return nil, fmt.Errorf("resize image: no encoder for %s", contentType)
+		}
+		err = enc(buf, m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resize image: encode %s: %w", contentType, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleToWidth returns a copy of m scaled down to the given width
+// using an area-averaging filter.
+// If m is already no wider than width, it is returned unchanged.
+func scaleToWidth(m image.Image, width int) image.Image {
+	b := m.Bounds()
+	if b.Dx() <= width {
+		return m
+	}
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), m, b.Min, draw.Src)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * b.Dy() / height
+		y1 := (y + 1) * b.Dy() / height
+		for x := 0; x < width; x++ {
+			x0 := x * b.Dx() / width
+			x1 := (x + 1) * b.Dx() / width
+			var sum [4]uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					sum[0] += uint64(p[0])
+					sum[1] += uint64(p[1])
+					sum[2] += uint64(p[2])
+					sum[3] += uint64(p[3])
+				}
+			}
+			n := uint64((y1 - y0) * (x1 - x0))
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0] = uint8(sum[0] / n)
+			d[1] = uint8(sum[1] / n)
+			d[2] = uint8(sum[2] / n)
+			d[3] = uint8(sum[3] / n)
+		}
+	}
+	return dst
+}
+
+func (opts *imageOptions) readCache(key string) ([]byte, error) {
+	if opts.cacheDir == "" {
+		return nil, errors.New("no image cache")
+	}
+	return os.ReadFile(filepath.Join(opts.cacheDir, key))
+}
+
+func (opts *imageOptions) writeCache(key string, data []byte) error {
+	if opts.cacheDir == "" {
+		return nil
+	}
+	f, err := os.CreateTemp(opts.cacheDir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cache resized image: %w", err)
+	}
+	_, err = f.Write(data)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(opts.cacheDir, key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("cache resized image: %w", err)
+	}
+	return nil
+}
+
+func containsInt(list []int, x int) bool {
+	for _, elem := range list {
+		if elem == x {
+			return true
+		}
+	}
+	return false
+}
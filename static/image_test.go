@@ -0,0 +1,141 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package static
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestImageResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			src.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+		}
+	}
+	srcData := new(bytes.Buffer)
+	if err := png.Encode(srcData, src); err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"photo.png": {Data: srcData.Bytes()},
+	}
+	cacheDir := t.TempDir()
+	h := NewHandler(fsys)
+	h.SetImageOptions(&ImageOptions{
+		Widths:   []int{10, 100},
+		CacheDir: cacheDir,
+		Encoders: map[string]ImageEncoder{
+			"image/x-test": func(w io.Writer, m image.Image) error {
+				_, err := io.WriteString(w, "test image")
+				return err
+			},
+		},
+	})
+
+	tests := []struct {
+		name            string
+		target          string
+		accept          string
+		wantStatusCode  int
+		wantContentType string
+		wantSize        image.Point
+	}{
+		{
+			name:            "Original",
+			target:          "/photo.png",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "image/png",
+			wantSize:        image.Pt(40, 20),
+		},
+		{
+			name:            "Resized",
+			target:          "/photo.png?w=10",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "image/png",
+			wantSize:        image.Pt(10, 5),
+		},
+		{
+			name:            "NoUpscale",
+			target:          "/photo.png?w=100",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "image/png",
+			wantSize:        image.Pt(40, 20),
+		},
+		{
+			name:           "UnlistedWidth",
+			target:         "/photo.png?w=11",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:            "NegotiatedFormat",
+			target:          "/photo.png?w=10",
+			accept:          "image/x-test,image/*;q=0.8",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "image/x-test",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, test.target, nil)
+			if test.accept != "" {
+				r.Header.Set("Accept", test.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			if rec.Code != test.wantStatusCode {
+				t.Fatalf("StatusCode = %d; want %d", rec.Code, test.wantStatusCode)
+			}
+			if test.wantStatusCode != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != test.wantContentType {
+				t.Errorf("Content-Type = %q; want %q", got, test.wantContentType)
+			}
+			if test.wantContentType != "image/png" {
+				return
+			}
+			m, err := png.Decode(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := m.Bounds().Size(); got != test.wantSize {
+				t.Errorf("size = %v; want %v", got, test.wantSize)
+			}
+			if r, g, b, a := m.At(0, 0).RGBA(); r != 0xffff || g != 0 || b != 0 || a != 0xffff {
+				t.Errorf("pixel (0, 0) = %v; want opaque red", m.At(0, 0))
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("cache directory has %d entries; want 3", len(entries))
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package static
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"zombiezen.com/go/bass/accept"
+)
+
+// ManifestName is the name of the file that [Precompress] writes
+// to describe the compressed variants it produced.
+const ManifestName = "precompressed.json"
+
+// An Algorithm is a content coding that [Precompress] can produce.
+//
+// The Go standard library only includes gzip.
+// Other codings can be added with third-party encoders.
+// For example, with github.com/andybalholm/brotli:
+//
+//	static.Algorithm{
+//		Encoding: "br",
+//		Ext:      ".br",
+//		NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+//			return brotli.NewWriterLevel(w, brotli.BestCompression), nil
+//		},
+//	}
+type Algorithm struct {
+	// Encoding is the content coding name used in
+	// the Accept-Encoding and Content-Encoding headers, like "br" or "zstd".
+	Encoding string
+	// Ext is the suffix added to file names for compressed variants,
+	// like ".br" or ".zst".
+	Ext string
+	// NewWriter returns a writer that compresses data written to it into w.
+	// Precompress calls Close on the returned writer
+	// after writing the whole file.
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+// Gzip is the gzip [Algorithm] at its best compression level.
+var Gzip = Algorithm{
+	Encoding: "gzip",
+	Ext:      ".gz",
+	NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, gzip.BestCompression)
+	},
+}
+
+// minPrecompressSaving is the fraction of a file's size that a compressed
+// variant must save for Precompress to keep it.
+const minPrecompressSaving = 0.1
+
+// A manifest maps slash-separated file paths
+// to the compressed variants of the file.
+type manifest map[string]*manifestEntry
+
+type manifestEntry struct {
+	// SHA256 is the hex-encoded hash of the uncompressed file,
+	// used to detect variants that are out of date.
+	SHA256 string `json:"sha256"`
+	// Encodings maps content coding names
+	// to the slash-separated paths of the compressed files.
+	Encodings map[string]string `json:"encodings"`
+}
+
+// Precompress writes compressed variants of the files in fsys
+// to the directory outDir using each of the given algorithms,
+// along with a manifest file named [ManifestName].
+// A variant of a file "foo/app.js" compressed with [Gzip]
+// is written to "foo/app.js.gz" inside outDir.
+// Variants that would not be meaningfully smaller than the original file
+// are not written.
+// outDir may be the same directory that fsys reads from.
+//
+// Precompress is intended to be run at build time.
+// Passing outDir's files to [Handler.SetPrecompressed]
+// serves the variants to clients that accept them.
+func Precompress(fsys fs.FS, outDir string, algorithms ...Algorithm) error {
+	for _, alg := range algorithms {
+		if alg.Encoding == "" || alg.Ext == "" || alg.NewWriter == nil {
+			return fmt.Errorf("precompress: invalid algorithm %q", alg.Encoding)
+		}
+	}
+	// Skip files produced by a previous run.
+	isVariant := func(path string) bool {
+		if path == ManifestName {
+			return true
+		}
+		for _, alg := range algorithms {
+			if strings.HasSuffix(path, alg.Ext) {
+				return true
+			}
+		}
+		return false
+	}
+
+	m := make(manifest)
+	err := fs.WalkDir(fsys, ".", func(path string, ent fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !ent.Type().IsRegular() || isVariant(path) {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256(data)
+		entry := &manifestEntry{
+			SHA256:    hex.EncodeToString(hash[:]),
+			Encodings: make(map[string]string),
+		}
+		for _, alg := range algorithms {
+			compressed, err := compress(alg, data)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", path, alg.Encoding, err)
+			}
+			if float64(len(compressed)) > float64(len(data))*(1-minPrecompressSaving) {
+				continue
+			}
+			dst := filepath.Join(outDir, filepath.FromSlash(path+alg.Ext))
+			if err := os.MkdirAll(filepath.Dir(dst), 0o777); err != nil {
+				return err
+			}
+			if err := os.WriteFile(dst, compressed, 0o666); err != nil {
+				return err
+			}
+			entry.Encodings[alg.Encoding] = path + alg.Ext
+		}
+		if len(entry.Encodings) > 0 {
+			m[path] = entry
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("precompress: %w", err)
+	}
+	manifestData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("precompress: %w", err)
+	}
+	manifestData = append(manifestData, '\n')
+	if err := os.MkdirAll(outDir, 0o777); err != nil {
+		return fmt.Errorf("precompress: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, ManifestName), manifestData, 0o666); err != nil {
+		return fmt.Errorf("precompress: %w", err)
+	}
+	return nil
+}
+
+func compress(alg Algorithm, data []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w, err := alg.NewWriter(buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type precompressedFiles struct {
+	fs        fs.FS
+	manifest  manifest
+	encodings []string
+}
+
+// SetPrecompressed configures the Handler to serve compressed variants
+// produced by [Precompress] from fsys
+// to clients whose Accept-Encoding header prefers them.
+// Variants are only served if the file in the Handler's file system
+// has the same content as when the variant was produced.
+// Passing nil stops serving compressed variants.
+// SetPrecompressed returns an error if it cannot read fsys's manifest.
+//
+// SetPrecompressed must not be called concurrently with ServeHTTP.
+func (h *Handler) SetPrecompressed(fsys fs.FS) error {
+	if fsys == nil {
+		h.precompressed = nil
+		return nil
+	}
+	data, err := fs.ReadFile(fsys, ManifestName)
+	if err != nil {
+		return fmt.Errorf("set precompressed files: %w", err)
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("set precompressed files: %s: %w", ManifestName, err)
+	}
+	pf := &precompressedFiles{fs: fsys, manifest: m}
+	seen := make(map[string]bool)
+	for _, ent := range m {
+		for enc := range ent.Encodings {
+			if !seen[enc] {
+				seen[enc] = true
+				pf.encodings = append(pf.encodings, enc)
+			}
+		}
+	}
+	sort.Strings(pf.encodings)
+	h.precompressed = pf
+	return nil
+}
+
+// servePrecompressed serves the compressed variant of the file at path
+// that the request prefers, if there is one.
+// srcHash is the SHA-256 hash of the uncompressed file.
+// servePrecompressed reports whether it wrote a response.
+func (h *Handler) servePrecompressed(w http.ResponseWriter, r *http.Request, path string, srcHash []byte) bool {
+	ent := h.precompressed.manifest[path]
+	srcHashHex := hex.EncodeToString(srcHash)
+	if ent == nil || ent.SHA256 != srcHashHex {
+		return false
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	acceptEncoding, err := accept.ParseEncodingHeader(r.Header.Get("Accept-Encoding"))
+	if err != nil {
+		return false
+	}
+	offers := make([]string, 0, len(ent.Encodings)+1)
+	for _, enc := range h.precompressed.encodings {
+		if _, ok := ent.Encodings[enc]; ok {
+			offers = append(offers, enc)
+		}
+	}
+	offers = append(offers, accept.Identity)
+	enc := acceptEncoding.Negotiate(offers...)
+	if enc == "" || enc == accept.Identity {
+		return false
+	}
+	f, err := h.precompressed.fs.Open(ent.Encodings[enc])
+	if errors.Is(err, fs.ErrNotExist) {
+		return false
+	}
+	ctx := r.Context()
+	if err != nil {
+		h.error(ctx, w, path, err)
+		return true
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		h.error(ctx, w, path, err)
+		return true
+	}
+	s, err := toSeeker(f, info.Size())
+	if err != nil {
+		h.error(ctx, w, path, err)
+		return true
+	}
+	// Compressed content can't be sniffed, so use the original file's extension.
+	if ctype := h.contentTypeByExtension(path); ctype != "" {
+		w.Header().Set("Content-Type", ctype)
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	w.Header().Set("Content-Encoding", enc)
+	w.Header().Set("ETag", `"`+srcHashHex+"-"+enc+`"`)
+	http.ServeContent(w, r, path, time.Time{}, s)
+	return true
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package static
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPrecompress(t *testing.T) {
+	script := strings.Repeat("console.log('Hello, World!');\n", 100)
+	fsys := fstest.MapFS{
+		"app.js":      {Data: []byte(script)},
+		"dir/app.css": {Data: []byte(strings.Repeat("body { color: red; }\n", 100))},
+		"tiny.txt":    {Data: []byte("hi")},
+	}
+	outDir := t.TempDir()
+	if err := Precompress(fsys, outDir, Gzip); err != nil {
+		t.Fatal("Precompress:", err)
+	}
+	for _, name := range []string{"app.js.gz", "dir/app.css.gz", ManifestName} {
+		if _, err := os.Stat(filepath.Join(outDir, filepath.FromSlash(name))); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "tiny.txt.gz")); err == nil {
+		t.Error("tiny.txt.gz was written, even though it is not smaller")
+	}
+
+	h := NewHandler(fsys)
+	if err := h.SetPrecompressed(os.DirFS(outDir)); err != nil {
+		t.Fatal("SetPrecompressed:", err)
+	}
+
+	t.Run("Gzip", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+		r.Header.Set("Accept-Encoding", "gzip, deflate")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d; want %d", rec.Code, http.StatusOK)
+		}
+		if got, want := rec.Header().Get("Content-Encoding"), "gzip"; got != want {
+			t.Errorf("Content-Encoding = %q; want %q", got, want)
+		}
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/javascript") {
+			t.Errorf("Content-Type = %q; want text/javascript", got)
+		}
+		if got, want := rec.Header().Get("Vary"), "Accept-Encoding"; got != want {
+			t.Errorf("Vary = %q; want %q", got, want)
+		}
+		if etag := rec.Header().Get("ETag"); !strings.HasSuffix(etag, `-gzip"`) {
+			t.Errorf("ETag = %q; want to end with -gzip", etag)
+		}
+		zr, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != script {
+			t.Errorf("decompressed body does not match app.js")
+		}
+	})
+
+	t.Run("Identity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+		r.Header.Set("Accept-Encoding", "br")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Content-Encoding = %q; want \"\"", got)
+		}
+		if got, want := rec.Header().Get("Vary"), "Accept-Encoding"; got != want {
+			t.Errorf("Vary = %q; want %q", got, want)
+		}
+		if got := rec.Body.String(); got != script {
+			t.Errorf("body does not match app.js")
+		}
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		changed := fstest.MapFS{
+			"app.js": {Data: []byte("console.log('changed');\n")},
+		}
+		h := NewHandler(changed)
+		if err := h.SetPrecompressed(os.DirFS(outDir)); err != nil {
+			t.Fatal("SetPrecompressed:", err)
+		}
+		r := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+		r.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Content-Encoding = %q; want \"\"", got)
+		}
+		if got, want := rec.Body.String(), "console.log('changed');\n"; got != want {
+			t.Errorf("body = %q; want %q", got, want)
+		}
+	})
+}
+
+func TestPrecompressInPlace(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte(strings.Repeat("Hello, World!\n", 100))
+	if err := os.WriteFile(filepath.Join(dir, "foo.txt"), data, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	// Running twice should not compress the previous run's output.
+	for i := 0; i < 2; i++ {
+		if err := Precompress(os.DirFS(dir), dir, Gzip); err != nil {
+			t.Fatal("Precompress:", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "foo.txt.gz.gz")); err == nil {
+		t.Error("foo.txt.gz.gz was written")
+	}
+}
@@ -41,6 +41,14 @@ import (
 type Handler struct {
 	fs      fs.FS
 	errFunc func(ctx context.Context, path string, err error) string
+	onServe func(ctx context.Context, path string, status int, bytes int64, d time.Duration)
+	images  *imageOptions
+
+	// contentTypes maps lowercased extensions to Content-Types.
+	// See SetContentTypes.
+	contentTypes map[string]string
+
+	precompressed *precompressedFiles
 }
 
 // NewHandler returns a new Handler that serves the given file system.
@@ -65,6 +73,14 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // uses net/http.ServeContent, but sets a content-based ETag first.
 func (h *Handler) ServeFile(w http.ResponseWriter, r *http.Request, path string) {
 	ctx := r.Context()
+	if h.onServe != nil {
+		start := time.Now()
+		rec := &serveRecorder{ResponseWriter: w}
+		w = rec
+		defer func() {
+			h.onServe(ctx, path, rec.status(), rec.written, time.Since(start))
+		}()
+	}
 	if !fs.ValidPath(path) {
 		http.Error(w, "not found", http.StatusNotFound)
 		return
@@ -122,7 +138,18 @@ func (h *Handler) ServeFile(w http.ResponseWriter, r *http.Request, path string)
 		h.error(ctx, w, path, err)
 		return
 	}
+	if h.images.isResizeRequest(r, path) {
+		h.serveImage(w, r, path, s, hash.Sum(nil))
+		return
+	}
+	if h.precompressed != nil && h.servePrecompressed(w, r, path, hash.Sum(nil)) {
+		return
+	}
 	w.Header().Set("ETag", `"`+hex.EncodeToString(hash.Sum(nil))+`"`)
+	if ctype := h.overrideContentType(path); ctype != "" {
+		// ServeContent only picks a Content-Type if one isn't already set.
+		w.Header().Set("Content-Type", ctype)
+	}
 	http.ServeContent(w, r, path, time.Time{}, s)
 }
 
@@ -139,6 +166,18 @@ func (h *Handler) SetErrorFunc(f func(ctx context.Context, path string, err erro
 	h.errFunc = f
 }
 
+// SetOnServe sets a callback that is called after every response
+// served by ServeFile with the file path, the response's status code,
+// the number of body bytes written, and the time taken to serve the file.
+// This allows measuring asset serving without wrapping the Handler,
+// which would lose the resolved file path.
+// A nil function removes the callback.
+//
+// SetOnServe must not be called concurrently with ServeHTTP.
+func (h *Handler) SetOnServe(f func(ctx context.Context, path string, status int, bytes int64, d time.Duration)) {
+	h.onServe = f
+}
+
 func (h *Handler) error(ctx context.Context, w http.ResponseWriter, path string, err error) {
 	msg := h.errFunc(ctx, path, err)
 	http.Error(w, msg, http.StatusInternalServerError)
@@ -200,3 +239,34 @@ func toSeeker(r io.Reader, size int64) (io.ReadSeeker, error) {
 	}
 	return bytes.NewReader(data), nil
 }
+
+// serveRecorder is an [http.ResponseWriter] that records
+// the status code and number of bytes written.
+type serveRecorder struct {
+	http.ResponseWriter
+	code    int
+	written int64
+}
+
+func (rec *serveRecorder) WriteHeader(code int) {
+	if rec.code == 0 {
+		rec.code = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *serveRecorder) Write(p []byte) (int, error) {
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(p)
+	rec.written += int64(n)
+	return n, err
+}
+
+func (rec *serveRecorder) status() int {
+	if rec.code == 0 {
+		return http.StatusOK
+	}
+	return rec.code
+}
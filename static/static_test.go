@@ -17,6 +17,7 @@
 package static
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -24,6 +25,9 @@ import (
 	"strings"
 	"testing"
 	"testing/fstest"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestHandler(t *testing.T) {
@@ -219,3 +223,93 @@ func TestHandler(t *testing.T) {
 		})
 	})
 }
+
+func TestOnServe(t *testing.T) {
+	fsys := fstest.MapFS{
+		"foo.txt": {
+			Data: []byte("Hello, World!\n"),
+		},
+	}
+	type serveEvent struct {
+		path   string
+		status int
+		bytes  int64
+	}
+	tests := []struct {
+		path string
+		want serveEvent
+	}{
+		{"/foo.txt", serveEvent{"foo.txt", http.StatusOK, 14}},
+		{"/./foo.txt", serveEvent{"foo.txt", http.StatusOK, 14}},
+		{"/bar.txt", serveEvent{"bar.txt", http.StatusNotFound, 10}},
+	}
+	for _, test := range tests {
+		h := NewHandler(fsys)
+		var got []serveEvent
+		h.SetOnServe(func(ctx context.Context, path string, status int, bytes int64, d time.Duration) {
+			if d < 0 {
+				t.Errorf("%s: duration = %v; want >=0", test.path, d)
+			}
+			got = append(got, serveEvent{path, status, bytes})
+		})
+		h.ServeHTTP(httptest.NewRecorder(), &http.Request{
+			Method: http.MethodGet,
+			URL:    &url.URL{Path: test.path},
+		})
+		if diff := cmp.Diff([]serveEvent{test.want}, got, cmp.AllowUnexported(serveEvent{})); diff != "" {
+			t.Errorf("%s: OnServe calls (-want +got):\n%s", test.path, diff)
+		}
+	}
+}
+
+func TestContentTypes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.mjs":      {Data: []byte("export default 42;\n")},
+		"app.wasm":     {Data: []byte("\x00asm\x01\x00\x00\x00")},
+		"font.WOFF2":   {Data: []byte("wOF2")},
+		"data.custom":  {Data: []byte("{}")},
+		"image.avif":   {Data: []byte("not really an image")},
+		"page.unknown": {Data: []byte("<!DOCTYPE html><p>Hi</p>")},
+	}
+	tests := []struct {
+		path         string
+		contentTypes map[string]string
+		want         string
+	}{
+		{path: "/app.mjs", want: "text/javascript; charset=utf-8"},
+		{path: "/app.wasm", want: "application/wasm"},
+		{path: "/font.WOFF2", want: "font/woff2"},
+		{path: "/image.avif", want: "image/avif"},
+		{path: "/page.unknown", want: "text/html; charset=utf-8"},
+		{
+			path:         "/data.custom",
+			contentTypes: map[string]string{".CUSTOM": "application/json"},
+			want:         "application/json",
+		},
+		{
+			path:         "/app.mjs",
+			contentTypes: map[string]string{".mjs": "application/javascript"},
+			want:         "application/javascript",
+		},
+	}
+	for _, test := range tests {
+		for _, method := range []string{http.MethodGet, http.MethodHead} {
+			h := NewHandler(fsys)
+			if test.contentTypes != nil {
+				h.SetContentTypes(test.contentTypes)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, &http.Request{
+				Method: method,
+				URL:    &url.URL{Path: test.path},
+			})
+			if rec.Code != http.StatusOK {
+				t.Errorf("%s %s: status = %d; want %d", method, test.path, rec.Code, http.StatusOK)
+			}
+			if got := rec.Header().Get("Content-Type"); got != test.want {
+				t.Errorf("%s %s (contentTypes = %v): Content-Type = %q; want %q",
+					method, test.path, test.contentTypes, got, test.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package templateloader
+
+import (
+	"html/template"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+// A Cache holds parsed templates so that each is only parsed once.
+// Cached templates are never returned directly:
+// callers receive a clone that uses the functions passed to the call,
+// so a template can be parsed once and executed with per-request functions.
+// A nil *Cache parses templates on every call.
+// It is safe to use a Cache from multiple goroutines.
+type Cache struct {
+	mu        sync.Mutex
+	templates map[cacheKey]any
+}
+
+// cacheKey identifies a parsed template.
+// Templates are parsed with the set of function names available,
+// so the names are part of the key.
+type cacheKey struct {
+	key       any
+	funcNames string
+}
+
+// HTML returns a copy of the html/template cached under key
+// that uses the given funcs,
+// calling parse to create the template if it is not in the cache.
+// key must be comparable.
+func (c *Cache) HTML(key any, funcs template.FuncMap, parse func() (*template.Template, error)) (*template.Template, error) {
+	return loadCached(c, key, funcs, parse, func(tmpl *template.Template) (*template.Template, error) {
+		clone, err := tmpl.Clone()
+		if err != nil {
+			return nil, err
+		}
+		return clone.Funcs(funcs), nil
+	})
+}
+
+// Text returns a copy of the text/template cached under key
+// that uses the given funcs,
+// calling parse to create the template if it is not in the cache.
+// key must be comparable.
+func (c *Cache) Text(key any, funcs template.FuncMap, parse func() (*texttemplate.Template, error)) (*texttemplate.Template, error) {
+	return loadCached(c, key, funcs, parse, func(tmpl *texttemplate.Template) (*texttemplate.Template, error) {
+		clone, err := tmpl.Clone()
+		if err != nil {
+			return nil, err
+		}
+		return clone.Funcs(texttemplate.FuncMap(funcs)), nil
+	})
+}
+
+// loadCached returns a template from c or parses a new one with parse.
+// Cached templates are passed to bind to create a copy
+// that uses the given funcs.
+func loadCached[T any](c *Cache, key any, funcs template.FuncMap, parse func() (T, error), bind func(T) (T, error)) (T, error) {
+	if c == nil {
+		return parse()
+	}
+	funcNames := make([]string, 0, len(funcs))
+	for k := range funcs {
+		funcNames = append(funcNames, k)
+	}
+	sort.Strings(funcNames)
+	k := cacheKey{
+		key:       key,
+		funcNames: strings.Join(funcNames, "\x00"),
+	}
+
+	c.mu.Lock()
+	cached, ok := c.templates[k].(T)
+	c.mu.Unlock()
+	if !ok {
+		var err error
+		cached, err = parse()
+		if err != nil {
+			return cached, err
+		}
+		c.mu.Lock()
+		if c.templates == nil {
+			c.templates = make(map[cacheKey]any)
+		}
+		c.templates[k] = cached
+		c.mu.Unlock()
+	}
+	return bind(cached)
+}
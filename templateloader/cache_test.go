@@ -0,0 +1,63 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package templateloader
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	parses := 0
+	render := func(c *Cache, user string) string {
+		t.Helper()
+		funcs := template.FuncMap{"user": func() string { return user }}
+		tmpl, err := c.HTML("greet", funcs, func() (*template.Template, error) {
+			parses++
+			return template.New("greet").Funcs(funcs).Parse(`Hello, {{ user }}`)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb := new(strings.Builder)
+		if err := tmpl.Execute(sb, nil); err != nil {
+			t.Fatal(err)
+		}
+		return sb.String()
+	}
+
+	c := new(Cache)
+	for _, user := range []string{"Alice", "Bob"} {
+		if got, want := render(c, user), "Hello, "+user; got != want {
+			t.Errorf("render(c, %q) = %q; want %q", user, got, want)
+		}
+	}
+	if parses != 1 {
+		t.Errorf("parsed %d times with cache; want 1", parses)
+	}
+
+	parses = 0
+	for _, user := range []string{"Alice", "Bob"} {
+		if got, want := render(nil, user), "Hello, "+user; got != want {
+			t.Errorf("render(nil, %q) = %q; want %q", user, got, want)
+		}
+	}
+	if parses != 2 {
+		t.Errorf("parsed %d times with nil cache; want 2", parses)
+	}
+}
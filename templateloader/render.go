@@ -0,0 +1,138 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package templateloader
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	slashpath "path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Render executes the named template file with the given data
+// and returns the output as a string.
+// It is intended for rendering templates outside of HTTP handlers,
+// like in emails, command-line output, or background jobs.
+//
+// Files ending in ".txt" are parsed with text/template
+// along with any text partials (see [AddTextPartials]).
+// Other files are parsed with html/template:
+// if base.html exists in fsys, then the file extends it as if by [Base] and [Extend],
+// otherwise the file is executed directly with any partials (see [AddPartials]).
+//
+// If fsys is an [embed.FS], then the parsed templates are cached,
+// since its contents cannot change.
+func Render(fsys fs.FS, name string, data any, funcs template.FuncMap) (string, error) {
+	return (*Options)(nil).Render(fsys, name, data, funcs)
+}
+
+// Options holds optional parameters for [Options.Render].
+// A nil *Options is equivalent to a zero Options.
+type Options struct {
+	// If Strict is true, then templates are executed as if by [Strict]:
+	// a missing map key is an error instead of "<no value>",
+	// and execution errors are returned as an [*ExecError]
+	// that names the data path being evaluated.
+	// This is useful during development to catch typos in template variables.
+	Strict bool
+}
+
+// Render executes the named template file with the given data
+// like [Render], using the given options.
+func (opts *Options) Render(fsys fs.FS, name string, data any, funcs template.FuncMap) (string, error) {
+	strict := opts != nil && opts.Strict
+	sb := new(strings.Builder)
+	if slashpath.Ext(name) == ".txt" {
+		tmpl, err := loadText(fsys, name, funcs)
+		if err != nil {
+			return "", fmt.Errorf("render %s: %w", name, err)
+		}
+		if strict {
+			StrictText(tmpl)
+		}
+		if err := tmpl.Execute(sb, data); err != nil {
+			return "", fmt.Errorf("render %s: %w", name, wrapExecError(err, strict))
+		}
+		return sb.String(), nil
+	}
+	tmpl, err := loadHTML(fsys, name, funcs)
+	if err != nil {
+		return "", fmt.Errorf("render %s: %w", name, err)
+	}
+	if strict {
+		Strict(tmpl)
+	}
+	if err := tmpl.Execute(sb, data); err != nil {
+		return "", fmt.Errorf("render %s: %w", name, wrapExecError(err, strict))
+	}
+	return sb.String(), nil
+}
+
+// renderCache holds the templates parsed by [Render]
+// from an [embed.FS].
+var renderCache Cache
+
+// renderCacheKey identifies a template parsed by [Render].
+type renderCacheKey struct {
+	fsys embed.FS
+	name string
+}
+
+// renderTemplates returns the cache to use for templates in fsys,
+// or nil if templates from fsys should not be cached.
+func renderTemplates(fsys fs.FS, name string) (*Cache, any) {
+	efs, ok := fsys.(embed.FS)
+	if !ok {
+		return nil, nil
+	}
+	return &renderCache, renderCacheKey{fsys: efs, name: name}
+}
+
+func loadHTML(fsys fs.FS, name string, funcs template.FuncMap) (*template.Template, error) {
+	cache, key := renderTemplates(fsys, name)
+	return cache.HTML(key, funcs, func() (*template.Template, error) {
+		base, err := Base(fsys, funcs)
+		if errors.Is(err, fs.ErrNotExist) {
+			if _, statErr := fs.Stat(fsys, "base.html"); errors.Is(statErr, fs.ErrNotExist) {
+				tmpl, err := ParseFile(template.New(name).Funcs(funcs), fsys, name)
+				if err != nil {
+					return nil, err
+				}
+				return AddPartials(tmpl, fsys)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return Extend(base, fsys, name)
+	})
+}
+
+func loadText(fsys fs.FS, name string, funcs template.FuncMap) (*texttemplate.Template, error) {
+	cache, key := renderTemplates(fsys, name)
+	return cache.Text(key, funcs, func() (*texttemplate.Template, error) {
+		tmpl, err := ParseTextFile(texttemplate.New(name).Funcs(texttemplate.FuncMap(funcs)), fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		return AddTextPartials(tmpl, fsys)
+	})
+}
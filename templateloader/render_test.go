@@ -0,0 +1,85 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package templateloader
+
+import (
+	"embed"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+//go:embed testdata/RenderCache
+var renderTestFiles embed.FS
+
+func TestRender(t *testing.T) {
+	fsys := os.DirFS(filepath.Join("testdata", "Render"))
+	funcs := template.FuncMap{"upper": strings.ToUpper}
+	tests := []struct {
+		name string
+		data any
+		want string
+	}{
+		{
+			name: "page.html",
+			data: "<World>",
+			want: "<html><body><p>Hello, &lt;World&gt;!</p></body></html>",
+		},
+		{
+			name: "mail.txt",
+			data: "<World>",
+			want: "<WORLD>, welcome.\n-- The Team",
+		},
+	}
+	for _, test := range tests {
+		got, err := Render(fsys, test.name, test.data, funcs)
+		if got != test.want || err != nil {
+			t.Errorf("Render(fsys, %q, %q, funcs) = %q, %v; want %q, <nil>", test.name, test.data, got, err, test.want)
+		}
+	}
+}
+
+func TestRenderWithoutBase(t *testing.T) {
+	fsys := fstest.MapFS{
+		"email.html":   {Data: []byte(`<p>{{ template "footer" }}</p>`)},
+		"_footer.html": {Data: []byte(`Bye`)},
+	}
+	got, err := Render(fsys, "email.html", nil, nil)
+	const want = "<p>Bye</p>"
+	if got != want || err != nil {
+		t.Errorf("Render(fsys, \"email.html\", nil, nil) = %q, %v; want %q, <nil>", got, err, want)
+	}
+}
+
+func TestRenderCache(t *testing.T) {
+	const name = "testdata/RenderCache/greet.txt"
+	for _, suffix := range []string{"!", "?"} {
+		funcs := template.FuncMap{
+			"upper": func(s string) string { return strings.ToUpper(s) + suffix },
+		}
+		got, err := Render(renderTestFiles, name, "x", funcs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "X" + suffix; got != want {
+			t.Errorf("Render(renderTestFiles, %q, \"x\", funcs) = %q; want %q", name, got, want)
+		}
+	}
+}
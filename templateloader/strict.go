@@ -0,0 +1,107 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package templateloader
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+const missingKeyError = "missingkey=error"
+
+// Strict sets the "missingkey=error" option on t
+// and every template associated with it,
+// so that indexing a map with a missing key stops execution with an error
+// instead of printing "<no value>".
+// Template options are not inherited by templates parsed later,
+// so Strict should be called after all of t's templates have been parsed.
+// Strict returns t.
+func Strict(t *template.Template) *template.Template {
+	t.Option(missingKeyError)
+	for _, assoc := range t.Templates() {
+		assoc.Option(missingKeyError)
+	}
+	return t
+}
+
+// StrictText sets the "missingkey=error" option on t
+// and every template associated with it, like [Strict].
+// StrictText returns t.
+func StrictText(t *texttemplate.Template) *texttemplate.Template {
+	t.Option(missingKeyError)
+	for _, assoc := range t.Templates() {
+		assoc.Option(missingKeyError)
+	}
+	return t
+}
+
+// An ExecError is an error executing a template
+// that records the data path that was being evaluated,
+// like ".User.Name".
+// It is returned by [Options.Render] in strict mode.
+type ExecError struct {
+	// Path is the pipeline being evaluated when the error occurred.
+	Path string
+	// Err is the error returned by template execution,
+	// usually a [texttemplate.ExecError].
+	Err error
+}
+
+func (e *ExecError) Error() string {
+	return "data " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// wrapExecError wraps a template execution error in an [*ExecError]
+// if strict is true and the error names the pipeline being evaluated.
+func wrapExecError(err error, strict bool) error {
+	if !strict {
+		return err
+	}
+	var execErr texttemplate.ExecError
+	if !errors.As(err, &execErr) {
+		return err
+	}
+	path, ok := execErrorPath(execErr.Err.Error())
+	if !ok {
+		return err
+	}
+	return &ExecError{Path: path, Err: err}
+}
+
+// execErrorPath extracts the pipeline from a text/template execution error message,
+// which has the form:
+//
+//	template: name:1:2: executing "name" at <.User.Name>: map has no entry for key "Name"
+func execErrorPath(msg string) (string, bool) {
+	const prefix = " at <"
+	i := strings.Index(msg, prefix)
+	if i < 0 {
+		return "", false
+	}
+	msg = msg[i+len(prefix):]
+	j := strings.Index(msg, ">: ")
+	if j < 0 {
+		return "", false
+	}
+	return msg[:j], true
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package templateloader
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRenderStrict(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base.html":     {Data: []byte(`<p>{{ block "content" . }}{{ end }}</p>`)},
+		"page.html":     {Data: []byte(`{{ define "content" }}Hello, {{ .User.Nmae }}!{{ end }}`)},
+		"_partial.html": {Data: []byte(`{{ .Missing }}`)},
+		"partial.html":  {Data: []byte(`{{ define "content" }}{{ template "partial" . }}{{ end }}`)},
+		"mail.txt":      {Data: []byte(`Hi {{ .Nmae }}`)},
+	}
+	data := map[string]any{
+		"User": map[string]any{"Name": "Alice"},
+		"Name": "Alice",
+	}
+	tests := []struct {
+		name     string
+		wantPath string
+	}{
+		{name: "page.html", wantPath: ".User.Nmae"},
+		{name: "partial.html", wantPath: ".Missing"},
+		{name: "mail.txt", wantPath: ".Nmae"},
+	}
+	for _, test := range tests {
+		if got, err := Render(fsys, test.name, data, nil); err != nil {
+			t.Errorf("Render(fsys, %q, data, nil) = _, %v; want <nil>", test.name, err)
+		} else if got == "" {
+			t.Errorf("Render(fsys, %q, data, nil) = \"\"", test.name)
+		}
+
+		_, err := (&Options{Strict: true}).Render(fsys, test.name, data, nil)
+		var execErr *ExecError
+		if !errors.As(err, &execErr) {
+			t.Errorf("strict Render(fsys, %q, data, nil) = _, %v; want *ExecError", test.name, err)
+			continue
+		}
+		if execErr.Path != test.wantPath {
+			t.Errorf("strict Render(fsys, %q, data, nil) error path = %q; want %q", test.name, execErr.Path, test.wantPath)
+		}
+	}
+}
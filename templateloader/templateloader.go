@@ -24,6 +24,7 @@ import (
 	"io"
 	"io/fs"
 	slashpath "path"
+	"sort"
 	"strings"
 	texttemplate "text/template"
 )
@@ -44,7 +45,7 @@ func Base(fsys fs.FS, funcs template.FuncMap) (*template.Template, error) {
 // ".html" are stripped from the template name, so "shared/_menu.html" will be
 // available as "shared/menu".
 func AddPartials(t *template.Template, fsys fs.FS) (*template.Template, error) {
-	return addPartials(t, fsys, ".html")
+	return addPartials(t, []fs.FS{fsys}, ".html")
 }
 
 // AddTextPartials searches the given file system for partial templates,
@@ -53,11 +54,60 @@ func AddPartials(t *template.Template, fsys fs.FS) (*template.Template, error) {
 // ".txt" are stripped from the template name, so "shared/_menu.txt" will be
 // available as "shared/menu".
 func AddTextPartials(t *texttemplate.Template, fsys fs.FS) (*texttemplate.Template, error) {
-	return addPartials(t, fsys, ".txt")
+	return addPartials(t, []fs.FS{fsys}, ".txt")
 }
 
-func addPartials[T templateType[T]](t T, fsys fs.FS, ext string) (T, error) {
-	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+// AddLayeredPartials is like [AddPartials],
+// but searches several file systems for partial templates.
+// If more than one file system has a partial with the same name,
+// the one from the last file system is used,
+// so layers should be ordered from lowest to highest precedence:
+// for example, a library's default partials followed by an application's.
+// Partials are added in name order regardless of layering,
+// so the result does not depend on the order that files are found.
+func AddLayeredPartials(t *template.Template, layers ...fs.FS) (*template.Template, error) {
+	return addPartials(t, layers, ".html")
+}
+
+// AddLayeredTextPartials is like [AddTextPartials],
+// but searches several file systems for partial templates
+// with the same precedence as [AddLayeredPartials].
+func AddLayeredTextPartials(t *texttemplate.Template, layers ...fs.FS) (*texttemplate.Template, error) {
+	return addPartials(t, layers, ".txt")
+}
+
+// partialFile is the location of a partial template.
+type partialFile struct {
+	fsys fs.FS
+	path string
+}
+
+func addPartials[T templateType[T]](t T, layers []fs.FS, ext string) (T, error) {
+	var zero T
+	partials := make(map[string]partialFile)
+	for _, fsys := range layers {
+		if err := findPartials(partials, fsys, ext); err != nil {
+			return zero, err
+		}
+	}
+	names := make([]string, 0, len(partials))
+	for name := range partials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := partials[name]
+		if _, err := parse(t.New(name), p.fsys, p.path); err != nil {
+			return zero, err
+		}
+	}
+	return t, nil
+}
+
+// findPartials adds the partial templates in fsys to dst,
+// replacing any existing partials with the same name.
+func findPartials(dst map[string]partialFile, fsys fs.FS, ext string) error {
+	return fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -76,15 +126,9 @@ func addPartials[T templateType[T]](t T, fsys fs.FS, ext string) (T, error) {
 			// Not a partial template: ignore.
 			return nil
 		}
-		templateName := dir + name[1:len(name)-len(ext)]
-		_, err = parse(t.New(templateName), fsys, path)
-		return err
+		dst[dir+name[1:len(name)-len(ext)]] = partialFile{fsys, path}
+		return nil
 	})
-	if err != nil {
-		var zero T
-		return zero, err
-	}
-	return t, nil
 }
 
 // Extend returns a duplicate of a base template, including all associated
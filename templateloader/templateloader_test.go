@@ -22,6 +22,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	texttemplate "text/template"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -56,3 +58,54 @@ func TestAddPartials(t *testing.T) {
 		t.Errorf("template output (-want +got):\n%s", diff)
 	}
 }
+
+func TestAddLayeredPartials(t *testing.T) {
+	library := fstest.MapFS{
+		"_flash.html":        {Data: []byte(`<div class="flash">{{ . }}</div>`)},
+		"_pagination.html":   {Data: []byte(`<nav>default pages</nav>`)},
+		"forms/_errors.html": {Data: []byte(`<ul>{{ range . }}<li>{{ . }}</li>{{ end }}</ul>`)},
+	}
+	app := fstest.MapFS{
+		"_pagination.html": {Data: []byte(`<nav>custom pages</nav>`)},
+		"_menu.html":       {Data: []byte(`<menu></menu>`)},
+	}
+	tmpl := template.Must(template.New("page").Parse(
+		`{{ template "flash" "Saved" }}{{ template "pagination" }}` +
+			`{{ template "forms/errors" .Errors }}{{ template "menu" }}`,
+	))
+	if _, err := AddLayeredPartials(tmpl, library, app); err != nil {
+		t.Fatal("AddLayeredPartials:", err)
+	}
+	got := new(strings.Builder)
+	if err := tmpl.Execute(got, map[string]any{"Errors": []string{"bad"}}); err != nil {
+		t.Fatal(err)
+	}
+	const want = `<div class="flash">Saved</div>` +
+		`<nav>custom pages</nav>` +
+		`<ul><li>bad</li></ul>` +
+		`<menu></menu>`
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("template output (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddLayeredTextPartials(t *testing.T) {
+	library := fstest.MapFS{
+		"_greeting.txt": {Data: []byte(`Hello`)},
+		"_footer.txt":   {Data: []byte(`-- The Library`)},
+	}
+	app := fstest.MapFS{
+		"_footer.txt": {Data: []byte(`-- The App`)},
+	}
+	tmpl := texttemplate.Must(texttemplate.New("email").Parse(`{{ template "greeting" }} {{ template "footer" }}`))
+	if _, err := AddLayeredTextPartials(tmpl, library, app); err != nil {
+		t.Fatal("AddLayeredTextPartials:", err)
+	}
+	got := new(strings.Builder)
+	if err := tmpl.Execute(got, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hello -- The App"; got.String() != want {
+		t.Errorf("template output = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package turbostream
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// A Sanitizer filters a fragment of HTML before it is placed in a turbo-stream element.
+type Sanitizer interface {
+	SanitizeHTML(w io.Writer, fragment []byte) error
+}
+
+// Allowlist is a [Sanitizer] that only permits a fixed set of elements and attributes.
+// Disallowed elements are removed, but their text content is kept,
+// except for elements like script and style,
+// whose content is removed entirely.
+// Comments and doctypes are always removed.
+type Allowlist struct {
+	// Elements maps lowercase element names
+	// to the lowercase attribute names permitted on them.
+	Elements map[string][]string
+}
+
+// SanitizeHTML writes the permitted parts of the HTML fragment to w.
+// Attributes that contain URLs are removed
+// unless they use the http, https, or mailto schemes or are relative.
+func (al *Allowlist) SanitizeHTML(w io.Writer, fragment []byte) error {
+	buf := new(bytes.Buffer)
+	t := html.NewTokenizer(bytes.NewReader(fragment))
+	// skipName is the name of the element whose content is being removed.
+	// skipDepth is the number of open skipName elements.
+	skipName, skipDepth := "", 0
+	for {
+		tt := t.Next()
+		if tt == html.ErrorToken {
+			if err := t.Err(); err != io.EOF {
+				return err
+			}
+			break
+		}
+		tok := t.Token()
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if skipDepth > 0 {
+				if tt == html.StartTagToken && tok.Data == skipName {
+					skipDepth++
+				}
+				continue
+			}
+			if isRawTextElement(tok.Data) {
+				if tt == html.StartTagToken {
+					skipName, skipDepth = tok.Data, 1
+				}
+				continue
+			}
+			allowedAttrs, ok := al.Elements[tok.Data]
+			if !ok {
+				continue
+			}
+			tok.Attr = filterAttributes(tok.Attr, allowedAttrs)
+			buf.WriteString(tok.String())
+		case html.EndTagToken:
+			if skipDepth > 0 {
+				if tok.Data == skipName {
+					skipDepth--
+				}
+				continue
+			}
+			if _, ok := al.Elements[tok.Data]; ok {
+				buf.WriteString(tok.String())
+			}
+		case html.TextToken:
+			if skipDepth == 0 {
+				buf.WriteString(html.EscapeString(tok.Data))
+			}
+		}
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// isRawTextElement reports whether the element's content
+// should be removed along with the element.
+func isRawTextElement(name string) bool {
+	switch name {
+	case "script", "style", "iframe", "noscript", "noembed", "noframes", "template", "textarea", "title", "xmp":
+		return true
+	default:
+		return false
+	}
+}
+
+func filterAttributes(attrs []html.Attribute, allowed []string) []html.Attribute {
+	filtered := attrs[:0]
+	for _, attr := range attrs {
+		if attr.Namespace != "" || !containsString(allowed, attr.Key) {
+			continue
+		}
+		if isURLAttribute(attr.Key) && !isSafeURL(attr.Val) {
+			continue
+		}
+		filtered = append(filtered, attr)
+	}
+	return filtered
+}
+
+func containsString(list []string, s string) bool {
+	for _, elem := range list {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isURLAttribute(name string) bool {
+	switch name {
+	case "href", "src", "action", "formaction", "cite", "poster", "background", "srcset", "xlink:href":
+		return true
+	default:
+		return false
+	}
+}
+
+func isSafeURL(u string) bool {
+	u = strings.TrimSpace(u)
+	i := strings.IndexAny(u, ":/?#")
+	if i == -1 || u[i] != ':' {
+		// Relative URL.
+		return true
+	}
+	switch strings.ToLower(u[:i]) {
+	case "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
@@ -20,11 +20,15 @@ package turbostream
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"html"
+	"html/template"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"zombiezen.com/go/bass/accept"
 )
@@ -36,7 +40,9 @@ const ContentType = "text/vnd.turbo-stream.html"
 // response is supported.
 func IsSupported(reqHeader http.Header) bool {
 	h, err := accept.ParseHeader(reqHeader.Get("Accept"))
-	if err != nil {
+	if err != nil || len(h) == 0 {
+		// An absent Accept header permits any media type,
+		// but Turbo always asks for streams explicitly.
 		return false
 	}
 	return h.Quality(ContentType, map[string]string{"charset": "utf-8"}) > 0
@@ -63,6 +69,39 @@ func Render(w http.ResponseWriter, actions ...*Action) error {
 	return nil
 }
 
+// RenderJSON sends the same actions as [Render]
+// as a JSON array of objects (see [*Action.MarshalJSON]).
+// This is useful for clients like Turbo Native apps
+// that request application/json instead of Turbo Stream HTML.
+// Nil actions are skipped.
+//
+// RenderJSON does not write any data or set headers if it returns an error.
+func RenderJSON(w http.ResponseWriter, actions ...*Action) error {
+	data, err := marshalJSONArray(actions)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data) // ignore errors, since we already wrote
+	return nil
+}
+
+func marshalJSONArray(actions []*Action) ([]byte, error) {
+	objects := make([]json.RawMessage, 0, len(actions))
+	for _, a := range actions {
+		if a == nil {
+			continue
+		}
+		data, err := a.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, data)
+	}
+	return json.Marshal(objects)
+}
+
 // ActionType is the value of the turbo-stream element's action attribute.
 type ActionType string
 
@@ -93,6 +132,19 @@ type Action struct {
 	TargetID string
 	Template Executer
 	Data     interface{}
+
+	// ChildrenOnly adds the children-only attribute to the action,
+	// which limits morphing to the target's children.
+	ChildrenOnly bool
+	// BooleanAttributes is a list of additional attributes without values
+	// to add to the turbo-stream element.
+	// Names must start with an ASCII letter
+	// and consist only of ASCII letters, digits, '-', '_', '.', or ':'.
+	BooleanAttributes []string
+	// If Sanitizer is not nil, then the Template's output
+	// is passed through it before being placed in the turbo-stream element.
+	// This is useful when the template renders untrusted HTML.
+	Sanitizer Sanitizer
 }
 
 // Executer is the interface that wraps the Execute method of templates.
@@ -102,11 +154,50 @@ type Executer interface {
 	Execute(wr io.Writer, data interface{}) error
 }
 
+// A Broadcaster sends actions to every client subscribed to a channel,
+// typically over a WebSocket or server-sent events connection.
+// Implementations must be safe to use from multiple goroutines.
+// The turbostreamtest package provides an implementation
+// that records broadcasts for tests.
+type Broadcaster interface {
+	Broadcast(ctx context.Context, channel string, actions ...*Action) error
+}
+
 // NewRemove returns a new action with type Remove.
 func NewRemove(id string) *Action {
 	return &Action{Type: Remove, TargetID: id}
 }
 
+// WithFuncs returns a shallow copy of a
+// whose Template uses the given template functions,
+// replacing any functions of the same name.
+// This allows templates parsed once at startup
+// to use request-specific helpers.
+// The Template must be nil or an [*html/template.Template],
+// which is cloned before the functions are added,
+// so the Template must not have been executed.
+// If a is nil, WithFuncs returns (nil, nil).
+func (a *Action) WithFuncs(funcs template.FuncMap) (*Action, error) {
+	if a == nil {
+		return nil, nil
+	}
+	a2 := new(Action)
+	*a2 = *a
+	if a.Template == nil || len(funcs) == 0 {
+		return a2, nil
+	}
+	tmpl, ok := a.Template.(*template.Template)
+	if !ok {
+		return nil, fmt.Errorf("turbo-stream %s %s: add template funcs: template is %T, not *html/template.Template", a.Type, a.TargetID, a.Template)
+	}
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("turbo-stream %s %s: add template funcs: %w", a.Type, a.TargetID, err)
+	}
+	a2.Template = clone.Funcs(funcs)
+	return a2, nil
+}
+
 // MarshalText renders the template as HTML. If the Action is nil, then it
 // returns (nil, nil).
 func (a *Action) MarshalText() ([]byte, error) {
@@ -117,6 +208,52 @@ func (a *Action) MarshalText() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// jsonAction is the JSON representation of an [Action].
+type jsonAction struct {
+	Action            ActionType `json:"action"`
+	Target            string     `json:"target"`
+	HTML              *string    `json:"html,omitempty"`
+	ChildrenOnly      bool       `json:"childrenOnly,omitempty"`
+	BooleanAttributes []string   `json:"booleanAttributes,omitempty"`
+}
+
+// MarshalJSON renders the action as a JSON object
+// with the same information as its turbo-stream element:
+//
+//	{"action": "append", "target": "messages", "html": "<p>Hello</p>"}
+//
+// The "html" member holds the output of the Template
+// (after the Sanitizer, if any)
+// and is omitted for Remove actions.
+// The "childrenOnly" and "booleanAttributes" members
+// are omitted if they are empty.
+// If the Action is nil, then it returns null.
+func (a *Action) MarshalJSON() ([]byte, error) {
+	if a == nil {
+		return []byte("null"), nil
+	}
+	if err := a.validate(); err != nil {
+		return nil, fmt.Errorf("marshal turbo-stream: %w", err)
+	}
+	obj := &jsonAction{
+		Action:            a.Type,
+		Target:            a.TargetID,
+		ChildrenOnly:      a.ChildrenOnly,
+		BooleanAttributes: a.BooleanAttributes,
+	}
+	if a.Type != Remove {
+		buf := new(bytes.Buffer)
+		if a.Template != nil {
+			if err := a.executeTemplate(buf); err != nil {
+				return nil, fmt.Errorf("marshal turbo-stream: %s %s: %w", a.Type, a.TargetID, err)
+			}
+		}
+		html := buf.String()
+		obj.HTML = &html
+	}
+	return json.Marshal(obj)
+}
+
 func (a *Action) validate() error {
 	if a == nil {
 		return nil
@@ -130,9 +267,35 @@ func (a *Action) validate() error {
 	if a.Type == Remove && (a.Template != nil || a.Data != nil) {
 		return fmt.Errorf("%s %s: content not empty", a.Type, a.TargetID)
 	}
+	for _, name := range a.BooleanAttributes {
+		if !isValidAttributeName(name) {
+			return fmt.Errorf("%s %s: invalid attribute name %q", a.Type, a.TargetID, name)
+		}
+		switch strings.ToLower(name) {
+		case "action", "target", "children-only":
+			return fmt.Errorf("%s %s: reserved attribute name %q", a.Type, a.TargetID, name)
+		}
+	}
 	return nil
 }
 
+func isValidAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		isLetter := 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+		if i == 0 && !isLetter {
+			return false
+		}
+		if !isLetter && !('0' <= c && c <= '9') && c != '-' && c != '_' && c != '.' && c != ':' {
+			return false
+		}
+	}
+	return true
+}
+
 func (a *Action) appendTo(buf *bytes.Buffer) error {
 	if a == nil {
 		return nil
@@ -141,14 +304,22 @@ func (a *Action) appendTo(buf *bytes.Buffer) error {
 		return fmt.Errorf("marshal turbo-stream: %w", err)
 	}
 	buf.WriteString(`<turbo-stream action="`)
-	buf.WriteString(string(a.Type))
+	buf.WriteString(html.EscapeString(string(a.Type)))
 	buf.WriteString(`" target="`)
 	buf.WriteString(html.EscapeString(a.TargetID))
-	buf.WriteString(`">`)
+	buf.WriteString(`"`)
+	if a.ChildrenOnly {
+		buf.WriteString(" children-only")
+	}
+	for _, name := range a.BooleanAttributes {
+		buf.WriteString(" ")
+		buf.WriteString(name)
+	}
+	buf.WriteString(`>`)
 	if a.Type != Remove {
 		buf.WriteString("\n\t<template>")
 		if a.Template != nil {
-			if err := a.Template.Execute(buf, a.Data); err != nil {
+			if err := a.executeTemplate(buf); err != nil {
 				return fmt.Errorf("marshal turbo-stream: %s %s: %w", a.Type, a.TargetID, err)
 			}
 		}
@@ -157,3 +328,14 @@ func (a *Action) appendTo(buf *bytes.Buffer) error {
 	buf.WriteString("</turbo-stream>")
 	return nil
 }
+
+func (a *Action) executeTemplate(buf *bytes.Buffer) error {
+	if a.Sanitizer == nil {
+		return a.Template.Execute(buf, a.Data)
+	}
+	content := new(bytes.Buffer)
+	if err := a.Template.Execute(content, a.Data); err != nil {
+		return err
+	}
+	return a.Sanitizer.SanitizeHTML(buf, content.Bytes())
+}
@@ -18,8 +18,10 @@ package turbostream
 
 import (
 	"bytes"
+	htmltemplate "html/template"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -121,6 +123,45 @@ func TestMarshalText(t *testing.T) {
 			},
 			wantHTML: `<turbo-stream action="remove" target="message&amp;1"></turbo-stream>`,
 		},
+		{
+			name: "ChildrenOnly",
+			action: &Action{
+				Type:         Update,
+				TargetID:     "messages",
+				Template:     staticTemplate(`<p>Hi</p>`),
+				ChildrenOnly: true,
+			},
+			wantHTML: `<turbo-stream action="update" target="messages" children-only>` +
+				`<template><p>Hi</p></template>` +
+				`</turbo-stream>`,
+		},
+		{
+			name: "BooleanAttributes",
+			action: &Action{
+				Type:              Replace,
+				TargetID:          "messages",
+				Template:          staticTemplate(`<p>Hi</p>`),
+				BooleanAttributes: []string{"data-morph", "x:y"},
+			},
+			wantHTML: `<turbo-stream action="replace" target="messages" data-morph x:y>` +
+				`<template><p>Hi</p></template>` +
+				`</turbo-stream>`,
+		},
+		{
+			name: "Sanitizer",
+			action: &Action{
+				Type:     Append,
+				TargetID: "messages",
+				Template: staticTemplate(`<p onclick="evil()">Hi <script>evil()</script><a href="javascript:evil()">there</a></p></template><b>!</b>`),
+				Sanitizer: &Allowlist{Elements: map[string][]string{
+					"p": nil,
+					"a": {"href"},
+				}},
+			},
+			wantHTML: `<turbo-stream action="append" target="messages">` +
+				`<template><p>Hi <a>there</a></p>!</template>` +
+				`</turbo-stream>`,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -143,6 +184,207 @@ func TestMarshalText(t *testing.T) {
 	}
 }
 
+func TestMarshalTextErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Action
+	}{
+		{
+			name:   "InvalidType",
+			action: &Action{Type: `"><script>`, TargetID: "foo"},
+		},
+		{
+			name: "InvalidAttributeName",
+			action: &Action{
+				Type:              Update,
+				TargetID:          "foo",
+				BooleanAttributes: []string{`x onload="evil()"`},
+			},
+		},
+		{
+			name: "ReservedAttributeName",
+			action: &Action{
+				Type:              Update,
+				TargetID:          "foo",
+				BooleanAttributes: []string{"Target"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.action.MarshalText()
+			if err == nil {
+				t.Errorf("MarshalText() = %q, <nil>; want error", got)
+			}
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Action
+		want   string
+	}{
+		{
+			name:   "Nil",
+			action: nil,
+			want:   `null`,
+		},
+		{
+			name: "Append",
+			action: &Action{
+				Type:     Append,
+				TargetID: "messages",
+				Template: staticTemplate(`<div id="message_1">Hello</div>`),
+			},
+			want: `{"action":"append","target":"messages","html":"\u003cdiv id=\"message_1\"\u003eHello\u003c/div\u003e"}`,
+		},
+		{
+			name: "EmptyUpdate",
+			action: &Action{
+				Type:              Update,
+				TargetID:          "messages",
+				ChildrenOnly:      true,
+				BooleanAttributes: []string{"data-morph"},
+			},
+			want: `{"action":"update","target":"messages","html":"","childrenOnly":true,"booleanAttributes":["data-morph"]}`,
+		},
+		{
+			name:   "Remove",
+			action: NewRemove("message_1"),
+			want:   `{"action":"remove","target":"message_1"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.action.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("MarshalJSON() = %s; want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := RenderJSON(rec, nil, NewRemove("a"), NewRemove("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rec.Header().Get("Content-Type"), "application/json; charset=utf-8"; got != want {
+		t.Errorf("Content-Type = %q; want %q", got, want)
+	}
+	const want = `[{"action":"remove","target":"a"},{"action":"remove","target":"b"}]`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body = %s; want %s", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	if err := RenderJSON(rec, &Action{Type: "bork", TargetID: "a"}); err == nil {
+		t.Error("RenderJSON with invalid action did not return an error")
+	}
+	if rec.Body.Len() > 0 || len(rec.Header()) > 0 {
+		t.Error("RenderJSON wrote data before returning an error")
+	}
+}
+
+func TestWithFuncs(t *testing.T) {
+	tmpl := htmltemplate.Must(htmltemplate.New("item").Funcs(htmltemplate.FuncMap{
+		"greet": func() string { return "" },
+	}).Parse(`<p>{{ greet }}, {{ . }}</p>`))
+	a := &Action{
+		Type:     Append,
+		TargetID: "list",
+		Template: tmpl,
+		Data:     "World",
+	}
+	for _, greeting := range []string{"Hello", "Hi"} {
+		greeting := greeting
+		bound, err := a.WithFuncs(htmltemplate.FuncMap{
+			"greet": func() string { return greeting },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := bound.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "<turbo-stream action=\"append\" target=\"list\">\n\t<template><p>" + greeting + ", World</p></template>\n</turbo-stream>"
+		if string(got) != want {
+			t.Errorf("WithFuncs(greet=%q).MarshalText() = %q; want %q", greeting, got, want)
+		}
+	}
+	if a.Template != tmpl {
+		t.Error("WithFuncs modified the original action")
+	}
+
+	other := &Action{Type: Append, TargetID: "list", Template: nopExecuter{}}
+	if _, err := other.WithFuncs(htmltemplate.FuncMap{"greet": func() string { return "" }}); err == nil {
+		t.Error("WithFuncs on non-html/template Executer did not return an error")
+	}
+}
+
+type nopExecuter struct{}
+
+func (nopExecuter) Execute(io.Writer, interface{}) error { return nil }
+
+func TestAllowlist(t *testing.T) {
+	al := &Allowlist{Elements: map[string][]string{
+		"a":  {"href", "title"},
+		"b":  nil,
+		"br": nil,
+		"p":  {"class"},
+	}}
+	tests := []struct {
+		fragment string
+		want     string
+	}{
+		{
+			fragment: "Hello, World!",
+			want:     "Hello, World!",
+		},
+		{
+			fragment: `<p class="x" style="color:red">A<br/>B</p>`,
+			want:     `<p class="x">A<br/>B</p>`,
+		},
+		{
+			fragment: `<div><b>bold</b></div>`,
+			want:     `<b>bold</b>`,
+		},
+		{
+			fragment: `<a href="/foo" title="Foo">rel</a><a href="https://example.com/">abs</a><a href=" JavaScript:x">js</a>`,
+			want:     `<a href="/foo" title="Foo">rel</a><a href="https://example.com/">abs</a><a>js</a>`,
+		},
+		{
+			fragment: `<style>b { color: red }</style><script>alert("<b>")</script><!-- comment -->ok`,
+			want:     `ok`,
+		},
+		{
+			fragment: `<template><template><b>x</b></template><b>y</b></template><b>z</b>`,
+			want:     `<b>z</b>`,
+		},
+		{
+			fragment: `1 &lt; 2`,
+			want:     `1 &lt; 2`,
+		},
+	}
+	for _, test := range tests {
+		buf := new(bytes.Buffer)
+		if err := al.SanitizeHTML(buf, []byte(test.fragment)); err != nil {
+			t.Errorf("SanitizeHTML(%q): %v", test.fragment, err)
+			continue
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("SanitizeHTML(%q) = %q; want %q", test.fragment, got, test.want)
+		}
+	}
+}
+
 type staticTemplate string
 
 func (s staticTemplate) Execute(w io.Writer, data interface{}) error {
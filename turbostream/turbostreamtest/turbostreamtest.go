@@ -0,0 +1,108 @@
+// Copyright 2021 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package turbostreamtest provides a [turbostream.Broadcaster]
+// that records broadcasts, so that application tests
+// can verify real-time updates without opening connections.
+package turbostreamtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"zombiezen.com/go/bass/turbostream"
+)
+
+// Hub is a [turbostream.Broadcaster] that records every action
+// broadcast to it. The zero value is an empty Hub.
+// It is safe to use a Hub from multiple goroutines.
+type Hub struct {
+	mu       sync.Mutex
+	channels map[string][]*turbostream.Action
+	// all holds every recorded action in the order it was broadcast.
+	all []*turbostream.Action
+	// changed is closed and replaced whenever an action is recorded.
+	changed chan struct{}
+}
+
+// Broadcast records the actions for the channel.
+// Each action is rendered first, as a real broadcaster would,
+// so an action that fails to render is reported as an error
+// and none of the actions are recorded.
+func (hub *Hub) Broadcast(ctx context.Context, channel string, actions ...*turbostream.Action) error {
+	for _, a := range actions {
+		if _, err := a.MarshalText(); err != nil {
+			return fmt.Errorf("broadcast to %s: %w", channel, err)
+		}
+	}
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if hub.channels == nil {
+		hub.channels = make(map[string][]*turbostream.Action)
+	}
+	hub.channels[channel] = append(hub.channels[channel], actions...)
+	hub.all = append(hub.all, actions...)
+	if hub.changed != nil {
+		close(hub.changed)
+		hub.changed = nil
+	}
+	return nil
+}
+
+// ActionsFor returns the actions broadcast to the channel
+// in the order they were broadcast.
+func (hub *Hub) ActionsFor(channel string) []*turbostream.Action {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	return append([]*turbostream.Action(nil), hub.channels[channel]...)
+}
+
+// WaitFor returns the first action broadcast to any channel
+// with the given target DOM ID and type,
+// waiting for one to be broadcast if necessary.
+// If ctx is done before a matching action is broadcast,
+// WaitFor returns an error that wraps ctx.Err().
+func (hub *Hub) WaitFor(ctx context.Context, targetID string, typ turbostream.ActionType) (*turbostream.Action, error) {
+	for {
+		hub.mu.Lock()
+		for _, a := range hub.all {
+			if a.TargetID == targetID && a.Type == typ {
+				hub.mu.Unlock()
+				return a, nil
+			}
+		}
+		if hub.changed == nil {
+			hub.changed = make(chan struct{})
+		}
+		changed := hub.changed
+		hub.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for turbo-stream %s %s: %w", typ, targetID, ctx.Err())
+		}
+	}
+}
+
+// Reset discards all recorded actions.
+func (hub *Hub) Reset() {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hub.channels = nil
+	hub.all = nil
+}
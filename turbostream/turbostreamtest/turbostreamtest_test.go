@@ -0,0 +1,94 @@
+// Copyright 2021 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package turbostreamtest
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"testing"
+	"time"
+
+	"zombiezen.com/go/bass/turbostream"
+)
+
+func TestHub(t *testing.T) {
+	ctx := context.Background()
+	var b turbostream.Broadcaster = new(Hub)
+	hub := b.(*Hub)
+	tmpl := template.Must(template.New("").Parse(`<li>{{ . }}</li>`))
+	appendAction := &turbostream.Action{Type: turbostream.Append, TargetID: "messages", Template: tmpl, Data: "hi"}
+	if err := hub.Broadcast(ctx, "room:1", appendAction); err != nil {
+		t.Fatal(err)
+	}
+	if err := hub.Broadcast(ctx, "room:2", turbostream.NewRemove("message_5")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := hub.ActionsFor("room:1"); len(got) != 1 || got[0] != appendAction {
+		t.Errorf("hub.ActionsFor(\"room:1\") = %v; want [%v]", got, appendAction)
+	}
+	if got := hub.ActionsFor("room:3"); len(got) != 0 {
+		t.Errorf("hub.ActionsFor(\"room:3\") = %v; want []", got)
+	}
+	got, err := hub.WaitFor(ctx, "message_5", turbostream.Remove)
+	if err != nil || got.TargetID != "message_5" {
+		t.Errorf("hub.WaitFor(ctx, \"message_5\", turbostream.Remove) = %v, %v; want remove action, <nil>", got, err)
+	}
+
+	hub.Reset()
+	if got := hub.ActionsFor("room:1"); len(got) != 0 {
+		t.Errorf("after Reset, hub.ActionsFor(\"room:1\") = %v; want []", got)
+	}
+}
+
+func TestHubWaitFor(t *testing.T) {
+	hub := new(Hub)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go func() {
+		hub.Broadcast(ctx, "room:1", turbostream.NewRemove("other"))
+		hub.Broadcast(ctx, "room:1", turbostream.NewRemove("message_1"))
+	}()
+	got, err := hub.WaitFor(ctx, "message_1", turbostream.Remove)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TargetID != "message_1" {
+		t.Errorf("TargetID = %q; want %q", got.TargetID, "message_1")
+	}
+
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	if _, err := hub.WaitFor(shortCtx, "message_1", turbostream.Append); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("hub.WaitFor(...) error = %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestHubRejectsInvalidActions(t *testing.T) {
+	hub := new(Hub)
+	err := hub.Broadcast(context.Background(), "room:1",
+		turbostream.NewRemove("ok"),
+		&turbostream.Action{Type: "explode", TargetID: "x"},
+	)
+	if err == nil {
+		t.Error("Broadcast did not return an error")
+	}
+	if got := hub.ActionsFor("room:1"); len(got) != 0 {
+		t.Errorf("hub.ActionsFor(\"room:1\") = %v; want []", got)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2021 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package uploads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DirStore is a [Store] that keeps uploads in a directory on disk.
+// Each upload is stored as two files:
+// "{id}.bin" holds the data received so far
+// and "{id}.json" holds the upload's length and metadata.
+// The upload's offset is the size of its data file.
+type DirStore struct {
+	dir string
+
+	mu    sync.Mutex
+	locks map[string]*uploadLock
+}
+
+// uploadLock serializes writes to a single upload.
+type uploadLock struct {
+	mu   sync.Mutex
+	refs int // protected by DirStore.mu
+}
+
+// NewDirStore returns a new [DirStore] that stores uploads in dir.
+// The directory must already exist.
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{
+		dir:   dir,
+		locks: make(map[string]*uploadLock),
+	}
+}
+
+type dirUploadInfo struct {
+	Length   int64             `json:"length"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Create starts a new empty upload.
+func (d *DirStore) Create(ctx context.Context, length int64, metadata map[string]string) (*Upload, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	info, err := json.Marshal(&dirUploadInfo{Length: length, Metadata: metadata})
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(d.dataPath(id), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(d.dataPath(id))
+		return nil, err
+	}
+	if err := os.WriteFile(d.infoPath(id), info, 0o666); err != nil {
+		os.Remove(d.dataPath(id))
+		return nil, err
+	}
+	return &Upload{ID: id, Length: length, Metadata: metadata}, nil
+}
+
+// Get returns the upload with the given ID.
+func (d *DirStore) Get(ctx context.Context, id string) (*Upload, error) {
+	if !validID(id) {
+		return nil, fmt.Errorf("upload %s: %w", id, ErrNotFound)
+	}
+	data, err := os.ReadFile(d.infoPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("upload %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	info := new(dirUploadInfo)
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("upload %s: %w", id, err)
+	}
+	st, err := os.Stat(d.dataPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("upload %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Upload{
+		ID:       id,
+		Length:   info.Length,
+		Offset:   st.Size(),
+		Metadata: info.Metadata,
+	}, nil
+}
+
+// Append writes data from r to the end of the upload.
+func (d *DirStore) Append(ctx context.Context, id string, offset int64, r io.Reader) (int64, error) {
+	unlock := d.lock(id)
+	defer unlock()
+	u, err := d.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if offset != u.Offset {
+		return u.Offset, fmt.Errorf("upload %s: %w", id, ErrOffsetMismatch)
+	}
+	f, err := os.OpenFile(d.dataPath(id), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return u.Offset, err
+	}
+	n, copyErr := io.Copy(f, io.LimitReader(r, u.Length-u.Offset))
+	closeErr := f.Close()
+	newOffset := u.Offset + n
+	if copyErr != nil {
+		return newOffset, copyErr
+	}
+	if closeErr != nil {
+		return newOffset, closeErr
+	}
+	if newOffset == u.Length {
+		// Check for data past the end of the upload.
+		var extra [1]byte
+		if n, _ := r.Read(extra[:]); n > 0 {
+			return newOffset, fmt.Errorf("upload %s: %w", id, ErrTooLarge)
+		}
+	}
+	return newOffset, nil
+}
+
+// Open returns the contents of the upload.
+func (d *DirStore) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	if !validID(id) {
+		return nil, fmt.Errorf("upload %s: %w", id, ErrNotFound)
+	}
+	f, err := os.Open(d.dataPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("upload %s: %w", id, ErrNotFound)
+	}
+	return f, err
+}
+
+// Delete removes the upload's files.
+func (d *DirStore) Delete(ctx context.Context, id string) error {
+	if !validID(id) {
+		return fmt.Errorf("upload %s: %w", id, ErrNotFound)
+	}
+	unlock := d.lock(id)
+	defer unlock()
+	err1 := os.Remove(d.infoPath(id))
+	err2 := os.Remove(d.dataPath(id))
+	if errors.Is(err1, fs.ErrNotExist) && errors.Is(err2, fs.ErrNotExist) {
+		return fmt.Errorf("upload %s: %w", id, ErrNotFound)
+	}
+	if err1 != nil && !errors.Is(err1, fs.ErrNotExist) {
+		return err1
+	}
+	if err2 != nil && !errors.Is(err2, fs.ErrNotExist) {
+		return err2
+	}
+	return nil
+}
+
+// lock acquires the mutex for the given upload
+// and returns a function that releases it.
+func (d *DirStore) lock(id string) (unlock func()) {
+	d.mu.Lock()
+	l := d.locks[id]
+	if l == nil {
+		l = new(uploadLock)
+		d.locks[id] = l
+	}
+	l.refs++
+	d.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		d.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(d.locks, id)
+		}
+		d.mu.Unlock()
+	}
+}
+
+func (d *DirStore) dataPath(id string) string {
+	return filepath.Join(d.dir, id+".bin")
+}
+
+func (d *DirStore) infoPath(id string) string {
+	return filepath.Join(d.dir, id+".json")
+}
+
+// validID reports whether id could have been returned by newID,
+// so that IDs from requests cannot refer to other files.
+func validID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if c := id[i]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
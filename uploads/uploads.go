@@ -0,0 +1,387 @@
+// Copyright 2021 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package uploads provides an HTTP handler for resumable file uploads
+// using the core and creation parts of the [tus protocol].
+//
+// A client creates an upload with a POST request to the handler's root,
+// then sends the file's contents in one or more PATCH requests
+// to the URL in the response's Location header.
+// If a PATCH is interrupted, the client asks for the upload's offset
+// with a HEAD request and resumes from there.
+//
+// [tus protocol]: https://tus.io/protocols/resumable-upload
+package uploads
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	slashpath "path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of the tus protocol implemented by [Handler].
+const Version = "1.0.0"
+
+const (
+	tusResumableHeaderName = "Tus-Resumable"
+	uploadOffsetHeaderName = "Upload-Offset"
+	uploadLengthHeaderName = "Upload-Length"
+	uploadMetadataHeader   = "Upload-Metadata"
+
+	offsetContentType = "application/offset+octet-stream"
+)
+
+// Errors returned by a [Store].
+var (
+	// ErrNotFound is returned for an upload that does not exist.
+	ErrNotFound = errors.New("upload not found")
+	// ErrOffsetMismatch is returned by [Store.Append]
+	// if the offset does not match the upload's current offset.
+	ErrOffsetMismatch = errors.New("upload offset mismatch")
+	// ErrTooLarge is returned by [Store.Append]
+	// if the data would extend the upload past its length.
+	ErrTooLarge = errors.New("upload exceeds its length")
+)
+
+// An Upload describes a file being uploaded.
+type Upload struct {
+	// ID is the store's identifier for the upload.
+	// It consists only of URL-safe characters.
+	ID string
+	// Length is the total size of the file in bytes.
+	Length int64
+	// Offset is the number of bytes received so far.
+	Offset int64
+	// Metadata holds the key-value pairs
+	// sent by the client in the Upload-Metadata header,
+	// like the file's name.
+	Metadata map[string]string
+}
+
+// Complete reports whether all of the upload's bytes have been received.
+func (u *Upload) Complete() bool {
+	return u.Offset >= u.Length
+}
+
+// Store is the interface for the storage of uploads.
+// Implementations must be safe to call from multiple goroutines
+// and must serialize calls to Append for the same upload.
+type Store interface {
+	// Create starts a new empty upload.
+	Create(ctx context.Context, length int64, metadata map[string]string) (*Upload, error)
+	// Get returns the upload with the given ID.
+	Get(ctx context.Context, id string) (*Upload, error)
+	// Append writes data from r to the end of the upload
+	// if the upload's current offset is equal to offset.
+	// It returns the upload's new offset,
+	// which reflects the bytes written even if reading r fails partway,
+	// so that the client can resume.
+	Append(ctx context.Context, id string, offset int64, r io.Reader) (int64, error)
+	// Open returns the contents of the upload.
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+	// Delete removes the upload.
+	Delete(ctx context.Context, id string) error
+}
+
+// Handler is an HTTP handler that receives resumable uploads into a [Store].
+// It serves POST requests to its root path ("/")
+// and HEAD, PATCH, and DELETE requests to "/{id}",
+// so it is typically mounted with [http.StripPrefix].
+type Handler struct {
+	store       Store
+	maxSize     int64
+	onComplete  func(ctx context.Context, u *Upload) error
+	reportError func(ctx context.Context, err error)
+}
+
+// NewHandler returns a new Handler that stores uploads in the given store.
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+// SetMaxSize sets the largest upload in bytes that the Handler accepts.
+// If n is zero or negative, upload sizes are not limited.
+//
+// SetMaxSize must not be called concurrently with ServeHTTP.
+func (h *Handler) SetMaxSize(n int64) {
+	h.maxSize = n
+}
+
+// SetOnComplete sets a callback that is called
+// after the final byte of an upload has been stored,
+// before the response to the final PATCH request is sent.
+// The callback typically moves the file to its permanent home
+// (using [Store.Open]) and deletes the upload from the store.
+// If the callback returns an error,
+// the PATCH request fails with a 500 (Internal Server Error),
+// but the upload remains complete.
+// A nil function removes the callback.
+//
+// SetOnComplete must not be called concurrently with ServeHTTP.
+func (h *Handler) SetOnComplete(f func(ctx context.Context, u *Upload) error) {
+	h.onComplete = f
+}
+
+// SetReportError sets a callback for errors from the store
+// or from the completion callback.
+// A nil function removes the callback.
+//
+// SetReportError must not be called concurrently with ServeHTTP.
+func (h *Handler) SetReportError(f func(ctx context.Context, err error)) {
+	h.reportError = f
+}
+
+// ServeHTTP handles a tus protocol request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(tusResumableHeaderName, Version)
+	if r.Method == http.MethodOptions {
+		h.options(w)
+		return
+	}
+	if v := r.Header.Get(tusResumableHeaderName); v != Version {
+		w.Header().Set("Tus-Version", Version)
+		http.Error(w, "unsupported tus protocol version", http.StatusPreconditionFailed)
+		return
+	}
+	id := strings.TrimPrefix(slashpath.Clean("/"+r.URL.Path), "/")
+	if id == "" {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "OPTIONS, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.create(w, r)
+		return
+	}
+	if strings.Contains(id, "/") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodHead:
+		h.head(w, r, id)
+	case http.MethodPatch:
+		h.patch(w, r, id)
+	case http.MethodDelete:
+		h.delete(w, r, id)
+	default:
+		w.Header().Set("Allow", "OPTIONS, HEAD, PATCH, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) options(w http.ResponseWriter) {
+	w.Header().Set("Tus-Version", Version)
+	w.Header().Set("Tus-Extension", "creation,termination")
+	if h.maxSize > 0 {
+		w.Header().Set("Tus-Max-Size", strconv.FormatInt(h.maxSize, 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	length, err := strconv.ParseInt(r.Header.Get(uploadLengthHeaderName), 10, 64)
+	if err != nil || length < 0 {
+		http.Error(w, "missing or invalid "+uploadLengthHeaderName, http.StatusBadRequest)
+		return
+	}
+	if h.maxSize > 0 && length > h.maxSize {
+		http.Error(w, "upload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	metadata, err := parseMetadata(r.Header.Get(uploadMetadataHeader))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	u, err := h.store.Create(r.Context(), length, metadata)
+	if err != nil {
+		h.error(r.Context(), w, fmt.Errorf("create upload: %w", err))
+		return
+	}
+	w.Header().Set("Location", slashpath.Join(requestPath(r), u.ID))
+	w.Header().Set(uploadOffsetHeaderName, "0")
+	if length == 0 && !h.complete(w, r, u) {
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) head(w http.ResponseWriter, r *http.Request, id string) {
+	u, err := h.store.Get(r.Context(), id)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.error(r.Context(), w, fmt.Errorf("get upload %s: %w", id, err))
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set(uploadOffsetHeaderName, strconv.FormatInt(u.Offset, 10))
+	w.Header().Set(uploadLengthHeaderName, strconv.FormatInt(u.Length, 10))
+	if len(u.Metadata) > 0 {
+		w.Header().Set(uploadMetadataHeader, formatMetadata(u.Metadata))
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) patch(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Header.Get("Content-Type") != offsetContentType {
+		http.Error(w, "Content-Type must be "+offsetContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get(uploadOffsetHeaderName), 10, 64)
+	if err != nil || offset < 0 {
+		http.Error(w, "missing or invalid "+uploadOffsetHeaderName, http.StatusBadRequest)
+		return
+	}
+	newOffset, err := h.store.Append(r.Context(), id, offset, r.Body)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	case errors.Is(err, ErrOffsetMismatch):
+		http.Error(w, "offset does not match upload", http.StatusConflict)
+		return
+	case errors.Is(err, ErrTooLarge):
+		http.Error(w, "data exceeds upload length", http.StatusRequestEntityTooLarge)
+		return
+	case err != nil:
+		h.error(r.Context(), w, fmt.Errorf("append to upload %s: %w", id, err))
+		return
+	}
+	w.Header().Set(uploadOffsetHeaderName, strconv.FormatInt(newOffset, 10))
+	if newOffset > offset {
+		u, err := h.store.Get(r.Context(), id)
+		if err != nil {
+			h.error(r.Context(), w, fmt.Errorf("get upload %s: %w", id, err))
+			return
+		}
+		if u.Complete() && !h.complete(w, r, u) {
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// complete calls the completion callback for u.
+// If the callback fails, complete serves an error and returns false.
+func (h *Handler) complete(w http.ResponseWriter, r *http.Request, u *Upload) bool {
+	if h.onComplete == nil {
+		return true
+	}
+	if err := h.onComplete(r.Context(), u); err != nil {
+		h.error(r.Context(), w, fmt.Errorf("complete upload %s: %w", u.ID, err))
+		return false
+	}
+	return true
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, id string) {
+	err := h.store.Delete(r.Context(), id)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.error(r.Context(), w, fmt.Errorf("delete upload %s: %w", id, err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) error(ctx context.Context, w http.ResponseWriter, err error) {
+	if h.reportError != nil {
+		h.reportError(ctx, err)
+	}
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
+
+// requestPath returns the escaped path that the client requested,
+// before any prefix was removed by [http.StripPrefix].
+func requestPath(r *http.Request) string {
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		return u.EscapedPath()
+	}
+	return r.URL.EscapedPath()
+}
+
+// parseMetadata parses an Upload-Metadata header:
+// a comma-separated list of keys, each optionally followed by a space
+// and a base64-encoded value.
+func parseMetadata(s string) (map[string]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	m := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		key, encoded := pair, ""
+		if i := strings.IndexByte(pair, ' '); i >= 0 {
+			key, encoded = pair[:i], strings.TrimSpace(pair[i+1:])
+		}
+		if key == "" {
+			return nil, fmt.Errorf("parse %s: empty key", uploadMetadataHeader)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("parse %s: duplicate key %q", uploadMetadataHeader, key)
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: key %q: %w", uploadMetadataHeader, key, err)
+		}
+		m[key] = string(value)
+	}
+	return m, nil
+}
+
+// formatMetadata formats m for an Upload-Metadata header.
+// Keys are sorted.
+func formatMetadata(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k
+		if v := m[k]; v != "" {
+			parts[i] += " " + base64.StdEncoding.EncodeToString([]byte(v))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// newID returns a new random upload identifier.
+func newID() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
@@ -0,0 +1,238 @@
+// Copyright 2021 The Bass Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//		 https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package uploads
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandler(t *testing.T) {
+	store := NewDirStore(t.TempDir())
+	h := NewHandler(store)
+	h.SetMaxSize(1 << 20)
+	var completed *Upload
+	var completedData string
+	h.SetOnComplete(func(ctx context.Context, u *Upload) error {
+		completed = u
+		rc, err := store.Open(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		completedData = string(data)
+		return err
+	})
+	h.SetReportError(func(ctx context.Context, err error) {
+		t.Error("Reported error:", err)
+	})
+	srv := http.NewServeMux()
+	srv.Handle("/files/", http.StripPrefix("/files", h))
+
+	do := func(method, target string, header map[string]string, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Tus-Resumable", Version)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, r)
+		return rec
+	}
+
+	// Create.
+	const content = "Hello, World!"
+	rec := do(http.MethodPost, "/files/", map[string]string{
+		"Upload-Length":   "13",
+		"Upload-Metadata": "filename aGVsbG8udHh0,public",
+	}, "")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST status = %d; want %d", rec.Code, http.StatusCreated)
+	}
+	location := rec.Header().Get("Location")
+	if !strings.HasPrefix(location, "/files/") {
+		t.Fatalf("Location = %q; want /files/{id}", location)
+	}
+
+	// Send the first chunk.
+	patchHeader := func(offset string) map[string]string {
+		return map[string]string{
+			"Content-Type":  "application/offset+octet-stream",
+			"Upload-Offset": offset,
+		}
+	}
+	rec = do(http.MethodPatch, location, patchHeader("0"), content[:5])
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Upload-Offset") != "5" {
+		t.Errorf("first PATCH = %d with Upload-Offset %q; want %d with 5", rec.Code, rec.Header().Get("Upload-Offset"), http.StatusNoContent)
+	}
+
+	// Resuming from the wrong offset is a conflict.
+	rec = do(http.MethodPatch, location, patchHeader("3"), content[3:])
+	if rec.Code != http.StatusConflict {
+		t.Errorf("PATCH with stale offset status = %d; want %d", rec.Code, http.StatusConflict)
+	}
+
+	// Ask for the offset.
+	rec = do(http.MethodHead, location, nil, "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("HEAD status = %d; want %d", rec.Code, http.StatusOK)
+	}
+	for k, want := range map[string]string{
+		"Upload-Offset":   "5",
+		"Upload-Length":   "13",
+		"Upload-Metadata": "filename aGVsbG8udHh0,public",
+		"Cache-Control":   "no-store",
+	} {
+		if got := rec.Header().Get(k); got != want {
+			t.Errorf("HEAD %s = %q; want %q", k, got, want)
+		}
+	}
+	if completed != nil {
+		t.Fatal("upload completed early")
+	}
+
+	// Finish.
+	rec = do(http.MethodPatch, location, patchHeader("5"), content[5:])
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Upload-Offset") != "13" {
+		t.Errorf("final PATCH = %d with Upload-Offset %q; want %d with 13", rec.Code, rec.Header().Get("Upload-Offset"), http.StatusNoContent)
+	}
+	if completed == nil {
+		t.Fatal("completion callback not called")
+	}
+	want := &Upload{
+		ID:       strings.TrimPrefix(location, "/files/"),
+		Length:   13,
+		Offset:   13,
+		Metadata: map[string]string{"filename": "hello.txt", "public": ""},
+	}
+	if diff := cmp.Diff(want, completed); diff != "" {
+		t.Errorf("completed upload (-want +got):\n%s", diff)
+	}
+	if completedData != content {
+		t.Errorf("completed data = %q; want %q", completedData, content)
+	}
+
+	// Delete.
+	if rec := do(http.MethodDelete, location, nil, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("DELETE status = %d; want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := do(http.MethodHead, location, nil, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("HEAD after DELETE status = %d; want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	store := NewDirStore(t.TempDir())
+	h := NewHandler(store)
+	h.SetMaxSize(10)
+	u, err := store.Create(context.Background(), 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		header   map[string]string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "MissingVersion",
+			method:   http.MethodPost,
+			path:     "/",
+			header:   map[string]string{"Upload-Length": "4"},
+			wantCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "TooLarge",
+			method:   http.MethodPost,
+			path:     "/",
+			header:   map[string]string{"Tus-Resumable": Version, "Upload-Length": "11"},
+			wantCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "MissingLength",
+			method:   http.MethodPost,
+			path:     "/",
+			header:   map[string]string{"Tus-Resumable": Version},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "BadMetadata",
+			method:   http.MethodPost,
+			path:     "/",
+			header:   map[string]string{"Tus-Resumable": Version, "Upload-Length": "1", "Upload-Metadata": "name !!!"},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "WrongContentType",
+			method:   http.MethodPatch,
+			path:     "/" + u.ID,
+			header:   map[string]string{"Tus-Resumable": Version, "Upload-Offset": "0", "Content-Type": "text/plain"},
+			body:     "abcd",
+			wantCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:     "PastEnd",
+			method:   http.MethodPatch,
+			path:     "/" + u.ID,
+			header:   map[string]string{"Tus-Resumable": Version, "Upload-Offset": "0", "Content-Type": "application/offset+octet-stream"},
+			body:     "abcdef",
+			wantCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "UnknownUpload",
+			method:   http.MethodHead,
+			path:     "/../../etc/passwd",
+			header:   map[string]string{"Tus-Resumable": Version},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Options",
+			method:   http.MethodOptions,
+			path:     "/",
+			wantCode: http.StatusNoContent,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			r.URL.Path = test.path
+			for k, v := range test.header {
+				r.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			if rec.Code != test.wantCode {
+				t.Errorf("status = %d; want %d", rec.Code, test.wantCode)
+			}
+			if got := rec.Header().Get("Tus-Resumable"); got != Version {
+				t.Errorf("Tus-Resumable = %q; want %q", got, Version)
+			}
+		})
+	}
+}